@@ -2,14 +2,38 @@ package main
 
 import (
 	"backend/internal"
+	"backend/internal/attachment"
+	"backend/internal/audit"
 	"backend/internal/auth"
+	"backend/internal/block"
+	"backend/internal/board"
+	"backend/internal/bookmark"
+	"backend/internal/clientcert"
 	"backend/internal/comment"
 	"backend/internal/config"
 	"backend/internal/database"
+	"backend/internal/event"
+	"backend/internal/filter"
+	"backend/internal/follow"
+	"backend/internal/gemini"
 	"backend/internal/jwt"
+	"backend/internal/live"
+	"backend/internal/mention"
+	"backend/internal/message"
+	"backend/internal/nntp"
+	"backend/internal/notification"
+	"backend/internal/poll"
 	"backend/internal/post"
+	"backend/internal/ratelimit"
+	"backend/internal/readmarker"
+	"backend/internal/report"
+	"backend/internal/sshkey"
+	"backend/internal/syndication"
+	"backend/internal/tui"
 	"backend/internal/user"
+	"backend/internal/watch"
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"github.com/google/uuid"
@@ -26,6 +50,7 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
 	"syscall"
@@ -106,48 +131,223 @@ func main() {
 
 	// initialize service
 	jwtService := jwt.NewService(logger, cfg.Secret, 24*time.Hour)
-	userService := user.NewService(logger, dbPool)
-	commentService := comment.NewService(logger, dbPool)
-	postService := post.NewService(logger, dbPool)
+	auditService := audit.NewService(logger, dbPool)
+	userService := user.NewService(logger, dbPool, auditService)
+	eventService := event.NewService(logger, dbPool)
+	notificationService := notification.NewService(logger, dbPool)
+	watchService := watch.NewService(logger, dbPool)
+	readMarkerService := readmarker.NewService(logger, dbPool)
+	mentionService := mention.NewService(logger, dbPool, userService, notificationService, watchService)
+	contentFilter, err := filter.FromConfig(logger, dbPool, cfg.ContentFilter)
+	if err != nil {
+		logger.Fatal("Failed to initialize content filter", zap.Error(err))
+	}
+	commentService := comment.NewService(logger, dbPool, eventService, notificationService, mentionService, watchService, auditService, contentFilter)
+	postService := post.NewService(logger, dbPool, eventService, mentionService, watchService, readMarkerService, auditService, contentFilter)
+	boardService := board.NewService(logger, dbPool)
+	blockService := block.NewService(logger, dbPool)
+	followService := follow.NewService(logger, dbPool)
+	syndicationService := syndication.NewService(logger, dbPool)
+	messageService := message.NewService(logger, dbPool, blockService)
+	reportService := report.NewService(logger, dbPool, postService, commentService, notificationService, auditService)
+	attachmentStorage, err := attachment.NewStorage(context.Background(), cfg.Attachments)
+	if err != nil {
+		logger.Fatal("Failed to initialize attachment storage", zap.Error(err))
+	}
+	attachmentService := attachment.NewService(logger, dbPool, attachmentStorage, cfg.Attachments)
+	pollService := poll.NewService(logger, dbPool)
+	bookmarkService := bookmark.NewService(logger, dbPool)
+	sshKeyService := sshkey.NewService(logger, dbPool)
+	clientCertService := clientcert.NewService(logger, dbPool)
 
 	// initialize middleware
-	jwtMiddleware := jwt.NewMiddleware(jwtService, logger)
+	jwtMiddleware := jwt.NewMiddleware(jwtService, userService, logger)
+	limiter, err := ratelimit.NewLimiter(cfg.RateLimits)
+	if err != nil {
+		logger.Fatal("Failed to initialize rate limiter", zap.Error(err))
+	}
 
 	// initialize handler
-	authHandler := auth.NewHandler(validator, logger, userService, jwtService)
-	userHandler := user.NewHandler(validator, logger, userService)
+	authHandler := auth.NewHandler(validator, logger, userService, jwtService, auditService)
+	userHandler := user.NewHandler(validator, logger, userService, notificationService)
 	commentHandler := comment.NewHandler(validator, logger, commentService)
 	postHandler := post.NewHandler(validator, logger, postService)
+	eventHandler := event.NewHandler(logger, eventService)
+	notificationHandler := notification.NewHandler(logger, notificationService)
+	boardHandler := board.NewHandler(validator, logger, boardService)
+	watchHandler := watch.NewHandler(validator, logger, watchService)
+	readMarkerHandler := readmarker.NewHandler(validator, logger, readMarkerService)
+	blockHandler := block.NewHandler(logger, blockService)
+	followHandler := follow.NewHandler(logger, followService)
+	syndicationHandler := syndication.NewHandler(logger, syndicationService, cfg.PublicURL)
+	messageHandler := message.NewHandler(validator, logger, messageService)
+	reportHandler := report.NewHandler(validator, logger, reportService)
+	auditHandler := audit.NewHandler(logger, auditService)
+	attachmentHandler := attachment.NewHandler(logger, attachmentService)
+	pollHandler := poll.NewHandler(validator, logger, pollService)
+	bookmarkHandler := bookmark.NewHandler(validator, logger, bookmarkService)
+	sshKeyHandler := sshkey.NewHandler(validator, logger, sshKeyService)
+	clientCertHandler := clientcert.NewHandler(validator, logger, clientCertService)
+	liveHub := live.NewHub(logger, eventService)
+	liveHandler := live.NewHandler(logger, liveHub, jwtMiddleware, postService)
 
 	// initialize mux
 	mux := http.NewServeMux()
 
 	// set up routes
-	mux.HandleFunc("POST /api/login", basicMiddleware(authHandler.LoginHandler, logger, cfg.Debug))
+	mux.HandleFunc("POST /api/login", basicMiddleware(authHandler.LoginHandler, limiter, logger, cfg.Debug))
 	// This handler duplicates the above handler intentionally for teaching clarity.
 	// mux.HandleFunc("POST /api/login", internal.TraceMiddleware(internal.RecoverMiddleware(authHandler.LoginHandler, logger), logger))
 
-	mux.HandleFunc("POST /api/register", basicMiddleware(authHandler.RegisterHandler, logger, cfg.Debug))
-
-	mux.HandleFunc("POST /api/user", requireUserRoleMiddleware(userHandler.CreateHandler, jwtMiddleware, logger, cfg.Debug))
-
-	mux.HandleFunc("GET /api/comments", requireUserRoleMiddleware(commentHandler.GetAllHandler, jwtMiddleware, logger, cfg.Debug))
-	mux.HandleFunc("GET /api/post/{post_id}/comments", requireUserRoleMiddleware(commentHandler.GetByPostHandler, jwtMiddleware, logger, cfg.Debug))
-	mux.HandleFunc("POST /api/post/{post_id}/comments", requireUserRoleMiddleware(commentHandler.CreateHandler, jwtMiddleware, logger, cfg.Debug))
-	mux.HandleFunc("GET /api/comment/{id}", requireUserRoleMiddleware(commentHandler.GetByIdHandler, jwtMiddleware, logger, cfg.Debug))
-
-	mux.HandleFunc("GET /api/posts", requireUserRoleMiddleware(postHandler.GetAllHandler, jwtMiddleware, logger, cfg.Debug))
-	mux.HandleFunc("POST /api/posts", requireUserRoleMiddleware(postHandler.CreateHandler, jwtMiddleware, logger, cfg.Debug))
-	mux.HandleFunc("GET /api/post/{id}", requireUserRoleMiddleware(postHandler.GetHandler, jwtMiddleware, logger, cfg.Debug))
+	mux.HandleFunc("POST /api/register", basicMiddleware(authHandler.RegisterHandler, limiter, logger, cfg.Debug))
+
+	mux.HandleFunc("POST /api/user", requireUserRoleMiddleware(userHandler.CreateHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("GET /api/me", requireUserRoleMiddleware(userHandler.MeHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("PUT /api/user/{id}/role", requireRoleMiddleware(userHandler.UpdateRoleHandler, jwt.RoleAdmin, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("POST /api/user/{id}/suspend", requireRoleMiddleware(userHandler.SuspendHandler, jwt.RoleModerator, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("DELETE /api/user/{id}/suspend", requireRoleMiddleware(userHandler.UnsuspendHandler, jwt.RoleModerator, jwtMiddleware, limiter, logger, cfg.Debug))
+
+	mux.HandleFunc("GET /api/notifications", requireUserRoleMiddleware(notificationHandler.GetAllHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("POST /api/notifications/read", requireUserRoleMiddleware(notificationHandler.MarkAllReadHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("POST /api/notification/{id}/read", requireUserRoleMiddleware(notificationHandler.MarkReadHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+
+	mux.HandleFunc("GET /api/comments", requireUserRoleMiddleware(commentHandler.GetAllHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("GET /api/post/{post_id}/comments", requireUserRoleMiddleware(commentHandler.GetByPostHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("POST /api/post/{post_id}/comments", requireUserRoleMiddleware(commentHandler.CreateHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("POST /api/post/{post_id}/read", requireUserRoleMiddleware(readMarkerHandler.MarkReadHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("GET /api/comment/{id}", requireUserRoleMiddleware(commentHandler.GetByIdHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("PUT /api/comment/{id}", requireUserRoleMiddleware(commentHandler.UpdateHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("DELETE /api/comment/{id}", requireUserRoleMiddleware(commentHandler.DeleteHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+
+	mux.HandleFunc("GET /api/posts", requireUserRoleMiddleware(postHandler.GetAllHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("POST /api/posts", requireUserRoleMiddleware(postHandler.CreateHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("GET /api/post/{id}", requireUserRoleMiddleware(postHandler.GetHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("PUT /api/post/{id}", requireUserRoleMiddleware(postHandler.UpdateHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("DELETE /api/post/{id}", requireUserRoleMiddleware(postHandler.DeleteHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("POST /api/post/{id}/publish", requireUserRoleMiddleware(postHandler.PublishHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("PUT /api/post/{id}/lock", requireRoleMiddleware(postHandler.LockHandler, jwt.RoleModerator, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("DELETE /api/post/{id}/lock", requireRoleMiddleware(postHandler.UnlockHandler, jwt.RoleModerator, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("PUT /api/post/{id}/pin", requireRoleMiddleware(postHandler.PinHandler, jwt.RoleModerator, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("DELETE /api/post/{id}/pin", requireRoleMiddleware(postHandler.UnpinHandler, jwt.RoleModerator, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("PUT /api/post/{id}/archive", requireRoleMiddleware(postHandler.ArchiveHandler, jwt.RoleModerator, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("DELETE /api/post/{id}/archive", requireRoleMiddleware(postHandler.UnarchiveHandler, jwt.RoleModerator, jwtMiddleware, limiter, logger, cfg.Debug))
+
+	mux.HandleFunc("GET /api/boards", requireUserRoleMiddleware(boardHandler.GetAllHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("POST /api/boards", requireRoleMiddleware(boardHandler.CreateHandler, jwt.RoleModerator, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("GET /api/board/{id}", requireUserRoleMiddleware(boardHandler.GetHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+
+	mux.HandleFunc("GET /api/watches", requireUserRoleMiddleware(watchHandler.GetAllHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("POST /api/post/{id}/watch", requireUserRoleMiddleware(watchHandler.WatchPostHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("DELETE /api/post/{id}/watch", requireUserRoleMiddleware(watchHandler.UnwatchPostHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("POST /api/board/{id}/watch", requireUserRoleMiddleware(watchHandler.WatchBoardHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("DELETE /api/board/{id}/watch", requireUserRoleMiddleware(watchHandler.UnwatchBoardHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+
+	mux.HandleFunc("GET /api/me/bookmarks", requireUserRoleMiddleware(bookmarkHandler.GetAllHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("PUT /api/post/{id}/bookmark", requireUserRoleMiddleware(bookmarkHandler.SaveHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("DELETE /api/post/{id}/bookmark", requireUserRoleMiddleware(bookmarkHandler.RemoveHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("GET /api/me/ssh-keys", requireUserRoleMiddleware(sshKeyHandler.GetAllHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("POST /api/me/ssh-keys", requireUserRoleMiddleware(sshKeyHandler.AddHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("DELETE /api/me/ssh-keys/{id}", requireUserRoleMiddleware(sshKeyHandler.RemoveHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("GET /api/me/client-certificates", requireUserRoleMiddleware(clientCertHandler.GetAllHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("POST /api/me/client-certificates", requireUserRoleMiddleware(clientCertHandler.AddHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("DELETE /api/me/client-certificates/{id}", requireUserRoleMiddleware(clientCertHandler.RemoveHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+
+	mux.HandleFunc("GET /api/blocks", requireUserRoleMiddleware(blockHandler.GetAllHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("PUT /api/user/{id}/block", requireUserRoleMiddleware(blockHandler.BlockHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("DELETE /api/user/{id}/block", requireUserRoleMiddleware(blockHandler.UnblockHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("GET /api/mutes", requireUserRoleMiddleware(blockHandler.GetMutesHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("PUT /api/user/{id}/mute", requireUserRoleMiddleware(blockHandler.MuteHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("DELETE /api/user/{id}/mute", requireUserRoleMiddleware(blockHandler.UnmuteHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+
+	mux.HandleFunc("GET /api/follows", requireUserRoleMiddleware(followHandler.GetAllHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("PUT /api/user/{id}/follow", requireUserRoleMiddleware(followHandler.FollowHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("DELETE /api/user/{id}/follow", requireUserRoleMiddleware(followHandler.UnfollowHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("GET /api/feed", requireUserRoleMiddleware(postHandler.FeedHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+
+	// Feeds are public so feed readers can poll them without a token, they only contain published posts
+	mux.HandleFunc("GET /feeds/posts.atom", basicMiddleware(syndicationHandler.PostsHandler, limiter, logger, cfg.Debug))
+	mux.HandleFunc("GET /feeds/posts.rss", basicMiddleware(syndicationHandler.PostsHandler, limiter, logger, cfg.Debug))
+	mux.HandleFunc("GET /feeds/board/{id}/{file}", basicMiddleware(syndicationHandler.BoardHandler, limiter, logger, cfg.Debug))
+	mux.HandleFunc("GET /feeds/user/{id}/{file}", basicMiddleware(syndicationHandler.UserHandler, limiter, logger, cfg.Debug))
+	mux.HandleFunc("GET /feeds/tag/{tag}/{file}", basicMiddleware(syndicationHandler.TagHandler, limiter, logger, cfg.Debug))
+	mux.HandleFunc("GET /feeds/post/{id}/{file}", basicMiddleware(syndicationHandler.CommentsHandler, limiter, logger, cfg.Debug))
+
+	mux.HandleFunc("GET /api/conversations", requireUserRoleMiddleware(messageHandler.GetConversationsHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("POST /api/conversations", requireUserRoleMiddleware(messageHandler.StartHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("GET /api/conversation/{id}/messages", requireUserRoleMiddleware(messageHandler.GetMessagesHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("POST /api/conversation/{id}/messages", requireUserRoleMiddleware(messageHandler.SendHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+
+	mux.HandleFunc("POST /api/post/{id}/report", requireUserRoleMiddleware(reportHandler.ReportPostHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("POST /api/comment/{id}/report", requireUserRoleMiddleware(reportHandler.ReportCommentHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("GET /api/moderation/reports", requireRoleMiddleware(reportHandler.QueueHandler, jwt.RoleModerator, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("POST /api/moderation/post/{id}/resolve", requireRoleMiddleware(reportHandler.ResolvePostHandler, jwt.RoleModerator, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("POST /api/moderation/comment/{id}/resolve", requireRoleMiddleware(reportHandler.ResolveCommentHandler, jwt.RoleModerator, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("GET /api/moderation/post/{id}/audit", requireRoleMiddleware(auditHandler.GetPostTrailHandler, jwt.RoleModerator, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("GET /api/admin/audit", requireRoleMiddleware(auditHandler.FindHandler, jwt.RoleAdmin, jwtMiddleware, limiter, logger, cfg.Debug))
+
+	mux.HandleFunc("POST /api/post/{id}/attachments", requireUserRoleMiddleware(attachmentHandler.UploadHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("GET /api/post/{id}/attachments", requireUserRoleMiddleware(attachmentHandler.GetByPostHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("GET /api/attachment/{id}", requireUserRoleMiddleware(attachmentHandler.DownloadHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("DELETE /api/attachment/{id}", requireUserRoleMiddleware(attachmentHandler.DeleteHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+
+	mux.HandleFunc("POST /api/post/{id}/poll", requireUserRoleMiddleware(pollHandler.CreateHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("GET /api/post/{id}/poll", requireUserRoleMiddleware(pollHandler.GetHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	mux.HandleFunc("POST /api/post/{id}/poll/vote", requireUserRoleMiddleware(pollHandler.VoteHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+
+	mux.HandleFunc("GET /api/stream", requireUserRoleMiddleware(eventHandler.StreamHandler, jwtMiddleware, limiter, logger, cfg.Debug))
+	// The live handler verifies the token itself, WebSocket clients in browsers cannot set the Authorization header
+	mux.HandleFunc("GET /api/post/{post_id}/live", basicMiddleware(liveHandler.LiveHandler, limiter, logger, cfg.Debug))
 
 	// handle interrupt signal
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
+	// fan out events published by any backend instance to the streams of this one
+	go eventService.Listen(ctx)
+	go eventService.RunRetention(ctx)
+	go liveHub.Run(ctx)
+	go limiter.Run(ctx)
+	go attachmentService.Run(ctx)
+	go postService.RunScheduler(ctx)
+	go postService.RunCacheInvalidation(ctx, eventService)
+
+	// the gateway for newsreaders, the terminal UI and the Gemini server are optional and share the services of the
+	// web API
+	if cfg.NNTP.Addr != "" {
+		var tlsConfig *tls.Config
+		if cfg.NNTP.CertPath != "" {
+			certificate, err := tls.LoadX509KeyPair(cfg.NNTP.CertPath, cfg.NNTP.KeyPath)
+			if err != nil {
+				logger.Fatal("Failed to load NNTP certificate", zap.String("path", cfg.NNTP.CertPath), zap.Error(err))
+			}
+			tlsConfig = &tls.Config{Certificates: []tls.Certificate{certificate}, MinVersion: tls.VersionTLS12}
+		}
+		nntpService := nntp.NewService(logger, dbPool, publicHostname(&cfg, cfg.NNTP.Domain), boardService, postService, commentService, userService, auditService)
+		go nntp.NewServer(logger, nntpService, limiter, cfg.NNTP.Addr, tlsConfig, cfg.NNTP.InsecureAuth).Run(ctx)
+	}
+	if cfg.SSH.Addr != "" {
+		hostKey, err := tui.LoadHostKey(cfg.SSH.HostKeyPath)
+		if err != nil {
+			logger.Fatal("Failed to load SSH host key", zap.String("path", cfg.SSH.HostKeyPath), zap.Error(err))
+		}
+		tuiService := tui.NewService(logger, sshKeyService, boardService, postService, commentService, userService, auditService, limiter)
+		go tui.NewServer(logger, tuiService, cfg.SSH.Addr, hostKey).Run(ctx)
+	}
+	if cfg.Gemini.Addr != "" {
+		hostname := publicHostname(&cfg, cfg.Gemini.Hostname)
+		certificate, err := gemini.LoadCertificate(cfg.Gemini.CertPath, cfg.Gemini.KeyPath, hostname)
+		if err != nil {
+			logger.Fatal("Failed to load Gemini certificate", zap.String("path", cfg.Gemini.CertPath), zap.Error(err))
+		}
+		geminiService := gemini.NewService(logger, clientCertService, boardService, postService, commentService, userService, limiter)
+		go gemini.NewServer(logger, geminiService, cfg.Gemini.Addr, hostname, certificate).Run(ctx)
+	}
+
 	srv := &http.Server{
 		Addr:    cfg.Host + ":" + cfg.Port,
 		Handler: mux,
 	}
+	srv.RegisterOnShutdown(eventService.CloseSubscribers)
 
 	go func() {
 		logger.Info("Starting listening request", zap.String("host", cfg.Host), zap.String("port", cfg.Port))
@@ -175,12 +375,32 @@ func main() {
 	logger.Info("Successfully shutdown")
 }
 
-func basicMiddleware(next http.HandlerFunc, logger *zap.Logger, debug bool) http.HandlerFunc {
-	return internal.TraceMiddleware(internal.RecoverMiddleware(next, logger, debug), logger)
+// basicMiddleware rate limits anonymous routes per client IP
+func basicMiddleware(next http.HandlerFunc, limiter *ratelimit.Limiter, logger *zap.Logger, debug bool) http.HandlerFunc {
+	return internal.TraceMiddleware(internal.RecoverMiddleware(limiter.Middleware(next, logger), logger, debug), logger)
 }
 
-func requireUserRoleMiddleware(next http.HandlerFunc, jwtMiddleware jwt.Middleware, logger *zap.Logger, debug bool) http.HandlerFunc {
-	return internal.TraceMiddleware(internal.RecoverMiddleware(jwtMiddleware.HandlerFunc(auth.Middleware(next, logger, "USER")), logger, debug), logger)
+func requireUserRoleMiddleware(next http.HandlerFunc, jwtMiddleware jwt.Middleware, limiter *ratelimit.Limiter, logger *zap.Logger, debug bool) http.HandlerFunc {
+	return requireRoleMiddleware(next, jwt.RoleUser, jwtMiddleware, limiter, logger, debug)
+}
+
+// requireRoleMiddleware only lets users with the role or a more privileged one through, see jwt.User.HasRole. Rate
+// limits apply per user after authentication.
+func requireRoleMiddleware(next http.HandlerFunc, role string, jwtMiddleware jwt.Middleware, limiter *ratelimit.Limiter, logger *zap.Logger, debug bool) http.HandlerFunc {
+	return internal.TraceMiddleware(internal.RecoverMiddleware(jwtMiddleware.HandlerFunc(auth.Middleware(limiter.Middleware(next, logger), logger, role)), logger, debug), logger)
+}
+
+// publicHostname returns the configured hostname, or the host of the public URL without one, or the listen host
+// without a public URL. It names the forum in the NNTP message ids and the Gemini URLs.
+func publicHostname(cfg *config.Config, hostname string) string {
+	if hostname != "" {
+		return hostname
+	}
+	publicURL, err := url.Parse(cfg.PublicURL)
+	if err == nil && publicURL.Hostname() != "" {
+		return publicURL.Hostname()
+	}
+	return cfg.Host
 }
 
 // initLogger create a new logger. If debug is enabled, it will create a development logger without metadata for better
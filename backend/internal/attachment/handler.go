@@ -0,0 +1,266 @@
+package attachment
+
+import (
+	"backend/internal"
+	errorPkg "backend/internal/error"
+	"backend/internal/jwt"
+	"backend/internal/problem"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// formField is the multipart field holding the uploaded file
+const formField = "file"
+
+const maxFilenameLength = 255
+
+type Response struct {
+	ID          string `json:"id"`
+	PostID      string `json:"post_id"`
+	UploaderID  string `json:"uploader_id"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	// URL downloads the file, it requires authentication like every other endpoint
+	URL       string `json:"url"`
+	CreatedAt string `json:"created_at"`
+}
+
+//go:generate mockery --name=Store
+type Store interface {
+	Upload(ctx context.Context, uploaderID, postID uuid.UUID, filename string, r io.Reader) (Attachment, error)
+	GetByID(ctx context.Context, viewerID, id uuid.UUID) (Attachment, error)
+	GetByPost(ctx context.Context, viewerID, postID uuid.UUID) ([]Attachment, error)
+	Open(ctx context.Context, viewerID, id uuid.UUID) (Attachment, io.ReadCloser, error)
+	Delete(ctx context.Context, viewerID, id uuid.UUID) error
+}
+
+type Handler struct {
+	logger *zap.Logger
+	tracer trace.Tracer
+	store  Store
+}
+
+func NewHandler(logger *zap.Logger, store Store) *Handler {
+	return &Handler{
+		logger: logger,
+		tracer: otel.Tracer("attachment/handler"),
+		store:  store,
+	}
+}
+
+// UploadHandler attaches the file in the multipart field "file" to a post of the current user. The file is streamed
+// to the storage without buffering the request.
+func (h *Handler) UploadHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "UploadAttachmentEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	postID, err := internal.ParseUUID(r.PathValue("id"))
+	if err != nil {
+		problem.WriteError(traceCtx, w, fmt.Errorf("%w: %v", errorPkg.ErrInvalidUUID, err), logger)
+		return
+	}
+
+	uploaderID, err := jwt.GetUserIDFromContext(traceCtx)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		problem.WriteError(traceCtx, w, fmt.Errorf("%w: expected a multipart/form-data body", errorPkg.ErrInvalidRequest), logger)
+		return
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			problem.WriteError(traceCtx, w, fmt.Errorf("%w: missing the %q field", errorPkg.ErrInvalidRequest, formField), logger)
+			return
+		}
+		if err != nil {
+			problem.WriteError(traceCtx, w, fmt.Errorf("%w: malformed multipart body: %v", errorPkg.ErrInvalidRequest, err), logger)
+			return
+		}
+		if part.FormName() != formField {
+			part.Close()
+			continue
+		}
+
+		attachment, err := h.store.Upload(traceCtx, uploaderID, postID, sanitizeFilename(part.FileName()), part)
+		part.Close()
+		if err != nil {
+			problem.WriteError(traceCtx, w, err, logger)
+			return
+		}
+
+		internal.WriteJSONResponse(w, http.StatusCreated, GenerateResponse(attachment))
+		return
+	}
+}
+
+func (h *Handler) GetByPostHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "GetAttachmentsByPostEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	postID, err := internal.ParseUUID(r.PathValue("id"))
+	if err != nil {
+		problem.WriteError(traceCtx, w, fmt.Errorf("%w: %v", errorPkg.ErrInvalidUUID, err), logger)
+		return
+	}
+
+	viewerID, err := jwt.GetUserIDFromContext(traceCtx)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	attachments, err := h.store.GetByPost(traceCtx, viewerID, postID)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	response := make([]Response, len(attachments))
+	for i, attachment := range attachments {
+		response[i] = GenerateResponse(attachment)
+	}
+
+	internal.WriteJSONResponse(w, http.StatusOK, response)
+}
+
+// DownloadHandler serves the file as a download, browsers neither sniff nor render it
+func (h *Handler) DownloadHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "DownloadAttachmentEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	id, err := internal.ParseUUID(r.PathValue("id"))
+	if err != nil {
+		problem.WriteError(traceCtx, w, fmt.Errorf("%w: %v", errorPkg.ErrInvalidUUID, err), logger)
+		return
+	}
+
+	viewerID, err := jwt.GetUserIDFromContext(traceCtx)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	attachment, content, err := h.store.Open(traceCtx, viewerID, id)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; sandbox")
+	w.Header().Set("Cache-Control", "private, max-age=3600")
+	w.WriteHeader(http.StatusOK)
+
+	_, err = io.Copy(w, content)
+	if err != nil {
+		// the status is already sent, the client sees a truncated body
+		logger.Warn("Failed to send attachment", zap.String("id", id.String()), zap.Error(err))
+	}
+}
+
+// DeleteHandler removes an attachment, allowed for the uploader and moderators
+func (h *Handler) DeleteHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "DeleteAttachmentEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	id, err := internal.ParseUUID(r.PathValue("id"))
+	if err != nil {
+		problem.WriteError(traceCtx, w, fmt.Errorf("%w: %v", errorPkg.ErrInvalidUUID, err), logger)
+		return
+	}
+
+	user, err := jwt.GetUserFromContext(traceCtx)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	userID, err := internal.ParseUUID(user.ID)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	attachment, err := h.store.GetByID(traceCtx, userID, id)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	if attachment.UploaderID.String() != user.ID && !user.HasRole(jwt.RoleModerator) {
+		problem.WriteError(traceCtx, w, errorPkg.ErrForbidden, logger)
+		return
+	}
+
+	err = h.store.Delete(traceCtx, userID, id)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// sanitizeFilename keeps the base name of the client supplied filename without control characters
+func sanitizeFilename(name string) string {
+	name = path.Base(strings.ReplaceAll(name, `\`, "/"))
+	name = strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name))
+
+	for len(name) > maxFilenameLength {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+
+	return name
+}
+
+func GenerateResponse(attachment Attachment) Response {
+	return Response{
+		ID:          attachment.ID.String(),
+		PostID:      uuid.UUID(attachment.PostID.Bytes).String(),
+		UploaderID:  attachment.UploaderID.String(),
+		Filename:    attachment.Filename,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		URL:         "/api/attachment/" + attachment.ID.String(),
+		CreatedAt:   attachment.CreatedAt.Time.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,241 @@
+package attachment_test
+
+import (
+	"backend/internal"
+	"backend/internal/attachment"
+	"backend/internal/attachment/mocks"
+	errorPkg "backend/internal/error"
+	"backend/internal/jwt"
+	"bytes"
+	"context"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	author = jwt.User{
+		ID:       "81c1ecc1-66d7-4134-b5fe-d886a6f418a4",
+		Username: "author",
+		Role:     jwt.RoleUser,
+	}
+	postID = uuid.MustParse("1f0b3c8e-3b0a-4d8e-9f3e-9b1b0f6b7c11")
+)
+
+func testAttachment() attachment.Attachment {
+	return attachment.Attachment{
+		ID:          uuid.MustParse("2a7d1b7e-6c53-4f6b-8d9e-0c1b2a3d4e5f"),
+		PostID:      pgtype.UUID{Bytes: postID, Valid: true},
+		UploaderID:  uuid.MustParse(author.ID),
+		Filename:    "notes.txt",
+		ContentType: "text/plain; charset=utf-8",
+		Size:        5,
+		StorageKey:  postID.String() + "/2a7d1b7e-6c53-4f6b-8d9e-0c1b2a3d4e5f",
+		CreatedAt:   pgtype.Timestamptz{Time: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), Valid: true},
+	}
+}
+
+func multipartBody(t *testing.T, field, filename, content string) (*bytes.Buffer, string) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile(field, filename)
+	if err != nil {
+		t.Fatalf("failed to create form file: %v", err)
+	}
+	_, _ = part.Write([]byte(content))
+	_ = writer.Close()
+
+	return body, writer.FormDataContentType()
+}
+
+func TestHandler_UploadHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		field       string
+		filename    string
+		contentType string
+		setupMock   func(m *mocks.Store)
+		wantStatus  int
+	}{
+		{
+			name:     "Should upload file",
+			field:    "file",
+			filename: "notes.txt",
+			setupMock: func(m *mocks.Store) {
+				m.On("Upload", mock.Anything, uuid.MustParse(author.ID), postID, "notes.txt", mock.Anything).
+					Return(testAttachment(), nil)
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:     "Should strip directories from filename",
+			field:    "file",
+			filename: `..\..\etc/passwd`,
+			setupMock: func(m *mocks.Store) {
+				m.On("Upload", mock.Anything, uuid.MustParse(author.ID), postID, "passwd", mock.Anything).
+					Return(testAttachment(), nil)
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "Should reject body without file field",
+			field:      "upload",
+			filename:   "notes.txt",
+			setupMock:  func(m *mocks.Store) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "Should reject body that is not multipart",
+			contentType: "application/json",
+			setupMock:   func(m *mocks.Store) {},
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:     "Should reject too large file",
+			field:    "file",
+			filename: "big.txt",
+			setupMock: func(m *mocks.Store) {
+				m.On("Upload", mock.Anything, uuid.MustParse(author.ID), postID, "big.txt", mock.Anything).
+					Return(attachment.Attachment{}, errorPkg.ErrFileTooLarge)
+			},
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:     "Should reject unsupported type",
+			field:    "file",
+			filename: "run.exe",
+			setupMock: func(m *mocks.Store) {
+				m.On("Upload", mock.Anything, uuid.MustParse(author.ID), postID, "run.exe", mock.Anything).
+					Return(attachment.Attachment{}, errorPkg.ErrUnsupportedType)
+			},
+			wantStatus: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:     "Should forbid upload to post of another user",
+			field:    "file",
+			filename: "notes.txt",
+			setupMock: func(m *mocks.Store) {
+				m.On("Upload", mock.Anything, uuid.MustParse(author.ID), postID, "notes.txt", mock.Anything).
+					Return(attachment.Attachment{}, errorPkg.ErrForbidden)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mocks.NewStore(t)
+			tt.setupMock(m)
+
+			var body io.Reader = strings.NewReader("{}")
+			contentType := tt.contentType
+			if contentType == "" {
+				body, contentType = multipartBody(t, tt.field, tt.filename, "hello")
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/api/post/"+postID.String()+"/attachments", body)
+			r.Header.Set("Content-Type", contentType)
+			r.SetPathValue("id", postID.String())
+			r = r.WithContext(context.WithValue(r.Context(), internal.UserContextKey, author))
+
+			h := attachment.NewHandler(zap.NewNop(), m)
+			h.UploadHandler(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestHandler_DownloadHandler(t *testing.T) {
+	m := mocks.NewStore(t)
+	a := testAttachment()
+	m.On("Open", mock.Anything, uuid.MustParse(author.ID), a.ID).Return(a, io.NopCloser(strings.NewReader("hello")), nil)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/api/attachment/"+a.ID.String(), nil)
+	r.SetPathValue("id", a.ID.String())
+	r = r.WithContext(context.WithValue(r.Context(), internal.UserContextKey, author))
+
+	h := attachment.NewHandler(zap.NewNop(), m)
+	h.DownloadHandler(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "hello", w.Body.String())
+	assert.Equal(t, a.ContentType, w.Header().Get("Content-Type"))
+	assert.Equal(t, "5", w.Header().Get("Content-Length"))
+	assert.Equal(t, `attachment; filename=notes.txt`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+}
+
+func TestHandler_DeleteHandler(t *testing.T) {
+	a := testAttachment()
+
+	tests := []struct {
+		name       string
+		user       jwt.User
+		setupMock  func(m *mocks.Store, viewerID uuid.UUID)
+		wantStatus int
+	}{
+		{
+			name: "Should delete own attachment",
+			user: author,
+			setupMock: func(m *mocks.Store, viewerID uuid.UUID) {
+				m.On("GetByID", mock.Anything, viewerID, a.ID).Return(a, nil)
+				m.On("Delete", mock.Anything, viewerID, a.ID).Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name: "Should let moderator delete attachment",
+			user: jwt.User{ID: uuid.NewString(), Username: "mod", Role: jwt.RoleModerator},
+			setupMock: func(m *mocks.Store, viewerID uuid.UUID) {
+				m.On("GetByID", mock.Anything, viewerID, a.ID).Return(a, nil)
+				m.On("Delete", mock.Anything, viewerID, a.ID).Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name: "Should forbid deleting attachment of another user",
+			user: jwt.User{ID: uuid.NewString(), Username: "other", Role: jwt.RoleUser},
+			setupMock: func(m *mocks.Store, viewerID uuid.UUID) {
+				m.On("GetByID", mock.Anything, viewerID, a.ID).Return(a, nil)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name: "Should return not found for unknown attachment",
+			user: author,
+			setupMock: func(m *mocks.Store, viewerID uuid.UUID) {
+				m.On("GetByID", mock.Anything, viewerID, a.ID).Return(attachment.Attachment{}, errorPkg.NewNotFoundError("attachment", "id", a.ID.String(), ""))
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mocks.NewStore(t)
+			tt.setupMock(m, uuid.MustParse(tt.user.ID))
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodDelete, "/api/attachment/"+a.ID.String(), nil)
+			r.SetPathValue("id", a.ID.String())
+			r = r.WithContext(context.WithValue(r.Context(), internal.UserContextKey, tt.user))
+
+			h := attachment.NewHandler(zap.NewNop(), m)
+			h.DeleteHandler(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
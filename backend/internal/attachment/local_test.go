@@ -0,0 +1,92 @@
+package attachment_test
+
+import (
+	"backend/internal/attachment"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// failingReader returns its content and then fails, like an aborted upload
+type failingReader struct {
+	r io.Reader
+}
+
+func (f failingReader) Read(p []byte) (int, error) {
+	n, err := f.r.Read(p)
+	if errors.Is(err, io.EOF) {
+		return n, errors.New("connection reset")
+	}
+	return n, err
+}
+
+func TestLocalStorage(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+
+	storage, err := attachment.NewLocalStorage(root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("Should store and read back content", func(t *testing.T) {
+		err := storage.Put(ctx, "post/file", strings.NewReader("hello"), -1, "text/plain")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		content, err := storage.Get(ctx, "post/file")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer content.Close()
+
+		data, err := io.ReadAll(content)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		assert.Equal(t, "hello", string(data))
+	})
+
+	t.Run("Should leave nothing behind when the upload fails", func(t *testing.T) {
+		err := storage.Put(ctx, "post/partial", failingReader{strings.NewReader("hel")}, -1, "text/plain")
+		assert.Error(t, err)
+
+		_, err = storage.Get(ctx, "post/partial")
+		assert.ErrorIs(t, err, attachment.ErrObjectNotFound)
+
+		entries, err := os.ReadDir(filepath.Join(root, "post"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, entry := range entries {
+			assert.False(t, strings.HasPrefix(entry.Name(), ".upload-"), "temporary file %s left behind", entry.Name())
+		}
+	})
+
+	t.Run("Should delete content", func(t *testing.T) {
+		err := storage.Put(ctx, "post/deleted", strings.NewReader("bye"), -1, "text/plain")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		assert.NoError(t, storage.Delete(ctx, "post/deleted"))
+		assert.NoError(t, storage.Delete(ctx, "post/deleted"), "deleting a missing key is not an error")
+
+		_, err = storage.Get(ctx, "post/deleted")
+		assert.ErrorIs(t, err, attachment.ErrObjectNotFound)
+	})
+
+	t.Run("Should reject keys outside the root", func(t *testing.T) {
+		err := storage.Put(ctx, "../escape", strings.NewReader("x"), -1, "text/plain")
+		assert.Error(t, err)
+
+		_, err = os.Stat(filepath.Join(filepath.Dir(root), "escape"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
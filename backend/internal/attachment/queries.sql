@@ -0,0 +1,23 @@
+-- name: Create :one
+INSERT INTO attachments (id, post_id, uploader_id, filename, content_type, size, storage_key)
+VALUES (@id, @post_id::uuid, @uploader_id, @filename, @content_type, @size, @storage_key)
+RETURNING *;
+
+-- name: FindByID :one
+-- Attachments of hidden or deleted posts are not found, attachments of drafts and scheduled posts only for their author
+SELECT attachments.* FROM attachments
+JOIN posts ON posts.id = attachments.post_id
+WHERE attachments.id = @id AND posts.hidden_at IS NULL
+  AND (posts.status = 'published' OR posts.author_id = @viewer_id);
+
+-- name: FindByPost :many
+SELECT * FROM attachments WHERE post_id = @post_id::uuid ORDER BY created_at;
+
+-- name: FindPost :one
+SELECT id, author_id, archived_at, status FROM posts WHERE id = $1 AND hidden_at IS NULL;
+
+-- name: Delete :execrows
+DELETE FROM attachments WHERE id = $1;
+
+-- name: FindOrphaned :many
+SELECT * FROM attachments WHERE post_id IS NULL LIMIT @size;
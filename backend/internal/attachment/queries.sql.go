@@ -0,0 +1,183 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: queries.sql
+
+package attachment
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const create = `-- name: Create :one
+INSERT INTO attachments (id, post_id, uploader_id, filename, content_type, size, storage_key)
+VALUES ($1, $2::uuid, $3, $4, $5, $6, $7)
+RETURNING id, post_id, uploader_id, filename, content_type, size, storage_key, created_at
+`
+
+type CreateParams struct {
+	ID          uuid.UUID
+	PostID      uuid.UUID
+	UploaderID  uuid.UUID
+	Filename    string
+	ContentType string
+	Size        int64
+	StorageKey  string
+}
+
+func (q *Queries) Create(ctx context.Context, arg CreateParams) (Attachment, error) {
+	row := q.db.QueryRow(ctx, create,
+		arg.ID,
+		arg.PostID,
+		arg.UploaderID,
+		arg.Filename,
+		arg.ContentType,
+		arg.Size,
+		arg.StorageKey,
+	)
+	var i Attachment
+	err := row.Scan(
+		&i.ID,
+		&i.PostID,
+		&i.UploaderID,
+		&i.Filename,
+		&i.ContentType,
+		&i.Size,
+		&i.StorageKey,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const delete = `-- name: Delete :execrows
+DELETE FROM attachments WHERE id = $1
+`
+
+func (q *Queries) Delete(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, delete, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const findByID = `-- name: FindByID :one
+SELECT attachments.id, attachments.post_id, attachments.uploader_id, attachments.filename, attachments.content_type, attachments.size, attachments.storage_key, attachments.created_at FROM attachments
+JOIN posts ON posts.id = attachments.post_id
+WHERE attachments.id = $1 AND posts.hidden_at IS NULL
+  AND (posts.status = 'published' OR posts.author_id = $2)
+`
+
+type FindByIDParams struct {
+	ID       uuid.UUID
+	ViewerID uuid.UUID
+}
+
+// Attachments of hidden or deleted posts are not found, attachments of drafts and scheduled posts only for their author
+func (q *Queries) FindByID(ctx context.Context, arg FindByIDParams) (Attachment, error) {
+	row := q.db.QueryRow(ctx, findByID, arg.ID, arg.ViewerID)
+	var i Attachment
+	err := row.Scan(
+		&i.ID,
+		&i.PostID,
+		&i.UploaderID,
+		&i.Filename,
+		&i.ContentType,
+		&i.Size,
+		&i.StorageKey,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const findByPost = `-- name: FindByPost :many
+SELECT id, post_id, uploader_id, filename, content_type, size, storage_key, created_at FROM attachments WHERE post_id = $1::uuid ORDER BY created_at
+`
+
+func (q *Queries) FindByPost(ctx context.Context, postID uuid.UUID) ([]Attachment, error) {
+	rows, err := q.db.Query(ctx, findByPost, postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Attachment
+	for rows.Next() {
+		var i Attachment
+		if err := rows.Scan(
+			&i.ID,
+			&i.PostID,
+			&i.UploaderID,
+			&i.Filename,
+			&i.ContentType,
+			&i.Size,
+			&i.StorageKey,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findOrphaned = `-- name: FindOrphaned :many
+SELECT id, post_id, uploader_id, filename, content_type, size, storage_key, created_at FROM attachments WHERE post_id IS NULL LIMIT $1
+`
+
+func (q *Queries) FindOrphaned(ctx context.Context, size int32) ([]Attachment, error) {
+	rows, err := q.db.Query(ctx, findOrphaned, size)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Attachment
+	for rows.Next() {
+		var i Attachment
+		if err := rows.Scan(
+			&i.ID,
+			&i.PostID,
+			&i.UploaderID,
+			&i.Filename,
+			&i.ContentType,
+			&i.Size,
+			&i.StorageKey,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findPost = `-- name: FindPost :one
+SELECT id, author_id, archived_at, status FROM posts WHERE id = $1 AND hidden_at IS NULL
+`
+
+type FindPostRow struct {
+	ID         uuid.UUID
+	AuthorID   uuid.UUID
+	ArchivedAt pgtype.Timestamptz
+	Status     string
+}
+
+func (q *Queries) FindPost(ctx context.Context, id uuid.UUID) (FindPostRow, error) {
+	row := q.db.QueryRow(ctx, findPost, id)
+	var i FindPostRow
+	err := row.Scan(
+		&i.ID,
+		&i.AuthorID,
+		&i.ArchivedAt,
+		&i.Status,
+	)
+	return i, err
+}
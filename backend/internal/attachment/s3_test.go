@@ -0,0 +1,67 @@
+package attachment_test
+
+import (
+	"backend/internal/attachment"
+	"backend/internal/config"
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestS3Storage runs against an S3-compatible server such as a local MinIO:
+//
+//	docker run -d -p 9000:9000 minio/minio server /data
+//	ATTACHMENT_S3_ENDPOINT=localhost:9000 go test ./internal/attachment
+func TestS3Storage(t *testing.T) {
+	endpoint := os.Getenv("ATTACHMENT_S3_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("ATTACHMENT_S3_ENDPOINT is not set")
+	}
+
+	cfg := config.S3Config{
+		Endpoint:  endpoint,
+		Bucket:    "attachments-test",
+		AccessKey: envOr("ATTACHMENT_S3_ACCESS_KEY", "minioadmin"),
+		SecretKey: envOr("ATTACHMENT_S3_SECRET_KEY", "minioadmin"),
+	}
+
+	ctx := context.Background()
+	storage, err := attachment.NewS3Storage(ctx, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	key := "test/" + uuid.NewString()
+	err = storage.Put(ctx, key, strings.NewReader("hello"), -1, "text/plain")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	content, err := storage.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := io.ReadAll(content)
+	content.Close()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, "hello", string(data))
+
+	if err := storage.Delete(ctx, key); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = storage.Get(ctx, key)
+	assert.ErrorIs(t, err, attachment.ErrObjectNotFound)
+}
+
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS attachments (
+    id UUID PRIMARY KEY,
+    -- post_id is NULL once the post is deleted, the file is removed from storage by the next prune
+    post_id UUID REFERENCES posts(id) ON DELETE SET NULL,
+    uploader_id UUID REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(127) NOT NULL,
+    size BIGINT NOT NULL,
+    storage_key TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ DEFAULT now() NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS attachments_post_idx ON attachments (post_id, created_at);
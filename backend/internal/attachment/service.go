@@ -0,0 +1,311 @@
+package attachment
+
+import (
+	"backend/internal"
+	"backend/internal/config"
+	"backend/internal/database"
+	errorPkg "backend/internal/error"
+	postPkg "backend/internal/post"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"time"
+)
+
+const (
+	// sniffSize is the amount of content http.DetectContentType considers
+	sniffSize = 512
+	// pruneInterval is how often files of deleted posts are removed from the storage
+	pruneInterval = time.Hour
+	pruneBatch    = 100
+)
+
+type Service struct {
+	logger  *zap.Logger
+	tracer  trace.Tracer
+	query   *Queries
+	storage Storage
+
+	maxSize      int64
+	allowedTypes []string
+}
+
+func NewService(logger *zap.Logger, db DBTX, storage Storage, cfg config.AttachmentConfig) *Service {
+	return &Service{
+		logger:       logger,
+		tracer:       otel.Tracer("attachment/service"),
+		query:        New(db),
+		storage:      storage,
+		maxSize:      cfg.MaxSize,
+		allowedTypes: cfg.AllowedTypes,
+	}
+}
+
+// Upload stores a file attached to a post of the uploader. The media type is detected from the content and must be
+// one of the allowed types, the declared type of the upload is ignored.
+func (s *Service) Upload(ctx context.Context, uploaderID, postID uuid.UUID, filename string, r io.Reader) (Attachment, error) {
+	traceCtx, span := s.tracer.Start(ctx, "Upload")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	post, err := s.query.FindPost(traceCtx, postID)
+	if err != nil {
+		err = database.WrapDBErrorWithKeyValue(err, "post", "id", postID.String(), logger, "get post of attachment")
+		span.RecordError(err)
+		return Attachment{}, err
+	}
+	if post.AuthorID != uploaderID {
+		err = errorPkg.ErrForbidden
+		span.RecordError(err)
+		return Attachment{}, err
+	}
+	if post.ArchivedAt.Valid {
+		err = errorPkg.ErrPostArchived
+		span.RecordError(err)
+		return Attachment{}, err
+	}
+
+	limited := &limitReader{r: r, limit: s.maxSize}
+	contentType, content, err := s.detect(limited)
+	if err != nil {
+		span.RecordError(err)
+		return Attachment{}, err
+	}
+
+	id := uuid.New()
+	key := postID.String() + "/" + id.String()
+
+	err = s.storage.Put(traceCtx, key, content, -1, contentType)
+	if err != nil {
+		if limited.exceeded() {
+			err = fmt.Errorf("%w: attachments are limited to %d bytes", errorPkg.ErrFileTooLarge, s.maxSize)
+		} else {
+			logger.Error("Failed to store attachment", zap.String("key", key), zap.Error(err))
+			err = errorPkg.ErrInternalServer
+		}
+		span.RecordError(err)
+		return Attachment{}, err
+	}
+
+	attachment, err := s.query.Create(traceCtx, CreateParams{
+		ID:          id,
+		PostID:      postID,
+		UploaderID:  uploaderID,
+		Filename:    filename,
+		ContentType: contentType,
+		Size:        limited.n,
+		StorageKey:  key,
+	})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "create attachment")
+		span.RecordError(err)
+		s.deleteObject(traceCtx, key)
+		return Attachment{}, err
+	}
+
+	logger.Info("Uploaded attachment", zap.String("id", id.String()), zap.String("post_id", postID.String()),
+		zap.String("content_type", contentType), zap.Int64("size", attachment.Size))
+	return attachment, nil
+}
+
+// GetByID returns the metadata of an attachment, attachments of hidden posts are not found and attachments of
+// unpublished posts only for their author
+func (s *Service) GetByID(ctx context.Context, viewerID, id uuid.UUID) (Attachment, error) {
+	traceCtx, span := s.tracer.Start(ctx, "GetByID")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	attachment, err := s.query.FindByID(traceCtx, FindByIDParams{ID: id, ViewerID: viewerID})
+	if err != nil {
+		err = database.WrapDBErrorWithKeyValue(err, "attachment", "id", id.String(), logger, "get attachment by id")
+		span.RecordError(err)
+		return Attachment{}, err
+	}
+
+	return attachment, nil
+}
+
+// GetByPost lists the attachments of a post in upload order, drafts only list them for their author
+func (s *Service) GetByPost(ctx context.Context, viewerID, postID uuid.UUID) ([]Attachment, error) {
+	traceCtx, span := s.tracer.Start(ctx, "GetByPost")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	post, err := s.query.FindPost(traceCtx, postID)
+	if err != nil {
+		err = database.WrapDBErrorWithKeyValue(err, "post", "id", postID.String(), logger, "get post of attachments")
+		span.RecordError(err)
+		return nil, err
+	}
+	if post.Status != postPkg.StatusPublished && post.AuthorID != viewerID {
+		err = errorPkg.NewNotFoundError("post", "id", postID.String(), "")
+		span.RecordError(err)
+		return nil, err
+	}
+
+	attachments, err := s.query.FindByPost(traceCtx, postID)
+	if err != nil {
+		err = database.WrapDBError(err, logger, "get attachments by post")
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+// Open returns the metadata and the content of an attachment, the caller closes the content
+func (s *Service) Open(ctx context.Context, viewerID, id uuid.UUID) (Attachment, io.ReadCloser, error) {
+	traceCtx, span := s.tracer.Start(ctx, "Open")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	attachment, err := s.GetByID(traceCtx, viewerID, id)
+	if err != nil {
+		return Attachment{}, nil, err
+	}
+
+	content, err := s.storage.Get(traceCtx, attachment.StorageKey)
+	if err != nil {
+		if errors.Is(err, ErrObjectNotFound) {
+			logger.Error("Attachment is missing in storage", zap.String("id", id.String()), zap.String("key", attachment.StorageKey))
+			err = errorPkg.NewNotFoundError("attachment", "id", id.String(), "")
+		} else {
+			logger.Error("Failed to read attachment", zap.String("key", attachment.StorageKey), zap.Error(err))
+			err = errorPkg.ErrInternalServer
+		}
+		span.RecordError(err)
+		return Attachment{}, nil, err
+	}
+
+	return attachment, content, nil
+}
+
+// Delete removes the metadata and the content of an attachment
+func (s *Service) Delete(ctx context.Context, viewerID, id uuid.UUID) error {
+	traceCtx, span := s.tracer.Start(ctx, "Delete")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	attachment, err := s.GetByID(traceCtx, viewerID, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.query.Delete(traceCtx, id)
+	if err != nil {
+		err = database.WrapDBErrorWithKeyValue(err, "attachment", "id", id.String(), logger, "delete attachment")
+		span.RecordError(err)
+		return err
+	}
+
+	s.deleteObject(traceCtx, attachment.StorageKey)
+	logger.Info("Deleted attachment", zap.String("id", id.String()))
+	return nil
+}
+
+// Run removes the files of deleted posts from the storage until the context is done
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.prune(ctx)
+		}
+	}
+}
+
+func (s *Service) prune(ctx context.Context) {
+	traceCtx, span := s.tracer.Start(ctx, "Prune")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	orphaned, err := s.query.FindOrphaned(traceCtx, pruneBatch)
+	if err != nil {
+		err = database.WrapDBError(err, logger, "get orphaned attachments")
+		span.RecordError(err)
+		return
+	}
+
+	for _, attachment := range orphaned {
+		err = s.storage.Delete(traceCtx, attachment.StorageKey)
+		if err != nil {
+			// keep the row, the next prune retries
+			logger.Warn("Failed to delete orphaned attachment", zap.String("key", attachment.StorageKey), zap.Error(err))
+			continue
+		}
+
+		_, err = s.query.Delete(traceCtx, attachment.ID)
+		if err != nil {
+			logger.Warn("Failed to delete orphaned attachment metadata", zap.String("id", attachment.ID.String()), zap.Error(err))
+		}
+	}
+
+	if len(orphaned) > 0 {
+		logger.Info("Pruned attachments of deleted posts", zap.Int("count", len(orphaned)))
+	}
+}
+
+// detect sniffs the media type from the start of the content and returns a reader over the whole content
+func (s *Service) detect(r io.Reader) (string, io.Reader, error) {
+	head := make([]byte, sniffSize)
+	n, err := io.ReadFull(r, head)
+	if errors.Is(err, errorPkg.ErrFileTooLarge) {
+		return "", nil, err
+	}
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return "", nil, fmt.Errorf("%w: failed to read upload: %v", errorPkg.ErrInvalidRequest, err)
+	}
+	if n == 0 {
+		return "", nil, fmt.Errorf("%w: the file is empty", errorPkg.ErrInvalidRequest)
+	}
+	head = head[:n]
+
+	contentType := http.DetectContentType(head)
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || !slices.Contains(s.allowedTypes, mediaType) {
+		return "", nil, fmt.Errorf("%w: %s is not allowed", errorPkg.ErrUnsupportedType, mediaType)
+	}
+
+	return contentType, io.MultiReader(bytes.NewReader(head), r), nil
+}
+
+func (s *Service) deleteObject(ctx context.Context, key string) {
+	err := s.storage.Delete(ctx, key)
+	if err != nil {
+		internal.LoggerWithContext(ctx, s.logger).Warn("Failed to delete attachment from storage", zap.String("key", key), zap.Error(err))
+	}
+}
+
+// limitReader fails with errorPkg.ErrFileTooLarge once more than limit bytes were read
+type limitReader struct {
+	r     io.Reader
+	limit int64
+	n     int64
+}
+
+func (l *limitReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.n += int64(n)
+	if l.exceeded() {
+		return n, errorPkg.ErrFileTooLarge
+	}
+
+	return n, err
+}
+
+func (l *limitReader) exceeded() bool {
+	return l.limit > 0 && l.n > l.limit
+}
@@ -0,0 +1,82 @@
+package attachment_test
+
+import (
+	"backend/internal/attachment"
+	"backend/internal/config"
+	"backend/internal/database/databasetest"
+	errorPkg "backend/internal/error"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"testing"
+)
+
+func TestService_GetByID(t *testing.T) {
+	pool := databasetest.Open(t)
+	s := attachment.NewService(zap.NewNop(), pool, nil, config.AttachmentConfig{})
+	ctx := context.Background()
+
+	owner := databasetest.CreateUser(t, pool)
+	reader := databasetest.CreateUser(t, pool)
+
+	tests := []struct {
+		name    string
+		status  string
+		hidden  bool
+		viewer  uuid.UUID
+		wantErr error
+	}{
+		{
+			name:   "Should return attachment of published post",
+			status: "published",
+			viewer: reader,
+		},
+		{
+			name:   "Should return attachment of draft to its author",
+			status: "draft",
+			viewer: owner,
+		},
+		{
+			name:    "Should hide attachment of draft from other users",
+			status:  "draft",
+			viewer:  reader,
+			wantErr: errorPkg.ErrNotFound,
+		},
+		{
+			name:    "Should hide attachment of scheduled post from other users",
+			status:  "scheduled",
+			viewer:  reader,
+			wantErr: errorPkg.ErrNotFound,
+		},
+		{
+			name:    "Should hide attachment of hidden post",
+			status:  "published",
+			hidden:  true,
+			viewer:  owner,
+			wantErr: errorPkg.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			postID := databasetest.CreatePost(t, pool, owner)
+			_, err := pool.Exec(ctx, "UPDATE posts SET status = $2, publish_at = CASE WHEN $2 = 'scheduled' THEN now() + interval '1 day' END, hidden_at = CASE WHEN $3 THEN now() END WHERE id = $1",
+				postID, tt.status, tt.hidden)
+			if err != nil {
+				t.Fatalf("failed to update post: %v", err)
+			}
+			id := uuid.New()
+			_, err = pool.Exec(ctx, "INSERT INTO attachments (id, post_id, uploader_id, filename, content_type, size, storage_key) VALUES ($1, $2, $3, 'notes.txt', 'text/plain', 5, $4)",
+				id, postID, owner, postID.String()+"/"+id.String())
+			if err != nil {
+				t.Fatalf("failed to create attachment: %v", err)
+			}
+
+			_, err = s.GetByID(ctx, tt.viewer, id)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetByID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
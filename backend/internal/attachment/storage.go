@@ -0,0 +1,34 @@
+package attachment
+
+import (
+	"backend/internal/config"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrObjectNotFound is returned by storages for keys they do not hold
+var ErrObjectNotFound = errors.New("object not found")
+
+// Storage keeps the content of attachments, the metadata lives in Postgres
+type Storage interface {
+	// Put stores the content read from r under key, size is -1 when unknown. No object is left behind when reading
+	// r fails.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object, deleting a missing key is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// NewStorage creates the storage selected in the config
+func NewStorage(ctx context.Context, cfg config.AttachmentConfig) (Storage, error) {
+	switch cfg.Storage {
+	case "", "local":
+		return NewLocalStorage(cfg.LocalPath)
+	case "s3":
+		return NewS3Storage(ctx, cfg.S3)
+	default:
+		return nil, fmt.Errorf("unknown attachment storage %q", cfg.Storage)
+	}
+}
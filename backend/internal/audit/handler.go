@@ -0,0 +1,160 @@
+package audit
+
+import (
+	"backend/internal"
+	errorPkg "backend/internal/error"
+	"backend/internal/problem"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+type Response struct {
+	ID         int64  `json:"id"`
+	ActorID    string `json:"actor_id,omitempty"`
+	Action     string `json:"action"`
+	TargetType string `json:"target_type"`
+	TargetID   string `json:"target_id,omitempty"`
+	Details    string `json:"details,omitempty"`
+	IPAddress  string `json:"ip_address,omitempty"`
+	TraceID    string `json:"trace_id,omitempty"`
+	CreatedAt  string `json:"created_at"`
+}
+
+//go:generate mockery --name=Store
+type Store interface {
+	GetByTarget(ctx context.Context, targetType string, targetID uuid.UUID) ([]AuditLog, error)
+	Find(ctx context.Context, filter Filter, pagination internal.Pagination) ([]AuditLog, error)
+}
+
+type Handler struct {
+	logger *zap.Logger
+	tracer trace.Tracer
+	store  Store
+}
+
+func NewHandler(logger *zap.Logger, store Store) *Handler {
+	return &Handler{
+		logger: logger,
+		tracer: otel.Tracer("audit/handler"),
+		store:  store,
+	}
+}
+
+// GetPostTrailHandler lists the moderation actions taken on a post, oldest first
+func (h *Handler) GetPostTrailHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "GetPostTrailEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	postID, err := internal.ParseUUID(r.PathValue("id"))
+	if err != nil {
+		problem.WriteError(traceCtx, w, fmt.Errorf("%w: %v", errorPkg.ErrInvalidUUID, err), logger)
+		return
+	}
+
+	entries, err := h.store.GetByTarget(traceCtx, TargetPost, postID)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	internal.WriteJSONResponse(w, http.StatusOK, generateResponses(entries))
+}
+
+// FindHandler lists the audit log newest first, filtered by the actor_id, action, target_type, target_id,
+// ip_address, since and until query parameters
+func (h *Handler) FindHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "FindEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	filter, err := parseFilter(r)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	pagination, err := internal.ParsePagination(r)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	entries, err := h.store.Find(traceCtx, filter, pagination)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	internal.WriteJSONResponse(w, http.StatusOK, generateResponses(entries))
+}
+
+func parseFilter(r *http.Request) (Filter, error) {
+	query := r.URL.Query()
+	filter := Filter{
+		Action:     query.Get("action"),
+		TargetType: query.Get("target_type"),
+		IPAddress:  query.Get("ip_address"),
+	}
+
+	var err error
+	if value := query.Get("actor_id"); value != "" {
+		filter.ActorID, err = internal.ParseUUID(value)
+		if err != nil {
+			return Filter{}, fmt.Errorf("%w: actor_id must be a UUID", errorPkg.ErrInvalidQuery)
+		}
+	}
+	if value := query.Get("target_id"); value != "" {
+		filter.TargetID, err = internal.ParseUUID(value)
+		if err != nil {
+			return Filter{}, fmt.Errorf("%w: target_id must be a UUID", errorPkg.ErrInvalidQuery)
+		}
+	}
+	if value := query.Get("since"); value != "" {
+		filter.Since, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			return Filter{}, fmt.Errorf("%w: since must be an RFC 3339 time", errorPkg.ErrInvalidQuery)
+		}
+	}
+	if value := query.Get("until"); value != "" {
+		filter.Until, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			return Filter{}, fmt.Errorf("%w: until must be an RFC 3339 time", errorPkg.ErrInvalidQuery)
+		}
+	}
+
+	return filter, nil
+}
+
+func generateResponses(entries []AuditLog) []Response {
+	response := make([]Response, len(entries))
+	for i, entry := range entries {
+		response[i] = GenerateResponse(entry)
+	}
+	return response
+}
+
+func GenerateResponse(entry AuditLog) Response {
+	response := Response{
+		ID:         entry.ID,
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		Details:    entry.Details.String,
+		IPAddress:  entry.IpAddress.String,
+		TraceID:    entry.TraceID.String,
+		CreatedAt:  entry.CreatedAt.Time.Format(time.RFC3339),
+	}
+	if entry.ActorID.Valid {
+		response.ActorID = uuid.UUID(entry.ActorID.Bytes).String()
+	}
+	if entry.TargetID.Valid {
+		response.TargetID = uuid.UUID(entry.TargetID.Bytes).String()
+	}
+	return response
+}
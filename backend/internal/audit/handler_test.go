@@ -0,0 +1,117 @@
+package audit_test
+
+import (
+	"backend/internal"
+	"backend/internal/audit"
+	"backend/internal/audit/mocks"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHandler_FindHandler(t *testing.T) {
+	actorID := uuid.MustParse("81c1ecc1-66d7-4134-b5fe-d886a6f418a4")
+	stored := []audit.AuditLog{
+		{
+			ID:         2,
+			ActorID:    pgtype.UUID{Bytes: actorID, Valid: true},
+			Action:     "user.role_change",
+			TargetType: audit.TargetUser,
+			TargetID:   pgtype.UUID{Bytes: uuid.MustParse("7942c917-4770-43c1-a56a-952186b9970e"), Valid: true},
+			Details:    pgtype.Text{String: "MODERATOR", Valid: true},
+			IpAddress:  pgtype.Text{String: "192.0.2.1", Valid: true},
+			TraceID:    pgtype.Text{String: "4bf92f3577b34da6a3ce929d0e0e4736", Valid: true},
+			CreatedAt:  pgtype.Timestamptz{Time: time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC), Valid: true},
+		},
+		{
+			ID:         1,
+			Action:     "auth.login_failed",
+			TargetType: audit.TargetUser,
+			Details:    pgtype.Text{String: "unknown user ghost", Valid: true},
+			CreatedAt:  pgtype.Timestamptz{Time: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		setupMock  func(m *mocks.Store)
+		wantStatus int
+		wantResult []audit.Response
+	}{
+		{
+			name:  "Should list filtered entries",
+			query: "?actor_id=81c1ecc1-66d7-4134-b5fe-d886a6f418a4&action=user.role_change&since=2000-01-01T00:00:00Z&size=10",
+			setupMock: func(m *mocks.Store) {
+				filter := audit.Filter{
+					ActorID: actorID,
+					Action:  "user.role_change",
+					Since:   time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+				}
+				m.On("Find", mock.Anything, filter, internal.Pagination{Page: 1, Size: 10}).Return(stored, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantResult: []audit.Response{
+				{
+					ID:         2,
+					ActorID:    "81c1ecc1-66d7-4134-b5fe-d886a6f418a4",
+					Action:     "user.role_change",
+					TargetType: audit.TargetUser,
+					TargetID:   "7942c917-4770-43c1-a56a-952186b9970e",
+					Details:    "MODERATOR",
+					IPAddress:  "192.0.2.1",
+					TraceID:    "4bf92f3577b34da6a3ce929d0e0e4736",
+					CreatedAt:  "2000-01-02T00:00:00Z",
+				},
+				{
+					ID:         1,
+					Action:     "auth.login_failed",
+					TargetType: audit.TargetUser,
+					Details:    "unknown user ghost",
+					CreatedAt:  "2000-01-01T00:00:00Z",
+				},
+			},
+		},
+		{
+			name:       "Should reject invalid actor",
+			query:      "?actor_id=nobody",
+			setupMock:  func(m *mocks.Store) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Should reject invalid time",
+			query:      "?until=yesterday",
+			setupMock:  func(m *mocks.Store) {},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mocks.NewStore(t)
+			tt.setupMock(m)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/api/admin/audit"+tt.query, nil)
+
+			h := audit.NewHandler(zap.NewNop(), m)
+			h.FindHandler(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusOK {
+				jsonWant, err := json.Marshal(tt.wantResult)
+				if err != nil {
+					t.Fatalf("failed to marshal expected response: %v", err)
+				}
+				assert.Equal(t, string(jsonWant), w.Body.String())
+			}
+		})
+	}
+}
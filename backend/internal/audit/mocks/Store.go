@@ -0,0 +1,93 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	audit "backend/internal/audit"
+	context "context"
+
+	internal "backend/internal"
+
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// Store is an autogenerated mock type for the Store type
+type Store struct {
+	mock.Mock
+}
+
+// Find provides a mock function with given fields: ctx, filter, pagination
+func (_m *Store) Find(ctx context.Context, filter audit.Filter, pagination internal.Pagination) ([]audit.AuditLog, error) {
+	ret := _m.Called(ctx, filter, pagination)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Find")
+	}
+
+	var r0 []audit.AuditLog
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, audit.Filter, internal.Pagination) ([]audit.AuditLog, error)); ok {
+		return rf(ctx, filter, pagination)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, audit.Filter, internal.Pagination) []audit.AuditLog); ok {
+		r0 = rf(ctx, filter, pagination)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]audit.AuditLog)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, audit.Filter, internal.Pagination) error); ok {
+		r1 = rf(ctx, filter, pagination)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByTarget provides a mock function with given fields: ctx, targetType, targetID
+func (_m *Store) GetByTarget(ctx context.Context, targetType string, targetID uuid.UUID) ([]audit.AuditLog, error) {
+	ret := _m.Called(ctx, targetType, targetID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByTarget")
+	}
+
+	var r0 []audit.AuditLog
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uuid.UUID) ([]audit.AuditLog, error)); ok {
+		return rf(ctx, targetType, targetID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uuid.UUID) []audit.AuditLog); ok {
+		r0 = rf(ctx, targetType, targetID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]audit.AuditLog)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uuid.UUID) error); ok {
+		r1 = rf(ctx, targetType, targetID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewStore creates a new instance of Store. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Store {
+	mock := &Store{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,18 @@
+-- name: Create :exec
+INSERT INTO audit_log (actor_id, action, target_type, target_id, details, ip_address, trace_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: FindByTarget :many
+SELECT * FROM audit_log WHERE target_type = $1 AND target_id = @target_id::uuid ORDER BY created_at, id;
+
+-- name: Find :many
+SELECT * FROM audit_log
+WHERE (sqlc.narg(actor_id)::uuid IS NULL OR actor_id = sqlc.narg(actor_id)::uuid)
+  AND (sqlc.narg(action)::text IS NULL OR action = sqlc.narg(action)::text)
+  AND (sqlc.narg(target_type)::text IS NULL OR target_type = sqlc.narg(target_type)::text)
+  AND (sqlc.narg(target_id)::uuid IS NULL OR target_id = sqlc.narg(target_id)::uuid)
+  AND (sqlc.narg(ip_address)::text IS NULL OR ip_address = sqlc.narg(ip_address)::text)
+  AND (sqlc.narg(since)::timestamptz IS NULL OR created_at >= sqlc.narg(since)::timestamptz)
+  AND (sqlc.narg(until)::timestamptz IS NULL OR created_at < sqlc.narg(until)::timestamptz)
+ORDER BY created_at DESC, id DESC
+LIMIT @size OFFSET @skip;
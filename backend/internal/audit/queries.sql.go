@@ -0,0 +1,145 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: queries.sql
+
+package audit
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const create = `-- name: Create :exec
+INSERT INTO audit_log (actor_id, action, target_type, target_id, details, ip_address, trace_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+`
+
+type CreateParams struct {
+	ActorID    pgtype.UUID
+	Action     string
+	TargetType string
+	TargetID   pgtype.UUID
+	Details    pgtype.Text
+	IpAddress  pgtype.Text
+	TraceID    pgtype.Text
+}
+
+func (q *Queries) Create(ctx context.Context, arg CreateParams) error {
+	_, err := q.db.Exec(ctx, create,
+		arg.ActorID,
+		arg.Action,
+		arg.TargetType,
+		arg.TargetID,
+		arg.Details,
+		arg.IpAddress,
+		arg.TraceID,
+	)
+	return err
+}
+
+const find = `-- name: Find :many
+SELECT id, actor_id, action, target_type, target_id, details, ip_address, trace_id, created_at FROM audit_log
+WHERE ($1::uuid IS NULL OR actor_id = $1::uuid)
+  AND ($2::text IS NULL OR action = $2::text)
+  AND ($3::text IS NULL OR target_type = $3::text)
+  AND ($4::uuid IS NULL OR target_id = $4::uuid)
+  AND ($5::text IS NULL OR ip_address = $5::text)
+  AND ($6::timestamptz IS NULL OR created_at >= $6::timestamptz)
+  AND ($7::timestamptz IS NULL OR created_at < $7::timestamptz)
+ORDER BY created_at DESC, id DESC
+LIMIT $9 OFFSET $8
+`
+
+type FindParams struct {
+	ActorID    pgtype.UUID
+	Action     pgtype.Text
+	TargetType pgtype.Text
+	TargetID   pgtype.UUID
+	IpAddress  pgtype.Text
+	Since      pgtype.Timestamptz
+	Until      pgtype.Timestamptz
+	Skip       int32
+	Size       int32
+}
+
+func (q *Queries) Find(ctx context.Context, arg FindParams) ([]AuditLog, error) {
+	rows, err := q.db.Query(ctx, find,
+		arg.ActorID,
+		arg.Action,
+		arg.TargetType,
+		arg.TargetID,
+		arg.IpAddress,
+		arg.Since,
+		arg.Until,
+		arg.Skip,
+		arg.Size,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuditLog
+	for rows.Next() {
+		var i AuditLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.ActorID,
+			&i.Action,
+			&i.TargetType,
+			&i.TargetID,
+			&i.Details,
+			&i.IpAddress,
+			&i.TraceID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findByTarget = `-- name: FindByTarget :many
+SELECT id, actor_id, action, target_type, target_id, details, ip_address, trace_id, created_at FROM audit_log WHERE target_type = $1 AND target_id = $2::uuid ORDER BY created_at, id
+`
+
+type FindByTargetParams struct {
+	TargetType string
+	TargetID   uuid.UUID
+}
+
+func (q *Queries) FindByTarget(ctx context.Context, arg FindByTargetParams) ([]AuditLog, error) {
+	rows, err := q.db.Query(ctx, findByTarget, arg.TargetType, arg.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuditLog
+	for rows.Next() {
+		var i AuditLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.ActorID,
+			&i.Action,
+			&i.TargetType,
+			&i.TargetID,
+			&i.Details,
+			&i.IpAddress,
+			&i.TraceID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
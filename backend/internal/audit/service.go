@@ -0,0 +1,144 @@
+package audit
+
+import (
+	"backend/internal"
+	"backend/internal/database"
+	"backend/internal/jwt"
+	"context"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"time"
+)
+
+// Target types of audit entries
+const (
+	TargetPost    = "post"
+	TargetComment = "comment"
+	TargetUser    = "user"
+)
+
+// Entry is a privileged or security-relevant action on some target, Details is optional free text such as a pin
+// scope. A zero ActorID is replaced by the authenticated user of the request, a zero TargetID is stored as unknown.
+type Entry struct {
+	ActorID    uuid.UUID
+	Action     string
+	TargetType string
+	TargetID   uuid.UUID
+	Details    string
+}
+
+// Filter narrows down the audit log, zero fields match every entry
+type Filter struct {
+	ActorID    uuid.UUID
+	Action     string
+	TargetType string
+	TargetID   uuid.UUID
+	IPAddress  string
+	Since      time.Time
+	Until      time.Time
+}
+
+type Service struct {
+	logger *zap.Logger
+	tracer trace.Tracer
+	query  *Queries
+}
+
+func NewService(logger *zap.Logger, db DBTX) *Service {
+	return &Service{
+		logger: logger,
+		tracer: otel.Tracer("audit/service"),
+		query:  New(db),
+	}
+}
+
+// Record appends an entry to the audit trail together with the client IP and the trace ID of the request, entries
+// are never changed afterwards
+func (s *Service) Record(ctx context.Context, entry Entry) error {
+	traceCtx, span := s.tracer.Start(ctx, "Record")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	if entry.ActorID == uuid.Nil {
+		if u, err := jwt.GetUserFromContext(traceCtx); err == nil {
+			entry.ActorID, _ = uuid.Parse(u.ID)
+		}
+	}
+
+	ip := internal.ClientIPFromContext(traceCtx)
+	traceID := ""
+	if spanCtx := span.SpanContext(); spanCtx.HasTraceID() {
+		traceID = spanCtx.TraceID().String()
+	}
+
+	err := s.query.Create(traceCtx, CreateParams{
+		ActorID:    optionalUUID(entry.ActorID),
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetID:   optionalUUID(entry.TargetID),
+		Details:    optionalText(entry.Details),
+		IpAddress:  optionalText(ip),
+		TraceID:    optionalText(traceID),
+	})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "record audit entry")
+		span.RecordError(err)
+		return err
+	}
+
+	logger.Info("Recorded audit entry", zap.String("actor_id", entry.ActorID.String()), zap.String("action", entry.Action), zap.String("target_type", entry.TargetType), zap.String("target_id", entry.TargetID.String()))
+	return nil
+}
+
+// GetByTarget returns the audit trail of a target, oldest first
+func (s *Service) GetByTarget(ctx context.Context, targetType string, targetID uuid.UUID) ([]AuditLog, error) {
+	traceCtx, span := s.tracer.Start(ctx, "GetByTarget")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	entries, err := s.query.FindByTarget(traceCtx, FindByTargetParams{TargetType: targetType, TargetID: targetID})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "get audit entries by target")
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// Find returns the entries matching the filter, newest first
+func (s *Service) Find(ctx context.Context, filter Filter, pagination internal.Pagination) ([]AuditLog, error) {
+	traceCtx, span := s.tracer.Start(ctx, "Find")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	entries, err := s.query.Find(traceCtx, FindParams{
+		ActorID:    optionalUUID(filter.ActorID),
+		Action:     optionalText(filter.Action),
+		TargetType: optionalText(filter.TargetType),
+		TargetID:   optionalUUID(filter.TargetID),
+		IpAddress:  optionalText(filter.IPAddress),
+		Since:      pgtype.Timestamptz{Time: filter.Since, Valid: !filter.Since.IsZero()},
+		Until:      pgtype.Timestamptz{Time: filter.Until, Valid: !filter.Until.IsZero()},
+		Skip:       pagination.Offset(),
+		Size:       pagination.Size,
+	})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "find audit entries")
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func optionalUUID(id uuid.UUID) pgtype.UUID {
+	return pgtype.UUID{Bytes: id, Valid: id != uuid.Nil}
+}
+
+func optionalText(value string) pgtype.Text {
+	return pgtype.Text{String: value, Valid: value != ""}
+}
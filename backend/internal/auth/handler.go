@@ -2,11 +2,10 @@ package auth
 
 import (
 	"backend/internal"
-	errorPkg "backend/internal/error"
+	"backend/internal/audit"
 	"backend/internal/problem"
 	"backend/internal/user"
 	"context"
-	"fmt"
 	"github.com/go-playground/validator/v10"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
@@ -29,6 +28,13 @@ type RegisterRequest struct {
 	Password string `json:"password" validate:"required"`
 }
 
+// Actions recorded in the audit trail
+const (
+	ActionLogin       = "auth.login"
+	ActionLoginFailed = "auth.login_failed"
+	ActionRegister    = "auth.register"
+)
+
 type JWTIssuer interface {
 	New(ctx context.Context, id, username string, role string) (string, error)
 }
@@ -38,6 +44,11 @@ type UserStore interface {
 	GetByName(ctx context.Context, name string) (user.User, error)
 }
 
+// Auditor keeps the trail of logins and registrations, see audit.Service
+type Auditor interface {
+	Record(ctx context.Context, entry audit.Entry) error
+}
+
 type Handler struct {
 	validator *validator.Validate
 	logger    *zap.Logger
@@ -45,15 +56,19 @@ type Handler struct {
 
 	userStore UserStore
 	jwtIssuer JWTIssuer
+	auditor   Auditor
+	login     *Login
 }
 
-func NewHandler(validator *validator.Validate, logger *zap.Logger, userStore UserStore, jwtIssuer JWTIssuer) *Handler {
+func NewHandler(validator *validator.Validate, logger *zap.Logger, userStore UserStore, jwtIssuer JWTIssuer, auditor Auditor) *Handler {
 	return &Handler{
 		tracer:    otel.Tracer("auth/handler"),
 		validator: validator,
 		logger:    logger,
 		userStore: userStore,
 		jwtIssuer: jwtIssuer,
+		auditor:   auditor,
+		login:     NewLogin(logger, userStore, auditor),
 	}
 }
 
@@ -69,22 +84,13 @@ func (h *Handler) LoginHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	userEntity, err := h.userStore.GetByName(traceCtx, request.Username)
-	if err != nil {
-		logger.Warn("Failed to get user by name", zap.String("username", request.Username), zap.Error(err))
-
-		// Prevent leaking information about whether the user exists
-		problem.WriteError(traceCtx, w, fmt.Errorf("%w: %v", errorPkg.ErrCredentialInvalid, err), logger)
-		return
-	}
-
-	err = bcrypt.CompareHashAndPassword([]byte(userEntity.Password), []byte(request.Password))
+	userEntity, err := h.login.Check(traceCtx, "", request.Username, request.Password)
 	if err != nil {
-		problem.WriteError(traceCtx, w, fmt.Errorf("%w: %v", errorPkg.ErrCredentialInvalid, err), logger)
+		problem.WriteError(traceCtx, w, err, logger)
 		return
 	}
 
-	token, err := h.jwtIssuer.New(traceCtx, userEntity.ID.String(), userEntity.Name, "USER")
+	token, err := h.jwtIssuer.New(traceCtx, userEntity.ID.String(), userEntity.Name, userEntity.Role)
 	if err != nil {
 		problem.WriteError(traceCtx, w, err, logger)
 		return
@@ -123,5 +129,6 @@ func (h *Handler) RegisterHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	logger.Info("User registered", zap.String("username", request.Username), zap.String("user_id", userEntity.ID.String()))
+	record(traceCtx, h.logger, h.auditor, audit.Entry{ActorID: userEntity.ID, Action: ActionRegister, TargetID: userEntity.ID})
 	w.WriteHeader(http.StatusCreated)
 }
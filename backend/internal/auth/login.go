@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"backend/internal"
+	"backend/internal/audit"
+	errorPkg "backend/internal/error"
+	"backend/internal/user"
+	"context"
+	"fmt"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"time"
+)
+
+// UserFinder looks up the user of a login, see user.Service
+type UserFinder interface {
+	GetByName(ctx context.Context, name string) (user.User, error)
+}
+
+// Login checks passwords for the web API and the NNTP gateway, so both refuse the same users and leave the same
+// audit trail
+type Login struct {
+	logger  *zap.Logger
+	tracer  trace.Tracer
+	users   UserFinder
+	auditor Auditor
+}
+
+func NewLogin(logger *zap.Logger, users UserFinder, auditor Auditor) *Login {
+	return &Login{
+		logger:  logger,
+		tracer:  otel.Tracer("auth/login"),
+		users:   users,
+		auditor: auditor,
+	}
+}
+
+// Check returns the user if the password is right and the user is not suspended. Every attempt is recorded in the
+// audit trail, channel names the protocol in the details of the entries and is empty for the web API.
+func (l *Login) Check(ctx context.Context, channel, name, password string) (user.User, error) {
+	traceCtx, span := l.tracer.Start(ctx, "Check")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, l.logger)
+
+	userEntity, err := l.users.GetByName(traceCtx, name)
+	if err != nil {
+		logger.Warn("Failed to get user by name", zap.String("username", name), zap.Error(err))
+		record(traceCtx, l.logger, l.auditor, audit.Entry{Action: ActionLoginFailed, Details: details(channel, "unknown user "+name)})
+		// Prevent leaking information about whether the user exists
+		err = fmt.Errorf("%w: %v", errorPkg.ErrCredentialInvalid, err)
+		span.RecordError(err)
+		return user.User{}, err
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(userEntity.Password), []byte(password))
+	if err != nil {
+		record(traceCtx, l.logger, l.auditor, audit.Entry{Action: ActionLoginFailed, TargetID: userEntity.ID, Details: details(channel, "wrong password")})
+		err = fmt.Errorf("%w: %v", errorPkg.ErrCredentialInvalid, err)
+		span.RecordError(err)
+		return user.User{}, err
+	}
+
+	// Only checked after the password so the suspension of an account is not revealed to others
+	if userEntity.IsSuspended(time.Now()) {
+		record(traceCtx, l.logger, l.auditor, audit.Entry{Action: ActionLoginFailed, TargetID: userEntity.ID, Details: details(channel, "suspended")})
+		err = userEntity.SuspensionError()
+		span.RecordError(err)
+		return user.User{}, err
+	}
+
+	record(traceCtx, l.logger, l.auditor, audit.Entry{ActorID: userEntity.ID, Action: ActionLogin, TargetID: userEntity.ID, Details: details(channel, "")})
+	return userEntity, nil
+}
+
+// details prefixes the reason of an audit entry with the channel of the login
+func details(channel, reason string) string {
+	switch {
+	case channel == "":
+		return reason
+	case reason == "":
+		return channel
+	}
+	return channel + ": " + reason
+}
+
+// record adds an entry about a user to the audit trail, a failure is logged but does not fail the request
+func record(ctx context.Context, logger *zap.Logger, auditor Auditor, entry audit.Entry) {
+	entry.TargetType = audit.TargetUser
+	err := auditor.Record(ctx, entry)
+	if err != nil {
+		internal.LoggerWithContext(ctx, logger).Error("Failed to record audit entry", zap.String("action", entry.Action), zap.Error(err))
+	}
+}
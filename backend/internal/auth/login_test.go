@@ -0,0 +1,121 @@
+package auth_test
+
+import (
+	"backend/internal/audit"
+	"backend/internal/auth"
+	errorPkg "backend/internal/error"
+	"backend/internal/user"
+	"context"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"testing"
+	"time"
+)
+
+// users finds the users of a map by name
+type users map[string]user.User
+
+func (u users) GetByName(_ context.Context, name string) (user.User, error) {
+	found, ok := u[name]
+	if !ok {
+		return user.User{}, errorPkg.NewNotFoundError("users", "name", name, "")
+	}
+	return found, nil
+}
+
+// auditor keeps the recorded entries
+type auditor struct {
+	entries []audit.Entry
+}
+
+func (a *auditor) Record(_ context.Context, entry audit.Entry) error {
+	a.entries = append(a.entries, entry)
+	return nil
+}
+
+func TestLogin_Check(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("failed to hash password: %v", err)
+	}
+	alice := user.User{ID: uuid.MustParse("81c1ecc1-66d7-4134-b5fe-d886a6f418a4"), Name: "alice", Password: string(hash)}
+	bob := user.User{
+		ID:          uuid.MustParse("8d1c4a2e-0f4b-4c55-9a51-3b0c4f2e9d10"),
+		Name:        "bob",
+		Password:    string(hash),
+		SuspendedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	}
+
+	tests := []struct {
+		name        string
+		channel     string
+		username    string
+		password    string
+		wantErr     error
+		wantAction  string
+		wantDetails string
+	}{
+		{
+			name:       "Should accept the password",
+			username:   "alice",
+			password:   "secret",
+			wantAction: auth.ActionLogin,
+		},
+		{
+			name:        "Should name the channel in the audit trail",
+			channel:     "nntp",
+			username:    "alice",
+			password:    "secret",
+			wantAction:  auth.ActionLogin,
+			wantDetails: "nntp",
+		},
+		{
+			name:        "Should reject wrong password",
+			channel:     "nntp",
+			username:    "alice",
+			password:    "wrong",
+			wantErr:     errorPkg.ErrCredentialInvalid,
+			wantAction:  auth.ActionLoginFailed,
+			wantDetails: "nntp: wrong password",
+		},
+		{
+			name:        "Should reject unknown user like a wrong password",
+			username:    "carol",
+			password:    "secret",
+			wantErr:     errorPkg.ErrCredentialInvalid,
+			wantAction:  auth.ActionLoginFailed,
+			wantDetails: "unknown user carol",
+		},
+		{
+			name:        "Should reject suspended user",
+			username:    "bob",
+			password:    "secret",
+			wantErr:     errorPkg.ErrSuspended,
+			wantAction:  auth.ActionLoginFailed,
+			wantDetails: "suspended",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &auditor{}
+			login := auth.NewLogin(zap.NewNop(), users{"alice": alice, "bob": bob}, a)
+
+			got, err := login.Check(context.Background(), tt.channel, tt.username, tt.password)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, alice.ID, got.ID)
+			}
+			if assert.Len(t, a.entries, 1) {
+				assert.Equal(t, tt.wantAction, a.entries[0].Action)
+				assert.Equal(t, tt.wantDetails, a.entries[0].Details)
+				assert.Equal(t, audit.TargetUser, a.entries[0].TargetType)
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package block
+
+import (
+	"backend/internal"
+	errorPkg "backend/internal/error"
+	"backend/internal/jwt"
+	"backend/internal/problem"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+type Response struct {
+	UserID    string `json:"user_id"`
+	CreatedAt string `json:"created_at"`
+}
+
+//go:generate mockery --name=Store
+type Store interface {
+	Block(ctx context.Context, blockerID, blockedID uuid.UUID) error
+	Unblock(ctx context.Context, blockerID, blockedID uuid.UUID) error
+	GetByBlocker(ctx context.Context, blockerID uuid.UUID) ([]UserBlock, error)
+	Mute(ctx context.Context, muterID, mutedID uuid.UUID) error
+	Unmute(ctx context.Context, muterID, mutedID uuid.UUID) error
+	GetByMuter(ctx context.Context, muterID uuid.UUID) ([]UserMute, error)
+}
+
+type Handler struct {
+	logger *zap.Logger
+	tracer trace.Tracer
+	store  Store
+}
+
+func NewHandler(logger *zap.Logger, store Store) *Handler {
+	return &Handler{
+		logger: logger,
+		tracer: otel.Tracer("block/handler"),
+		store:  store,
+	}
+}
+
+// GetAllHandler lists the users the current user has blocked
+func (h *Handler) GetAllHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "GetAllBlockEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	userID, err := jwt.GetUserIDFromContext(traceCtx)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	blocks, err := h.store.GetByBlocker(traceCtx, userID)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	response := make([]Response, len(blocks))
+	for i, b := range blocks {
+		response[i] = Response{
+			UserID:    b.BlockedID.String(),
+			CreatedAt: b.CreatedAt.Time.Format(time.RFC3339),
+		}
+	}
+
+	internal.WriteJSONResponse(w, http.StatusOK, response)
+}
+
+func (h *Handler) BlockHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "BlockUserEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	userID, blockedID, err := userAndTarget(traceCtx, r)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	err = h.store.Block(traceCtx, userID, blockedID)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	logger.Info("Blocked user", zap.String("blocked_id", blockedID.String()))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) UnblockHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "UnblockUserEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	userID, blockedID, err := userAndTarget(traceCtx, r)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	err = h.store.Unblock(traceCtx, userID, blockedID)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetMutesHandler lists the users the current user has muted
+func (h *Handler) GetMutesHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "GetAllMuteEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	userID, err := jwt.GetUserIDFromContext(traceCtx)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	mutes, err := h.store.GetByMuter(traceCtx, userID)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	response := make([]Response, len(mutes))
+	for i, m := range mutes {
+		response[i] = Response{
+			UserID:    m.MutedID.String(),
+			CreatedAt: m.CreatedAt.Time.Format(time.RFC3339),
+		}
+	}
+
+	internal.WriteJSONResponse(w, http.StatusOK, response)
+}
+
+func (h *Handler) MuteHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "MuteUserEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	userID, mutedID, err := userAndTarget(traceCtx, r)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	err = h.store.Mute(traceCtx, userID, mutedID)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	logger.Info("Muted user", zap.String("muted_id", mutedID.String()))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) UnmuteHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "UnmuteUserEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	userID, mutedID, err := userAndTarget(traceCtx, r)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	err = h.store.Unmute(traceCtx, userID, mutedID)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// userAndTarget returns the current user and the user named in the id path value
+func userAndTarget(ctx context.Context, r *http.Request) (uuid.UUID, uuid.UUID, error) {
+	userID, err := jwt.GetUserIDFromContext(ctx)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	targetID, err := internal.ParseUUID(r.PathValue("id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("%w: %v", errorPkg.ErrInvalidUUID, err)
+	}
+
+	return userID, targetID, nil
+}
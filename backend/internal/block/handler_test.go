@@ -0,0 +1,154 @@
+package block_test
+
+import (
+	"backend/internal"
+	"backend/internal/block"
+	"backend/internal/block/mocks"
+	errorPkg "backend/internal/error"
+	"backend/internal/jwt"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var (
+	blocker = jwt.User{
+		ID:       "81c1ecc1-66d7-4134-b5fe-d886a6f418a4",
+		Username: "blocker",
+		Role:     jwt.RoleUser,
+	}
+	blockerID = uuid.MustParse(blocker.ID)
+	blockedID = uuid.MustParse("9b2e4c1a-7f3d-4e8b-a6c5-0d1f2e3a4b5c")
+)
+
+func newRequest(method, id string) *http.Request {
+	r := httptest.NewRequest(method, "/api/user/"+id+"/block", nil)
+	r.SetPathValue("id", id)
+	return r.WithContext(context.WithValue(r.Context(), internal.UserContextKey, blocker))
+}
+
+func TestHandler_BlockHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		setupMock  func(m *mocks.Store)
+		wantStatus int
+	}{
+		{
+			name: "Should block user",
+			id:   blockedID.String(),
+			setupMock: func(m *mocks.Store) {
+				m.On("Block", mock.Anything, blockerID, blockedID).Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "Should reject invalid id",
+			id:         "blocked",
+			setupMock:  func(m *mocks.Store) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Should reject blocking oneself",
+			id:   blocker.ID,
+			setupMock: func(m *mocks.Store) {
+				m.On("Block", mock.Anything, blockerID, blockerID).
+					Return(fmt.Errorf("%w: users cannot block themselves", errorPkg.ErrInvalidQuery))
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Should return not found for unknown user",
+			id:   blockedID.String(),
+			setupMock: func(m *mocks.Store) {
+				m.On("Block", mock.Anything, blockerID, blockedID).
+					Return(errorPkg.NewNotFoundError("users", "id", blockedID.String(), ""))
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mocks.NewStore(t)
+			tt.setupMock(m)
+
+			w := httptest.NewRecorder()
+			h := block.NewHandler(zap.NewNop(), m)
+			h.BlockHandler(w, newRequest(http.MethodPut, tt.id))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestHandler_UnblockHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		setupMock  func(m *mocks.Store)
+		wantStatus int
+	}{
+		{
+			name: "Should unblock user",
+			setupMock: func(m *mocks.Store) {
+				m.On("Unblock", mock.Anything, blockerID, blockedID).Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name: "Should return not found for user that is not blocked",
+			setupMock: func(m *mocks.Store) {
+				m.On("Unblock", mock.Anything, blockerID, blockedID).
+					Return(errorPkg.NewNotFoundError("user_blocks", "blocked_id", blockedID.String(), ""))
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mocks.NewStore(t)
+			tt.setupMock(m)
+
+			w := httptest.NewRecorder()
+			h := block.NewHandler(zap.NewNop(), m)
+			h.UnblockHandler(w, newRequest(http.MethodDelete, blockedID.String()))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestHandler_GetAllHandler(t *testing.T) {
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	m := mocks.NewStore(t)
+	m.On("GetByBlocker", mock.Anything, blockerID).Return([]block.UserBlock{{
+		BlockerID: blockerID,
+		BlockedID: blockedID,
+		CreatedAt: pgtype.Timestamptz{Time: createdAt, Valid: true},
+	}}, nil)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/api/blocks", nil)
+	r = r.WithContext(context.WithValue(r.Context(), internal.UserContextKey, blocker))
+
+	h := block.NewHandler(zap.NewNop(), m)
+	h.GetAllHandler(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []block.Response
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	assert.Equal(t, []block.Response{{UserID: blockedID.String(), CreatedAt: "2025-01-02T03:04:05Z"}}, response)
+}
@@ -0,0 +1,163 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	block "backend/internal/block"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// Store is an autogenerated mock type for the Store type
+type Store struct {
+	mock.Mock
+}
+
+// Block provides a mock function with given fields: ctx, blockerID, blockedID
+func (_m *Store) Block(ctx context.Context, blockerID uuid.UUID, blockedID uuid.UUID) error {
+	ret := _m.Called(ctx, blockerID, blockedID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Block")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(ctx, blockerID, blockedID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByBlocker provides a mock function with given fields: ctx, blockerID
+func (_m *Store) GetByBlocker(ctx context.Context, blockerID uuid.UUID) ([]block.UserBlock, error) {
+	ret := _m.Called(ctx, blockerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByBlocker")
+	}
+
+	var r0 []block.UserBlock
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]block.UserBlock, error)); ok {
+		return rf(ctx, blockerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []block.UserBlock); ok {
+		r0 = rf(ctx, blockerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]block.UserBlock)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, blockerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByMuter provides a mock function with given fields: ctx, muterID
+func (_m *Store) GetByMuter(ctx context.Context, muterID uuid.UUID) ([]block.UserMute, error) {
+	ret := _m.Called(ctx, muterID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByMuter")
+	}
+
+	var r0 []block.UserMute
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]block.UserMute, error)); ok {
+		return rf(ctx, muterID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []block.UserMute); ok {
+		r0 = rf(ctx, muterID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]block.UserMute)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, muterID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Mute provides a mock function with given fields: ctx, muterID, mutedID
+func (_m *Store) Mute(ctx context.Context, muterID uuid.UUID, mutedID uuid.UUID) error {
+	ret := _m.Called(ctx, muterID, mutedID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Mute")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(ctx, muterID, mutedID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Unblock provides a mock function with given fields: ctx, blockerID, blockedID
+func (_m *Store) Unblock(ctx context.Context, blockerID uuid.UUID, blockedID uuid.UUID) error {
+	ret := _m.Called(ctx, blockerID, blockedID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Unblock")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(ctx, blockerID, blockedID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Unmute provides a mock function with given fields: ctx, muterID, mutedID
+func (_m *Store) Unmute(ctx context.Context, muterID uuid.UUID, mutedID uuid.UUID) error {
+	ret := _m.Called(ctx, muterID, mutedID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Unmute")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(ctx, muterID, mutedID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewStore creates a new instance of Store. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Store {
+	mock := &Store{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
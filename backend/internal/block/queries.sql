@@ -0,0 +1,22 @@
+-- name: Create :exec
+INSERT INTO user_blocks (blocker_id, blocked_id) VALUES ($1, $2) ON CONFLICT DO NOTHING;
+
+-- name: Delete :execrows
+DELETE FROM user_blocks WHERE blocker_id = $1 AND blocked_id = $2;
+
+-- name: FindByBlocker :many
+SELECT * FROM user_blocks WHERE blocker_id = $1 ORDER BY created_at DESC;
+
+-- name: ExistsByAnyBlocker :one
+SELECT EXISTS (
+    SELECT 1 FROM user_blocks WHERE blocker_id = ANY(@blocker_ids::uuid[]) AND blocked_id = @blocked_id
+);
+
+-- name: CreateMute :exec
+INSERT INTO user_mutes (muter_id, muted_id) VALUES ($1, $2) ON CONFLICT DO NOTHING;
+
+-- name: DeleteMute :execrows
+DELETE FROM user_mutes WHERE muter_id = $1 AND muted_id = $2;
+
+-- name: FindMutesByMuter :many
+SELECT * FROM user_mutes WHERE muter_id = $1 ORDER BY created_at DESC;
@@ -0,0 +1,140 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: queries.sql
+
+package block
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const create = `-- name: Create :exec
+INSERT INTO user_blocks (blocker_id, blocked_id) VALUES ($1, $2) ON CONFLICT DO NOTHING
+`
+
+type CreateParams struct {
+	BlockerID uuid.UUID
+	BlockedID uuid.UUID
+}
+
+func (q *Queries) Create(ctx context.Context, arg CreateParams) error {
+	_, err := q.db.Exec(ctx, create, arg.BlockerID, arg.BlockedID)
+	return err
+}
+
+const createMute = `-- name: CreateMute :exec
+INSERT INTO user_mutes (muter_id, muted_id) VALUES ($1, $2) ON CONFLICT DO NOTHING
+`
+
+type CreateMuteParams struct {
+	MuterID uuid.UUID
+	MutedID uuid.UUID
+}
+
+func (q *Queries) CreateMute(ctx context.Context, arg CreateMuteParams) error {
+	_, err := q.db.Exec(ctx, createMute, arg.MuterID, arg.MutedID)
+	return err
+}
+
+const delete = `-- name: Delete :execrows
+DELETE FROM user_blocks WHERE blocker_id = $1 AND blocked_id = $2
+`
+
+type DeleteParams struct {
+	BlockerID uuid.UUID
+	BlockedID uuid.UUID
+}
+
+func (q *Queries) Delete(ctx context.Context, arg DeleteParams) (int64, error) {
+	result, err := q.db.Exec(ctx, delete, arg.BlockerID, arg.BlockedID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteMute = `-- name: DeleteMute :execrows
+DELETE FROM user_mutes WHERE muter_id = $1 AND muted_id = $2
+`
+
+type DeleteMuteParams struct {
+	MuterID uuid.UUID
+	MutedID uuid.UUID
+}
+
+func (q *Queries) DeleteMute(ctx context.Context, arg DeleteMuteParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteMute, arg.MuterID, arg.MutedID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const existsByAnyBlocker = `-- name: ExistsByAnyBlocker :one
+SELECT EXISTS (
+    SELECT 1 FROM user_blocks WHERE blocker_id = ANY($1::uuid[]) AND blocked_id = $2
+)
+`
+
+type ExistsByAnyBlockerParams struct {
+	BlockerIds []uuid.UUID
+	BlockedID  uuid.UUID
+}
+
+func (q *Queries) ExistsByAnyBlocker(ctx context.Context, arg ExistsByAnyBlockerParams) (bool, error) {
+	row := q.db.QueryRow(ctx, existsByAnyBlocker, arg.BlockerIds, arg.BlockedID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const findByBlocker = `-- name: FindByBlocker :many
+SELECT blocker_id, blocked_id, created_at FROM user_blocks WHERE blocker_id = $1 ORDER BY created_at DESC
+`
+
+func (q *Queries) FindByBlocker(ctx context.Context, blockerID uuid.UUID) ([]UserBlock, error) {
+	rows, err := q.db.Query(ctx, findByBlocker, blockerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserBlock
+	for rows.Next() {
+		var i UserBlock
+		if err := rows.Scan(&i.BlockerID, &i.BlockedID, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findMutesByMuter = `-- name: FindMutesByMuter :many
+SELECT muter_id, muted_id, created_at FROM user_mutes WHERE muter_id = $1 ORDER BY created_at DESC
+`
+
+func (q *Queries) FindMutesByMuter(ctx context.Context, muterID uuid.UUID) ([]UserMute, error) {
+	rows, err := q.db.Query(ctx, findMutesByMuter, muterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserMute
+	for rows.Next() {
+		var i UserMute
+		if err := rows.Scan(&i.MuterID, &i.MutedID, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS user_blocks (
+    blocker_id UUID REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    blocked_id UUID REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now() NOT NULL,
+    PRIMARY KEY (blocker_id, blocked_id),
+    CHECK (blocker_id <> blocked_id)
+);
+
+CREATE INDEX IF NOT EXISTS user_blocks_blocked_id_idx ON user_blocks (blocked_id);
+
+-- Muted users stay reachable, their posts and comments are only collapsed in the listings of the muter
+CREATE TABLE IF NOT EXISTS user_mutes (
+    muter_id UUID REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    muted_id UUID REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now() NOT NULL,
+    PRIMARY KEY (muter_id, muted_id),
+    CHECK (muter_id <> muted_id)
+);
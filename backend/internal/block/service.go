@@ -0,0 +1,164 @@
+package block
+
+import (
+	"backend/internal"
+	"backend/internal/database"
+	errorPkg "backend/internal/error"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+type Service struct {
+	logger *zap.Logger
+	tracer trace.Tracer
+	query  *Queries
+}
+
+func NewService(logger *zap.Logger, db DBTX) *Service {
+	return &Service{
+		logger: logger,
+		tracer: otel.Tracer("block/service"),
+		query:  New(db),
+	}
+}
+
+// Block adds blockedID to the block list of blockerID, blocking a user twice is not an error
+func (s *Service) Block(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	traceCtx, span := s.tracer.Start(ctx, "Block")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	if blockerID == blockedID {
+		err := fmt.Errorf("%w: users cannot block themselves", errorPkg.ErrInvalidQuery)
+		span.RecordError(err)
+		return err
+	}
+
+	err := s.query.Create(traceCtx, CreateParams{BlockerID: blockerID, BlockedID: blockedID})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "block user")
+		if errors.Is(err, database.ErrForeignKeyViolation) {
+			err = errorPkg.NewNotFoundError("users", "id", blockedID.String(), "")
+		}
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *Service) Unblock(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	traceCtx, span := s.tracer.Start(ctx, "Unblock")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	count, err := s.query.Delete(traceCtx, DeleteParams{BlockerID: blockerID, BlockedID: blockedID})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "unblock user")
+		span.RecordError(err)
+		return err
+	}
+	if count == 0 {
+		err = errorPkg.NewNotFoundError("user_blocks", "blocked_id", blockedID.String(), "")
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *Service) GetByBlocker(ctx context.Context, blockerID uuid.UUID) ([]UserBlock, error) {
+	traceCtx, span := s.tracer.Start(ctx, "GetByBlocker")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	blocks, err := s.query.FindByBlocker(traceCtx, blockerID)
+	if err != nil {
+		err = database.WrapDBError(err, logger, "get blocks by blocker")
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return blocks, nil
+}
+
+// IsBlockedByAny reports whether any of the blockers has blocked userID
+func (s *Service) IsBlockedByAny(ctx context.Context, userID uuid.UUID, blockerIDs []uuid.UUID) (bool, error) {
+	traceCtx, span := s.tracer.Start(ctx, "IsBlockedByAny")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	blocked, err := s.query.ExistsByAnyBlocker(traceCtx, ExistsByAnyBlockerParams{BlockerIds: blockerIDs, BlockedID: userID})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "check blocks")
+		span.RecordError(err)
+		return false, err
+	}
+
+	return blocked, nil
+}
+
+// Mute collapses the posts and comments of mutedID in the listings of muterID, muting a user twice is not an error
+func (s *Service) Mute(ctx context.Context, muterID, mutedID uuid.UUID) error {
+	traceCtx, span := s.tracer.Start(ctx, "Mute")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	if muterID == mutedID {
+		err := fmt.Errorf("%w: users cannot mute themselves", errorPkg.ErrInvalidQuery)
+		span.RecordError(err)
+		return err
+	}
+
+	err := s.query.CreateMute(traceCtx, CreateMuteParams{MuterID: muterID, MutedID: mutedID})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "mute user")
+		if errors.Is(err, database.ErrForeignKeyViolation) {
+			err = errorPkg.NewNotFoundError("users", "id", mutedID.String(), "")
+		}
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *Service) Unmute(ctx context.Context, muterID, mutedID uuid.UUID) error {
+	traceCtx, span := s.tracer.Start(ctx, "Unmute")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	count, err := s.query.DeleteMute(traceCtx, DeleteMuteParams{MuterID: muterID, MutedID: mutedID})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "unmute user")
+		span.RecordError(err)
+		return err
+	}
+	if count == 0 {
+		err = errorPkg.NewNotFoundError("user_mutes", "muted_id", mutedID.String(), "")
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *Service) GetByMuter(ctx context.Context, muterID uuid.UUID) ([]UserMute, error) {
+	traceCtx, span := s.tracer.Start(ctx, "GetByMuter")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	mutes, err := s.query.FindMutesByMuter(traceCtx, muterID)
+	if err != nil {
+		err = database.WrapDBError(err, logger, "get mutes by muter")
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return mutes, nil
+}
@@ -0,0 +1,114 @@
+package block_test
+
+import (
+	"backend/internal/block"
+	"backend/internal/database/databasetest"
+	errorPkg "backend/internal/error"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+)
+
+func TestService_Block(t *testing.T) {
+	pool := databasetest.Open(t)
+	s := block.NewService(zap.NewNop(), pool)
+	ctx := context.Background()
+
+	user := databasetest.CreateUser(t, pool)
+	blocked := databasetest.CreateUser(t, pool)
+
+	err := s.Block(ctx, user, user)
+	if !errors.Is(err, errorPkg.ErrInvalidQuery) {
+		t.Fatalf("Block() error = %v, want %v", err, errorPkg.ErrInvalidQuery)
+	}
+	err = s.Block(ctx, user, uuid.New())
+	if !errors.Is(err, errorPkg.ErrNotFound) {
+		t.Fatalf("Block() error = %v, want %v", err, errorPkg.ErrNotFound)
+	}
+
+	// blocking twice is not an error and keeps a single entry
+	for range 2 {
+		err = s.Block(ctx, user, blocked)
+		if err != nil {
+			t.Fatalf("Block() error = %v", err)
+		}
+	}
+	blocks, err := s.GetByBlocker(ctx, user)
+	if err != nil {
+		t.Fatalf("GetByBlocker() error = %v", err)
+	}
+	if assert.Len(t, blocks, 1) {
+		assert.Equal(t, blocked, blocks[0].BlockedID)
+	}
+
+	err = s.Unblock(ctx, user, blocked)
+	if err != nil {
+		t.Fatalf("Unblock() error = %v", err)
+	}
+	err = s.Unblock(ctx, user, blocked)
+	if !errors.Is(err, errorPkg.ErrNotFound) {
+		t.Fatalf("Unblock() error = %v, want %v", err, errorPkg.ErrNotFound)
+	}
+	blocks, err = s.GetByBlocker(ctx, user)
+	if err != nil {
+		t.Fatalf("GetByBlocker() error = %v", err)
+	}
+	assert.Empty(t, blocks)
+}
+
+func TestService_IsBlockedByAny(t *testing.T) {
+	pool := databasetest.Open(t)
+	s := block.NewService(zap.NewNop(), pool)
+	ctx := context.Background()
+
+	user := databasetest.CreateUser(t, pool)
+	blocker := databasetest.CreateUser(t, pool)
+	other := databasetest.CreateUser(t, pool)
+	blockedByUser := databasetest.CreateUser(t, pool)
+	for _, pair := range [][2]uuid.UUID{{blocker, user}, {user, blockedByUser}} {
+		err := s.Block(ctx, pair[0], pair[1])
+		if err != nil {
+			t.Fatalf("Block() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		blockerIDs []uuid.UUID
+		want       bool
+	}{
+		{
+			name:       "Should find block of one of the users",
+			blockerIDs: []uuid.UUID{other, blocker},
+			want:       true,
+		},
+		{
+			name:       "Should ignore users that did not block",
+			blockerIDs: []uuid.UUID{other},
+			want:       false,
+		},
+		{
+			name:       "Should ignore blocks in the other direction",
+			blockerIDs: []uuid.UUID{blockedByUser},
+			want:       false,
+		},
+		{
+			name:       "Should handle empty list",
+			blockerIDs: []uuid.UUID{},
+			want:       false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.IsBlockedByAny(ctx, user, tt.blockerIDs)
+			if err != nil {
+				t.Fatalf("IsBlockedByAny() error = %v", err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
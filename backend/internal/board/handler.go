@@ -0,0 +1,124 @@
+package board
+
+import (
+	"backend/internal"
+	errorPkg "backend/internal/error"
+	"backend/internal/problem"
+	"context"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+type CreateRequest struct {
+	Slug        string `json:"slug"        validate:"required,max=64,slug"`
+	Name        string `json:"name"        validate:"required,max=200"`
+	Description string `json:"description"`
+}
+
+type Response struct {
+	ID          string `json:"id"`
+	Slug        string `json:"slug"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	CreatedAt   string `json:"created_at"`
+}
+
+//go:generate mockery --name Store
+type Store interface {
+	GetAll(ctx context.Context) ([]Board, error)
+	GetByID(ctx context.Context, id uuid.UUID) (Board, error)
+	Create(ctx context.Context, request CreateRequest) (Board, error)
+}
+
+type Handler struct {
+	validator *validator.Validate
+	logger    *zap.Logger
+	tracer    trace.Tracer
+	store     Store
+}
+
+func NewHandler(v *validator.Validate, logger *zap.Logger, store Store) *Handler {
+	return &Handler{
+		validator: v,
+		logger:    logger,
+		tracer:    otel.Tracer("board/handler"),
+		store:     store,
+	}
+}
+
+func (h *Handler) GetAllHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "GetAllBoardEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	boards, err := h.store.GetAll(traceCtx)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	response := make([]Response, len(boards))
+	for i, b := range boards {
+		response[i] = GenerateResponse(b)
+	}
+
+	internal.WriteJSONResponse(w, http.StatusOK, response)
+}
+
+func (h *Handler) GetHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "GetBoardEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	id, err := internal.ParseUUID(r.PathValue("id"))
+	if err != nil {
+		problem.WriteError(traceCtx, w, fmt.Errorf("%w: %v", errorPkg.ErrInvalidUUID, err), logger)
+		return
+	}
+
+	board, err := h.store.GetByID(traceCtx, id)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	internal.WriteJSONResponse(w, http.StatusOK, GenerateResponse(board))
+}
+
+func (h *Handler) CreateHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "CreateBoardEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	var request CreateRequest
+	err := internal.ParseAndValidateRequestBody(traceCtx, h.validator, r, &request)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	board, err := h.store.Create(traceCtx, request)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	logger.Info("Created board", zap.String("id", board.ID.String()), zap.String("slug", board.Slug))
+	internal.WriteJSONResponse(w, http.StatusOK, GenerateResponse(board))
+}
+
+func GenerateResponse(b Board) Response {
+	return Response{
+		ID:          b.ID.String(),
+		Slug:        b.Slug,
+		Name:        b.Name,
+		Description: b.Description.String,
+		CreatedAt:   b.CreatedAt.Time.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,161 @@
+package board_test
+
+import (
+	"backend/internal"
+	"backend/internal/board"
+	"backend/internal/board/mocks"
+	"backend/internal/database"
+	errorPkg "backend/internal/error"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var boardID = uuid.MustParse("3c2f7a8e-1d4b-4f6a-9e0c-5b7d8a9f0e1c")
+
+func testBoard() board.Board {
+	return board.Board{
+		ID:          boardID,
+		Slug:        "general",
+		Name:        "General",
+		Description: pgtype.Text{String: "Anything goes", Valid: true},
+		CreatedAt:   pgtype.Timestamptz{Time: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), Valid: true},
+	}
+}
+
+func TestHandler_GetAllHandler(t *testing.T) {
+	m := mocks.NewStore(t)
+	m.On("GetAll", mock.Anything).Return([]board.Board{testBoard()}, nil)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/api/boards", nil)
+
+	h := board.NewHandler(internal.NewValidator(), zap.NewNop(), m)
+	h.GetAllHandler(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []board.Response
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	assert.Equal(t, []board.Response{{
+		ID:          boardID.String(),
+		Slug:        "general",
+		Name:        "General",
+		Description: "Anything goes",
+		CreatedAt:   "2025-01-02T03:04:05Z",
+	}}, response)
+}
+
+func TestHandler_GetHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		setupMock  func(m *mocks.Store)
+		wantStatus int
+	}{
+		{
+			name: "Should return board",
+			id:   boardID.String(),
+			setupMock: func(m *mocks.Store) {
+				m.On("GetByID", mock.Anything, boardID).Return(testBoard(), nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "Should reject invalid id",
+			id:         "general",
+			setupMock:  func(m *mocks.Store) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Should return not found for unknown board",
+			id:   boardID.String(),
+			setupMock: func(m *mocks.Store) {
+				m.On("GetByID", mock.Anything, boardID).Return(board.Board{}, errorPkg.NewNotFoundError("boards", "id", boardID.String(), "board not found"))
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mocks.NewStore(t)
+			tt.setupMock(m)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/api/board/"+tt.id, nil)
+			r.SetPathValue("id", tt.id)
+
+			h := board.NewHandler(internal.NewValidator(), zap.NewNop(), m)
+			h.GetHandler(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestHandler_CreateHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		setupMock  func(m *mocks.Store)
+		wantStatus int
+	}{
+		{
+			name: "Should create board",
+			body: `{"slug":"general","name":"General","description":"Anything goes"}`,
+			setupMock: func(m *mocks.Store) {
+				m.On("Create", mock.Anything, board.CreateRequest{Slug: "general", Name: "General", Description: "Anything goes"}).
+					Return(testBoard(), nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "Should reject missing name",
+			body:       `{"slug":"general"}`,
+			setupMock:  func(m *mocks.Store) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Should reject invalid slug",
+			body:       `{"slug":"General Board","name":"General"}`,
+			setupMock:  func(m *mocks.Store) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Should reject duplicate slug",
+			body: `{"slug":"general","name":"General"}`,
+			setupMock: func(m *mocks.Store) {
+				m.On("Create", mock.Anything, board.CreateRequest{Slug: "general", Name: "General"}).
+					Return(board.Board{}, fmt.Errorf("%w: duplicate key", database.ErrUniqueViolation))
+			},
+			wantStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mocks.NewStore(t)
+			tt.setupMock(m)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/api/boards", strings.NewReader(tt.body))
+
+			h := board.NewHandler(internal.NewValidator(), zap.NewNop(), m)
+			h.CreateHandler(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
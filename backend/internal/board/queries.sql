@@ -0,0 +1,11 @@
+-- name: FindAll :many
+SELECT * FROM boards ORDER BY slug;
+
+-- name: FindByID :one
+SELECT * FROM boards WHERE id = $1;
+
+-- name: FindBySlug :one
+SELECT * FROM boards WHERE slug = $1;
+
+-- name: Create :one
+INSERT INTO boards (slug, name, description) VALUES ($1, $2, $3) RETURNING *;
\ No newline at end of file
@@ -0,0 +1,100 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: queries.sql
+
+package board
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const create = `-- name: Create :one
+INSERT INTO boards (slug, name, description) VALUES ($1, $2, $3) RETURNING id, slug, name, description, created_at
+`
+
+type CreateParams struct {
+	Slug        string
+	Name        string
+	Description pgtype.Text
+}
+
+func (q *Queries) Create(ctx context.Context, arg CreateParams) (Board, error) {
+	row := q.db.QueryRow(ctx, create, arg.Slug, arg.Name, arg.Description)
+	var i Board
+	err := row.Scan(
+		&i.ID,
+		&i.Slug,
+		&i.Name,
+		&i.Description,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const findAll = `-- name: FindAll :many
+SELECT id, slug, name, description, created_at FROM boards ORDER BY slug
+`
+
+func (q *Queries) FindAll(ctx context.Context) ([]Board, error) {
+	rows, err := q.db.Query(ctx, findAll)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Board
+	for rows.Next() {
+		var i Board
+		if err := rows.Scan(
+			&i.ID,
+			&i.Slug,
+			&i.Name,
+			&i.Description,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findByID = `-- name: FindByID :one
+SELECT id, slug, name, description, created_at FROM boards WHERE id = $1
+`
+
+func (q *Queries) FindByID(ctx context.Context, id uuid.UUID) (Board, error) {
+	row := q.db.QueryRow(ctx, findByID, id)
+	var i Board
+	err := row.Scan(
+		&i.ID,
+		&i.Slug,
+		&i.Name,
+		&i.Description,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const findBySlug = `-- name: FindBySlug :one
+SELECT id, slug, name, description, created_at FROM boards WHERE slug = $1
+`
+
+func (q *Queries) FindBySlug(ctx context.Context, slug string) (Board, error) {
+	row := q.db.QueryRow(ctx, findBySlug, slug)
+	var i Board
+	err := row.Scan(
+		&i.ID,
+		&i.Slug,
+		&i.Name,
+		&i.Description,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,90 @@
+package board
+
+import (
+	"backend/internal"
+	"backend/internal/database"
+	"context"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+type Service struct {
+	logger *zap.Logger
+	tracer trace.Tracer
+	query  *Queries
+}
+
+func NewService(logger *zap.Logger, db DBTX) *Service {
+	return &Service{
+		logger: logger,
+		tracer: otel.Tracer("board/service"),
+		query:  New(db),
+	}
+}
+
+func (s *Service) GetAll(ctx context.Context) ([]Board, error) {
+	traceCtx, span := s.tracer.Start(ctx, "GetAll")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	boards, err := s.query.FindAll(traceCtx)
+	if err != nil {
+		err = database.WrapDBError(err, logger, "get all boards")
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return boards, nil
+}
+
+func (s *Service) GetByID(ctx context.Context, id uuid.UUID) (Board, error) {
+	traceCtx, span := s.tracer.Start(ctx, "GetByID")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	board, err := s.query.FindByID(traceCtx, id)
+	if err != nil {
+		err = database.WrapDBErrorWithKeyValue(err, "boards", "id", id.String(), logger, "get board by id")
+		span.RecordError(err)
+		return Board{}, err
+	}
+
+	return board, nil
+}
+
+func (s *Service) GetBySlug(ctx context.Context, slug string) (Board, error) {
+	traceCtx, span := s.tracer.Start(ctx, "GetBySlug")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	board, err := s.query.FindBySlug(traceCtx, slug)
+	if err != nil {
+		err = database.WrapDBErrorWithKeyValue(err, "boards", "slug", slug, logger, "get board by slug")
+		span.RecordError(err)
+		return Board{}, err
+	}
+
+	return board, nil
+}
+
+func (s *Service) Create(ctx context.Context, r CreateRequest) (Board, error) {
+	traceCtx, span := s.tracer.Start(ctx, "Create")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	board, err := s.query.Create(traceCtx, CreateParams{
+		Slug:        r.Slug,
+		Name:        r.Name,
+		Description: pgtype.Text{String: r.Description, Valid: r.Description != ""},
+	})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "create board")
+		span.RecordError(err)
+		return Board{}, err
+	}
+
+	return board, nil
+}
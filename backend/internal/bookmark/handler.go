@@ -0,0 +1,169 @@
+package bookmark
+
+import (
+	"backend/internal"
+	errorPkg "backend/internal/error"
+	"backend/internal/jwt"
+	"backend/internal/problem"
+	"context"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+// Request is optional, a bookmark without body has no note
+type Request struct {
+	Note string `json:"note" validate:"max=2000"`
+}
+
+type Response struct {
+	PostID string `json:"post_id"`
+	// Title and AuthorID describe the saved post, they are only set in bookmark lists
+	Title     string `json:"title,omitempty"`
+	AuthorID  string `json:"author_id,omitempty"`
+	Note      string `json:"note,omitempty"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+//go:generate mockery --name=Store
+type Store interface {
+	Save(ctx context.Context, userID, postID uuid.UUID, note string) (Bookmark, error)
+	Remove(ctx context.Context, userID, postID uuid.UUID) error
+	GetByUser(ctx context.Context, userID uuid.UUID, pagination internal.Pagination) ([]Entry, error)
+}
+
+type Handler struct {
+	validator *validator.Validate
+	logger    *zap.Logger
+	tracer    trace.Tracer
+	store     Store
+}
+
+func NewHandler(v *validator.Validate, logger *zap.Logger, store Store) *Handler {
+	return &Handler{
+		validator: v,
+		logger:    logger,
+		tracer:    otel.Tracer("bookmark/handler"),
+		store:     store,
+	}
+}
+
+// GetAllHandler lists the bookmarks of the current user, most recently saved first
+func (h *Handler) GetAllHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "GetAllBookmarksEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	userID, err := jwt.GetUserIDFromContext(traceCtx)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	pagination, err := internal.ParsePagination(r)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	entries, err := h.store.GetByUser(traceCtx, userID, pagination)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	response := make([]Response, len(entries))
+	for i, entry := range entries {
+		response[i] = GenerateEntryResponse(entry)
+	}
+
+	internal.WriteJSONResponse(w, http.StatusOK, response)
+}
+
+// SaveHandler bookmarks the post, saving it again replaces the note
+func (h *Handler) SaveHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "SaveBookmarkEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	userID, err := jwt.GetUserIDFromContext(traceCtx)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	postID, err := internal.ParseUUID(r.PathValue("id"))
+	if err != nil {
+		problem.WriteError(traceCtx, w, fmt.Errorf("%w: %v", errorPkg.ErrInvalidUUID, err), logger)
+		return
+	}
+
+	var request Request
+	if r.ContentLength != 0 {
+		err = internal.ParseAndValidateRequestBody(traceCtx, h.validator, r, &request)
+		if err != nil {
+			problem.WriteError(traceCtx, w, err, logger)
+			return
+		}
+	}
+
+	bookmark, err := h.store.Save(traceCtx, userID, postID, request.Note)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	internal.WriteJSONResponse(w, http.StatusOK, GenerateResponse(bookmark))
+}
+
+func (h *Handler) RemoveHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "RemoveBookmarkEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	userID, err := jwt.GetUserIDFromContext(traceCtx)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	postID, err := internal.ParseUUID(r.PathValue("id"))
+	if err != nil {
+		problem.WriteError(traceCtx, w, fmt.Errorf("%w: %v", errorPkg.ErrInvalidUUID, err), logger)
+		return
+	}
+
+	err = h.store.Remove(traceCtx, userID, postID)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func GenerateResponse(bookmark Bookmark) Response {
+	return Response{
+		PostID:    bookmark.PostID.String(),
+		Note:      bookmark.Note.String,
+		CreatedAt: bookmark.CreatedAt.Time.Format(time.RFC3339),
+		UpdatedAt: bookmark.UpdatedAt.Time.Format(time.RFC3339),
+	}
+}
+
+func GenerateEntryResponse(entry Entry) Response {
+	return Response{
+		PostID:    entry.PostID.String(),
+		Title:     entry.Title.String,
+		AuthorID:  entry.AuthorID.String(),
+		Note:      entry.Note.String,
+		CreatedAt: entry.CreatedAt.Time.Format(time.RFC3339),
+		UpdatedAt: entry.UpdatedAt.Time.Format(time.RFC3339),
+	}
+}
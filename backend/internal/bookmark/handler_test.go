@@ -0,0 +1,156 @@
+package bookmark_test
+
+import (
+	"backend/internal"
+	"backend/internal/bookmark"
+	"backend/internal/bookmark/mocks"
+	errorPkg "backend/internal/error"
+	"backend/internal/jwt"
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	user = jwt.User{
+		ID:       "81c1ecc1-66d7-4134-b5fe-d886a6f418a4",
+		Username: "reader",
+		Role:     jwt.RoleUser,
+	}
+	postID = uuid.MustParse("1f0b3c8e-3b0a-4d8e-9f3e-9b1b0f6b7c11")
+)
+
+func TestHandler_SaveHandler(t *testing.T) {
+	saved := bookmark.Bookmark{
+		UserID:    uuid.MustParse(user.ID),
+		PostID:    postID,
+		Note:      pgtype.Text{String: "read later", Valid: true},
+		CreatedAt: pgtype.Timestamptz{Time: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), Valid: true},
+		UpdatedAt: pgtype.Timestamptz{Time: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), Valid: true},
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		setupMock  func(m *mocks.Store)
+		wantStatus int
+	}{
+		{
+			name: "Should save bookmark with note",
+			body: `{"note":"read later"}`,
+			setupMock: func(m *mocks.Store) {
+				m.On("Save", mock.Anything, uuid.MustParse(user.ID), postID, "read later").Return(saved, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Should save bookmark without body",
+			setupMock: func(m *mocks.Store) {
+				m.On("Save", mock.Anything, uuid.MustParse(user.ID), postID, "").Return(saved, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "Should reject too long note",
+			body:       `{"note":"` + strings.Repeat("a", 2001) + `"}`,
+			setupMock:  func(m *mocks.Store) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Should return not found for invisible post",
+			setupMock: func(m *mocks.Store) {
+				m.On("Save", mock.Anything, uuid.MustParse(user.ID), postID, "").
+					Return(bookmark.Bookmark{}, errorPkg.NewNotFoundError("post", "id", postID.String(), ""))
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mocks.NewStore(t)
+			tt.setupMock(m)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPut, "/api/post/"+postID.String()+"/bookmark", strings.NewReader(tt.body))
+			r.SetPathValue("id", postID.String())
+			r = r.WithContext(context.WithValue(r.Context(), internal.UserContextKey, user))
+
+			h := bookmark.NewHandler(internal.NewValidator(), zap.NewNop(), m)
+			h.SaveHandler(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestHandler_GetAllHandler(t *testing.T) {
+	entry := bookmark.Entry{
+		PostID:    postID,
+		Title:     pgtype.Text{String: "Release notes", Valid: true},
+		AuthorID:  uuid.MustParse("7942c917-4770-43c1-a56a-952186b9970e"),
+		CreatedAt: pgtype.Timestamptz{Time: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), Valid: true},
+		UpdatedAt: pgtype.Timestamptz{Time: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), Valid: true},
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		setupMock  func(m *mocks.Store)
+		wantStatus int
+		wantCount  int
+	}{
+		{
+			name:  "Should list bookmarks of requested page",
+			query: "?page=2&size=10",
+			setupMock: func(m *mocks.Store) {
+				m.On("GetByUser", mock.Anything, uuid.MustParse(user.ID), internal.Pagination{Page: 2, Size: 10}).
+					Return([]bookmark.Entry{entry}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantCount:  1,
+		},
+		{
+			name:       "Should reject invalid page size",
+			query:      "?size=1000",
+			setupMock:  func(m *mocks.Store) {},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mocks.NewStore(t)
+			tt.setupMock(m)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/api/me/bookmarks"+tt.query, nil)
+			r = r.WithContext(context.WithValue(r.Context(), internal.UserContextKey, user))
+
+			h := bookmark.NewHandler(internal.NewValidator(), zap.NewNop(), m)
+			h.GetAllHandler(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var response []bookmark.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if assert.Len(t, response, tt.wantCount) {
+				assert.Equal(t, "Release notes", response[0].Title)
+			}
+		})
+	}
+}
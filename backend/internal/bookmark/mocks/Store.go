@@ -0,0 +1,109 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	bookmark "backend/internal/bookmark"
+	context "context"
+
+	internal "backend/internal"
+
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// Store is an autogenerated mock type for the Store type
+type Store struct {
+	mock.Mock
+}
+
+// GetByUser provides a mock function with given fields: ctx, userID, pagination
+func (_m *Store) GetByUser(ctx context.Context, userID uuid.UUID, pagination internal.Pagination) ([]bookmark.FindByUserRow, error) {
+	ret := _m.Called(ctx, userID, pagination)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByUser")
+	}
+
+	var r0 []bookmark.FindByUserRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, internal.Pagination) ([]bookmark.FindByUserRow, error)); ok {
+		return rf(ctx, userID, pagination)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, internal.Pagination) []bookmark.FindByUserRow); ok {
+		r0 = rf(ctx, userID, pagination)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]bookmark.FindByUserRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, internal.Pagination) error); ok {
+		r1 = rf(ctx, userID, pagination)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Remove provides a mock function with given fields: ctx, userID, postID
+func (_m *Store) Remove(ctx context.Context, userID uuid.UUID, postID uuid.UUID) error {
+	ret := _m.Called(ctx, userID, postID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Remove")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(ctx, userID, postID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Save provides a mock function with given fields: ctx, userID, postID, note
+func (_m *Store) Save(ctx context.Context, userID uuid.UUID, postID uuid.UUID, note string) (bookmark.Bookmark, error) {
+	ret := _m.Called(ctx, userID, postID, note)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 bookmark.Bookmark
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, string) (bookmark.Bookmark, error)); ok {
+		return rf(ctx, userID, postID, note)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, string) bookmark.Bookmark); ok {
+		r0 = rf(ctx, userID, postID, note)
+	} else {
+		r0 = ret.Get(0).(bookmark.Bookmark)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID, string) error); ok {
+		r1 = rf(ctx, userID, postID, note)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewStore creates a new instance of Store. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Store {
+	mock := &Store{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,248 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+
+package bookmark
+
+import (
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Attachment struct {
+	ID          uuid.UUID
+	PostID      pgtype.UUID
+	UploaderID  uuid.UUID
+	Filename    string
+	ContentType string
+	Size        int64
+	StorageKey  string
+	CreatedAt   pgtype.Timestamptz
+}
+
+type AuditLog struct {
+	ID         int64
+	ActorID    pgtype.UUID
+	Action     string
+	TargetType string
+	TargetID   pgtype.UUID
+	Details    pgtype.Text
+	IpAddress  pgtype.Text
+	TraceID    pgtype.Text
+	CreatedAt  pgtype.Timestamptz
+}
+
+type Board struct {
+	ID          uuid.UUID
+	Slug        string
+	Name        string
+	Description pgtype.Text
+	CreatedAt   pgtype.Timestamptz
+}
+
+type Bookmark struct {
+	UserID    uuid.UUID
+	PostID    uuid.UUID
+	Note      pgtype.Text
+	CreatedAt pgtype.Timestamptz
+	UpdatedAt pgtype.Timestamptz
+}
+
+type ClientCertificate struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	Name        string
+	Fingerprint string
+	Subject     string
+	ExpiresAt   pgtype.Timestamptz
+	CreatedAt   pgtype.Timestamptz
+	LastUsedAt  pgtype.Timestamptz
+}
+
+type Comment struct {
+	ID        uuid.UUID
+	PostID    uuid.UUID
+	AuthorID  uuid.UUID
+	Title     pgtype.Text
+	Content   pgtype.Text
+	CreatedAt pgtype.Timestamptz
+	UpdatedAt pgtype.Timestamptz
+	ParentID  pgtype.UUID
+	HiddenAt  pgtype.Timestamptz
+}
+
+type Conversation struct {
+	ID        uuid.UUID
+	CreatedBy uuid.UUID
+	CreatedAt pgtype.Timestamptz
+	UpdatedAt pgtype.Timestamptz
+}
+
+type ConversationMember struct {
+	ConversationID uuid.UUID
+	UserID         uuid.UUID
+	JoinedAt       pgtype.Timestamptz
+}
+
+type Event struct {
+	ID        int64
+	Type      string
+	Payload   []byte
+	CreatedAt pgtype.Timestamptz
+}
+
+type Follow struct {
+	FollowerID uuid.UUID
+	FolloweeID uuid.UUID
+	CreatedAt  pgtype.Timestamptz
+}
+
+type Mention struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	PostID      uuid.UUID
+	CommentID   pgtype.UUID
+	StartOffset int32
+	EndOffset   int32
+	CreatedAt   pgtype.Timestamptz
+}
+
+type Message struct {
+	ID             uuid.UUID
+	ConversationID uuid.UUID
+	AuthorID       uuid.UUID
+	Content        string
+	CreatedAt      pgtype.Timestamptz
+}
+
+type NntpArticle struct {
+	BoardID   uuid.UUID
+	Number    int64
+	PostID    uuid.UUID
+	CommentID pgtype.UUID
+}
+
+type NntpGroup struct {
+	BoardID    uuid.UUID
+	LastNumber int64
+}
+
+type Notification struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Type      string
+	ActorID   uuid.UUID
+	PostID    uuid.UUID
+	CommentID pgtype.UUID
+	ReadAt    pgtype.Timestamptz
+	CreatedAt pgtype.Timestamptz
+}
+
+type Poll struct {
+	ID                uuid.UUID
+	PostID            uuid.UUID
+	Question          string
+	MultipleChoice    bool
+	ResultsVisibility string
+	ClosesAt          pgtype.Timestamptz
+	CreatedAt         pgtype.Timestamptz
+}
+
+type PollBallot struct {
+	PollID    uuid.UUID
+	UserID    uuid.UUID
+	CreatedAt pgtype.Timestamptz
+}
+
+type PollOption struct {
+	ID       uuid.UUID
+	PollID   uuid.UUID
+	Position int32
+	Label    string
+}
+
+type PollVote struct {
+	PollID   uuid.UUID
+	UserID   uuid.UUID
+	OptionID uuid.UUID
+}
+
+type Post struct {
+	ID         uuid.UUID
+	AuthorID   uuid.UUID
+	Title      pgtype.Text
+	Content    pgtype.Text
+	CreateAt   pgtype.Timestamptz
+	UpdatedAt  pgtype.Timestamptz
+	BoardID    pgtype.UUID
+	HiddenAt   pgtype.Timestamptz
+	LockedAt   pgtype.Timestamptz
+	PinnedAt   pgtype.Timestamptz
+	PinScope   pgtype.Text
+	ArchivedAt pgtype.Timestamptz
+	Status     string
+	PublishAt  pgtype.Timestamptz
+	Tags       []string
+}
+
+type ReadMarker struct {
+	UserID     uuid.UUID
+	PostID     uuid.UUID
+	LastReadAt pgtype.Timestamptz
+}
+
+type Report struct {
+	ID             uuid.UUID
+	PostID         uuid.UUID
+	CommentID      pgtype.UUID
+	ReporterID     pgtype.UUID
+	Reason         string
+	Details        pgtype.Text
+	CreatedAt      pgtype.Timestamptz
+	Resolution     pgtype.Text
+	ResolutionNote pgtype.Text
+	ResolvedBy     pgtype.UUID
+	ResolvedAt     pgtype.Timestamptz
+}
+
+type SshKey struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	Name        string
+	PublicKey   string
+	Fingerprint string
+	CreatedAt   pgtype.Timestamptz
+	LastUsedAt  pgtype.Timestamptz
+}
+
+type User struct {
+	ID             uuid.UUID
+	Name           string
+	Password       string
+	Role           string
+	SuspendedAt    pgtype.Timestamptz
+	SuspendedUntil pgtype.Timestamptz
+	BanReason      pgtype.Text
+	CreatedAt      pgtype.Timestamptz
+}
+
+type UserBlock struct {
+	BlockerID uuid.UUID
+	BlockedID uuid.UUID
+	CreatedAt pgtype.Timestamptz
+}
+
+type UserMute struct {
+	MuterID   uuid.UUID
+	MutedID   uuid.UUID
+	CreatedAt pgtype.Timestamptz
+}
+
+type Watch struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	PostID    pgtype.UUID
+	BoardID   pgtype.UUID
+	Level     string
+	CreatedAt pgtype.Timestamptz
+}
@@ -0,0 +1,20 @@
+-- name: Upsert :one
+-- Only posts the user can see are saved, bookmarking again replaces the note
+INSERT INTO bookmarks (user_id, post_id, note)
+SELECT @user_id::uuid, p.id, @note
+FROM posts p
+WHERE p.id = @post_id::uuid AND p.hidden_at IS NULL AND (p.status = 'published' OR p.author_id = @user_id::uuid)
+ON CONFLICT (user_id, post_id) DO UPDATE SET note = EXCLUDED.note, updated_at = now()
+RETURNING *;
+
+-- name: Delete :execrows
+DELETE FROM bookmarks WHERE user_id = @user_id AND post_id = @post_id;
+
+-- name: FindByUser :many
+-- Most recently saved first, bookmarks of posts that were hidden since are left out
+SELECT b.post_id, b.note, b.created_at, b.updated_at, p.title, p.author_id
+FROM bookmarks b
+JOIN posts p ON p.id = b.post_id
+WHERE b.user_id = @user_id AND p.hidden_at IS NULL AND (p.status = 'published' OR p.author_id = @user_id)
+ORDER BY b.created_at DESC, b.post_id
+LIMIT @size OFFSET @skip;
@@ -0,0 +1,111 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: queries.sql
+
+package bookmark
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const delete = `-- name: Delete :execrows
+DELETE FROM bookmarks WHERE user_id = $1 AND post_id = $2
+`
+
+type DeleteParams struct {
+	UserID uuid.UUID
+	PostID uuid.UUID
+}
+
+func (q *Queries) Delete(ctx context.Context, arg DeleteParams) (int64, error) {
+	result, err := q.db.Exec(ctx, delete, arg.UserID, arg.PostID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const findByUser = `-- name: FindByUser :many
+SELECT b.post_id, b.note, b.created_at, b.updated_at, p.title, p.author_id
+FROM bookmarks b
+JOIN posts p ON p.id = b.post_id
+WHERE b.user_id = $1 AND p.hidden_at IS NULL AND (p.status = 'published' OR p.author_id = $1)
+ORDER BY b.created_at DESC, b.post_id
+LIMIT $3 OFFSET $2
+`
+
+type FindByUserParams struct {
+	UserID uuid.UUID
+	Skip   int32
+	Size   int32
+}
+
+type FindByUserRow struct {
+	PostID    uuid.UUID
+	Note      pgtype.Text
+	CreatedAt pgtype.Timestamptz
+	UpdatedAt pgtype.Timestamptz
+	Title     pgtype.Text
+	AuthorID  uuid.UUID
+}
+
+// Most recently saved first, bookmarks of posts that were hidden since are left out
+func (q *Queries) FindByUser(ctx context.Context, arg FindByUserParams) ([]FindByUserRow, error) {
+	rows, err := q.db.Query(ctx, findByUser, arg.UserID, arg.Skip, arg.Size)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FindByUserRow
+	for rows.Next() {
+		var i FindByUserRow
+		if err := rows.Scan(
+			&i.PostID,
+			&i.Note,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.AuthorID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsert = `-- name: Upsert :one
+INSERT INTO bookmarks (user_id, post_id, note)
+SELECT $1::uuid, p.id, $2
+FROM posts p
+WHERE p.id = $3::uuid AND p.hidden_at IS NULL AND (p.status = 'published' OR p.author_id = $1::uuid)
+ON CONFLICT (user_id, post_id) DO UPDATE SET note = EXCLUDED.note, updated_at = now()
+RETURNING user_id, post_id, note, created_at, updated_at
+`
+
+type UpsertParams struct {
+	UserID uuid.UUID
+	Note   pgtype.Text
+	PostID uuid.UUID
+}
+
+// Only posts the user can see are saved, bookmarking again replaces the note
+func (q *Queries) Upsert(ctx context.Context, arg UpsertParams) (Bookmark, error) {
+	row := q.db.QueryRow(ctx, upsert, arg.UserID, arg.Note, arg.PostID)
+	var i Bookmark
+	err := row.Scan(
+		&i.UserID,
+		&i.PostID,
+		&i.Note,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,98 @@
+package bookmark
+
+import (
+	"backend/internal"
+	"backend/internal/database"
+	errorPkg "backend/internal/error"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// Entry is a bookmark together with the post it saves
+type Entry = FindByUserRow
+
+type Service struct {
+	logger *zap.Logger
+	tracer trace.Tracer
+	query  *Queries
+}
+
+func NewService(logger *zap.Logger, db DBTX) *Service {
+	return &Service{
+		logger: logger,
+		tracer: otel.Tracer("bookmark/service"),
+		query:  New(db),
+	}
+}
+
+// Save bookmarks the post for the user or replaces the note of an existing bookmark, an empty note removes it
+func (s *Service) Save(ctx context.Context, userID, postID uuid.UUID, note string) (Bookmark, error) {
+	traceCtx, span := s.tracer.Start(ctx, "Save")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	bookmark, err := s.query.Upsert(traceCtx, UpsertParams{
+		UserID: userID,
+		PostID: postID,
+		Note:   pgtype.Text{String: note, Valid: note != ""},
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// the post does not exist or the user cannot see it
+		err = errorPkg.NewNotFoundError("post", "id", postID.String(), "")
+		span.RecordError(err)
+		return Bookmark{}, err
+	}
+	if err != nil {
+		err = database.WrapDBError(err, logger, "save bookmark")
+		span.RecordError(err)
+		return Bookmark{}, err
+	}
+
+	return bookmark, nil
+}
+
+func (s *Service) Remove(ctx context.Context, userID, postID uuid.UUID) error {
+	traceCtx, span := s.tracer.Start(ctx, "Remove")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	count, err := s.query.Delete(traceCtx, DeleteParams{UserID: userID, PostID: postID})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "remove bookmark")
+		span.RecordError(err)
+		return err
+	}
+	if count == 0 {
+		err = errorPkg.NewNotFoundError("bookmarks", "post_id", postID.String(), "")
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetByUser lists the bookmarks of the user, most recently saved first
+func (s *Service) GetByUser(ctx context.Context, userID uuid.UUID, pagination internal.Pagination) ([]Entry, error) {
+	traceCtx, span := s.tracer.Start(ctx, "GetByUser")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	entries, err := s.query.FindByUser(traceCtx, FindByUserParams{
+		UserID: userID,
+		Skip:   pagination.Offset(),
+		Size:   pagination.Size,
+	})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "get bookmarks by user")
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return entries, nil
+}
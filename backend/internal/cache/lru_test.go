@@ -0,0 +1,104 @@
+package cache_test
+
+import (
+	"backend/internal/cache"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestLRU(t *testing.T) {
+	tests := []struct {
+		name      string
+		capacity  int
+		operation func(c *cache.LRU[string, int])
+		wantKeys  map[string]int
+		wantMiss  []string
+	}{
+		{
+			name:     "Should return added values",
+			capacity: 2,
+			operation: func(c *cache.LRU[string, int]) {
+				c.Add("a", 1)
+				c.Add("b", 2)
+			},
+			wantKeys: map[string]int{"a": 1, "b": 2},
+		},
+		{
+			name:     "Should evict least recently used entry when full",
+			capacity: 2,
+			operation: func(c *cache.LRU[string, int]) {
+				c.Add("a", 1)
+				c.Add("b", 2)
+				c.Get("a")
+				c.Add("c", 3)
+			},
+			wantKeys: map[string]int{"a": 1, "c": 3},
+			wantMiss: []string{"b"},
+		},
+		{
+			name:     "Should overwrite existing entry",
+			capacity: 2,
+			operation: func(c *cache.LRU[string, int]) {
+				c.Add("a", 1)
+				c.Add("a", 10)
+			},
+			wantKeys: map[string]int{"a": 10},
+		},
+		{
+			name:     "Should remove entry",
+			capacity: 2,
+			operation: func(c *cache.LRU[string, int]) {
+				c.Add("a", 1)
+				c.Add("b", 2)
+				c.Remove("a")
+			},
+			wantKeys: map[string]int{"b": 2},
+			wantMiss: []string{"a"},
+		},
+		{
+			name:     "Should clear all entries",
+			capacity: 2,
+			operation: func(c *cache.LRU[string, int]) {
+				c.Add("a", 1)
+				c.Add("b", 2)
+				c.Clear()
+				c.Add("c", 3)
+			},
+			wantKeys: map[string]int{"c": 3},
+			wantMiss: []string{"a", "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := cache.NewLRU[string, int](tt.capacity, 0)
+			tt.operation(c)
+
+			assert.Equal(t, len(tt.wantKeys), c.Len())
+			for key, want := range tt.wantKeys {
+				got, ok := c.Get(key)
+				assert.True(t, ok, "expected key %s to be cached", key)
+				assert.Equal(t, want, got)
+			}
+			for _, key := range tt.wantMiss {
+				_, ok := c.Get(key)
+				assert.False(t, ok, "expected key %s to be evicted", key)
+			}
+		})
+	}
+}
+
+func TestLRU_TTL(t *testing.T) {
+	c := cache.NewLRU[string, int](2, 20*time.Millisecond)
+	c.Add("a", 1)
+
+	got, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, got)
+
+	time.Sleep(30 * time.Millisecond)
+	_, ok = c.Get("a")
+	assert.False(t, ok, "expected key a to be expired")
+	assert.Equal(t, 0, c.Len())
+}
@@ -0,0 +1,150 @@
+package clientcert
+
+import (
+	"backend/internal"
+	errorPkg "backend/internal/error"
+	"backend/internal/jwt"
+	"backend/internal/problem"
+	"context"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+type Request struct {
+	Name string `json:"name" validate:"required,max=100"`
+	// Certificate is the PEM encoded certificate the Gemini client presents, without its private key
+	Certificate string `json:"certificate" validate:"required,max=16000"`
+}
+
+type Response struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Fingerprint string `json:"fingerprint"`
+	Subject     string `json:"subject"`
+	ExpiresAt   string `json:"expires_at"`
+	CreatedAt   string `json:"created_at"`
+	LastUsedAt  string `json:"last_used_at,omitempty"`
+}
+
+//go:generate mockery --name=Store
+type Store interface {
+	Add(ctx context.Context, userID uuid.UUID, name, certificate string) (ClientCertificate, error)
+	Remove(ctx context.Context, userID, id uuid.UUID) error
+	GetByUser(ctx context.Context, userID uuid.UUID) ([]ClientCertificate, error)
+}
+
+type Handler struct {
+	validator *validator.Validate
+	logger    *zap.Logger
+	tracer    trace.Tracer
+	store     Store
+}
+
+func NewHandler(v *validator.Validate, logger *zap.Logger, store Store) *Handler {
+	return &Handler{
+		validator: v,
+		logger:    logger,
+		tracer:    otel.Tracer("clientcert/handler"),
+		store:     store,
+	}
+}
+
+// GetAllHandler lists the certificates the current user signs in to the Gemini server with
+func (h *Handler) GetAllHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "GetAllClientCertificatesEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	userID, err := jwt.GetUserIDFromContext(traceCtx)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	certificates, err := h.store.GetByUser(traceCtx, userID)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	response := make([]Response, len(certificates))
+	for i, certificate := range certificates {
+		response[i] = GenerateResponse(certificate)
+	}
+
+	internal.WriteJSONResponse(w, http.StatusOK, response)
+}
+
+func (h *Handler) AddHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "AddClientCertificateEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	userID, err := jwt.GetUserIDFromContext(traceCtx)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	var request Request
+	err = internal.ParseAndValidateRequestBody(traceCtx, h.validator, r, &request)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	certificate, err := h.store.Add(traceCtx, userID, request.Name, request.Certificate)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	internal.WriteJSONResponse(w, http.StatusCreated, GenerateResponse(certificate))
+}
+
+func (h *Handler) RemoveHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "RemoveClientCertificateEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	userID, err := jwt.GetUserIDFromContext(traceCtx)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	id, err := internal.ParseUUID(r.PathValue("id"))
+	if err != nil {
+		problem.WriteError(traceCtx, w, fmt.Errorf("%w: %v", errorPkg.ErrInvalidUUID, err), logger)
+		return
+	}
+
+	err = h.store.Remove(traceCtx, userID, id)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func GenerateResponse(certificate ClientCertificate) Response {
+	response := Response{
+		ID:          certificate.ID.String(),
+		Name:        certificate.Name,
+		Fingerprint: certificate.Fingerprint,
+		Subject:     certificate.Subject,
+		ExpiresAt:   certificate.ExpiresAt.Time.Format(time.RFC3339),
+		CreatedAt:   certificate.CreatedAt.Time.Format(time.RFC3339),
+	}
+	if certificate.LastUsedAt.Valid {
+		response.LastUsedAt = certificate.LastUsedAt.Time.Format(time.RFC3339)
+	}
+	return response
+}
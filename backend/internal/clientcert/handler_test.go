@@ -0,0 +1,112 @@
+package clientcert_test
+
+import (
+	"backend/internal"
+	"backend/internal/clientcert"
+	"backend/internal/clientcert/mocks"
+	"backend/internal/database"
+	errorPkg "backend/internal/error"
+	"backend/internal/jwt"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var user = jwt.User{
+	ID:       "81c1ecc1-66d7-4134-b5fe-d886a6f418a4",
+	Username: "reader",
+	Role:     jwt.RoleUser,
+}
+
+const certificate = "-----BEGIN CERTIFICATE-----\nMIIBfzCCASWgAwIBAgIQYw==\n-----END CERTIFICATE-----"
+
+func TestHandler_AddHandler(t *testing.T) {
+	added := clientcert.ClientCertificate{
+		ID:          uuid.MustParse("1f0b3c8e-3b0a-4d8e-9f3e-9b1b0f6b7c11"),
+		UserID:      uuid.MustParse(user.ID),
+		Name:        "lagrange",
+		Fingerprint: "3f5e6b0c1d2a49887766554433221100ffeeddccbbaa99887766554433221100",
+		Subject:     "CN=reader",
+		ExpiresAt:   pgtype.Timestamptz{Time: time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC), Valid: true},
+		CreatedAt:   pgtype.Timestamptz{Time: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), Valid: true},
+	}
+	body, err := json.Marshal(clientcert.Request{Name: "lagrange", Certificate: certificate})
+	if err != nil {
+		t.Fatalf("Failed to encode request: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		setupMock  func(m *mocks.Store)
+		wantStatus int
+	}{
+		{
+			name: "Should add certificate",
+			body: string(body),
+			setupMock: func(m *mocks.Store) {
+				m.On("Add", mock.Anything, uuid.MustParse(user.ID), "lagrange", certificate).Return(added, nil)
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "Should reject missing certificate",
+			body:       `{"name":"lagrange"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Should reject malformed certificate",
+			body: `{"name":"lagrange","certificate":"garbage"}`,
+			setupMock: func(m *mocks.Store) {
+				m.On("Add", mock.Anything, uuid.MustParse(user.ID), "lagrange", "garbage").
+					Return(clientcert.ClientCertificate{}, fmt.Errorf("%w: malformed certificate", errorPkg.ErrInvalidRequest))
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Should reject certificate registered before",
+			body: string(body),
+			setupMock: func(m *mocks.Store) {
+				m.On("Add", mock.Anything, uuid.MustParse(user.ID), "lagrange", certificate).Return(clientcert.ClientCertificate{}, database.ErrUniqueViolation)
+			},
+			wantStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewStore(t)
+			if tt.setupMock != nil {
+				tt.setupMock(mockStore)
+			}
+			handler := clientcert.NewHandler(internal.NewValidator(), zap.NewNop(), mockStore)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/me/client-certificates", strings.NewReader(tt.body))
+			req = req.WithContext(context.WithValue(req.Context(), internal.UserContextKey, user))
+			rr := httptest.NewRecorder()
+			handler.AddHandler(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			if tt.wantStatus == http.StatusCreated {
+				var response clientcert.Response
+				err := json.Unmarshal(rr.Body.Bytes(), &response)
+				if err != nil {
+					t.Fatalf("Failed to decode response: %v", err)
+				}
+				assert.Equal(t, added.Fingerprint, response.Fingerprint)
+				assert.Equal(t, "2030-01-02T03:04:05Z", response.ExpiresAt)
+				assert.Empty(t, response.LastUsedAt)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+-- name: Create :one
+INSERT INTO client_certificates (user_id, name, fingerprint, subject, expires_at) VALUES ($1, $2, $3, $4, $5) RETURNING *;
+
+-- name: Delete :execrows
+DELETE FROM client_certificates WHERE id = @id AND user_id = @user_id;
+
+-- name: FindByUser :many
+SELECT * FROM client_certificates WHERE user_id = $1 ORDER BY created_at, id;
+
+-- name: Use :one
+-- Looks up the certificate of a request and remembers when it was last used
+UPDATE client_certificates SET last_used_at = now() WHERE fingerprint = $1 RETURNING *;
@@ -0,0 +1,118 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: queries.sql
+
+package clientcert
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const create = `-- name: Create :one
+INSERT INTO client_certificates (user_id, name, fingerprint, subject, expires_at) VALUES ($1, $2, $3, $4, $5) RETURNING id, user_id, name, fingerprint, subject, expires_at, created_at, last_used_at
+`
+
+type CreateParams struct {
+	UserID      uuid.UUID
+	Name        string
+	Fingerprint string
+	Subject     string
+	ExpiresAt   pgtype.Timestamptz
+}
+
+func (q *Queries) Create(ctx context.Context, arg CreateParams) (ClientCertificate, error) {
+	row := q.db.QueryRow(ctx, create,
+		arg.UserID,
+		arg.Name,
+		arg.Fingerprint,
+		arg.Subject,
+		arg.ExpiresAt,
+	)
+	var i ClientCertificate
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Fingerprint,
+		&i.Subject,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+	)
+	return i, err
+}
+
+const delete = `-- name: Delete :execrows
+DELETE FROM client_certificates WHERE id = $1 AND user_id = $2
+`
+
+type DeleteParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) Delete(ctx context.Context, arg DeleteParams) (int64, error) {
+	result, err := q.db.Exec(ctx, delete, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const findByUser = `-- name: FindByUser :many
+SELECT id, user_id, name, fingerprint, subject, expires_at, created_at, last_used_at FROM client_certificates WHERE user_id = $1 ORDER BY created_at, id
+`
+
+func (q *Queries) FindByUser(ctx context.Context, userID uuid.UUID) ([]ClientCertificate, error) {
+	rows, err := q.db.Query(ctx, findByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ClientCertificate
+	for rows.Next() {
+		var i ClientCertificate
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.Fingerprint,
+			&i.Subject,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.LastUsedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const use = `-- name: Use :one
+UPDATE client_certificates SET last_used_at = now() WHERE fingerprint = $1 RETURNING id, user_id, name, fingerprint, subject, expires_at, created_at, last_used_at
+`
+
+// Looks up the certificate of a request and remembers when it was last used
+func (q *Queries) Use(ctx context.Context, fingerprint string) (ClientCertificate, error) {
+	row := q.db.QueryRow(ctx, use, fingerprint)
+	var i ClientCertificate
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Fingerprint,
+		&i.Subject,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS client_certificates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    -- fingerprint is the hex encoded SHA-256 hash of the DER certificate and identifies the user on Gemini requests,
+    -- the certificates are usually self-signed so nothing but the fingerprint is trusted
+    fingerprint CHAR(64) UNIQUE NOT NULL,
+    subject TEXT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now() NOT NULL,
+    last_used_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS client_certificates_user_idx ON client_certificates (user_id, created_at);
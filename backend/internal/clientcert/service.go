@@ -0,0 +1,138 @@
+package clientcert
+
+import (
+	"backend/internal"
+	"backend/internal/database"
+	errorPkg "backend/internal/error"
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+type Service struct {
+	logger *zap.Logger
+	tracer trace.Tracer
+	query  *Queries
+}
+
+func NewService(logger *zap.Logger, db DBTX) *Service {
+	return &Service{
+		logger: logger,
+		tracer: otel.Tracer("clientcert/service"),
+		query:  New(db),
+	}
+}
+
+// Fingerprint is the hex encoded SHA-256 hash of the DER encoded certificate
+func Fingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// Add registers a PEM encoded client certificate for the user, a certificate can only belong to one user
+func (s *Service) Add(ctx context.Context, userID uuid.UUID, name, certificate string) (ClientCertificate, error) {
+	traceCtx, span := s.tracer.Start(ctx, "Add")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	cert, err := parseCertificate(certificate)
+	if err != nil {
+		err = fmt.Errorf("%w: malformed certificate: %v", errorPkg.ErrInvalidRequest, err)
+		span.RecordError(err)
+		return ClientCertificate{}, err
+	}
+	if time.Now().After(cert.NotAfter) {
+		err = fmt.Errorf("%w: the certificate expired on %s", errorPkg.ErrInvalidRequest, cert.NotAfter.Format(time.DateOnly))
+		span.RecordError(err)
+		return ClientCertificate{}, err
+	}
+
+	clientCertificate, err := s.query.Create(traceCtx, CreateParams{
+		UserID:      userID,
+		Name:        name,
+		Fingerprint: Fingerprint(cert),
+		Subject:     cert.Subject.String(),
+		ExpiresAt:   pgtype.Timestamptz{Time: cert.NotAfter, Valid: true},
+	})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "add client certificate")
+		span.RecordError(err)
+		return ClientCertificate{}, err
+	}
+
+	return clientCertificate, nil
+}
+
+func (s *Service) Remove(ctx context.Context, userID, id uuid.UUID) error {
+	traceCtx, span := s.tracer.Start(ctx, "Remove")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	count, err := s.query.Delete(traceCtx, DeleteParams{ID: id, UserID: userID})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "remove client certificate")
+		span.RecordError(err)
+		return err
+	}
+	if count == 0 {
+		err = errorPkg.NewNotFoundError("client_certificates", "id", id.String(), "")
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetByUser lists the certificates of the user, oldest first
+func (s *Service) GetByUser(ctx context.Context, userID uuid.UUID) ([]ClientCertificate, error) {
+	traceCtx, span := s.tracer.Start(ctx, "GetByUser")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	certificates, err := s.query.FindByUser(traceCtx, userID)
+	if err != nil {
+		err = database.WrapDBError(err, logger, "get client certificates by user")
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return certificates, nil
+}
+
+// Authenticate returns the user the certificate is registered for and marks the certificate as used, the validity
+// period of the certificate is checked by the caller
+func (s *Service) Authenticate(ctx context.Context, cert *x509.Certificate) (uuid.UUID, error) {
+	traceCtx, span := s.tracer.Start(ctx, "Authenticate")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	fingerprint := Fingerprint(cert)
+	clientCertificate, err := s.query.Use(traceCtx, fingerprint)
+	if err != nil {
+		err = database.WrapDBErrorWithKeyValue(err, "client_certificates", "fingerprint", fingerprint, logger, "use client certificate")
+		span.RecordError(err)
+		return uuid.Nil, err
+	}
+
+	return clientCertificate.UserID, nil
+}
+
+// parseCertificate decodes the first certificate of PEM data
+func parseCertificate(data string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(strings.TrimSpace(data)))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("expected a PEM encoded CERTIFICATE block")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
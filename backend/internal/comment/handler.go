@@ -4,6 +4,8 @@ import (
 	"backend/internal"
 	errorPkg "backend/internal/error"
 	"backend/internal/jwt"
+	"backend/internal/markdown"
+	"backend/internal/mention"
 	"backend/internal/problem"
 	"context"
 	"fmt"
@@ -18,10 +20,14 @@ import (
 
 //go:generate mockery --name=Store
 type Store interface {
-	GetAll(ctx context.Context) ([]Comment, error)
+	GetAll(ctx context.Context, viewerID uuid.UUID) ([]Listed, error)
 	GetById(ctx context.Context, id uuid.UUID) (Comment, error)
-	GetByPost(ctx context.Context, postId uuid.UUID) ([]Comment, error)
+	GetByPost(ctx context.Context, viewerID, postId uuid.UUID) ([]Listed, error)
+	GetUnreadByPost(ctx context.Context, userID, postID uuid.UUID) ([]Listed, error)
 	Create(ctx context.Context, arg CreateRequest) (Comment, error)
+	Update(ctx context.Context, id uuid.UUID, arg UpdateRequest) (Comment, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	GetMentions(ctx context.Context, commentIDs []uuid.UUID) (map[uuid.UUID][]mention.Span, error)
 }
 
 type Handler struct {
@@ -41,19 +47,37 @@ func NewHandler(validator *validator.Validate, logger *zap.Logger, store Store)
 }
 
 type CreateRequest struct {
-	PostID   uuid.UUID `json:"post_id"`
-	AuthorID uuid.UUID `json:"author_id"`
-	Title    string    `json:"title" validate:"required"`
-	Content  string    `json:"content" validate:"required"`
+	PostID   uuid.UUID  `json:"post_id"`
+	AuthorID uuid.UUID  `json:"author_id"`
+	ParentID *uuid.UUID `json:"parent_id,omitempty"`
+	Title    string     `json:"title" validate:"required"`
+	Content  string     `json:"content" validate:"required"`
+}
+
+type UpdateRequest struct {
+	Title   string `json:"title" validate:"required"`
+	Content string `json:"content" validate:"required"`
 }
 
 type Response struct {
 	ID        string `json:"id"`
 	PostId    string `json:"post_id"`
 	AuthorId  string `json:"author_id"`
+	ParentId  string `json:"parent_id,omitempty"`
 	Title     string `json:"title"`
 	Content   string `json:"content"`
 	CreatedAt string `json:"created_at"`
+	// Rendered is Content in the format of the render query parameter, html or ansi
+	Rendered string `json:"rendered,omitempty"`
+
+	// Held comments were stopped by the content filter and stay hidden until a moderator approves them
+	Held bool `json:"held,omitempty"`
+
+	// Mentions are the resolved @mentions in Content, for clients to highlight
+	Mentions []mention.Span `json:"mentions,omitempty"`
+
+	// Collapsed comments are by authors the current user muted or blocked, only set in comment lists
+	Collapsed bool `json:"collapsed,omitempty"`
 }
 
 func (h *Handler) GetAllHandler(w http.ResponseWriter, r *http.Request) {
@@ -61,7 +85,19 @@ func (h *Handler) GetAllHandler(w http.ResponseWriter, r *http.Request) {
 	defer span.End()
 	logger := internal.LoggerWithContext(traceCtx, h.logger)
 
-	commentList, err := h.store.GetAll(traceCtx)
+	format, err := markdown.ParseFormat(r)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	userID, err := jwt.GetUserIDFromContext(traceCtx)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	commentList, err := h.store.GetAll(traceCtx, userID)
 
 	// Handle error if fetching comment list fails
 	if err != nil {
@@ -71,9 +107,10 @@ func (h *Handler) GetAllHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Convert commentList to Response
-	response := make([]Response, len(commentList))
-	for i, comment := range commentList {
-		response[i] = GenerateResponse(comment)
+	response, err := h.generateListResponses(traceCtx, format, commentList)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
 	}
 
 	internal.WriteJSONResponse(w, http.StatusOK, response)
@@ -84,6 +121,12 @@ func (h *Handler) GetByIdHandler(w http.ResponseWriter, r *http.Request) {
 	defer span.End()
 	logger := internal.LoggerWithContext(traceCtx, h.logger)
 
+	format, err := markdown.ParseFormat(r)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
 	commentID := r.PathValue("id")
 
 	// Verify and transform ID to UUID
@@ -102,16 +145,28 @@ func (h *Handler) GetByIdHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Convert comment to Response
-	response := GenerateResponse(comment)
+	response, err := h.generateResponses(traceCtx, format, comment)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
 
-	internal.WriteJSONResponse(w, http.StatusOK, response)
+	internal.WriteJSONResponse(w, http.StatusOK, response[0])
 }
 
+// GetByPostHandler lists the comments of a post oldest first. With from=unread the list starts at the first comment
+// the current user has not read.
 func (h *Handler) GetByPostHandler(w http.ResponseWriter, r *http.Request) {
 	traceCtx, span := h.tracer.Start(r.Context(), "GetCommentByPostEndpoint")
 	defer span.End()
 	logger := internal.LoggerWithContext(traceCtx, h.logger)
 
+	format, err := markdown.ParseFormat(r)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
 	postID := r.PathValue("post_id")
 
 	// Verify and transform ID to UUID
@@ -122,7 +177,22 @@ func (h *Handler) GetByPostHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	comments, err := h.store.GetByPost(traceCtx, id)
+	userID, err := jwt.GetUserIDFromContext(traceCtx)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	var comments []Listed
+	switch from := r.URL.Query().Get("from"); from {
+	case "":
+		comments, err = h.store.GetByPost(traceCtx, userID, id)
+	case "unread":
+		comments, err = h.store.GetUnreadByPost(traceCtx, userID, id)
+	default:
+		problem.WriteError(traceCtx, w, fmt.Errorf("%w: from must be unread", errorPkg.ErrInvalidQuery), logger)
+		return
+	}
 	if err != nil {
 		logger.Error("Error fetching comments by post id", zap.Error(err), zap.String("post_id", id.String()))
 		problem.WriteError(traceCtx, w, err, logger)
@@ -130,9 +200,10 @@ func (h *Handler) GetByPostHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Convert comments to Response
-	response := make([]Response, len(comments))
-	for i, comment := range comments {
-		response[i] = GenerateResponse(comment)
+	response, err := h.generateListResponses(traceCtx, format, comments)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
 	}
 
 	internal.WriteJSONResponse(w, http.StatusOK, response)
@@ -143,9 +214,15 @@ func (h *Handler) CreateHandler(w http.ResponseWriter, r *http.Request) {
 	defer span.End()
 	logger := internal.LoggerWithContext(traceCtx, h.logger)
 
+	format, err := markdown.ParseFormat(r)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
 	// Parse and validate requestBody body
 	var req CreateRequest
-	err := internal.ParseAndValidateRequestBody(traceCtx, h.validator, r, &req)
+	err = internal.ParseAndValidateRequestBody(traceCtx, h.validator, r, &req)
 	if err != nil {
 		logger.Error("Error decoding requestBody body", zap.Error(err), zap.Any("body", r.Body))
 		problem.WriteError(traceCtx, w, err, logger)
@@ -185,19 +262,177 @@ func (h *Handler) CreateHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Convert comment to Response
-	response := GenerateResponse(comment)
+	response, err := h.generateResponses(traceCtx, format, comment)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
 
-	// Write response
-	internal.WriteJSONResponse(w, http.StatusOK, response)
+	// Write response, held comments are accepted but not visible yet
+	status := http.StatusOK
+	if response[0].Held {
+		status = http.StatusAccepted
+	}
+	internal.WriteJSONResponse(w, status, response[0])
+}
+
+func (h *Handler) UpdateHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "UpdateCommentEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	format, err := markdown.ParseFormat(r)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	commentID := r.PathValue("id")
+
+	// Verify and transform ID to UUID
+	id, err := internal.ParseUUID(commentID)
+	if err != nil {
+		logger.Error("Error parsing UUID", zap.Error(err), zap.String("id", commentID))
+		problem.WriteError(traceCtx, w, fmt.Errorf("%w: %v", errorPkg.ErrInvalidUUID, err), logger)
+		return
+	}
+
+	var req UpdateRequest
+	err = internal.ParseAndValidateRequestBody(traceCtx, h.validator, r, &req)
+	if err != nil {
+		logger.Error("Error decoding requestBody body", zap.Error(err))
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	err = h.requireAuthor(traceCtx, id)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	comment, err := h.store.Update(traceCtx, id, req)
+	if err != nil {
+		logger.Error("Error updating comment", zap.Error(err), zap.String("id", commentID))
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	// Convert comment to Response
+	response, err := h.generateResponses(traceCtx, format, comment)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	internal.WriteJSONResponse(w, http.StatusOK, response[0])
+}
+
+func (h *Handler) DeleteHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "DeleteCommentEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	commentID := r.PathValue("id")
+
+	// Verify and transform ID to UUID
+	id, err := internal.ParseUUID(commentID)
+	if err != nil {
+		logger.Error("Error parsing UUID", zap.Error(err), zap.String("id", commentID))
+		problem.WriteError(traceCtx, w, fmt.Errorf("%w: %v", errorPkg.ErrInvalidUUID, err), logger)
+		return
+	}
+
+	err = h.requireAuthor(traceCtx, id)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	err = h.store.Delete(traceCtx, id)
+	if err != nil {
+		logger.Error("Error deleting comment", zap.Error(err), zap.String("id", commentID))
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// requireAuthor returns errorPkg.ErrForbidden unless the user in the context wrote the comment
+func (h *Handler) requireAuthor(ctx context.Context, id uuid.UUID) error {
+	u, err := jwt.GetUserFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	comment, err := h.store.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if comment.AuthorID.String() != u.ID {
+		return errorPkg.ErrForbidden
+	}
+
+	return nil
+}
+
+// generateResponses converts comments to responses including their resolved mentions and the content rendered to
+// format
+func (h *Handler) generateResponses(ctx context.Context, format string, comments ...Comment) ([]Response, error) {
+	ids := make([]uuid.UUID, len(comments))
+	for i, comment := range comments {
+		ids[i] = comment.ID
+	}
+
+	mentions, err := h.store.GetMentions(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]Response, len(comments))
+	for i, comment := range comments {
+		response[i] = GenerateResponse(comment)
+		response[i].Mentions = mentions[comment.ID]
+		if format != markdown.FormatNone {
+			response[i].Rendered = markdown.Render(response[i].Content, format)
+		}
+	}
+
+	return response, nil
+}
+
+// generateListResponses converts listed comments like generateResponses and adds whether they are collapsed
+func (h *Handler) generateListResponses(ctx context.Context, format string, listed []Listed) ([]Response, error) {
+	comments := make([]Comment, len(listed))
+	for i, l := range listed {
+		comments[i] = l.Comment
+	}
+
+	response, err := h.generateResponses(ctx, format, comments...)
+	if err != nil {
+		return nil, err
+	}
+	for i, l := range listed {
+		response[i].Collapsed = l.Collapsed
+	}
+
+	return response, nil
 }
 
 func GenerateResponse(post Comment) Response {
-	return Response{
+	response := Response{
 		ID:        post.ID.String(),
 		PostId:    post.PostID.String(),
 		AuthorId:  post.AuthorID.String(),
 		Title:     post.Title.String,
 		Content:   post.Content.String,
 		CreatedAt: post.CreatedAt.Time.Format(time.RFC3339),
+		Held:      post.HiddenAt.Valid,
+	}
+	if post.ParentID.Valid {
+		response.ParentId = uuid.UUID(post.ParentID.Bytes).String()
 	}
+	return response
 }
@@ -4,7 +4,9 @@ import (
 	"backend/internal"
 	"backend/internal/comment"
 	"backend/internal/comment/mocks"
+	errorPkg "backend/internal/error"
 	"backend/internal/jwt"
+	"backend/internal/mention"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -105,6 +107,40 @@ func TestHandler_CreateHandler(t *testing.T) {
 			wantStatus: http.StatusBadRequest,
 			wantResult: comment.Response{},
 		},
+		{
+			name: "Should reject comment on locked post",
+			body: args{
+				user: jwt.User{
+					ID:       "7942c917-4770-43c1-a56a-952186b9970e",
+					Username: "testuser",
+					Role:     "user",
+				},
+				requestPostId: "1f0b3c8e-3b0a-4d8e-9f3e-9b1b0f6b7c11",
+				requestBody: comment.CreateRequest{
+					Title:   "Test Title",
+					Content: "Test Content",
+				},
+			},
+			wantStatus: http.StatusLocked,
+			wantResult: comment.Response{},
+		},
+		{
+			name: "Should reject comment on post of blocking author",
+			body: args{
+				user: jwt.User{
+					ID:       "7942c917-4770-43c1-a56a-952186b9970e",
+					Username: "testuser",
+					Role:     "user",
+				},
+				requestPostId: "54a46af2-b454-4746-8ab0-3cf26085a50b",
+				requestBody: comment.CreateRequest{
+					Title:   "Test Title",
+					Content: "Test Content",
+				},
+			},
+			wantStatus: http.StatusForbidden,
+			wantResult: comment.Response{},
+		},
 	}
 
 	// Mock the server
@@ -128,6 +164,21 @@ func TestHandler_CreateHandler(t *testing.T) {
 			CreatedAt: pgtype.Timestamptz{Time: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)},
 		}, nil)
 
+	store.On("GetMentions", mock.Anything, []uuid.UUID{uuid.MustParse("7942c917-4770-43c1-a56a-952186b9970e")}).
+		Return(map[uuid.UUID][]mention.Span{}, nil)
+	store.On("Create", mock.Anything, comment.CreateRequest{
+		PostID:   uuid.MustParse("1f0b3c8e-3b0a-4d8e-9f3e-9b1b0f6b7c11"),
+		AuthorID: uuid.MustParse("7942c917-4770-43c1-a56a-952186b9970e"),
+		Title:    "Test Title",
+		Content:  "Test Content",
+	}).Return(comment.Comment{}, errorPkg.ErrPostLocked)
+	store.On("Create", mock.Anything, comment.CreateRequest{
+		PostID:   uuid.MustParse("54a46af2-b454-4746-8ab0-3cf26085a50b"),
+		AuthorID: uuid.MustParse("7942c917-4770-43c1-a56a-952186b9970e"),
+		Title:    "Test Title",
+		Content:  "Test Content",
+	}).Return(comment.Comment{}, errorPkg.ErrBlocked)
+
 	h := comment.NewHandler(internal.NewValidator(), logger, store)
 
 	for _, tt := range tests {
@@ -160,3 +211,54 @@ func TestHandler_CreateHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_GetByPostHandler(t *testing.T) {
+	user := jwt.User{
+		ID:       "7942c917-4770-43c1-a56a-952186b9970e",
+		Username: "testuser",
+		Role:     "user",
+	}
+	postID := uuid.MustParse("1f0b3c8e-3b0a-4d8e-9f3e-9b1b0f6b7c11")
+	visible := comment.Comment{
+		ID:        uuid.MustParse("54a46af2-b454-4746-8ab0-3cf26085a50b"),
+		PostID:    postID,
+		AuthorID:  uuid.MustParse("81c1ecc1-66d7-4134-b5fe-d886a6f418a4"),
+		Title:     pgtype.Text{String: "Visible"},
+		Content:   pgtype.Text{String: "Content"},
+		CreatedAt: pgtype.Timestamptz{Time: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	muted := comment.Comment{
+		ID:        uuid.MustParse("3d3c9a4e-96a4-4c39-9b53-55a0b6a0c6a1"),
+		PostID:    postID,
+		AuthorID:  uuid.MustParse("c0a80121-7ac0-4e1c-9f3b-4a3f3c2b1a00"),
+		Title:     pgtype.Text{String: "Muted"},
+		Content:   pgtype.Text{String: "Content"},
+		CreatedAt: pgtype.Timestamptz{Time: time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC)},
+	}
+
+	store := mocks.NewStore(t)
+	store.On("GetByPost", mock.Anything, uuid.MustParse(user.ID), postID).
+		Return([]comment.Listed{{Comment: visible}, {Comment: muted, Collapsed: true}}, nil)
+	store.On("GetMentions", mock.Anything, []uuid.UUID{visible.ID, muted.ID}).Return(map[uuid.UUID][]mention.Span{}, nil)
+
+	r := httptest.NewRequest(http.MethodGet, "/api/post/"+postID.String()+"/comments", nil)
+	r.SetPathValue("post_id", postID.String())
+	r = r.WithContext(context.WithValue(r.Context(), internal.UserContextKey, user))
+	w := httptest.NewRecorder()
+
+	h := comment.NewHandler(internal.NewValidator(), zap.NewNop(), store)
+	h.GetByPostHandler(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var got []comment.Response
+	err := json.Unmarshal(w.Body.Bytes(), &got)
+	if err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if assert.Len(t, got, 2) {
+		assert.False(t, got[0].Collapsed)
+		assert.True(t, got[1].Collapsed)
+		assert.Equal(t, "Content", got[1].Content)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	comment "backend/internal/comment"
 	context "context"
 
+	mention "backend/internal/mention"
+
 	mock "github.com/stretchr/testify/mock"
 
 	uuid "github.com/google/uuid"
@@ -44,29 +46,47 @@ func (_m *Store) Create(ctx context.Context, arg comment.CreateRequest) (comment
 	return r0, r1
 }
 
-// GetAll provides a mock function with given fields: ctx
-func (_m *Store) GetAll(ctx context.Context) ([]comment.Comment, error) {
-	ret := _m.Called(ctx)
+// Delete provides a mock function with given fields: ctx, id
+func (_m *Store) Delete(ctx context.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAll provides a mock function with given fields: ctx, viewerID
+func (_m *Store) GetAll(ctx context.Context, viewerID uuid.UUID) ([]comment.Listed, error) {
+	ret := _m.Called(ctx, viewerID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetAll")
 	}
 
-	var r0 []comment.Comment
+	var r0 []comment.Listed
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]comment.Comment, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]comment.Listed, error)); ok {
+		return rf(ctx, viewerID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []comment.Comment); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []comment.Listed); ok {
+		r0 = rf(ctx, viewerID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]comment.Comment)
+			r0 = ret.Get(0).([]comment.Listed)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, viewerID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -102,29 +122,117 @@ func (_m *Store) GetById(ctx context.Context, id uuid.UUID) (comment.Comment, er
 	return r0, r1
 }
 
-// GetByPost provides a mock function with given fields: ctx, postId
-func (_m *Store) GetByPost(ctx context.Context, postId uuid.UUID) ([]comment.Comment, error) {
-	ret := _m.Called(ctx, postId)
+// GetByPost provides a mock function with given fields: ctx, viewerID, postId
+func (_m *Store) GetByPost(ctx context.Context, viewerID uuid.UUID, postId uuid.UUID) ([]comment.Listed, error) {
+	ret := _m.Called(ctx, viewerID, postId)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetByPost")
 	}
 
-	var r0 []comment.Comment
+	var r0 []comment.Listed
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]comment.Comment, error)); ok {
-		return rf(ctx, postId)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) ([]comment.Listed, error)); ok {
+		return rf(ctx, viewerID, postId)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []comment.Comment); ok {
-		r0 = rf(ctx, postId)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) []comment.Listed); ok {
+		r0 = rf(ctx, viewerID, postId)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]comment.Comment)
+			r0 = ret.Get(0).([]comment.Listed)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
-		r1 = rf(ctx, postId)
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(ctx, viewerID, postId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMentions provides a mock function with given fields: ctx, commentIDs
+func (_m *Store) GetMentions(ctx context.Context, commentIDs []uuid.UUID) (map[uuid.UUID][]mention.Span, error) {
+	ret := _m.Called(ctx, commentIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMentions")
+	}
+
+	var r0 map[uuid.UUID][]mention.Span
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []uuid.UUID) (map[uuid.UUID][]mention.Span, error)); ok {
+		return rf(ctx, commentIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []uuid.UUID) map[uuid.UUID][]mention.Span); ok {
+		r0 = rf(ctx, commentIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[uuid.UUID][]mention.Span)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []uuid.UUID) error); ok {
+		r1 = rf(ctx, commentIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUnreadByPost provides a mock function with given fields: ctx, userID, postID
+func (_m *Store) GetUnreadByPost(ctx context.Context, userID uuid.UUID, postID uuid.UUID) ([]comment.Listed, error) {
+	ret := _m.Called(ctx, userID, postID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUnreadByPost")
+	}
+
+	var r0 []comment.Listed
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) ([]comment.Listed, error)); ok {
+		return rf(ctx, userID, postID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) []comment.Listed); ok {
+		r0 = rf(ctx, userID, postID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]comment.Listed)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID, postID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, id, arg
+func (_m *Store) Update(ctx context.Context, id uuid.UUID, arg comment.UpdateRequest) (comment.Comment, error) {
+	ret := _m.Called(ctx, id, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 comment.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, comment.UpdateRequest) (comment.Comment, error)); ok {
+		return rf(ctx, id, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, comment.UpdateRequest) comment.Comment); ok {
+		r0 = rf(ctx, id, arg)
+	} else {
+		r0 = ret.Get(0).(comment.Comment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, comment.UpdateRequest) error); ok {
+		r1 = rf(ctx, id, arg)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1,17 +1,70 @@
 -- name: FindAll :many
-SELECT * FROM comments;
+-- Comments of authors the viewer muted or blocked are collapsed
+SELECT sqlc.embed(c), EXISTS (
+    SELECT 1 FROM user_mutes m WHERE m.muter_id = @viewer_id::uuid AND m.muted_id = c.author_id
+    UNION ALL
+    SELECT 1 FROM user_blocks b WHERE b.blocker_id = @viewer_id::uuid AND b.blocked_id = c.author_id
+) AS collapsed
+FROM comments c
+WHERE c.hidden_at IS NULL;
 
 -- name: FindByID :one
-SELECT * FROM comments WHERE id = $1;
+SELECT * FROM comments WHERE id = $1 AND hidden_at IS NULL;
 
 -- name: FindByPostID :many
-SELECT * FROM comments WHERE post_id = $1;
+-- Comments are collapsed like FindAll
+SELECT sqlc.embed(c), EXISTS (
+    SELECT 1 FROM user_mutes m WHERE m.muter_id = @viewer_id::uuid AND m.muted_id = c.author_id
+    UNION ALL
+    SELECT 1 FROM user_blocks b WHERE b.blocker_id = @viewer_id::uuid AND b.blocked_id = c.author_id
+) AS collapsed
+FROM comments c
+WHERE c.post_id = @post_id AND c.hidden_at IS NULL
+ORDER BY c.created_at, c.id;
+
+-- name: FindUnreadByPostID :many
+-- Comments after the read marker of the viewer, all comments if the viewer never read the post. Comments are
+-- collapsed like FindAll.
+SELECT sqlc.embed(c), EXISTS (
+    SELECT 1 FROM user_mutes m WHERE m.muter_id = @viewer_id::uuid AND m.muted_id = c.author_id
+    UNION ALL
+    SELECT 1 FROM user_blocks b WHERE b.blocker_id = @viewer_id::uuid AND b.blocked_id = c.author_id
+) AS collapsed
+FROM comments c
+WHERE c.post_id = @post_id
+  AND c.hidden_at IS NULL
+  AND c.created_at > COALESCE(
+      (SELECT r.last_read_at FROM read_markers r WHERE r.user_id = @viewer_id::uuid AND r.post_id = @post_id),
+      '-infinity'::timestamptz)
+ORDER BY c.created_at, c.id;
+
+-- name: FindPostState :one
+-- blocked tells whether the author of the post blocked the user
+SELECT p.locked_at, p.archived_at, EXISTS (
+    SELECT 1 FROM user_blocks b WHERE b.blocker_id = p.author_id AND b.blocked_id = @user_id::uuid
+) AS blocked
+FROM posts p
+WHERE p.id = @id AND p.hidden_at IS NULL AND p.status = 'published';
 
 -- name: Create :one
-INSERT INTO comments (post_id, author_id, title, content) VALUES ($1, $2, $3, $4) RETURNING *;
+-- Held comments are created hidden until a moderator approves them
+INSERT INTO comments (post_id, author_id, title, content, parent_id, hidden_at) VALUES ($1, $2, $3, $4, $5, $6) RETURNING *;
+
+-- name: FindByIDAndPostID :one
+SELECT * FROM comments WHERE id = $1 AND post_id = $2 AND hidden_at IS NULL;
 
 -- name: Update :one
-UPDATE comments SET title = $2, content = $3 WHERE id = $1 RETURNING *;
+-- Edits held by the content filter hide the comment until a moderator approves it
+UPDATE comments
+SET title = @title, content = @content, updated_at = now(),
+    hidden_at = CASE WHEN @hold::boolean THEN COALESCE(hidden_at, now()) ELSE hidden_at END
+WHERE id = @id RETURNING *;
+
+-- name: Delete :execrows
+DELETE FROM comments WHERE id = $1;
+
+-- name: Hide :one
+UPDATE comments SET hidden_at = COALESCE(hidden_at, now()) WHERE id = $1 RETURNING post_id;
 
--- name: Delete :exec
-DELETE FROM comments WHERE id = $1;
\ No newline at end of file
+-- name: Unhide :one
+UPDATE comments SET hidden_at = NULL WHERE id = $1 AND hidden_at IS NOT NULL RETURNING *;
\ No newline at end of file
@@ -13,7 +13,7 @@ import (
 )
 
 const create = `-- name: Create :one
-INSERT INTO comments (post_id, author_id, title, content) VALUES ($1, $2, $3, $4) RETURNING id, post_id, author_id, title, content, created_at
+INSERT INTO comments (post_id, author_id, title, content, parent_id, hidden_at) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, post_id, author_id, title, content, created_at, updated_at, parent_id, hidden_at
 `
 
 type CreateParams struct {
@@ -21,14 +21,19 @@ type CreateParams struct {
 	AuthorID uuid.UUID
 	Title    pgtype.Text
 	Content  pgtype.Text
+	ParentID pgtype.UUID
+	HiddenAt pgtype.Timestamptz
 }
 
+// Held comments are created hidden until a moderator approves them
 func (q *Queries) Create(ctx context.Context, arg CreateParams) (Comment, error) {
 	row := q.db.QueryRow(ctx, create,
 		arg.PostID,
 		arg.AuthorID,
 		arg.Title,
 		arg.Content,
+		arg.ParentID,
+		arg.HiddenAt,
 	)
 	var i Comment
 	err := row.Scan(
@@ -38,39 +43,61 @@ func (q *Queries) Create(ctx context.Context, arg CreateParams) (Comment, error)
 		&i.Title,
 		&i.Content,
 		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ParentID,
+		&i.HiddenAt,
 	)
 	return i, err
 }
 
-const delete = `-- name: Delete :exec
+const delete = `-- name: Delete :execrows
 DELETE FROM comments WHERE id = $1
 `
 
-func (q *Queries) Delete(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.Exec(ctx, delete, id)
-	return err
+func (q *Queries) Delete(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, delete, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
 const findAll = `-- name: FindAll :many
-SELECT id, post_id, author_id, title, content, created_at FROM comments
+SELECT c.id, c.post_id, c.author_id, c.title, c.content, c.created_at, c.updated_at, c.parent_id, c.hidden_at, EXISTS (
+    SELECT 1 FROM user_mutes m WHERE m.muter_id = $1::uuid AND m.muted_id = c.author_id
+    UNION ALL
+    SELECT 1 FROM user_blocks b WHERE b.blocker_id = $1::uuid AND b.blocked_id = c.author_id
+) AS collapsed
+FROM comments c
+WHERE c.hidden_at IS NULL
 `
 
-func (q *Queries) FindAll(ctx context.Context) ([]Comment, error) {
-	rows, err := q.db.Query(ctx, findAll)
+type FindAllRow struct {
+	Comment   Comment
+	Collapsed bool
+}
+
+// Comments of authors the viewer muted or blocked are collapsed
+func (q *Queries) FindAll(ctx context.Context, viewerID uuid.UUID) ([]FindAllRow, error) {
+	rows, err := q.db.Query(ctx, findAll, viewerID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Comment
+	var items []FindAllRow
 	for rows.Next() {
-		var i Comment
+		var i FindAllRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.PostID,
-			&i.AuthorID,
-			&i.Title,
-			&i.Content,
-			&i.CreatedAt,
+			&i.Comment.ID,
+			&i.Comment.PostID,
+			&i.Comment.AuthorID,
+			&i.Comment.Title,
+			&i.Comment.Content,
+			&i.Comment.CreatedAt,
+			&i.Comment.UpdatedAt,
+			&i.Comment.ParentID,
+			&i.Comment.HiddenAt,
+			&i.Collapsed,
 		); err != nil {
 			return nil, err
 		}
@@ -83,7 +110,7 @@ func (q *Queries) FindAll(ctx context.Context) ([]Comment, error) {
 }
 
 const findByID = `-- name: FindByID :one
-SELECT id, post_id, author_id, title, content, created_at FROM comments WHERE id = $1
+SELECT id, post_id, author_id, title, content, created_at, updated_at, parent_id, hidden_at FROM comments WHERE id = $1 AND hidden_at IS NULL
 `
 
 func (q *Queries) FindByID(ctx context.Context, id uuid.UUID) (Comment, error) {
@@ -96,30 +123,81 @@ func (q *Queries) FindByID(ctx context.Context, id uuid.UUID) (Comment, error) {
 		&i.Title,
 		&i.Content,
 		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ParentID,
+		&i.HiddenAt,
+	)
+	return i, err
+}
+
+const findByIDAndPostID = `-- name: FindByIDAndPostID :one
+SELECT id, post_id, author_id, title, content, created_at, updated_at, parent_id, hidden_at FROM comments WHERE id = $1 AND post_id = $2 AND hidden_at IS NULL
+`
+
+type FindByIDAndPostIDParams struct {
+	ID     uuid.UUID
+	PostID uuid.UUID
+}
+
+func (q *Queries) FindByIDAndPostID(ctx context.Context, arg FindByIDAndPostIDParams) (Comment, error) {
+	row := q.db.QueryRow(ctx, findByIDAndPostID, arg.ID, arg.PostID)
+	var i Comment
+	err := row.Scan(
+		&i.ID,
+		&i.PostID,
+		&i.AuthorID,
+		&i.Title,
+		&i.Content,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ParentID,
+		&i.HiddenAt,
 	)
 	return i, err
 }
 
 const findByPostID = `-- name: FindByPostID :many
-SELECT id, post_id, author_id, title, content, created_at FROM comments WHERE post_id = $1
+SELECT c.id, c.post_id, c.author_id, c.title, c.content, c.created_at, c.updated_at, c.parent_id, c.hidden_at, EXISTS (
+    SELECT 1 FROM user_mutes m WHERE m.muter_id = $1::uuid AND m.muted_id = c.author_id
+    UNION ALL
+    SELECT 1 FROM user_blocks b WHERE b.blocker_id = $1::uuid AND b.blocked_id = c.author_id
+) AS collapsed
+FROM comments c
+WHERE c.post_id = $2 AND c.hidden_at IS NULL
+ORDER BY c.created_at, c.id
 `
 
-func (q *Queries) FindByPostID(ctx context.Context, postID uuid.UUID) ([]Comment, error) {
-	rows, err := q.db.Query(ctx, findByPostID, postID)
+type FindByPostIDParams struct {
+	ViewerID uuid.UUID
+	PostID   uuid.UUID
+}
+
+type FindByPostIDRow struct {
+	Comment   Comment
+	Collapsed bool
+}
+
+// Comments are collapsed like FindAll
+func (q *Queries) FindByPostID(ctx context.Context, arg FindByPostIDParams) ([]FindByPostIDRow, error) {
+	rows, err := q.db.Query(ctx, findByPostID, arg.ViewerID, arg.PostID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Comment
+	var items []FindByPostIDRow
 	for rows.Next() {
-		var i Comment
+		var i FindByPostIDRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.PostID,
-			&i.AuthorID,
-			&i.Title,
-			&i.Content,
-			&i.CreatedAt,
+			&i.Comment.ID,
+			&i.Comment.PostID,
+			&i.Comment.AuthorID,
+			&i.Comment.Title,
+			&i.Comment.Content,
+			&i.Comment.CreatedAt,
+			&i.Comment.UpdatedAt,
+			&i.Comment.ParentID,
+			&i.Comment.HiddenAt,
+			&i.Collapsed,
 		); err != nil {
 			return nil, err
 		}
@@ -131,18 +209,145 @@ func (q *Queries) FindByPostID(ctx context.Context, postID uuid.UUID) ([]Comment
 	return items, nil
 }
 
+const findPostState = `-- name: FindPostState :one
+SELECT p.locked_at, p.archived_at, EXISTS (
+    SELECT 1 FROM user_blocks b WHERE b.blocker_id = p.author_id AND b.blocked_id = $1::uuid
+) AS blocked
+FROM posts p
+WHERE p.id = $2 AND p.hidden_at IS NULL AND p.status = 'published'
+`
+
+type FindPostStateParams struct {
+	UserID uuid.UUID
+	ID     uuid.UUID
+}
+
+type FindPostStateRow struct {
+	LockedAt   pgtype.Timestamptz
+	ArchivedAt pgtype.Timestamptz
+	Blocked    bool
+}
+
+// blocked tells whether the author of the post blocked the user
+func (q *Queries) FindPostState(ctx context.Context, arg FindPostStateParams) (FindPostStateRow, error) {
+	row := q.db.QueryRow(ctx, findPostState, arg.UserID, arg.ID)
+	var i FindPostStateRow
+	err := row.Scan(&i.LockedAt, &i.ArchivedAt, &i.Blocked)
+	return i, err
+}
+
+const findUnreadByPostID = `-- name: FindUnreadByPostID :many
+SELECT c.id, c.post_id, c.author_id, c.title, c.content, c.created_at, c.updated_at, c.parent_id, c.hidden_at, EXISTS (
+    SELECT 1 FROM user_mutes m WHERE m.muter_id = $1::uuid AND m.muted_id = c.author_id
+    UNION ALL
+    SELECT 1 FROM user_blocks b WHERE b.blocker_id = $1::uuid AND b.blocked_id = c.author_id
+) AS collapsed
+FROM comments c
+WHERE c.post_id = $2
+  AND c.hidden_at IS NULL
+  AND c.created_at > COALESCE(
+      (SELECT r.last_read_at FROM read_markers r WHERE r.user_id = $1::uuid AND r.post_id = $2),
+      '-infinity'::timestamptz)
+ORDER BY c.created_at, c.id
+`
+
+type FindUnreadByPostIDParams struct {
+	ViewerID uuid.UUID
+	PostID   uuid.UUID
+}
+
+type FindUnreadByPostIDRow struct {
+	Comment   Comment
+	Collapsed bool
+}
+
+// Comments after the read marker of the viewer, all comments if the viewer never read the post. Comments are
+// collapsed like FindAll.
+func (q *Queries) FindUnreadByPostID(ctx context.Context, arg FindUnreadByPostIDParams) ([]FindUnreadByPostIDRow, error) {
+	rows, err := q.db.Query(ctx, findUnreadByPostID, arg.ViewerID, arg.PostID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FindUnreadByPostIDRow
+	for rows.Next() {
+		var i FindUnreadByPostIDRow
+		if err := rows.Scan(
+			&i.Comment.ID,
+			&i.Comment.PostID,
+			&i.Comment.AuthorID,
+			&i.Comment.Title,
+			&i.Comment.Content,
+			&i.Comment.CreatedAt,
+			&i.Comment.UpdatedAt,
+			&i.Comment.ParentID,
+			&i.Comment.HiddenAt,
+			&i.Collapsed,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const hide = `-- name: Hide :one
+UPDATE comments SET hidden_at = COALESCE(hidden_at, now()) WHERE id = $1 RETURNING post_id
+`
+
+func (q *Queries) Hide(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, hide, id)
+	var post_id uuid.UUID
+	err := row.Scan(&post_id)
+	return post_id, err
+}
+
+const unhide = `-- name: Unhide :one
+UPDATE comments SET hidden_at = NULL WHERE id = $1 AND hidden_at IS NOT NULL RETURNING id, post_id, author_id, title, content, created_at, updated_at, parent_id, hidden_at
+`
+
+func (q *Queries) Unhide(ctx context.Context, id uuid.UUID) (Comment, error) {
+	row := q.db.QueryRow(ctx, unhide, id)
+	var i Comment
+	err := row.Scan(
+		&i.ID,
+		&i.PostID,
+		&i.AuthorID,
+		&i.Title,
+		&i.Content,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ParentID,
+		&i.HiddenAt,
+	)
+	return i, err
+}
+
 const update = `-- name: Update :one
-UPDATE comments SET title = $2, content = $3 WHERE id = $1 RETURNING id, post_id, author_id, title, content, created_at
+UPDATE comments
+SET title = $1, content = $2, updated_at = now(),
+    hidden_at = CASE WHEN $3::boolean THEN COALESCE(hidden_at, now()) ELSE hidden_at END
+WHERE id = $4 RETURNING id, post_id, author_id, title, content, created_at, updated_at, parent_id, hidden_at
 `
 
 type UpdateParams struct {
-	ID      uuid.UUID
 	Title   pgtype.Text
 	Content pgtype.Text
+	Hold    bool
+	ID      uuid.UUID
 }
 
+// Edits held by the content filter hide the comment until a moderator approves it
 func (q *Queries) Update(ctx context.Context, arg UpdateParams) (Comment, error) {
-	row := q.db.QueryRow(ctx, update, arg.ID, arg.Title, arg.Content)
+	row := q.db.QueryRow(ctx, update,
+		arg.Title,
+		arg.Content,
+		arg.Hold,
+		arg.ID,
+	)
 	var i Comment
 	err := row.Scan(
 		&i.ID,
@@ -151,6 +356,9 @@ func (q *Queries) Update(ctx context.Context, arg UpdateParams) (Comment, error)
 		&i.Title,
 		&i.Content,
 		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ParentID,
+		&i.HiddenAt,
 	)
 	return i, err
 }
@@ -2,9 +2,12 @@ CREATE EXTENSION IF NOT EXISTS "pgcrypto";
 
 CREATE TABLE IF NOT EXISTS comments (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
-    post_id UUID REFERENCES posts(id) NOT NULL,
+    post_id UUID REFERENCES posts(id) ON DELETE CASCADE NOT NULL,
     author_id UUID REFERENCES users(id) NOT NULL,
     title VARCHAR(200),
     content TEXT,
-    created_at TIMESTAMPTZ DEFAULT NOW()
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    parent_id UUID REFERENCES comments(id) ON DELETE CASCADE,
+    hidden_at TIMESTAMPTZ
 );
\ No newline at end of file
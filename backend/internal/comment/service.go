@@ -2,41 +2,112 @@ package comment
 
 import (
 	"backend/internal"
+	"backend/internal/audit"
 	"backend/internal/database"
+	errorPkg "backend/internal/error"
+	"backend/internal/event"
+	"backend/internal/filter"
+	"backend/internal/markdown"
+	"backend/internal/mention"
+	"backend/internal/notification"
+	"backend/internal/watch"
 	"context"
+	"errors"
+	"fmt"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
+	"time"
 )
 
+// ActionDelete is the audit trail action of deleted comments
+const ActionDelete = "comment.delete"
+
+// Publisher announces changes to comments, see event.Service
+type Publisher interface {
+	Publish(ctx context.Context, eventType string, payload any) error
+}
+
+// Notifier informs users about activity on their content, see notification.Service
+type Notifier interface {
+	Notify(ctx context.Context, recipientID, actorID uuid.UUID, notificationType string, postID, commentID uuid.UUID) error
+}
+
+// Mentioner keeps the @mentions of comment content, see mention.Service
+type Mentioner interface {
+	Sync(ctx context.Context, target mention.Target, authorID uuid.UUID, content string) ([]mention.Span, error)
+	GetByComments(ctx context.Context, commentIDs []uuid.UUID) (map[uuid.UUID][]mention.Span, error)
+}
+
+// ContentFilter screens new and edited comments, see filter.Pipeline
+type ContentFilter interface {
+	Check(ctx context.Context, content filter.Content) filter.Verdict
+	Report(ctx context.Context, target filter.Target, verdict filter.Verdict) error
+}
+
+// Auditor keeps the trail of deletions, see audit.Service
+type Auditor interface {
+	Record(ctx context.Context, entry audit.Entry) error
+}
+
+// Watcher knows who follows a thread, see watch.Service
+type Watcher interface {
+	GetWatchers(ctx context.Context, postID uuid.UUID) ([]watch.Watcher, error)
+	WatchIfAbsent(ctx context.Context, userID, postID uuid.UUID) error
+}
+
+// Listed is a comment in a listing for a viewer, Collapsed comments are by authors the viewer muted or blocked
+type Listed struct {
+	Comment   Comment
+	Collapsed bool
+}
+
 type Service struct {
-	logger *zap.Logger
-	tracer trace.Tracer
-	query  *Queries
+	logger    *zap.Logger
+	tracer    trace.Tracer
+	query     *Queries
+	publisher Publisher
+	notifier  Notifier
+	mentioner Mentioner
+	watcher   Watcher
+	auditor   Auditor
+	filter    ContentFilter
 }
 
-func NewService(logger *zap.Logger, db DBTX) *Service {
+func NewService(logger *zap.Logger, db DBTX, publisher Publisher, notifier Notifier, mentioner Mentioner, watcher Watcher, auditor Auditor, contentFilter ContentFilter) *Service {
 	return &Service{
-		logger: logger,
-		tracer: otel.Tracer("comment/service"),
-		query:  New(db),
+		logger:    logger,
+		tracer:    otel.Tracer("comment/service"),
+		query:     New(db),
+		publisher: publisher,
+		notifier:  notifier,
+		mentioner: mentioner,
+		watcher:   watcher,
+		auditor:   auditor,
+		filter:    contentFilter,
 	}
 }
 
-func (s *Service) GetAll(ctx context.Context) ([]Comment, error) {
+func (s *Service) GetAll(ctx context.Context, viewerID uuid.UUID) ([]Listed, error) {
 	traceCtx, span := s.tracer.Start(ctx, "GetAll")
 	defer span.End()
 	logger := internal.LoggerWithContext(traceCtx, s.logger)
 
-	comments, err := s.query.FindAll(traceCtx)
+	rows, err := s.query.FindAll(traceCtx, viewerID)
 
 	if err != nil {
 		err = database.WrapDBError(err, logger, "get all comments")
 		span.RecordError(err)
 		return nil, err
 	}
+
+	comments := make([]Listed, len(rows))
+	for i, row := range rows {
+		comments[i] = Listed(row)
+	}
 	return comments, nil
 }
 
@@ -55,12 +126,12 @@ func (s *Service) GetById(ctx context.Context, id uuid.UUID) (Comment, error) {
 	return comment, nil
 }
 
-func (s *Service) GetByPost(ctx context.Context, postId uuid.UUID) ([]Comment, error) {
+func (s *Service) GetByPost(ctx context.Context, viewerID, postId uuid.UUID) ([]Listed, error) {
 	traceCtx, span := s.tracer.Start(ctx, "GetByPost")
 	defer span.End()
 	logger := internal.LoggerWithContext(traceCtx, s.logger)
 
-	comments, err := s.query.FindByPostID(traceCtx, postId)
+	rows, err := s.query.FindByPostID(traceCtx, FindByPostIDParams{ViewerID: viewerID, PostID: postId})
 
 	if err != nil {
 		err = database.WrapDBError(err, logger, "get comments by post ID")
@@ -68,6 +139,30 @@ func (s *Service) GetByPost(ctx context.Context, postId uuid.UUID) ([]Comment, e
 		return nil, err
 	}
 
+	comments := make([]Listed, len(rows))
+	for i, row := range rows {
+		comments[i] = Listed(row)
+	}
+	return comments, nil
+}
+
+// GetUnreadByPost returns the comments of the post written after the read marker of the user, oldest first
+func (s *Service) GetUnreadByPost(ctx context.Context, userID, postID uuid.UUID) ([]Listed, error) {
+	traceCtx, span := s.tracer.Start(ctx, "GetUnreadByPost")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	rows, err := s.query.FindUnreadByPostID(traceCtx, FindUnreadByPostIDParams{PostID: postID, ViewerID: userID})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "get unread comments by post ID")
+		span.RecordError(err)
+		return nil, err
+	}
+
+	comments := make([]Listed, len(rows))
+	for i, row := range rows {
+		comments[i] = Listed(row)
+	}
 	return comments, nil
 }
 
@@ -76,11 +171,48 @@ func (s *Service) Create(ctx context.Context, arg CreateRequest) (Comment, error
 	defer span.End()
 	logger := internal.LoggerWithContext(traceCtx, s.logger)
 
+	err := markdown.Validate(arg.Content)
+	if err != nil {
+		span.RecordError(err)
+		return Comment{}, err
+	}
+
+	err = s.checkPostState(traceCtx, arg.PostID, arg.AuthorID, true)
+	if err != nil {
+		span.RecordError(err)
+		return Comment{}, err
+	}
+
+	var parent Comment
+	if arg.ParentID != nil {
+		// A reply must stay in the thread of the comment it answers
+		var err error
+		parent, err = s.query.FindByIDAndPostID(traceCtx, FindByIDAndPostIDParams{
+			ID:     *arg.ParentID,
+			PostID: arg.PostID,
+		})
+		if err != nil {
+			err = database.WrapDBErrorWithKeyValue(err, "comments", "id", arg.ParentID.String(), logger, "get parent comment")
+			span.RecordError(err)
+			return Comment{}, err
+		}
+	}
+
+	verdict := s.filter.Check(traceCtx, filter.Content{AuthorID: arg.AuthorID, Title: arg.Title, Body: arg.Content})
+	if verdict.Action == filter.ActionReject {
+		err = fmt.Errorf("%w: %s", errorPkg.ErrContentRejected, verdict.Reason)
+		span.RecordError(err)
+		return Comment{}, err
+	}
+	held := verdict.Action == filter.ActionHold
+
 	comment, err := s.query.Create(traceCtx, CreateParams{
 		PostID:   arg.PostID,
 		AuthorID: arg.AuthorID,
 		Title:    pgtype.Text{String: arg.Title, Valid: true},
 		Content:  pgtype.Text{String: arg.Content, Valid: true},
+		ParentID: pgtype.UUID{Bytes: parent.ID, Valid: arg.ParentID != nil},
+		HiddenAt: pgtype.Timestamptz{Time: time.Now(), Valid: held},
 	})
 
 	if err != nil {
@@ -88,6 +220,128 @@ func (s *Service) Create(ctx context.Context, arg CreateRequest) (Comment, error
 		span.RecordError(err)
 		return Comment{}, err
 	}
+
+	if verdict.Action != filter.ActionAllow {
+		err = s.filter.Report(traceCtx, filter.Target{PostID: comment.PostID, CommentID: comment.ID}, verdict)
+		if err != nil {
+			logger.Error("Failed to report filtered comment", zap.String("id", comment.ID.String()), zap.String("action", verdict.Action), zap.Error(err))
+		}
+	}
+	if held {
+		logger.Info("Held comment for review", zap.String("id", comment.ID.String()), zap.String("reason", verdict.Reason))
+		return comment, nil
+	}
+
+	s.announce(traceCtx, comment, parent)
+	return comment, nil
+}
+
+// announce runs the side effects of a comment becoming visible
+func (s *Service) announce(ctx context.Context, comment Comment, parent Comment) {
+	s.publish(ctx, event.CommentCreated, GenerateResponse(comment))
+	s.notify(ctx, comment, parent)
+	s.syncMentions(ctx, comment)
+}
+
+// notify fans a new comment out to the watchers of its post that want every comment, watchers with a lower level
+// only hear about mentions. The commenter then watches the thread unless they already chose a level for it. Failures
+// are logged but do not fail the comment that was already created.
+func (s *Service) notify(ctx context.Context, comment Comment, parent Comment) {
+	logger := internal.LoggerWithContext(ctx, s.logger)
+
+	watchers, err := s.watcher.GetWatchers(ctx, comment.PostID)
+	if err != nil {
+		logger.Warn("Failed to find watchers for notification", zap.String("post_id", comment.PostID.String()), zap.Error(err))
+	}
+
+	for _, w := range watchers {
+		if w.Level != watch.LevelAll {
+			continue
+		}
+
+		notificationType := notification.TypeCommentOnPost
+		if parent.ID != uuid.Nil && w.UserID == parent.AuthorID {
+			notificationType = notification.TypeReplyToComment
+		}
+
+		err = s.notifier.Notify(ctx, w.UserID, comment.AuthorID, notificationType, comment.PostID, comment.ID)
+		if err != nil {
+			logger.Warn("Failed to notify watcher", zap.String("comment_id", comment.ID.String()), zap.String("user_id", w.UserID.String()), zap.Error(err))
+		}
+	}
+
+	err = s.watcher.WatchIfAbsent(ctx, comment.AuthorID, comment.PostID)
+	if err != nil {
+		logger.Warn("Failed to watch commented post", zap.String("post_id", comment.PostID.String()), zap.Error(err))
+	}
+}
+
+func (s *Service) Update(ctx context.Context, id uuid.UUID, arg UpdateRequest) (Comment, error) {
+	traceCtx, span := s.tracer.Start(ctx, "Update")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	err := markdown.Validate(arg.Content)
+	if err != nil {
+		span.RecordError(err)
+		return Comment{}, err
+	}
+
+	existing, err := s.query.FindByID(traceCtx, id)
+	if err != nil {
+		err = database.WrapDBErrorWithKeyValue(err, "comments", "id", id.String(), logger, "update comment")
+		span.RecordError(err)
+		return Comment{}, err
+	}
+	err = s.checkPostState(traceCtx, existing.PostID, existing.AuthorID, false)
+	if err != nil {
+		span.RecordError(err)
+		return Comment{}, err
+	}
+
+	target := filter.Target{PostID: existing.PostID, CommentID: id}
+	verdict := s.filter.Check(traceCtx, filter.Content{AuthorID: existing.AuthorID, Title: arg.Title, Body: arg.Content, Edited: target})
+	if verdict.Action == filter.ActionReject {
+		err = fmt.Errorf("%w: %s", errorPkg.ErrContentRejected, verdict.Reason)
+		span.RecordError(err)
+		return Comment{}, err
+	}
+	held := verdict.Action == filter.ActionHold
+
+	comment, err := s.query.Update(traceCtx, UpdateParams{
+		ID:      id,
+		Title:   pgtype.Text{String: arg.Title, Valid: true},
+		Content: pgtype.Text{String: arg.Content, Valid: true},
+		Hold:    held,
+	})
+
+	if err != nil {
+		err = database.WrapDBErrorWithKeyValue(err, "comments", "id", id.String(), logger, "update comment")
+		span.RecordError(err)
+		return Comment{}, err
+	}
+
+	if verdict.Action != filter.ActionAllow {
+		err = s.filter.Report(traceCtx, target, verdict)
+		if err != nil {
+			logger.Error("Failed to report filtered comment", zap.String("id", id.String()), zap.String("action", verdict.Action), zap.Error(err))
+		}
+	}
+	if held {
+		logger.Info("Held edited comment for review", zap.String("id", id.String()), zap.String("reason", verdict.Reason))
+		if !existing.HiddenAt.Valid {
+			// the comment disappears for everyone else like a hidden comment
+			s.publish(traceCtx, event.CommentDeleted, map[string]string{"id": id.String(), "post_id": comment.PostID.String()})
+		}
+		return comment, nil
+	}
+	if comment.HiddenAt.Valid {
+		// hidden comments are announced again once a moderator approves them
+		return comment, nil
+	}
+
+	s.publish(traceCtx, event.CommentUpdated, GenerateResponse(comment))
+	s.syncMentions(traceCtx, comment)
 	return comment, nil
 }
 
@@ -96,12 +350,132 @@ func (s *Service) Delete(ctx context.Context, id uuid.UUID) error {
 	defer span.End()
 	logger := internal.LoggerWithContext(traceCtx, s.logger)
 
-	err := s.query.Delete(traceCtx, id)
+	comment, err := s.query.FindByID(traceCtx, id)
+	if err != nil {
+		err = database.WrapDBErrorWithKeyValue(err, "comments", "id", id.String(), logger, "delete comment")
+		span.RecordError(err)
+		return err
+	}
+
+	count, err := s.query.Delete(traceCtx, id)
 
 	if err != nil {
 		err = database.WrapDBErrorWithKeyValue(err, "comments", "id", id.String(), logger, "delete comment")
 		span.RecordError(err)
 		return err
 	}
+	if count == 0 {
+		err = errorPkg.NewNotFoundError("comments", "id", id.String(), "")
+		span.RecordError(err)
+		return err
+	}
+
+	err = s.auditor.Record(traceCtx, audit.Entry{Action: ActionDelete, TargetType: audit.TargetComment, TargetID: id})
+	if err != nil {
+		logger.Error("Failed to record audit entry", zap.String("id", id.String()), zap.String("action", ActionDelete), zap.Error(err))
+	}
+
+	s.publish(traceCtx, event.CommentDeleted, map[string]string{"id": id.String(), "post_id": comment.PostID.String()})
+	return nil
+}
+
+// Hide removes the comment from every read endpoint without deleting it, clients see it as deleted. Hiding a hidden
+// comment again is not an error.
+func (s *Service) Hide(ctx context.Context, id uuid.UUID) error {
+	traceCtx, span := s.tracer.Start(ctx, "Hide")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	postID, err := s.query.Hide(traceCtx, id)
+	if err != nil {
+		err = database.WrapDBErrorWithKeyValue(err, "comments", "id", id.String(), logger, "hide comment")
+		span.RecordError(err)
+		return err
+	}
+
+	s.publish(traceCtx, event.CommentDeleted, map[string]string{"id": id.String(), "post_id": postID.String()})
 	return nil
 }
+
+// Unhide makes a hidden comment visible again and announces it like a new comment, so approving a held comment
+// notifies the watchers of the thread. Comments that are not hidden are left alone.
+func (s *Service) Unhide(ctx context.Context, id uuid.UUID) error {
+	traceCtx, span := s.tracer.Start(ctx, "Unhide")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	comment, err := s.query.Unhide(traceCtx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		_, err = s.GetById(traceCtx, id)
+		if err != nil {
+			span.RecordError(err)
+		}
+		return err
+	}
+	if err != nil {
+		err = database.WrapDBErrorWithKeyValue(err, "comments", "id", id.String(), logger, "unhide comment")
+		span.RecordError(err)
+		return err
+	}
+
+	var parent Comment
+	if comment.ParentID.Valid {
+		parent, err = s.query.FindByID(traceCtx, comment.ParentID.Bytes)
+		if err != nil {
+			logger.Warn("Failed to get parent of approved comment", zap.String("id", id.String()), zap.Error(err))
+		}
+	}
+
+	s.announce(traceCtx, comment, parent)
+	return nil
+}
+
+// GetMentions returns the resolved mentions in the content of the given comments, keyed by comment ID
+func (s *Service) GetMentions(ctx context.Context, commentIDs []uuid.UUID) (map[uuid.UUID][]mention.Span, error) {
+	traceCtx, span := s.tracer.Start(ctx, "GetMentions")
+	defer span.End()
+
+	mentions, err := s.mentioner.GetByComments(traceCtx, commentIDs)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return mentions, nil
+}
+
+// checkPostState rejects writes to comments of archived posts and, for new comments, of locked posts and of posts
+// whose author blocked userID
+func (s *Service) checkPostState(ctx context.Context, postID, userID uuid.UUID, newComment bool) error {
+	state, err := s.query.FindPostState(ctx, FindPostStateParams{ID: postID, UserID: userID})
+	if err != nil {
+		return database.WrapDBErrorWithKeyValue(err, "post", "id", postID.String(), internal.LoggerWithContext(ctx, s.logger), "get post state")
+	}
+
+	switch {
+	case state.ArchivedAt.Valid:
+		return errorPkg.ErrPostArchived
+	case newComment && state.LockedAt.Valid:
+		return errorPkg.ErrPostLocked
+	case newComment && state.Blocked:
+		return errorPkg.ErrBlocked
+	}
+
+	return nil
+}
+
+// syncMentions stores the mentions of the comment content, a failure is logged but does not fail the write
+func (s *Service) syncMentions(ctx context.Context, comment Comment) {
+	_, err := s.mentioner.Sync(ctx, mention.Target{PostID: comment.PostID, CommentID: comment.ID}, comment.AuthorID, comment.Content.String)
+	if err != nil {
+		internal.LoggerWithContext(ctx, s.logger).Warn("Failed to sync comment mentions", zap.String("id", comment.ID.String()), zap.Error(err))
+	}
+}
+
+// publish announces a change, a failure is logged but does not fail the write that already happened
+func (s *Service) publish(ctx context.Context, eventType string, payload any) {
+	err := s.publisher.Publish(ctx, eventType, payload)
+	if err != nil {
+		internal.LoggerWithContext(ctx, s.logger).Warn("Failed to publish comment event", zap.String("type", eventType), zap.Error(err))
+	}
+}
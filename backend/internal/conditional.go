@@ -0,0 +1,85 @@
+package internal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// WriteConditionalJSONResponse writes data as JSON with ETag and Last-Modified headers. If the request carries an
+// If-None-Match or If-Modified-Since header matching the current representation, it answers 304 Not Modified
+// without a body instead. Following RFC 9110, If-Modified-Since is ignored when If-None-Match is present.
+//
+// A zero lastModified omits the Last-Modified header and disables the If-Modified-Since check.
+func WriteConditionalJSONResponse(w http.ResponseWriter, r *http.Request, status int, data interface{}, lastModified time.Time) {
+	jsonBytes, err := json.Marshal(data)
+	if err != nil {
+		http.Error(w, "Failed to marshal response", http.StatusInternalServerError)
+		return
+	}
+
+	WriteConditionalResponse(w, r, status, "application/json", jsonBytes, lastModified)
+}
+
+// WriteConditionalResponse is the content type agnostic variant of WriteConditionalJSONResponse.
+func WriteConditionalResponse(w http.ResponseWriter, r *http.Request, status int, contentType string, body []byte, lastModified time.Time) {
+	etag := WeakETag(body)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "private, no-cache")
+
+	lastModified = lastModified.UTC().Truncate(time.Second)
+	if !lastModified.IsZero() {
+		w.Header().Set("Last-Modified", lastModified.Format(http.TimeFormat))
+	}
+
+	if NotModified(r, etag, lastModified) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	_, err := w.Write(body)
+	if err != nil {
+		http.Error(w, "Failed to write response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// WeakETag returns a weak entity tag derived from the SHA-256 digest of body. The tag is weak because the JSON
+// encoding is not guaranteed to be byte-for-byte stable across releases.
+func WeakETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// NotModified reports whether the conditional headers of r indicate the client already holds the representation
+// identified by etag and lastModified.
+func NotModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		since, err := http.ParseTime(ims)
+		if err != nil {
+			return false
+		}
+		return !lastModified.After(since)
+	}
+
+	return false
+}
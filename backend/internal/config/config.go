@@ -8,6 +8,7 @@ import (
 	"gopkg.in/yaml.v3"
 	"os"
 	"reflect"
+	"time"
 )
 
 const DefaultSecret = "default-secret"
@@ -22,12 +23,134 @@ type Config struct {
 	DatabaseURL      string `yaml:"database_url"       envconfig:"DATABASE_URL"`
 	MigrationSource  string `yaml:"migration_source"   envconfig:"MIGRATION_SOURCE"`
 	OtelCollectorUrl string `yaml:"otel_collector_url" envconfig:"OTEL_COLLECTOR_URL"`
+	PublicURL        string `yaml:"public_url"         envconfig:"PUBLIC_URL"`
+
+	// ContentFilter, RateLimits, Attachments, NNTP, SSH and Gemini are only read from the config file
+	ContentFilter ContentFilterConfig `yaml:"content_filter"`
+	RateLimits    []RateLimitRule     `yaml:"rate_limits"`
+	Attachments   AttachmentConfig    `yaml:"attachments"`
+	NNTP          NNTPConfig          `yaml:"nntp"`
+	SSH           SSHConfig           `yaml:"ssh"`
+	Gemini        GeminiConfig        `yaml:"gemini"`
+}
+
+// NNTPConfig enables the gateway for newsreaders while Addr is set. Domain is the right side of the message ids of
+// articles and defaults to the host of PublicURL, or Host without one. CertPath and KeyPath are the certificate of
+// STARTTLS, newsreaders can only log in after STARTTLS unless InsecureAuth is set.
+type NNTPConfig struct {
+	Addr         string `yaml:"addr"`
+	Domain       string `yaml:"domain"`
+	CertPath     string `yaml:"cert_path"`
+	KeyPath      string `yaml:"key_path"`
+	InsecureAuth bool   `yaml:"insecure_auth"`
+}
+
+// SSHConfig enables the terminal UI over SSH while Addr is set. HostKeyPath is the private host key, an Ed25519 key
+// is generated there on the first start.
+type SSHConfig struct {
+	Addr        string `yaml:"addr"`
+	HostKeyPath string `yaml:"host_key_path"`
+}
+
+// GeminiConfig enables the Gemini server while Addr is set. Hostname is the host requests must be for and defaults
+// like NNTPConfig.Domain, a self-signed certificate for it is generated at CertPath and KeyPath on the first start.
+type GeminiConfig struct {
+	Addr     string `yaml:"addr"`
+	Hostname string `yaml:"hostname"`
+	CertPath string `yaml:"cert_path"`
+	KeyPath  string `yaml:"key_path"`
+}
+
+// AttachmentConfig limits uploads and selects where the files are stored. Storage is local, which keeps the files
+// in LocalPath, or s3 for any S3-compatible object store such as MinIO.
+type AttachmentConfig struct {
+	// MaxSize is the largest accepted file in bytes
+	MaxSize int64 `yaml:"max_size"`
+	// AllowedTypes are the accepted media types, detected from the content of the file rather than the upload
+	AllowedTypes []string `yaml:"allowed_types"`
+
+	Storage   string   `yaml:"storage"`
+	LocalPath string   `yaml:"local_path"`
+	S3        S3Config `yaml:"s3"`
+}
+
+type S3Config struct {
+	Endpoint  string `yaml:"endpoint"`
+	Region    string `yaml:"region"`
+	Bucket    string `yaml:"bucket"`
+	AccessKey string `yaml:"access_key"`
+	SecretKey string `yaml:"secret_key"`
+	UseSSL    bool   `yaml:"use_ssl"`
+}
+
+// ContentFilterConfig configures the checks new posts and comments go through. Every check is disabled while its
+// fields are zero. Actions are reject, hold (hidden until a moderator approves) or flag (published and reported).
+type ContentFilterConfig struct {
+	Rules []FilterRule `yaml:"rules"`
+
+	// Accounts younger than NewAccountAge may post at most NewAccountMaxLinks links per post or comment
+	NewAccountAge        time.Duration `yaml:"new_account_age"`
+	NewAccountMaxLinks   int           `yaml:"new_account_max_links"`
+	NewAccountLinkAction string        `yaml:"new_account_link_action"`
+
+	// Posting the same content again within DuplicateWindow triggers DuplicateAction
+	DuplicateWindow time.Duration `yaml:"duplicate_window"`
+	DuplicateAction string        `yaml:"duplicate_action"`
+}
+
+// FilterRule matches content by a regular expression or by a list of whole words, words ignore case
+type FilterRule struct {
+	Name    string   `yaml:"name"`
+	Pattern string   `yaml:"pattern"`
+	Words   []string `yaml:"words"`
+	Action  string   `yaml:"action"`
+}
+
+// RateLimitRule limits the requests to a route, Route is the pattern the route is registered with on the mux, for
+// example "POST /api/posts". Clients get Burst requests at once and Requests more every Per, Burst defaults to
+// Requests. Requests are counted per user, or per client IP before login. A rule with a Role applies to users with
+// exactly that role instead of the rule without one, a Requests of zero exempts them.
+type RateLimitRule struct {
+	Route    string        `yaml:"route"`
+	Role     string        `yaml:"role"`
+	Requests int           `yaml:"requests"`
+	Per      time.Duration `yaml:"per"`
+	Burst    int           `yaml:"burst"`
+}
+
+// defaultRateLimits protect logins, posts, comments and reports from floods, rate_limits in the config file replaces
+// all of them
+func defaultRateLimits() []RateLimitRule {
+	return []RateLimitRule{
+		{Route: "POST /api/login", Requests: 5, Per: time.Minute, Burst: 10},
+		{Route: "POST /api/posts", Requests: 5, Per: time.Minute},
+		{Route: "POST /api/post/{post_id}/comments", Requests: 20, Per: time.Minute, Burst: 5},
+		{Route: "POST /api/post/{post_id}/comments", Role: "MODERATOR", Requests: 0},
+		{Route: "POST /api/post/{id}/report", Requests: 10, Per: time.Hour, Burst: 5},
+		{Route: "POST /api/comment/{id}/report", Requests: 10, Per: time.Hour, Burst: 5},
+	}
 }
 
 func (c Config) Validate() error {
 	if c.DatabaseURL == "" {
 		return ErrDatabaseURLRequired
 	}
+	// a file config with an attachments section replaces the whole default section
+	if c.Attachments.MaxSize <= 0 || len(c.Attachments.AllowedTypes) == 0 {
+		return errors.New("attachments.max_size and attachments.allowed_types are required")
+	}
+	if (c.NNTP.CertPath == "") != (c.NNTP.KeyPath == "") {
+		return errors.New("nntp.cert_path and nntp.key_path must be set together")
+	}
+	if c.NNTP.Addr != "" && c.NNTP.CertPath == "" && !c.NNTP.InsecureAuth {
+		return errors.New("nntp.cert_path and nntp.key_path are required with nntp.addr unless nntp.insecure_auth is set")
+	}
+	if c.SSH.Addr != "" && c.SSH.HostKeyPath == "" {
+		return errors.New("ssh.host_key_path is required with ssh.addr")
+	}
+	if c.Gemini.Addr != "" && (c.Gemini.CertPath == "" || c.Gemini.KeyPath == "") {
+		return errors.New("gemini.cert_path and gemini.key_path are required with gemini.addr")
+	}
 
 	return nil
 }
@@ -75,6 +198,13 @@ func Load() (Config, *LogBuffer) {
 		DatabaseURL:      "",
 		MigrationSource:  "file://internal/database/migrations",
 		OtelCollectorUrl: "",
+		RateLimits:       defaultRateLimits(),
+		Attachments: AttachmentConfig{
+			MaxSize:      10 << 20,
+			AllowedTypes: []string{"image/png", "image/jpeg", "image/gif", "image/webp", "application/pdf", "text/plain"},
+			Storage:      "local",
+			LocalPath:    "data/attachments",
+		},
 	}
 
 	var err error
@@ -129,6 +259,7 @@ func FromEnv(config *Config, logger *LogBuffer) (*Config, error) {
 		DatabaseURL:      os.Getenv("DATABASE_URL"),
 		MigrationSource:  os.Getenv("MIGRATION_SOURCE"),
 		OtelCollectorUrl: os.Getenv("OTEL_COLLECTOR_URL"),
+		PublicURL:        os.Getenv("PUBLIC_URL"),
 	}
 
 	return merge(config, envConfig)
@@ -144,6 +275,7 @@ func FromFlags(config *Config) (*Config, error) {
 	flag.StringVar(&flagConfig.DatabaseURL, "database_url", "", "database url")
 	flag.StringVar(&flagConfig.MigrationSource, "migration_source", "", "migration source")
 	flag.StringVar(&flagConfig.OtelCollectorUrl, "otel_collector_url", "", "OpenTelemetry collector URL")
+	flag.StringVar(&flagConfig.PublicURL, "public_url", "", "public URL of the server")
 
 	flag.Parse()
 
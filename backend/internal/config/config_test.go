@@ -0,0 +1,61 @@
+package config_test
+
+import (
+	"backend/internal/config"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoad_RateLimits(t *testing.T) {
+	// Load defines the command line flags, so it runs once for all cases
+	defaults, _ := config.Load()
+	for _, route := range []string{"POST /api/login", "POST /api/posts", "POST /api/post/{post_id}/comments", "POST /api/post/{id}/report", "POST /api/comment/{id}/report"} {
+		assert.Positive(t, findRule(defaults.RateLimits, route).Requests, route)
+	}
+
+	tests := []struct {
+		name string
+		file string
+		want []config.RateLimitRule
+	}{
+		{
+			name: "Should keep the defaults without rate_limits",
+			file: "debug: true\n",
+			want: defaults.RateLimits,
+		},
+		{
+			name: "Should replace the defaults with rate_limits",
+			file: "rate_limits:\n  - route: POST /api/posts\n    requests: 1\n    per: 1h\n",
+			want: []config.RateLimitRule{{Route: "POST /api/posts", Requests: 1, Per: time.Hour}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			err := os.WriteFile(path, []byte(tt.file), 0o600)
+			if err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+
+			got, err := config.FromFile(path, &defaults)
+			if err != nil {
+				t.Fatalf("FromFile() error = %v", err)
+			}
+			assert.Equal(t, tt.want, got.RateLimits)
+		})
+	}
+}
+
+// findRule returns the rule of the route without a role, or a rule without requests if there is none
+func findRule(rules []config.RateLimitRule, route string) config.RateLimitRule {
+	for _, rule := range rules {
+		if rule.Route == route && rule.Role == "" {
+			return rule
+		}
+	}
+	return config.RateLimitRule{}
+}
@@ -4,18 +4,26 @@ CREATE EXTENSION IF NOT EXISTS "pgcrypto";
 
 CREATE TABLE IF NOT EXISTS comments (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
-    post_id UUID REFERENCES posts(id) NOT NULL,
+    post_id UUID REFERENCES posts(id) ON DELETE CASCADE NOT NULL,
     author_id UUID REFERENCES users(id) NOT NULL,
     title VARCHAR(200),
     content TEXT,
-    created_at TIMESTAMPTZ DEFAULT NOW()
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    parent_id UUID REFERENCES comments(id) ON DELETE CASCADE,
+    hidden_at TIMESTAMPTZ
 );CREATE EXTENSION IF NOT EXISTS "pgcrypto";
 
 CREATE TABLE IF NOT EXISTS users
 (
     id       UUID PRIMARY KEY DEFAULT gen_random_uuid(),
     name     VARCHAR(255) UNIQUE NOT NULL,
-    password VARCHAR(255) NOT NULL
+    password VARCHAR(255) NOT NULL,
+    role     VARCHAR(32) NOT NULL DEFAULT 'USER' CHECK (role IN ('USER', 'MODERATOR', 'ADMIN')),
+    suspended_at    TIMESTAMPTZ,
+    suspended_until TIMESTAMPTZ,
+    ban_reason      TEXT,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now()
 );CREATE EXTENSION IF NOT EXISTS pgcrypto;
 
 CREATE TABLE IF NOT EXISTS posts (
@@ -23,5 +31,285 @@ CREATE TABLE IF NOT EXISTS posts (
      author_id UUID REFERENCES users(id) NOT NULL,
      title VARCHAR(200),
      content TEXT,
-     create_at TIMESTAMPTZ DEFAULT now()
-);
\ No newline at end of file
+     create_at TIMESTAMPTZ DEFAULT now(),
+     updated_at TIMESTAMPTZ DEFAULT now(),
+     board_id UUID REFERENCES boards(id) ON DELETE SET NULL,
+     hidden_at TIMESTAMPTZ,
+     locked_at TIMESTAMPTZ,
+     pinned_at TIMESTAMPTZ,
+     pin_scope VARCHAR(16) CHECK (pin_scope IN ('global', 'board')),
+     archived_at TIMESTAMPTZ,
+     -- drafts are only visible to their author, scheduled posts are published at publish_at
+     status VARCHAR(16) DEFAULT 'published' NOT NULL CHECK (status IN ('draft', 'scheduled', 'published')),
+     publish_at TIMESTAMPTZ,
+     -- lowercase slugs, posts are listed in the feed of each of their tags
+     tags VARCHAR(32)[] DEFAULT '{}' NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS posts_scheduled_idx ON posts (publish_at) WHERE status = 'scheduled';
+CREATE INDEX IF NOT EXISTS posts_author_feed_idx ON posts (author_id, create_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS posts_board_feed_idx ON posts (board_id, create_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS posts_tags_idx ON posts USING GIN (tags);
+CREATE TABLE IF NOT EXISTS attachments (
+    id UUID PRIMARY KEY,
+    -- post_id is NULL once the post is deleted, the file is removed from storage by the next prune
+    post_id UUID REFERENCES posts(id) ON DELETE SET NULL,
+    uploader_id UUID REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(127) NOT NULL,
+    size BIGINT NOT NULL,
+    storage_key TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ DEFAULT now() NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS attachments_post_idx ON attachments (post_id, created_at);
+-- actor_id has no foreign key so the trail outlives deleted users, it is NULL for anonymous actions such as failed
+-- logins. target_id is NULL when the target does not exist, for example a login attempt for an unknown user.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    actor_id UUID,
+    action VARCHAR(64) NOT NULL,
+    target_type VARCHAR(32) NOT NULL,
+    target_id UUID,
+    details TEXT,
+    ip_address VARCHAR(45),
+    trace_id VARCHAR(32),
+    created_at TIMESTAMPTZ DEFAULT now() NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_target_idx ON audit_log (target_type, target_id, created_at);
+CREATE INDEX IF NOT EXISTS audit_log_actor_idx ON audit_log (actor_id, created_at);
+CREATE INDEX IF NOT EXISTS audit_log_created_at_idx ON audit_log (created_at);
+
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER audit_log_append_only
+    BEFORE UPDATE OR DELETE OR TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
+CREATE TABLE IF NOT EXISTS user_blocks (
+    blocker_id UUID REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    blocked_id UUID REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now() NOT NULL,
+    PRIMARY KEY (blocker_id, blocked_id),
+    CHECK (blocker_id <> blocked_id)
+);
+
+CREATE INDEX IF NOT EXISTS user_blocks_blocked_id_idx ON user_blocks (blocked_id);
+
+-- Muted users stay reachable, their posts and comments are only collapsed in the listings of the muter
+CREATE TABLE IF NOT EXISTS user_mutes (
+    muter_id UUID REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    muted_id UUID REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now() NOT NULL,
+    PRIMARY KEY (muter_id, muted_id),
+    CHECK (muter_id <> muted_id)
+);
+CREATE TABLE IF NOT EXISTS boards (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    slug VARCHAR(64) UNIQUE NOT NULL,
+    name VARCHAR(200) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMPTZ DEFAULT now() NOT NULL
+);CREATE TABLE IF NOT EXISTS bookmarks (
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    post_id UUID REFERENCES posts(id) ON DELETE CASCADE NOT NULL,
+    -- note is private to the user who saved the post
+    note TEXT,
+    created_at TIMESTAMPTZ DEFAULT now() NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT now() NOT NULL,
+    PRIMARY KEY (user_id, post_id)
+);
+
+CREATE INDEX IF NOT EXISTS bookmarks_user_created_idx ON bookmarks (user_id, created_at);
+CREATE TABLE IF NOT EXISTS client_certificates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    -- fingerprint is the hex encoded SHA-256 hash of the DER certificate and identifies the user on Gemini requests,
+    -- the certificates are usually self-signed so nothing but the fingerprint is trusted
+    fingerprint CHAR(64) UNIQUE NOT NULL,
+    subject TEXT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now() NOT NULL,
+    last_used_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS client_certificates_user_idx ON client_certificates (user_id, created_at);
+CREATE TABLE IF NOT EXISTS events (
+    id BIGSERIAL PRIMARY KEY,
+    type VARCHAR(64) NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now() NOT NULL
+);
+CREATE INDEX IF NOT EXISTS events_created_at_idx ON events (created_at);
+CREATE TABLE IF NOT EXISTS follows (
+    follower_id UUID REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    followee_id UUID REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now() NOT NULL,
+    PRIMARY KEY (follower_id, followee_id),
+    CHECK (follower_id <> followee_id)
+);
+
+CREATE INDEX IF NOT EXISTS follows_followee_id_idx ON follows (followee_id);
+CREATE TABLE IF NOT EXISTS mentions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    post_id UUID REFERENCES posts(id) ON DELETE CASCADE NOT NULL,
+    comment_id UUID REFERENCES comments(id) ON DELETE CASCADE,
+    start_offset INTEGER NOT NULL,
+    end_offset INTEGER NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now() NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS mentions_post_id_idx ON mentions (post_id);
+CREATE INDEX IF NOT EXISTS mentions_comment_id_idx ON mentions (comment_id);CREATE TABLE IF NOT EXISTS conversations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_by UUID REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now() NOT NULL,
+    updated_at TIMESTAMPTZ DEFAULT now() NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS conversation_members (
+    conversation_id UUID REFERENCES conversations(id) ON DELETE CASCADE NOT NULL,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    joined_at TIMESTAMPTZ DEFAULT now() NOT NULL,
+    PRIMARY KEY (conversation_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS conversation_members_user_id_idx ON conversation_members (user_id);
+
+CREATE TABLE IF NOT EXISTS messages (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    conversation_id UUID REFERENCES conversations(id) ON DELETE CASCADE NOT NULL,
+    author_id UUID REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    content TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now() NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS messages_conversation_id_created_at_idx ON messages (conversation_id, created_at DESC);
+-- Article numbers of the NNTP gateway. Newsreaders expect numbers that only grow within a group, so posts and comments
+-- get their number once and last_number keeps counting when numbered articles are deleted.
+CREATE TABLE IF NOT EXISTS nntp_groups (
+    board_id UUID PRIMARY KEY REFERENCES boards(id) ON DELETE CASCADE,
+    last_number BIGINT DEFAULT 0 NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS nntp_articles (
+    board_id UUID REFERENCES nntp_groups(board_id) ON DELETE CASCADE NOT NULL,
+    number BIGINT NOT NULL,
+    post_id UUID REFERENCES posts(id) ON DELETE CASCADE NOT NULL,
+    comment_id UUID REFERENCES comments(id) ON DELETE CASCADE,
+    PRIMARY KEY (board_id, number)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS nntp_articles_post_idx ON nntp_articles (post_id) WHERE comment_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS nntp_articles_comment_idx ON nntp_articles (comment_id);
+CREATE TABLE IF NOT EXISTS notifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    type VARCHAR(32) NOT NULL,
+    actor_id UUID REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    post_id UUID REFERENCES posts(id) ON DELETE CASCADE NOT NULL,
+    comment_id UUID REFERENCES comments(id) ON DELETE CASCADE,
+    read_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT now() NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS notifications_user_id_created_at_idx ON notifications (user_id, created_at DESC);CREATE TABLE IF NOT EXISTS polls (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    post_id UUID REFERENCES posts(id) ON DELETE CASCADE NOT NULL UNIQUE,
+    question VARCHAR(300) NOT NULL,
+    multiple_choice BOOLEAN DEFAULT false NOT NULL,
+    -- results are shown always, once the user voted or once the poll closed
+    results_visibility VARCHAR(16) DEFAULT 'always' NOT NULL CHECK (results_visibility IN ('always', 'after_vote', 'after_close')),
+    closes_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT now() NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS poll_options (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    poll_id UUID REFERENCES polls(id) ON DELETE CASCADE NOT NULL,
+    position INTEGER NOT NULL,
+    label VARCHAR(200) NOT NULL,
+    UNIQUE (poll_id, position),
+    UNIQUE (id, poll_id)
+);
+
+-- A ballot is the single vote of a user, it holds one or, in multiple choice polls, several options
+CREATE TABLE IF NOT EXISTS poll_ballots (
+    poll_id UUID REFERENCES polls(id) ON DELETE CASCADE NOT NULL,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now() NOT NULL,
+    PRIMARY KEY (poll_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS poll_votes (
+    poll_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    option_id UUID NOT NULL,
+    PRIMARY KEY (poll_id, user_id, option_id),
+    FOREIGN KEY (poll_id, user_id) REFERENCES poll_ballots(poll_id, user_id) ON DELETE CASCADE,
+    -- the option must belong to the poll of the ballot
+    FOREIGN KEY (option_id, poll_id) REFERENCES poll_options(id, poll_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS poll_votes_option_idx ON poll_votes (option_id);
+CREATE TABLE IF NOT EXISTS read_markers (
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    post_id UUID REFERENCES posts(id) ON DELETE CASCADE NOT NULL,
+    last_read_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (user_id, post_id)
+);
+CREATE TABLE IF NOT EXISTS reports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    post_id UUID REFERENCES posts(id) ON DELETE CASCADE NOT NULL,
+    comment_id UUID REFERENCES comments(id) ON DELETE CASCADE,
+    -- reporter_id is NULL for reports filed by the content filter
+    reporter_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    reason VARCHAR(32) NOT NULL CHECK (reason IN ('spam', 'harassment', 'off_topic', 'illegal', 'other', 'filter')),
+    details TEXT,
+    created_at TIMESTAMPTZ DEFAULT now() NOT NULL,
+    resolution VARCHAR(16) CHECK (resolution IN ('dismiss', 'hide', 'warn', 'approve')),
+    resolution_note TEXT,
+    resolved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    resolved_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS reports_open_idx ON reports (post_id, comment_id) WHERE resolved_at IS NULL;
+
+-- A user has one open report per target, comment_id is NULL for reports about the post itself and reporter_id for
+-- reports of the content filter, which may report a target again
+CREATE UNIQUE INDEX IF NOT EXISTS reports_open_reporter_idx
+    ON reports (reporter_id, post_id, COALESCE(comment_id, '00000000-0000-0000-0000-000000000000'))
+    WHERE resolved_at IS NULL;
+CREATE TABLE IF NOT EXISTS ssh_keys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    -- public_key is in authorized_keys format without comment, fingerprint is its SHA256 fingerprint as printed by
+    -- ssh-keygen -l and identifies the user on login
+    public_key TEXT NOT NULL,
+    fingerprint VARCHAR(64) UNIQUE NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now() NOT NULL,
+    last_used_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS ssh_keys_user_idx ON ssh_keys (user_id, created_at);
+CREATE TABLE IF NOT EXISTS watches (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    post_id UUID REFERENCES posts(id) ON DELETE CASCADE,
+    board_id UUID REFERENCES boards(id) ON DELETE CASCADE,
+    level VARCHAR(16) NOT NULL CHECK (level IN ('all', 'mentions', 'muted')),
+    created_at TIMESTAMPTZ DEFAULT now() NOT NULL,
+    CHECK ((post_id IS NULL) <> (board_id IS NULL)),
+    UNIQUE (user_id, post_id),
+    UNIQUE (user_id, board_id)
+);
+
+CREATE INDEX IF NOT EXISTS watches_post_id_idx ON watches (post_id);
+CREATE INDEX IF NOT EXISTS watches_board_id_idx ON watches (board_id);
\ No newline at end of file
@@ -0,0 +1,21 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(32) NOT NULL DEFAULT 'USER' CHECK (role IN ('USER', 'MODERATOR', 'ADMIN'));
+
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS hidden_at TIMESTAMPTZ;
+
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS hidden_at TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS reports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    post_id UUID REFERENCES posts(id) ON DELETE CASCADE NOT NULL,
+    comment_id UUID REFERENCES comments(id) ON DELETE CASCADE,
+    reporter_id UUID REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    reason VARCHAR(32) NOT NULL CHECK (reason IN ('spam', 'harassment', 'off_topic', 'illegal', 'other')),
+    details TEXT,
+    created_at TIMESTAMPTZ DEFAULT now() NOT NULL,
+    resolution VARCHAR(16) CHECK (resolution IN ('dismiss', 'hide', 'warn')),
+    resolution_note TEXT,
+    resolved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    resolved_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS reports_open_idx ON reports (post_id, comment_id) WHERE resolved_at IS NULL;
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS audit_log;
+
+ALTER TABLE posts DROP COLUMN IF EXISTS archived_at;
+ALTER TABLE posts DROP COLUMN IF EXISTS pin_scope;
+ALTER TABLE posts DROP COLUMN IF EXISTS pinned_at;
+ALTER TABLE posts DROP COLUMN IF EXISTS locked_at;
@@ -0,0 +1,11 @@
+DELETE FROM reports WHERE reporter_id IS NULL;
+UPDATE reports SET resolution = 'dismiss' WHERE resolution = 'approve';
+ALTER TABLE reports DROP CONSTRAINT IF EXISTS reports_resolution_check;
+ALTER TABLE reports ADD CONSTRAINT reports_resolution_check
+    CHECK (resolution IN ('dismiss', 'hide', 'warn'));
+ALTER TABLE reports DROP CONSTRAINT IF EXISTS reports_reason_check;
+ALTER TABLE reports ADD CONSTRAINT reports_reason_check
+    CHECK (reason IN ('spam', 'harassment', 'off_topic', 'illegal', 'other'));
+ALTER TABLE reports ALTER COLUMN reporter_id SET NOT NULL;
+
+ALTER TABLE users DROP COLUMN IF EXISTS created_at;
@@ -0,0 +1,13 @@
+-- Accounts that existed before count as established for the new account heuristics
+ALTER TABLE users ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ;
+UPDATE users SET created_at = 'epoch' WHERE created_at IS NULL;
+ALTER TABLE users ALTER COLUMN created_at SET DEFAULT now();
+ALTER TABLE users ALTER COLUMN created_at SET NOT NULL;
+
+ALTER TABLE reports ALTER COLUMN reporter_id DROP NOT NULL;
+ALTER TABLE reports DROP CONSTRAINT IF EXISTS reports_reason_check;
+ALTER TABLE reports ADD CONSTRAINT reports_reason_check
+    CHECK (reason IN ('spam', 'harassment', 'off_topic', 'illegal', 'other', 'filter'));
+ALTER TABLE reports DROP CONSTRAINT IF EXISTS reports_resolution_check;
+ALTER TABLE reports ADD CONSTRAINT reports_resolution_check
+    CHECK (resolution IN ('dismiss', 'hide', 'warn', 'approve'));
@@ -0,0 +1,12 @@
+DROP TRIGGER IF EXISTS audit_log_append_only ON audit_log;
+DROP FUNCTION IF EXISTS audit_log_append_only();
+
+DROP INDEX IF EXISTS audit_log_created_at_idx;
+DROP INDEX IF EXISTS audit_log_actor_idx;
+
+ALTER TABLE audit_log DROP COLUMN IF EXISTS trace_id;
+ALTER TABLE audit_log DROP COLUMN IF EXISTS ip_address;
+-- anonymous entries cannot be kept once actor_id and target_id are required again
+DELETE FROM audit_log WHERE actor_id IS NULL OR target_id IS NULL;
+ALTER TABLE audit_log ALTER COLUMN target_id SET NOT NULL;
+ALTER TABLE audit_log ALTER COLUMN actor_id SET NOT NULL;
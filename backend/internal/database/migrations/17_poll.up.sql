@@ -0,0 +1,39 @@
+CREATE TABLE IF NOT EXISTS polls (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    post_id UUID REFERENCES posts(id) ON DELETE CASCADE NOT NULL UNIQUE,
+    question VARCHAR(300) NOT NULL,
+    multiple_choice BOOLEAN DEFAULT false NOT NULL,
+    -- results are shown always, once the user voted or once the poll closed
+    results_visibility VARCHAR(16) DEFAULT 'always' NOT NULL CHECK (results_visibility IN ('always', 'after_vote', 'after_close')),
+    closes_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT now() NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS poll_options (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    poll_id UUID REFERENCES polls(id) ON DELETE CASCADE NOT NULL,
+    position INTEGER NOT NULL,
+    label VARCHAR(200) NOT NULL,
+    UNIQUE (poll_id, position),
+    UNIQUE (id, poll_id)
+);
+
+-- A ballot is the single vote of a user, it holds one or, in multiple choice polls, several options
+CREATE TABLE IF NOT EXISTS poll_ballots (
+    poll_id UUID REFERENCES polls(id) ON DELETE CASCADE NOT NULL,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now() NOT NULL,
+    PRIMARY KEY (poll_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS poll_votes (
+    poll_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    option_id UUID NOT NULL,
+    PRIMARY KEY (poll_id, user_id, option_id),
+    FOREIGN KEY (poll_id, user_id) REFERENCES poll_ballots(poll_id, user_id) ON DELETE CASCADE,
+    -- the option must belong to the poll of the ballot
+    FOREIGN KEY (option_id, poll_id) REFERENCES poll_options(id, poll_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS poll_votes_option_idx ON poll_votes (option_id);
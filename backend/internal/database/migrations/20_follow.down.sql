@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS posts_board_feed_idx;
+DROP INDEX IF EXISTS posts_author_feed_idx;
+DROP TABLE IF EXISTS follows;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS follows (
+    follower_id UUID REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    followee_id UUID REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now() NOT NULL,
+    PRIMARY KEY (follower_id, followee_id),
+    CHECK (follower_id <> followee_id)
+);
+
+CREATE INDEX IF NOT EXISTS follows_followee_id_idx ON follows (followee_id);
+
+-- Keyset scans for the personalized feed
+CREATE INDEX IF NOT EXISTS posts_author_feed_idx ON posts (author_id, create_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS posts_board_feed_idx ON posts (board_id, create_at DESC, id DESC);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS nntp_articles;
+DROP TABLE IF EXISTS nntp_groups;
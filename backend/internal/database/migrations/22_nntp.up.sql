@@ -0,0 +1,17 @@
+-- Article numbers of the NNTP gateway. Newsreaders expect numbers that only grow within a group, so posts and comments
+-- get their number once and last_number keeps counting when numbered articles are deleted.
+CREATE TABLE IF NOT EXISTS nntp_groups (
+    board_id UUID PRIMARY KEY REFERENCES boards(id) ON DELETE CASCADE,
+    last_number BIGINT DEFAULT 0 NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS nntp_articles (
+    board_id UUID REFERENCES nntp_groups(board_id) ON DELETE CASCADE NOT NULL,
+    number BIGINT NOT NULL,
+    post_id UUID REFERENCES posts(id) ON DELETE CASCADE NOT NULL,
+    comment_id UUID REFERENCES comments(id) ON DELETE CASCADE,
+    PRIMARY KEY (board_id, number)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS nntp_articles_post_idx ON nntp_articles (post_id) WHERE comment_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS nntp_articles_comment_idx ON nntp_articles (comment_id);
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS events_created_at_idx ON events (created_at);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS reports_open_reporter_idx;
@@ -0,0 +1,11 @@
+-- Reports filed twice by concurrent requests, the oldest open report of a reporter about a target is kept
+DELETE FROM reports r
+USING reports o
+WHERE r.resolved_at IS NULL AND o.resolved_at IS NULL
+  AND r.reporter_id = o.reporter_id AND r.post_id = o.post_id
+  AND r.comment_id IS NOT DISTINCT FROM o.comment_id
+  AND (o.created_at, o.id) < (r.created_at, r.id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS reports_open_reporter_idx
+    ON reports (reporter_id, post_id, COALESCE(comment_id, '00000000-0000-0000-0000-000000000000'))
+    WHERE resolved_at IS NULL;
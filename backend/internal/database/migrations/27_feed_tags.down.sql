@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS posts_tags_idx;
+ALTER TABLE posts DROP COLUMN IF EXISTS tags;
+
+ALTER TABLE comments DROP COLUMN IF EXISTS updated_at;
@@ -0,0 +1,7 @@
+-- Existing comments count as unedited, new comments get the time of their creation like posts
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ;
+UPDATE comments SET updated_at = created_at WHERE updated_at IS NULL;
+ALTER TABLE comments ALTER COLUMN updated_at SET DEFAULT now();
+
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS tags VARCHAR(32)[] NOT NULL DEFAULT '{}';
+CREATE INDEX IF NOT EXISTS posts_tags_idx ON posts USING GIN (tags);
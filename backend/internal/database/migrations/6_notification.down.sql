@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS notifications;
+
+ALTER TABLE comments DROP COLUMN IF EXISTS parent_id;
\ No newline at end of file
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS watches;
+
+ALTER TABLE posts DROP COLUMN IF EXISTS board_id;
+
+DROP TABLE IF EXISTS boards;
\ No newline at end of file
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS boards (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    slug VARCHAR(64) UNIQUE NOT NULL,
+    name VARCHAR(200) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMPTZ DEFAULT now() NOT NULL
+);
+
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS board_id UUID REFERENCES boards(id) ON DELETE SET NULL;
+
+CREATE TABLE IF NOT EXISTS watches (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    post_id UUID REFERENCES posts(id) ON DELETE CASCADE,
+    board_id UUID REFERENCES boards(id) ON DELETE CASCADE,
+    level VARCHAR(16) NOT NULL CHECK (level IN ('all', 'mentions', 'muted')),
+    created_at TIMESTAMPTZ DEFAULT now() NOT NULL,
+    CHECK ((post_id IS NULL) <> (board_id IS NULL)),
+    UNIQUE (user_id, post_id),
+    UNIQUE (user_id, board_id)
+);
+
+CREATE INDEX IF NOT EXISTS watches_post_id_idx ON watches (post_id);
+CREATE INDEX IF NOT EXISTS watches_board_id_idx ON watches (board_id);
+
+-- Authors and commenters keep being notified about their threads
+INSERT INTO watches (user_id, post_id, level)
+SELECT author_id, id, 'all' FROM posts
+UNION
+SELECT author_id, post_id, 'all' FROM comments
+ON CONFLICT DO NOTHING;
\ No newline at end of file
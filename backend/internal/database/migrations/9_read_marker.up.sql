@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS read_markers (
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    post_id UUID REFERENCES posts(id) ON DELETE CASCADE NOT NULL,
+    last_read_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (user_id, post_id)
+);
@@ -3,6 +3,7 @@ package error
 import (
 	"errors"
 	"fmt"
+	"time"
 )
 
 var (
@@ -13,6 +14,20 @@ var (
 	ErrUnauthorized      = errors.New("unauthorized")
 	ErrInternalServer    = errors.New("internal server error")
 	ErrInvalidUUID       = errors.New("failed to parse UUID")
+	ErrInvalidQuery      = errors.New("invalid query parameter")
+	ErrBlocked           = errors.New("blocked by user")
+	ErrAlreadyReported   = errors.New("content already reported")
+	ErrSuspended         = errors.New("user suspended")
+	ErrPostLocked        = errors.New("post locked")
+	ErrPostArchived      = errors.New("post archived")
+	ErrInvalidRequest    = errors.New("invalid request")
+	ErrContentRejected   = errors.New("content rejected")
+	ErrRateLimited       = errors.New("rate limit exceeded")
+	ErrInvalidMarkdown   = errors.New("invalid markdown")
+	ErrFileTooLarge      = errors.New("file too large")
+	ErrUnsupportedType   = errors.New("unsupported media type")
+	ErrPollClosed        = errors.New("poll closed")
+	ErrAlreadyVoted      = errors.New("already voted")
 )
 
 type NotFoundError struct {
@@ -44,3 +59,27 @@ func NewNotFoundError(table, key, value, message string) NotFoundError {
 		Message: message,
 	}
 }
+
+// SuspendedError is returned for suspended users, a nil Until means the user is banned permanently
+type SuspendedError struct {
+	Until  *time.Time
+	Reason string
+}
+
+func (e SuspendedError) Error() string {
+	if e.Until == nil {
+		return fmt.Sprintf("banned: %s", e.Reason)
+	}
+	return fmt.Sprintf("suspended until %s: %s", e.Until.Format(time.RFC3339), e.Reason)
+}
+
+func (e SuspendedError) Is(target error) bool {
+	return errors.Is(target, ErrSuspended)
+}
+
+func NewSuspendedError(until *time.Time, reason string) SuspendedError {
+	return SuspendedError{
+		Until:  until,
+		Reason: reason,
+	}
+}
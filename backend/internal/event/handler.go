@@ -0,0 +1,147 @@
+package event
+
+import (
+	"backend/internal"
+	"backend/internal/problem"
+	"context"
+	"fmt"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// heartbeatInterval keeps idle connections open through proxies that close silent streams
+const heartbeatInterval = 15 * time.Second
+
+//go:generate mockery --name=Store
+type Store interface {
+	GetAfter(ctx context.Context, id int64) ([]Event, error)
+	Subscribe() (<-chan Event, func())
+}
+
+type Handler struct {
+	logger *zap.Logger
+	tracer trace.Tracer
+	store  Store
+}
+
+func NewHandler(logger *zap.Logger, store Store) *Handler {
+	return &Handler{
+		logger: logger,
+		tracer: otel.Tracer("event/handler"),
+		store:  store,
+	}
+}
+
+// StreamHandler streams forum events as Server-Sent Events. Clients resuming with the Last-Event-ID header (or the
+// last_event_id query parameter for clients that cannot set headers) first receive the events they missed, as far as
+// they are still kept, see Service.RunRetention.
+func (h *Handler) StreamHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "StreamEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+	lastID, err := ParseID(lastEventID)
+	if err != nil {
+		logger.Warn("Ignoring invalid Last-Event-ID", zap.String("last_event_id", lastEventID))
+		lastID = 0
+	}
+
+	// Subscribe before replaying so no event falls in the gap between the two, duplicates are skipped by ID
+	events, unsubscribe := h.store.Subscribe()
+	defer unsubscribe()
+
+	var missed []Event
+	if lastID > 0 {
+		missed, err = h.store.GetAfter(traceCtx, lastID)
+		if err != nil {
+			problem.WriteError(traceCtx, w, err, logger)
+			return
+		}
+	}
+
+	controller := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	// GetAfter returns a page of events, the replay continues until a short page reaches the newest event
+	replayed := 0
+	for len(missed) > 0 {
+		for _, e := range missed {
+			err = writeEvent(w, e)
+			if err != nil {
+				logger.Debug("Failed to replay event", zap.Error(err))
+				return
+			}
+			lastID = e.ID
+		}
+		replayed += len(missed)
+		if len(missed) < replayLimit {
+			break
+		}
+
+		err = controller.Flush()
+		if err != nil {
+			logger.Warn("Streaming is not supported by the response writer", zap.Error(err))
+			return
+		}
+		missed, err = h.store.GetAfter(traceCtx, lastID)
+		if err != nil {
+			// the client resumes from the last replayed event when it reconnects
+			logger.Error("Failed to replay events", zap.Int64("last_event_id", lastID), zap.Error(err))
+			return
+		}
+	}
+	err = controller.Flush()
+	if err != nil {
+		logger.Warn("Streaming is not supported by the response writer", zap.Error(err))
+		return
+	}
+
+	logger.Debug("Event stream opened", zap.Int64("last_event_id", lastID), zap.Int("replayed", replayed))
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			logger.Debug("Event stream closed by client")
+			return
+		case <-heartbeat.C:
+			_, err = fmt.Fprint(w, ": heartbeat\n\n")
+		case e, ok := <-events:
+			if !ok {
+				logger.Debug("Event stream subscription closed")
+				return
+			}
+			if e.ID <= lastID {
+				continue
+			}
+			err = writeEvent(w, e)
+			lastID = e.ID
+		}
+
+		if err == nil {
+			err = controller.Flush()
+		}
+		if err != nil {
+			logger.Debug("Event stream write failed", zap.Error(err))
+			return
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, e Event) error {
+	_, err := fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", strconv.FormatInt(e.ID, 10), e.Type, e.Payload)
+	return err
+}
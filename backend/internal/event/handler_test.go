@@ -0,0 +1,99 @@
+package event_test
+
+import (
+	"backend/internal/event"
+	"backend/internal/event/mocks"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler_StreamHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		lastEventID string
+		setupMock   func(m *mocks.Store, live chan event.Event)
+		wantBody    string
+	}{
+		{
+			name:        "Should replay missed events and skip duplicates from the live subscription",
+			lastEventID: "1",
+			setupMock: func(m *mocks.Store, live chan event.Event) {
+				m.On("GetAfter", mock.Anything, int64(1)).Return([]event.Event{
+					{ID: 2, Type: event.PostCreated, Payload: []byte(`{"id":"a"}`)},
+					{ID: 3, Type: event.CommentCreated, Payload: []byte(`{"id":"b"}`)},
+				}, nil)
+				live <- event.Event{ID: 3, Type: event.CommentCreated, Payload: []byte(`{"id":"b"}`)}
+				live <- event.Event{ID: 4, Type: event.PostDeleted, Payload: []byte(`{"id":"a"}`)}
+			},
+			wantBody: "id: 2\nevent: post.created\ndata: {\"id\":\"a\"}\n\n" +
+				"id: 3\nevent: comment.created\ndata: {\"id\":\"b\"}\n\n" +
+				"id: 4\nevent: post.deleted\ndata: {\"id\":\"a\"}\n\n",
+		},
+		{
+			name:        "Should only stream live events without Last-Event-ID",
+			lastEventID: "",
+			setupMock: func(m *mocks.Store, live chan event.Event) {
+				live <- event.Event{ID: 7, Type: event.PostUpdated, Payload: []byte(`{"id":"c"}`)}
+			},
+			wantBody: "id: 7\nevent: post.updated\ndata: {\"id\":\"c\"}\n\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(mocks.Store)
+			live := make(chan event.Event, 8)
+			tt.setupMock(m, live)
+			close(live)
+			m.On("Subscribe").Return((<-chan event.Event)(live), func() {})
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/api/stream", nil)
+			if tt.lastEventID != "" {
+				r.Header.Set("Last-Event-ID", tt.lastEventID)
+			}
+
+			h := event.NewHandler(zap.NewNop(), m)
+			h.StreamHandler(w, r)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+			assert.Equal(t, tt.wantBody, w.Body.String())
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_StreamHandler_ReplaysEveryPage(t *testing.T) {
+	// GetAfter returns pages of 500 events, see replayLimit
+	var page []event.Event
+	var want strings.Builder
+	for id := int64(2); id <= 501; id++ {
+		page = append(page, event.Event{ID: id, Type: event.PostUpdated, Payload: []byte(`{}`)})
+		fmt.Fprintf(&want, "id: %d\nevent: post.updated\ndata: {}\n\n", id)
+	}
+	want.WriteString("id: 502\nevent: post.deleted\ndata: {}\n\n")
+
+	m := new(mocks.Store)
+	m.On("GetAfter", mock.Anything, int64(1)).Return(page, nil)
+	m.On("GetAfter", mock.Anything, int64(501)).Return([]event.Event{{ID: 502, Type: event.PostDeleted, Payload: []byte(`{}`)}}, nil)
+	live := make(chan event.Event, 1)
+	live <- event.Event{ID: 502, Type: event.PostDeleted, Payload: []byte(`{}`)}
+	close(live)
+	m.On("Subscribe").Return((<-chan event.Event)(live), func() {})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/api/stream", nil)
+	r.Header.Set("Last-Event-ID", "1")
+
+	event.NewHandler(zap.NewNop(), m).StreamHandler(w, r)
+
+	assert.Equal(t, want.String(), w.Body.String())
+	m.AssertExpectations(t)
+}
@@ -0,0 +1,21 @@
+-- name: Create :one
+INSERT INTO events (type, payload) VALUES ($1, $2) RETURNING *;
+
+-- name: FindByID :one
+SELECT * FROM events WHERE id = $1;
+
+-- name: FindAfter :many
+SELECT * FROM events WHERE id > $1 ORDER BY id LIMIT $2;
+
+-- name: FindLastID :one
+SELECT COALESCE(MAX(id), 0)::bigint AS id FROM events;
+
+-- name: Notify :exec
+SELECT pg_notify(@channel::text, @payload::text);
+
+-- name: LockPublish :exec
+-- Serializes publishers until their transaction ends, so event ids are committed in increasing order
+SELECT pg_advisory_xact_lock(@key::bigint);
+
+-- name: DeleteBefore :execrows
+DELETE FROM events WHERE created_at < $1;
@@ -0,0 +1,130 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: queries.sql
+
+package event
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const create = `-- name: Create :one
+INSERT INTO events (type, payload) VALUES ($1, $2) RETURNING id, type, payload, created_at
+`
+
+type CreateParams struct {
+	Type    string
+	Payload []byte
+}
+
+func (q *Queries) Create(ctx context.Context, arg CreateParams) (Event, error) {
+	row := q.db.QueryRow(ctx, create, arg.Type, arg.Payload)
+	var i Event
+	err := row.Scan(
+		&i.ID,
+		&i.Type,
+		&i.Payload,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteBefore = `-- name: DeleteBefore :execrows
+DELETE FROM events WHERE created_at < $1
+`
+
+func (q *Queries) DeleteBefore(ctx context.Context, createdAt pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteBefore, createdAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const findAfter = `-- name: FindAfter :many
+SELECT id, type, payload, created_at FROM events WHERE id > $1 ORDER BY id LIMIT $2
+`
+
+type FindAfterParams struct {
+	ID    int64
+	Limit int32
+}
+
+func (q *Queries) FindAfter(ctx context.Context, arg FindAfterParams) ([]Event, error) {
+	rows, err := q.db.Query(ctx, findAfter, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Event
+	for rows.Next() {
+		var i Event
+		if err := rows.Scan(
+			&i.ID,
+			&i.Type,
+			&i.Payload,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findByID = `-- name: FindByID :one
+SELECT id, type, payload, created_at FROM events WHERE id = $1
+`
+
+func (q *Queries) FindByID(ctx context.Context, id int64) (Event, error) {
+	row := q.db.QueryRow(ctx, findByID, id)
+	var i Event
+	err := row.Scan(
+		&i.ID,
+		&i.Type,
+		&i.Payload,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const findLastID = `-- name: FindLastID :one
+SELECT COALESCE(MAX(id), 0)::bigint AS id FROM events
+`
+
+func (q *Queries) FindLastID(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, findLastID)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const lockPublish = `-- name: LockPublish :exec
+SELECT pg_advisory_xact_lock($1::bigint)
+`
+
+// Serializes publishers until their transaction ends, so event ids are committed in increasing order
+func (q *Queries) LockPublish(ctx context.Context, key int64) error {
+	_, err := q.db.Exec(ctx, lockPublish, key)
+	return err
+}
+
+const notify = `-- name: Notify :exec
+SELECT pg_notify($1::text, $2::text)
+`
+
+type NotifyParams struct {
+	Channel string
+	Payload string
+}
+
+func (q *Queries) Notify(ctx context.Context, arg NotifyParams) error {
+	_, err := q.db.Exec(ctx, notify, arg.Channel, arg.Payload)
+	return err
+}
@@ -0,0 +1,342 @@
+package event
+
+import (
+	"backend/internal"
+	"backend/internal/database"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Channel is the Postgres NOTIFY channel used to tell every backend instance that a new event was stored
+const Channel = "forum_events"
+
+const (
+	PostCreated    = "post.created"
+	PostUpdated    = "post.updated"
+	PostDeleted    = "post.deleted"
+	CommentCreated = "comment.created"
+	CommentUpdated = "comment.updated"
+	CommentDeleted = "comment.deleted"
+)
+
+// subscriberBuffer is the number of events buffered per subscriber before it is considered too slow and dropped
+const subscriberBuffer = 64
+
+// replayLimit caps the number of missed events sent to a client resuming with Last-Event-ID
+const replayLimit = 500
+
+const (
+	// retention is how long events are kept, clients resuming after a longer absence miss the pruned events
+	retention     = 24 * time.Hour
+	pruneInterval = time.Hour
+)
+
+// publishLockKey is the advisory lock that orders publishers, see Publish
+const publishLockKey = 0x6576656e7473
+
+type Service struct {
+	logger *zap.Logger
+	tracer trace.Tracer
+	query  *Queries
+	pool   *pgxpool.Pool
+
+	mu          sync.Mutex
+	subscribers map[chan Event]struct{}
+
+	// lastID is the last event Listen broadcast, a new connection resumes after it so events notified while the
+	// connection was lost still reach the subscribers. Only the goroutine of Listen uses it.
+	lastID   int64
+	listened bool
+}
+
+func NewService(logger *zap.Logger, pool *pgxpool.Pool) *Service {
+	return &Service{
+		logger:      logger,
+		tracer:      otel.Tracer("event/service"),
+		query:       New(pool),
+		pool:        pool,
+		subscribers: make(map[chan Event]struct{}),
+	}
+}
+
+// Publish stores the event and notifies all listening backend instances about it. The payload is marshalled as JSON.
+//
+// Publishers hold an advisory lock from taking the event ID until the commit, so events become visible in the order
+// of their IDs. Without it a concurrent publisher could commit a lower ID after a client already received a higher
+// one, and resuming from Last-Event-ID would skip the lower event.
+func (s *Service) Publish(ctx context.Context, eventType string, payload any) error {
+	traceCtx, span := s.tracer.Start(ctx, "Publish")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	tx, err := s.pool.Begin(traceCtx)
+	if err != nil {
+		err = database.WrapDBError(err, logger, "begin publish event")
+		span.RecordError(err)
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(context.WithoutCancel(traceCtx))
+	}()
+	query := s.query.WithTx(tx)
+
+	err = query.LockPublish(traceCtx, publishLockKey)
+	if err != nil {
+		err = database.WrapDBError(err, logger, "lock publish event")
+		span.RecordError(err)
+		return err
+	}
+
+	e, err := query.Create(traceCtx, CreateParams{
+		Type:    eventType,
+		Payload: payloadBytes,
+	})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "create event")
+		span.RecordError(err)
+		return err
+	}
+
+	// the notification is delivered on commit, after the event can be read
+	err = query.Notify(traceCtx, NotifyParams{
+		Channel: Channel,
+		Payload: strconv.FormatInt(e.ID, 10),
+	})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "notify event")
+		span.RecordError(err)
+		return err
+	}
+
+	err = tx.Commit(traceCtx)
+	if err != nil {
+		err = database.WrapDBError(err, logger, "commit event")
+		span.RecordError(err)
+		return err
+	}
+
+	logger.Debug("Published event", zap.Int64("id", e.ID), zap.String("type", eventType))
+	return nil
+}
+
+// GetAfter returns the events stored after the given ID in order, used to resume a stream from Last-Event-ID
+func (s *Service) GetAfter(ctx context.Context, id int64) ([]Event, error) {
+	traceCtx, span := s.tracer.Start(ctx, "GetAfter")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	events, err := s.query.FindAfter(traceCtx, FindAfterParams{
+		ID:    id,
+		Limit: replayLimit,
+	})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "get events after id")
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// Subscribe registers a new in-process subscriber. The returned channel receives every event delivered by Listen
+// and is closed when the cancel function is called or when the subscriber falls too far behind.
+func (s *Service) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+
+	s.mu.Lock()
+	s.subscribers[ch] = struct{}{}
+	s.mu.Unlock()
+
+	return ch, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if _, ok := s.subscribers[ch]; ok {
+			delete(s.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// CloseSubscribers closes every subscription so open streams end, it is registered to run on server shutdown
+// because streams never become idle on their own.
+func (s *Service) CloseSubscribers() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for ch := range s.subscribers {
+		delete(s.subscribers, ch)
+		close(ch)
+	}
+}
+
+// Listen blocks until ctx is done, receiving notifications on Channel and fanning the stored events out to the
+// in-process subscribers. The connection is re-established with a backoff when it is lost, the events stored in the
+// meantime are broadcast before waiting for notifications again.
+func (s *Service) Listen(ctx context.Context) {
+	backoff := time.Second
+	for {
+		err := s.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		s.logger.Warn("Event listener disconnected, retrying", zap.Error(err), zap.Duration("backoff", backoff))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff = min(backoff*2, 30*time.Second)
+	}
+}
+
+func (s *Service) listen(ctx context.Context) error {
+	conn, err := s.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	// the first connection starts at the newest event, subscribers only expect events from now on
+	if !s.listened {
+		s.lastID, err = s.query.FindLastID(ctx)
+		if err != nil {
+			return err
+		}
+		s.listened = true
+	}
+
+	_, err = conn.Exec(ctx, "LISTEN "+Channel)
+	if err != nil {
+		return err
+	}
+	s.logger.Info("Listening for events", zap.String("channel", Channel), zap.Int64("last_event_id", s.lastID))
+
+	err = s.catchUp(ctx)
+	if err != nil {
+		return err
+	}
+
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		id, err := strconv.ParseInt(notification.Payload, 10, 64)
+		if err != nil {
+			s.logger.Warn("Received malformed event notification", zap.String("payload", notification.Payload))
+			continue
+		}
+		if id <= s.lastID {
+			// already broadcast by catchUp
+			continue
+		}
+
+		e, err := s.query.FindByID(ctx, id)
+		if err != nil {
+			s.logger.Error("Failed to load notified event", zap.Int64("id", id), zap.Error(err))
+			continue
+		}
+
+		s.broadcast(e)
+	}
+}
+
+// catchUp broadcasts the events stored after the last broadcast one. It runs once LISTEN is active, so later events
+// are notified and none falls in between.
+func (s *Service) catchUp(ctx context.Context) error {
+	for {
+		events, err := s.query.FindAfter(ctx, FindAfterParams{
+			ID:    s.lastID,
+			Limit: replayLimit,
+		})
+		if err != nil {
+			return err
+		}
+		for _, e := range events {
+			s.broadcast(e)
+		}
+		if len(events) < replayLimit {
+			return nil
+		}
+	}
+}
+
+func (s *Service) broadcast(e Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastID = e.ID
+
+	for ch := range s.subscribers {
+		select {
+		case ch <- e:
+		default:
+			s.logger.Warn("Dropping slow event subscriber", zap.Int64("event_id", e.ID))
+			delete(s.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// RunRetention deletes events older than the retention until the context is done
+func (s *Service) RunRetention(ctx context.Context) {
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.prune(ctx)
+		}
+	}
+}
+
+func (s *Service) prune(ctx context.Context) {
+	traceCtx, span := s.tracer.Start(ctx, "Prune")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	count, err := s.query.DeleteBefore(traceCtx, pgtype.Timestamptz{Time: time.Now().Add(-retention), Valid: true})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "delete old events")
+		span.RecordError(err)
+		return
+	}
+
+	if count > 0 {
+		logger.Info("Deleted old events", zap.Int64("count", count))
+	}
+}
+
+// ParseID parses a Last-Event-ID value, an empty value resumes from nothing
+func ParseID(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id < 0 {
+		return 0, errors.New("invalid event id")
+	}
+
+	return id, nil
+}
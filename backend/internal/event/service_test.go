@@ -0,0 +1,84 @@
+package event_test
+
+import (
+	"backend/internal/database/databasetest"
+	"backend/internal/event"
+	"context"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestService_ListenResumesAfterReconnect(t *testing.T) {
+	pool := databasetest.Open(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the listener has a pool of its own, named so the test can drop its connection
+	name := "events-" + uuid.NewString()
+	config := pool.Config()
+	config.ConnConfig.RuntimeParams["application_name"] = name
+	listenPool, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		t.Fatalf("failed to connect to database: %v", err)
+	}
+	t.Cleanup(listenPool.Close)
+
+	listener := event.NewService(zap.NewNop(), listenPool)
+	publisher := event.NewService(zap.NewNop(), pool)
+	events, unsubscribe := listener.Subscribe()
+	defer unsubscribe()
+	go listener.Listen(ctx)
+
+	// events are only delivered once the listener is connected
+	connected := false
+	for range 50 {
+		err = publisher.Publish(ctx, event.PostCreated, map[string]string{"test": name, "step": "connected"})
+		if err != nil {
+			t.Fatalf("failed to publish event: %v", err)
+		}
+		if receive(events, name, "connected", 200*time.Millisecond) {
+			connected = true
+			break
+		}
+	}
+	if !connected {
+		t.Fatal("listener did not connect")
+	}
+
+	_, err = pool.Exec(ctx, "SELECT pg_terminate_backend(pid) FROM pg_stat_activity WHERE application_name = $1", name)
+	if err != nil {
+		t.Fatalf("failed to drop listener connection: %v", err)
+	}
+	// published while the listener waits to reconnect, so its notification is lost
+	err = publisher.Publish(ctx, event.PostDeleted, map[string]string{"test": name, "step": "missed"})
+	if err != nil {
+		t.Fatalf("failed to publish event: %v", err)
+	}
+
+	if !receive(events, name, "missed", 10*time.Second) {
+		t.Fatal("event published during the reconnect was not broadcast")
+	}
+}
+
+// receive waits for the event of the test at the given step, events of other tests sharing the database are skipped
+func receive(events <-chan event.Event, name, step string, timeout time.Duration) bool {
+	deadline := time.After(timeout)
+	for {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				return false
+			}
+			payload := string(e.Payload)
+			if strings.Contains(payload, name) && strings.Contains(payload, `"step":"`+step+`"`) {
+				return true
+			}
+		case <-deadline:
+			return false
+		}
+	}
+}
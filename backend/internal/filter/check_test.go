@@ -0,0 +1,226 @@
+package filter_test
+
+import (
+	"backend/internal/database/databasetest"
+	"backend/internal/filter"
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// rowDB answers every query with a single row holding value, or with err
+type rowDB struct {
+	value   any
+	err     error
+	queries int
+}
+
+func (d *rowDB) Exec(context.Context, string, ...interface{}) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, errors.New("unexpected exec")
+}
+
+func (d *rowDB) Query(context.Context, string, ...interface{}) (pgx.Rows, error) {
+	return nil, errors.New("unexpected query")
+}
+
+func (d *rowDB) QueryRow(context.Context, string, ...interface{}) pgx.Row {
+	d.queries++
+	return d
+}
+
+func (d *rowDB) Scan(dest ...any) error {
+	if d.err != nil {
+		return d.err
+	}
+	switch dest := dest[0].(type) {
+	case *bool:
+		*dest = d.value.(bool)
+	case *pgtype.Timestamptz:
+		*dest = pgtype.Timestamptz{Time: d.value.(time.Time), Valid: true}
+	}
+	return nil
+}
+
+func TestLinkCheck_Check(t *testing.T) {
+	links := "see https://a.example and www.b.example"
+
+	tests := []struct {
+		name        string
+		content     filter.Content
+		db          *rowDB
+		want        string
+		wantErr     bool
+		wantQueries int
+	}{
+		{
+			name:    "Should allow links up to the limit without looking up the account",
+			content: filter.Content{Title: "https://a.example", Body: "www.b.example"},
+			db:      &rowDB{},
+			want:    filter.ActionAllow,
+		},
+		{
+			name:        "Should match too many links of new account",
+			content:     filter.Content{Title: "links", Body: links + " http://c.example"},
+			db:          &rowDB{value: time.Now().Add(-time.Hour)},
+			want:        filter.ActionHold,
+			wantQueries: 1,
+		},
+		{
+			name:        "Should count links in title and body",
+			content:     filter.Content{Title: "http://c.example", Body: links},
+			db:          &rowDB{value: time.Now().Add(-time.Hour)},
+			want:        filter.ActionHold,
+			wantQueries: 1,
+		},
+		{
+			name:        "Should allow many links of old account",
+			content:     filter.Content{Body: links + " http://c.example"},
+			db:          &rowDB{value: time.Now().Add(-48 * time.Hour)},
+			want:        filter.ActionAllow,
+			wantQueries: 1,
+		},
+		{
+			name:        "Should allow content when account lookup fails",
+			content:     filter.Content{Body: links + " http://c.example"},
+			db:          &rowDB{err: errors.New("connection refused")},
+			want:        filter.ActionAllow,
+			wantErr:     true,
+			wantQueries: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := filter.NewLinkCheck(tt.db, 24*time.Hour, 2, filter.ActionHold)
+
+			verdict, err := check.Check(context.Background(), tt.content)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, verdict.Action)
+			assert.Equal(t, tt.wantQueries, tt.db.queries)
+		})
+	}
+}
+
+func TestDuplicateCheck_Check(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     filter.Content
+		db          *rowDB
+		want        string
+		wantErr     bool
+		wantQueries int
+	}{
+		{
+			name:    "Should allow empty body without query",
+			content: filter.Content{Title: "Title"},
+			db:      &rowDB{},
+			want:    filter.ActionAllow,
+		},
+		{
+			name:        "Should match repeated content",
+			content:     filter.Content{Body: "Buy now"},
+			db:          &rowDB{value: true},
+			want:        filter.ActionReject,
+			wantQueries: 1,
+		},
+		{
+			name:        "Should allow new content",
+			content:     filter.Content{Body: "Buy now"},
+			db:          &rowDB{value: false},
+			want:        filter.ActionAllow,
+			wantQueries: 1,
+		},
+		{
+			name:        "Should allow content when lookup fails",
+			content:     filter.Content{Body: "Buy now"},
+			db:          &rowDB{err: errors.New("connection refused")},
+			want:        filter.ActionAllow,
+			wantErr:     true,
+			wantQueries: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := filter.NewDuplicateCheck(tt.db, time.Hour, filter.ActionReject)
+
+			verdict, err := check.Check(context.Background(), tt.content)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, verdict.Action)
+			assert.Equal(t, tt.wantQueries, tt.db.queries)
+		})
+	}
+}
+
+func TestDuplicateCheck_Query(t *testing.T) {
+	pool := databasetest.Open(t)
+	check := filter.NewDuplicateCheck(pool, time.Hour, filter.ActionReject)
+	ctx := context.Background()
+
+	author := databasetest.CreateUser(t, pool)
+	other := databasetest.CreateUser(t, pool)
+	postID := databasetest.CreatePost(t, pool, author)
+	commentID := databasetest.CreateComment(t, pool, postID, author, time.Now())
+	oldPostID := databasetest.CreatePost(t, pool, other)
+	_, err := pool.Exec(ctx, "UPDATE posts SET content = 'Old news', create_at = now() - interval '2 hours' WHERE id = $1", oldPostID)
+	if err != nil {
+		t.Fatalf("failed to date post: %v", err)
+	}
+
+	// CreatePost and CreateComment store the content "Content"
+	tests := []struct {
+		name    string
+		content filter.Content
+		want    string
+	}{
+		{
+			name:    "Should match content the author posted",
+			content: filter.Content{AuthorID: author, Body: "Content"},
+			want:    filter.ActionReject,
+		},
+		{
+			name:    "Should not match content of other authors",
+			content: filter.Content{AuthorID: databasetest.CreateUser(t, pool), Body: "Content"},
+			want:    filter.ActionAllow,
+		},
+		{
+			name:    "Should not match content posted before the window",
+			content: filter.Content{AuthorID: other, Body: "Old news"},
+			want:    filter.ActionAllow,
+		},
+		{
+			name:    "Should match comment when the post is edited",
+			content: filter.Content{AuthorID: author, Body: "Content", Edited: filter.Target{PostID: postID}},
+			want:    filter.ActionReject,
+		},
+		{
+			name:    "Should match post when the comment is edited",
+			content: filter.Content{AuthorID: author, Body: "Content", Edited: filter.Target{PostID: postID, CommentID: commentID}},
+			want:    filter.ActionReject,
+		},
+		{
+			name:    "Should not match the edited post itself",
+			content: filter.Content{AuthorID: other, Body: "Content", Edited: filter.Target{PostID: databasetest.CreatePost(t, pool, other)}},
+			want:    filter.ActionAllow,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict, err := check.Check(ctx, tt.content)
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			assert.Equal(t, tt.want, verdict.Action)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package filter
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"time"
+)
+
+// DuplicateCheck matches content the author already posted or commented within the window
+type DuplicateCheck struct {
+	query  *Queries
+	window time.Duration
+	action string
+}
+
+func NewDuplicateCheck(db DBTX, window time.Duration, action string) DuplicateCheck {
+	return DuplicateCheck{
+		query:  New(db),
+		window: window,
+		action: action,
+	}
+}
+
+func (c DuplicateCheck) Check(ctx context.Context, content Content) (Verdict, error) {
+	if content.Body == "" {
+		return Allow, nil
+	}
+
+	// uuid.Nil matches no stored post or comment
+	editedPostID, editedCommentID := content.Edited.PostID, content.Edited.CommentID
+	if editedCommentID != uuid.Nil {
+		editedPostID = uuid.Nil
+	}
+
+	duplicate, err := c.query.ExistsDuplicate(ctx, ExistsDuplicateParams{
+		AuthorID:        content.AuthorID,
+		Content:         pgtype.Text{String: content.Body, Valid: true},
+		Since:           pgtype.Timestamptz{Time: time.Now().Add(-c.window), Valid: true},
+		EditedPostID:    editedPostID,
+		EditedCommentID: editedCommentID,
+	})
+	if err != nil {
+		return Allow, err
+	}
+	if !duplicate {
+		return Allow, nil
+	}
+
+	return Verdict{Action: c.action, Reason: "repeats content posted shortly before"}, nil
+}
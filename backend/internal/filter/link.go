@@ -0,0 +1,52 @@
+package filter
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+`)
+
+// CountLinks returns the number of web links in text
+func CountLinks(text string) int {
+	return len(linkPattern.FindAllStringIndex(text, -1))
+}
+
+// LinkCheck limits the number of links accounts younger than maxAge may post at once
+type LinkCheck struct {
+	query    *Queries
+	maxAge   time.Duration
+	maxLinks int
+	action   string
+}
+
+func NewLinkCheck(db DBTX, maxAge time.Duration, maxLinks int, action string) LinkCheck {
+	return LinkCheck{
+		query:    New(db),
+		maxAge:   maxAge,
+		maxLinks: maxLinks,
+		action:   action,
+	}
+}
+
+func (c LinkCheck) Check(ctx context.Context, content Content) (Verdict, error) {
+	links := CountLinks(content.Title) + CountLinks(content.Body)
+	if links <= c.maxLinks {
+		return Allow, nil
+	}
+
+	createdAt, err := c.query.FindAccountCreatedAt(ctx, content.AuthorID)
+	if err != nil {
+		return Allow, err
+	}
+	if time.Since(createdAt.Time) >= c.maxAge {
+		return Allow, nil
+	}
+
+	return Verdict{
+		Action: c.action,
+		Reason: fmt.Sprintf("contains %d links, new accounts may post at most %d", links, c.maxLinks),
+	}, nil
+}
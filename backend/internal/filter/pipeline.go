@@ -0,0 +1,160 @@
+package filter
+
+import (
+	"backend/internal"
+	"backend/internal/config"
+	"backend/internal/database"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// Actions in ascending order of severity. Held content is stored hidden until a moderator approves it, flagged
+// content is published and reported to moderators.
+const (
+	ActionAllow  = "allow"
+	ActionFlag   = "flag"
+	ActionHold   = "hold"
+	ActionReject = "reject"
+)
+
+var severity = map[string]int{
+	ActionAllow:  0,
+	ActionFlag:   1,
+	ActionHold:   2,
+	ActionReject: 3,
+}
+
+// Content is a new or edited post or comment
+type Content struct {
+	AuthorID uuid.UUID
+	Title    string
+	Body     string
+	// Edited is the stored post or comment the content replaces, zero for new content
+	Edited Target
+}
+
+// Verdict is the outcome of a check, Reason tells moderators and rejected authors which check matched
+type Verdict struct {
+	Action string
+	Reason string
+}
+
+// Allow is the verdict of checks that do not match
+var Allow = Verdict{Action: ActionAllow}
+
+// Check is a step of the Pipeline
+type Check interface {
+	Check(ctx context.Context, content Content) (Verdict, error)
+}
+
+// Target is the stored post or comment a verdict applies to, a zero CommentID refers to the post itself
+type Target struct {
+	PostID    uuid.UUID
+	CommentID uuid.UUID
+}
+
+type Pipeline struct {
+	logger *zap.Logger
+	tracer trace.Tracer
+	query  *Queries
+
+	checks []Check
+}
+
+func NewPipeline(logger *zap.Logger, db DBTX, checks ...Check) *Pipeline {
+	return &Pipeline{
+		logger: logger,
+		tracer: otel.Tracer("filter/pipeline"),
+		query:  New(db),
+		checks: checks,
+	}
+}
+
+// FromConfig builds the pipeline with the checks enabled in the config
+func FromConfig(logger *zap.Logger, db DBTX, cfg config.ContentFilterConfig) (*Pipeline, error) {
+	var checks []Check
+	for _, rule := range cfg.Rules {
+		check, err := NewRuleCheck(rule)
+		if err != nil {
+			return nil, err
+		}
+		checks = append(checks, check)
+	}
+
+	if cfg.NewAccountAge > 0 && cfg.NewAccountMaxLinks > 0 {
+		err := validateAction(cfg.NewAccountLinkAction)
+		if err != nil {
+			return nil, fmt.Errorf("new account link limit: %w", err)
+		}
+		checks = append(checks, NewLinkCheck(db, cfg.NewAccountAge, cfg.NewAccountMaxLinks, cfg.NewAccountLinkAction))
+	}
+
+	if cfg.DuplicateWindow > 0 {
+		err := validateAction(cfg.DuplicateAction)
+		if err != nil {
+			return nil, fmt.Errorf("duplicate detection: %w", err)
+		}
+		checks = append(checks, NewDuplicateCheck(db, cfg.DuplicateWindow, cfg.DuplicateAction))
+	}
+
+	return NewPipeline(logger, db, checks...), nil
+}
+
+// Check runs the content through every check and returns the most severe verdict. A failing check is logged and
+// skipped, so an outage of the filter does not stop users from posting.
+func (p *Pipeline) Check(ctx context.Context, content Content) Verdict {
+	traceCtx, span := p.tracer.Start(ctx, "Check")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, p.logger)
+
+	result := Allow
+	for _, check := range p.checks {
+		verdict, err := check.Check(traceCtx, content)
+		if err != nil {
+			logger.Warn("Content filter check failed", zap.String("check", fmt.Sprintf("%T", check)), zap.Error(err))
+			span.RecordError(err)
+			continue
+		}
+		if severity[verdict.Action] > severity[result.Action] {
+			result = verdict
+		}
+	}
+
+	if result.Action != ActionAllow {
+		logger.Info("Content filter matched", zap.String("author_id", content.AuthorID.String()), zap.String("action", result.Action), zap.String("reason", result.Reason))
+	}
+
+	return result
+}
+
+// Report files a report about held or flagged content for the moderation queue
+func (p *Pipeline) Report(ctx context.Context, target Target, verdict Verdict) error {
+	traceCtx, span := p.tracer.Start(ctx, "Report")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, p.logger)
+
+	err := p.query.CreateReport(traceCtx, CreateReportParams{
+		PostID:    target.PostID,
+		CommentID: pgtype.UUID{Bytes: target.CommentID, Valid: target.CommentID != uuid.Nil},
+		Details:   pgtype.Text{String: verdict.Action + ": " + verdict.Reason, Valid: true},
+	})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "create filter report")
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func validateAction(action string) error {
+	if severity[action] == 0 {
+		return fmt.Errorf("unknown action %q, use %s, %s or %s", action, ActionReject, ActionHold, ActionFlag)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package filter_test
+
+import (
+	"backend/internal/filter"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+)
+
+type stubCheck struct {
+	verdict filter.Verdict
+	err     error
+}
+
+func (c stubCheck) Check(context.Context, filter.Content) (filter.Verdict, error) {
+	return c.verdict, c.err
+}
+
+func TestPipeline_Check(t *testing.T) {
+	flag := filter.Verdict{Action: filter.ActionFlag, Reason: "flagged"}
+	hold := filter.Verdict{Action: filter.ActionHold, Reason: "held"}
+
+	tests := []struct {
+		name   string
+		checks []filter.Check
+		want   filter.Verdict
+	}{
+		{
+			name: "Should allow without checks",
+			want: filter.Allow,
+		},
+		{
+			name:   "Should return most severe verdict",
+			checks: []filter.Check{stubCheck{verdict: flag}, stubCheck{verdict: hold}, stubCheck{verdict: filter.Allow}},
+			want:   hold,
+		},
+		{
+			name:   "Should skip failing checks",
+			checks: []filter.Check{stubCheck{err: errors.New("database unavailable")}, stubCheck{verdict: flag}},
+			want:   flag,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := filter.NewPipeline(zap.NewNop(), nil, tt.checks...)
+			assert.Equal(t, tt.want, p.Check(context.Background(), filter.Content{}))
+		})
+	}
+}
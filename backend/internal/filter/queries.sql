@@ -0,0 +1,16 @@
+-- name: ExistsDuplicate :one
+-- Whether the author posted or commented the same content since the given time, hidden content counts as well. The
+-- edited post or comment does not repeat itself.
+SELECT (EXISTS (
+    SELECT 1 FROM posts p
+    WHERE p.author_id = @author_id AND p.content = @content AND p.create_at > @since AND p.id <> @edited_post_id
+) OR EXISTS (
+    SELECT 1 FROM comments c
+    WHERE c.author_id = @author_id AND c.content = @content AND c.created_at > @since AND c.id <> @edited_comment_id
+))::boolean AS duplicate;
+
+-- name: FindAccountCreatedAt :one
+SELECT created_at FROM users WHERE id = $1;
+
+-- name: CreateReport :exec
+INSERT INTO reports (post_id, comment_id, reason, details) VALUES ($1, $2, 'filter', $3);
@@ -0,0 +1,72 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: queries.sql
+
+package filter
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createReport = `-- name: CreateReport :exec
+INSERT INTO reports (post_id, comment_id, reason, details) VALUES ($1, $2, 'filter', $3)
+`
+
+type CreateReportParams struct {
+	PostID    uuid.UUID
+	CommentID pgtype.UUID
+	Details   pgtype.Text
+}
+
+func (q *Queries) CreateReport(ctx context.Context, arg CreateReportParams) error {
+	_, err := q.db.Exec(ctx, createReport, arg.PostID, arg.CommentID, arg.Details)
+	return err
+}
+
+const existsDuplicate = `-- name: ExistsDuplicate :one
+SELECT (EXISTS (
+    SELECT 1 FROM posts p
+    WHERE p.author_id = $1 AND p.content = $2 AND p.create_at > $3 AND p.id <> $4
+) OR EXISTS (
+    SELECT 1 FROM comments c
+    WHERE c.author_id = $1 AND c.content = $2 AND c.created_at > $3 AND c.id <> $5
+))::boolean AS duplicate
+`
+
+type ExistsDuplicateParams struct {
+	AuthorID        uuid.UUID
+	Content         pgtype.Text
+	Since           pgtype.Timestamptz
+	EditedPostID    uuid.UUID
+	EditedCommentID uuid.UUID
+}
+
+// Whether the author posted or commented the same content since the given time, hidden content counts as well. The
+// edited post or comment does not repeat itself.
+func (q *Queries) ExistsDuplicate(ctx context.Context, arg ExistsDuplicateParams) (bool, error) {
+	row := q.db.QueryRow(ctx, existsDuplicate,
+		arg.AuthorID,
+		arg.Content,
+		arg.Since,
+		arg.EditedPostID,
+		arg.EditedCommentID,
+	)
+	var duplicate bool
+	err := row.Scan(&duplicate)
+	return duplicate, err
+}
+
+const findAccountCreatedAt = `-- name: FindAccountCreatedAt :one
+SELECT created_at FROM users WHERE id = $1
+`
+
+func (q *Queries) FindAccountCreatedAt(ctx context.Context, id uuid.UUID) (pgtype.Timestamptz, error) {
+	row := q.db.QueryRow(ctx, findAccountCreatedAt, id)
+	var created_at pgtype.Timestamptz
+	err := row.Scan(&created_at)
+	return created_at, err
+}
@@ -0,0 +1,58 @@
+package filter
+
+import (
+	"backend/internal/config"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RuleCheck matches the title and body of content against a configured rule
+type RuleCheck struct {
+	name    string
+	pattern *regexp.Regexp
+	action  string
+}
+
+// NewRuleCheck compiles the pattern and words of the rule into one expression, words match whole words ignoring case
+func NewRuleCheck(rule config.FilterRule) (RuleCheck, error) {
+	var alternatives []string
+	if rule.Pattern != "" {
+		alternatives = append(alternatives, "(?:"+rule.Pattern+")")
+	}
+	if len(rule.Words) > 0 {
+		words := make([]string, len(rule.Words))
+		for i, word := range rule.Words {
+			words[i] = regexp.QuoteMeta(word)
+		}
+		alternatives = append(alternatives, `(?i:\b(?:`+strings.Join(words, "|")+`)\b)`)
+	}
+	if len(alternatives) == 0 {
+		return RuleCheck{}, fmt.Errorf("filter rule %q needs a pattern or words", rule.Name)
+	}
+
+	err := validateAction(rule.Action)
+	if err != nil {
+		return RuleCheck{}, fmt.Errorf("filter rule %q: %w", rule.Name, err)
+	}
+
+	pattern, err := regexp.Compile(strings.Join(alternatives, "|"))
+	if err != nil {
+		return RuleCheck{}, fmt.Errorf("filter rule %q: %w", rule.Name, err)
+	}
+
+	return RuleCheck{name: rule.Name, pattern: pattern, action: rule.Action}, nil
+}
+
+func (c RuleCheck) Check(_ context.Context, content Content) (Verdict, error) {
+	if !c.pattern.MatchString(content.Title) && !c.pattern.MatchString(content.Body) {
+		return Allow, nil
+	}
+
+	reason := "matches a filter rule"
+	if c.name != "" {
+		reason = fmt.Sprintf("matches the filter rule %q", c.name)
+	}
+	return Verdict{Action: c.action, Reason: reason}, nil
+}
@@ -0,0 +1,72 @@
+package filter_test
+
+import (
+	"backend/internal/config"
+	"backend/internal/filter"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRuleCheck_Check(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    config.FilterRule
+		content filter.Content
+		want    string
+	}{
+		{
+			name:    "Should match word ignoring case",
+			rule:    config.FilterRule{Name: "spam", Words: []string{"casino"}, Action: filter.ActionHold},
+			content: filter.Content{Title: "Best CASINO in town"},
+			want:    filter.ActionHold,
+		},
+		{
+			name:    "Should not match word inside another word",
+			rule:    config.FilterRule{Name: "spam", Words: []string{"ass"}, Action: filter.ActionReject},
+			content: filter.Content{Body: "a classic assignment"},
+			want:    filter.ActionAllow,
+		},
+		{
+			name:    "Should match pattern in body",
+			rule:    config.FilterRule{Name: "phone", Pattern: `\d{3}-\d{4}`, Action: filter.ActionFlag},
+			content: filter.Content{Title: "call me", Body: "at 555-1234"},
+			want:    filter.ActionFlag,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check, err := filter.NewRuleCheck(tt.rule)
+			assert.NoError(t, err)
+
+			verdict, err := check.Check(context.Background(), tt.content)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, verdict.Action)
+		})
+	}
+}
+
+func TestNewRuleCheck(t *testing.T) {
+	tests := []struct {
+		name string
+		rule config.FilterRule
+	}{
+		{name: "Should reject rule without pattern and words", rule: config.FilterRule{Name: "empty", Action: filter.ActionReject}},
+		{name: "Should reject unknown action", rule: config.FilterRule{Name: "spam", Words: []string{"x"}, Action: "delete"}},
+		{name: "Should reject allow action", rule: config.FilterRule{Name: "spam", Words: []string{"x"}, Action: filter.ActionAllow}},
+		{name: "Should reject invalid pattern", rule: config.FilterRule{Name: "broken", Pattern: "(", Action: filter.ActionFlag}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := filter.NewRuleCheck(tt.rule)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCountLinks(t *testing.T) {
+	assert.Equal(t, 0, filter.CountLinks("no links here, just example.com"))
+	assert.Equal(t, 3, filter.CountLinks("see https://a.example and http://b.example or www.c.example"))
+}
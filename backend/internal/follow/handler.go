@@ -0,0 +1,127 @@
+package follow
+
+import (
+	"backend/internal"
+	errorPkg "backend/internal/error"
+	"backend/internal/jwt"
+	"backend/internal/problem"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+type Response struct {
+	UserID    string `json:"user_id"`
+	CreatedAt string `json:"created_at"`
+}
+
+//go:generate mockery --name=Store
+type Store interface {
+	Follow(ctx context.Context, followerID, followeeID uuid.UUID) error
+	Unfollow(ctx context.Context, followerID, followeeID uuid.UUID) error
+	GetByFollower(ctx context.Context, followerID uuid.UUID) ([]Follow, error)
+}
+
+type Handler struct {
+	logger *zap.Logger
+	tracer trace.Tracer
+	store  Store
+}
+
+func NewHandler(logger *zap.Logger, store Store) *Handler {
+	return &Handler{
+		logger: logger,
+		tracer: otel.Tracer("follow/handler"),
+		store:  store,
+	}
+}
+
+// GetAllHandler lists the users the current user follows
+func (h *Handler) GetAllHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "GetAllFollowEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	userID, err := jwt.GetUserIDFromContext(traceCtx)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	follows, err := h.store.GetByFollower(traceCtx, userID)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	response := make([]Response, len(follows))
+	for i, f := range follows {
+		response[i] = Response{
+			UserID:    f.FolloweeID.String(),
+			CreatedAt: f.CreatedAt.Time.Format(time.RFC3339),
+		}
+	}
+
+	internal.WriteJSONResponse(w, http.StatusOK, response)
+}
+
+func (h *Handler) FollowHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "FollowUserEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	userID, followeeID, err := userAndTarget(traceCtx, r)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	err = h.store.Follow(traceCtx, userID, followeeID)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	logger.Info("Followed user", zap.String("followee_id", followeeID.String()))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) UnfollowHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "UnfollowUserEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	userID, followeeID, err := userAndTarget(traceCtx, r)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	err = h.store.Unfollow(traceCtx, userID, followeeID)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// userAndTarget returns the current user and the user named in the id path value
+func userAndTarget(ctx context.Context, r *http.Request) (uuid.UUID, uuid.UUID, error) {
+	userID, err := jwt.GetUserIDFromContext(ctx)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	targetID, err := internal.ParseUUID(r.PathValue("id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("%w: %v", errorPkg.ErrInvalidUUID, err)
+	}
+
+	return userID, targetID, nil
+}
@@ -0,0 +1,97 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	follow "backend/internal/follow"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// Store is an autogenerated mock type for the Store type
+type Store struct {
+	mock.Mock
+}
+
+// Follow provides a mock function with given fields: ctx, followerID, followeeID
+func (_m *Store) Follow(ctx context.Context, followerID uuid.UUID, followeeID uuid.UUID) error {
+	ret := _m.Called(ctx, followerID, followeeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Follow")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(ctx, followerID, followeeID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByFollower provides a mock function with given fields: ctx, followerID
+func (_m *Store) GetByFollower(ctx context.Context, followerID uuid.UUID) ([]follow.Follow, error) {
+	ret := _m.Called(ctx, followerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByFollower")
+	}
+
+	var r0 []follow.Follow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]follow.Follow, error)); ok {
+		return rf(ctx, followerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []follow.Follow); ok {
+		r0 = rf(ctx, followerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]follow.Follow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, followerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Unfollow provides a mock function with given fields: ctx, followerID, followeeID
+func (_m *Store) Unfollow(ctx context.Context, followerID uuid.UUID, followeeID uuid.UUID) error {
+	ret := _m.Called(ctx, followerID, followeeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Unfollow")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(ctx, followerID, followeeID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewStore creates a new instance of Store. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Store {
+	mock := &Store{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,8 @@
+-- name: Create :exec
+INSERT INTO follows (follower_id, followee_id) VALUES ($1, $2) ON CONFLICT DO NOTHING;
+
+-- name: Delete :execrows
+DELETE FROM follows WHERE follower_id = $1 AND followee_id = $2;
+
+-- name: FindByFollower :many
+SELECT * FROM follows WHERE follower_id = $1 ORDER BY created_at DESC;
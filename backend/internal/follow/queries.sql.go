@@ -0,0 +1,67 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: queries.sql
+
+package follow
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const create = `-- name: Create :exec
+INSERT INTO follows (follower_id, followee_id) VALUES ($1, $2) ON CONFLICT DO NOTHING
+`
+
+type CreateParams struct {
+	FollowerID uuid.UUID
+	FolloweeID uuid.UUID
+}
+
+func (q *Queries) Create(ctx context.Context, arg CreateParams) error {
+	_, err := q.db.Exec(ctx, create, arg.FollowerID, arg.FolloweeID)
+	return err
+}
+
+const delete = `-- name: Delete :execrows
+DELETE FROM follows WHERE follower_id = $1 AND followee_id = $2
+`
+
+type DeleteParams struct {
+	FollowerID uuid.UUID
+	FolloweeID uuid.UUID
+}
+
+func (q *Queries) Delete(ctx context.Context, arg DeleteParams) (int64, error) {
+	result, err := q.db.Exec(ctx, delete, arg.FollowerID, arg.FolloweeID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const findByFollower = `-- name: FindByFollower :many
+SELECT follower_id, followee_id, created_at FROM follows WHERE follower_id = $1 ORDER BY created_at DESC
+`
+
+func (q *Queries) FindByFollower(ctx context.Context, followerID uuid.UUID) ([]Follow, error) {
+	rows, err := q.db.Query(ctx, findByFollower, followerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Follow
+	for rows.Next() {
+		var i Follow
+		if err := rows.Scan(&i.FollowerID, &i.FolloweeID, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
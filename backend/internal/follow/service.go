@@ -0,0 +1,88 @@
+package follow
+
+import (
+	"backend/internal"
+	"backend/internal/database"
+	errorPkg "backend/internal/error"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+type Service struct {
+	logger *zap.Logger
+	tracer trace.Tracer
+	query  *Queries
+}
+
+func NewService(logger *zap.Logger, db DBTX) *Service {
+	return &Service{
+		logger: logger,
+		tracer: otel.Tracer("follow/service"),
+		query:  New(db),
+	}
+}
+
+// Follow adds the posts of followeeID to the feed of followerID, following a user twice is not an error
+func (s *Service) Follow(ctx context.Context, followerID, followeeID uuid.UUID) error {
+	traceCtx, span := s.tracer.Start(ctx, "Follow")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	if followerID == followeeID {
+		err := fmt.Errorf("%w: users cannot follow themselves", errorPkg.ErrInvalidQuery)
+		span.RecordError(err)
+		return err
+	}
+
+	err := s.query.Create(traceCtx, CreateParams{FollowerID: followerID, FolloweeID: followeeID})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "follow user")
+		if errors.Is(err, database.ErrForeignKeyViolation) {
+			err = errorPkg.NewNotFoundError("users", "id", followeeID.String(), "")
+		}
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *Service) Unfollow(ctx context.Context, followerID, followeeID uuid.UUID) error {
+	traceCtx, span := s.tracer.Start(ctx, "Unfollow")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	count, err := s.query.Delete(traceCtx, DeleteParams{FollowerID: followerID, FolloweeID: followeeID})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "unfollow user")
+		span.RecordError(err)
+		return err
+	}
+	if count == 0 {
+		err = errorPkg.NewNotFoundError("follows", "followee_id", followeeID.String(), "")
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *Service) GetByFollower(ctx context.Context, followerID uuid.UUID) ([]Follow, error) {
+	traceCtx, span := s.tracer.Start(ctx, "GetByFollower")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	follows, err := s.query.FindByFollower(traceCtx, followerID)
+	if err != nil {
+		err = database.WrapDBError(err, logger, "get follows by follower")
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return follows, nil
+}
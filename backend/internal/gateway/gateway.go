@@ -0,0 +1,116 @@
+// Package gateway holds what the servers for other protocols than HTTP share: the NNTP gateway, the terminal UI over
+// SSH and the Gemini server. They are optional and reach the forum through the services of the web API.
+package gateway
+
+import (
+	"backend/internal/ratelimit"
+	"backend/internal/user"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"net"
+	"time"
+)
+
+type Users interface {
+	GetByID(ctx context.Context, id uuid.UUID) (user.User, error)
+}
+
+// Run listens on addr and serves the listener until ctx is done. The servers are optional, so a failure to listen
+// is logged instead of stopping the backend.
+func Run(ctx context.Context, logger *zap.Logger, protocol, addr string, serve func(ctx context.Context, listener net.Listener), fields ...zap.Field) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		logger.Error("Failed to listen", zap.String("protocol", protocol), zap.String("addr", addr), zap.Error(err))
+		return
+	}
+
+	logger.Info("Starting server", append([]zap.Field{zap.String("protocol", protocol), zap.String("addr", addr)}, fields...)...)
+	serve(ctx, listener)
+}
+
+// Serve accepts connections on the listener until ctx is done and handles each of them in its own goroutine. A
+// connection is closed once handle returns or ctx is done.
+func Serve(ctx context.Context, logger *zap.Logger, protocol string, listener net.Listener, handle func(ctx context.Context, conn net.Conn)) {
+	stop := context.AfterFunc(ctx, func() {
+		_ = listener.Close()
+	})
+	defer stop()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return
+			}
+			logger.Error("Failed to accept connection", zap.String("protocol", protocol), zap.Error(err))
+			time.Sleep(time.Second)
+			continue
+		}
+
+		go func() {
+			defer conn.Close()
+			stop := context.AfterFunc(ctx, func() {
+				_ = conn.Close()
+			})
+			defer stop()
+
+			handle(ctx, conn)
+		}()
+	}
+}
+
+// ClientIP returns the host of the remote address of the connection, for internal.ClientIPContextKey
+func ClientIP(conn net.Conn) string {
+	host, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+	if err != nil {
+		return conn.RemoteAddr().String()
+	}
+	return host
+}
+
+// CheckSuspension returns the suspension of the user as error. Sessions outlive a suspension, unlike the tokens of
+// the web API which are checked on every request, so servers with sessions check it before every write.
+func CheckSuspension(ctx context.Context, users Users, userID uuid.UUID) error {
+	u, err := users.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if u.IsSuspended(time.Now()) {
+		return u.SuspensionError()
+	}
+	return nil
+}
+
+// CheckWrite rejects writes of users that were suspended during their session and writes over the rate limit of
+// the route of the web API, the user is counted if it is in ctx
+func CheckWrite(ctx context.Context, users Users, limiter *ratelimit.Limiter, userID uuid.UUID, route string) error {
+	err := CheckSuspension(ctx, users, userID)
+	if err != nil {
+		return err
+	}
+	return limiter.Check(ctx, route)
+}
+
+// Names looks up user names and asks the user service once per user, for the authors of a page
+type Names struct {
+	users Users
+	names map[uuid.UUID]string
+}
+
+func NewNames(users Users) *Names {
+	return &Names{users: users, names: make(map[uuid.UUID]string)}
+}
+
+func (n *Names) Get(ctx context.Context, id uuid.UUID) (string, error) {
+	if name, ok := n.names[id]; ok {
+		return name, nil
+	}
+	u, err := n.users.GetByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	n.names[id] = u.Name
+	return u.Name, nil
+}
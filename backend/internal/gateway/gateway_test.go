@@ -0,0 +1,111 @@
+package gateway_test
+
+import (
+	"backend/internal"
+	"backend/internal/config"
+	errorPkg "backend/internal/error"
+	"backend/internal/gateway"
+	"backend/internal/jwt"
+	"backend/internal/ratelimit"
+	"backend/internal/user"
+	"context"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+var (
+	alice = user.User{ID: uuid.MustParse("81c1ecc1-66d7-4134-b5fe-d886a6f418a4"), Name: "alice"}
+	bob   = user.User{
+		ID:          uuid.MustParse("8d1c4a2e-0f4b-4c55-9a51-3b0c4f2e9d10"),
+		Name:        "bob",
+		SuspendedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	}
+)
+
+// users finds alice and bob and counts the lookups
+type users struct {
+	lookups int
+}
+
+func (u *users) GetByID(_ context.Context, id uuid.UUID) (user.User, error) {
+	u.lookups++
+	for _, found := range []user.User{alice, bob} {
+		if found.ID == id {
+			return found, nil
+		}
+	}
+	return user.User{}, errorPkg.NewNotFoundError("users", "id", id.String(), "")
+}
+
+func TestNames_Get(t *testing.T) {
+	u := &users{}
+	names := gateway.NewNames(u)
+
+	for range 2 {
+		name, err := names.Get(context.Background(), alice.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "alice", name)
+	}
+	assert.Equal(t, 1, u.lookups)
+
+	_, err := names.Get(context.Background(), uuid.New())
+	assert.ErrorIs(t, err, errorPkg.ErrNotFound)
+}
+
+func TestCheckSuspension(t *testing.T) {
+	assert.NoError(t, gateway.CheckSuspension(context.Background(), &users{}, alice.ID))
+	assert.ErrorIs(t, gateway.CheckSuspension(context.Background(), &users{}, bob.ID), errorPkg.ErrSuspended)
+}
+
+func TestCheckWrite(t *testing.T) {
+	limiter, err := ratelimit.NewLimiter([]config.RateLimitRule{{Route: ratelimit.RouteComments, Requests: 1, Per: time.Minute}})
+	assert.NoError(t, err)
+	ctx := context.WithValue(context.Background(), internal.UserContextKey, jwt.User{ID: alice.ID.String(), Role: jwt.RoleUser})
+
+	assert.ErrorIs(t, gateway.CheckWrite(ctx, &users{}, limiter, bob.ID, ratelimit.RouteComments), errorPkg.ErrSuspended)
+	assert.NoError(t, gateway.CheckWrite(ctx, &users{}, limiter, alice.ID, ratelimit.RouteComments))
+	assert.ErrorIs(t, gateway.CheckWrite(ctx, &users{}, limiter, alice.ID, ratelimit.RouteComments), errorPkg.ErrRateLimited)
+}
+
+func TestServe(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	handled := make(chan string, 1)
+	done := make(chan struct{})
+	go func() {
+		gateway.Serve(ctx, zap.NewNop(), "test", listener, func(ctx context.Context, conn net.Conn) {
+			handled <- gateway.ClientIP(conn)
+			<-ctx.Done()
+		})
+		close(done)
+	}()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer conn.Close()
+	assert.Equal(t, "127.0.0.1", <-handled)
+
+	// the connection and the listener are closed once ctx is done
+	cancel()
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, err = conn.Read(make([]byte, 1))
+	assert.ErrorIs(t, err, io.EOF)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve() did not return after ctx was done")
+	}
+}
@@ -0,0 +1,351 @@
+package gemini
+
+import (
+	"backend/internal"
+	"backend/internal/comment"
+	errorPkg "backend/internal/error"
+	"backend/internal/jwt"
+	"backend/internal/markdown"
+	"backend/internal/post"
+	"backend/internal/problem"
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Status codes of Gemini responses
+const (
+	statusInput               = 10
+	statusSuccess             = 20
+	statusRedirect            = 30
+	statusTemporaryFailure    = 40
+	statusSlowDown            = 44
+	statusPermanentFailure    = 50
+	statusNotFound            = 51
+	statusProxyRequestRefused = 53
+	statusBadRequest          = 59
+	statusCertificateRequired = 60
+	statusNotAuthorised       = 61
+	statusCertificateNotValid = 62
+)
+
+const (
+	gemtext    = "text/gemini; charset=utf-8"
+	timeFormat = "2006-01-02 15:04"
+)
+
+var errCertificateNotValid = errors.New("certificate expired or not valid yet")
+
+type response struct {
+	status int
+	meta   string
+	body   string
+}
+
+// request is a parsed Gemini request, user is nil for requests without a registered client certificate
+type request struct {
+	ctx         context.Context
+	url         *url.URL
+	certificate bool
+	user        *jwt.User
+	// authErr is the reason the client certificate was not accepted
+	authErr error
+}
+
+func (r request) viewerID() uuid.UUID {
+	if r.user == nil {
+		return uuid.Nil
+	}
+	return uuid.MustParse(r.user.ID)
+}
+
+// serve answers the request line of a client
+func (s *Server) serve(ctx context.Context, line string, certificates []*x509.Certificate) response {
+	traceCtx, span := s.tracer.Start(ctx, "Request")
+	defer span.End()
+
+	line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+	u, err := url.Parse(line)
+	if err != nil || !u.IsAbs() || u.User != nil {
+		return response{status: statusBadRequest, meta: "Malformed request"}
+	}
+	if u.Scheme != "gemini" || !strings.EqualFold(u.Hostname(), s.hostname) {
+		return response{status: statusProxyRequestRefused, meta: "This server only serves gemini://" + s.hostname}
+	}
+
+	req := request{ctx: traceCtx, url: u, certificate: len(certificates) > 0}
+	if req.certificate {
+		req.authErr = s.authenticate(&req, certificates[0])
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	switch {
+	case u.Path == "" || u.Path == "/":
+		return s.index(req)
+	case len(segments) == 1 && segments[0] == "login":
+		return s.login(req)
+	case len(segments) == 2 && segments[0] == "board":
+		return s.board(req, segments[1])
+	case len(segments) == 2 && segments[0] == "post":
+		return s.thread(req, segments[1])
+	case len(segments) == 3 && segments[0] == "post" && segments[2] == "reply":
+		return s.reply(req, segments[1], "")
+	case len(segments) == 4 && segments[0] == "post" && segments[2] == "reply":
+		return s.reply(req, segments[1], segments[3])
+	}
+	return response{status: statusNotFound, meta: "Not found"}
+}
+
+// authenticate looks up the user of the client certificate and adds it to the context of the request
+func (s *Server) authenticate(req *request, cert *x509.Certificate) error {
+	now := time.Now()
+	if now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+		return errCertificateNotValid
+	}
+
+	user, err := s.store.Authenticate(req.ctx, cert)
+	if err != nil {
+		return err
+	}
+	req.user = &user
+	req.ctx = context.WithValue(req.ctx, internal.UserContextKey, user)
+	return nil
+}
+
+// requireUser returns the response for requests that need a registered certificate but did not present one
+func (s *Server) requireUser(req request) (uuid.UUID, *response) {
+	switch {
+	case req.user != nil:
+		return req.viewerID(), nil
+	case !req.certificate:
+		return uuid.Nil, &response{status: statusCertificateRequired, meta: "Sign in with a client certificate"}
+	case errors.Is(req.authErr, errCertificateNotValid):
+		return uuid.Nil, &response{status: statusCertificateNotValid, meta: "The certificate expired or is not valid yet"}
+	case errors.Is(req.authErr, errorPkg.ErrCredentialInvalid):
+		return uuid.Nil, &response{status: statusNotAuthorised, meta: "The certificate is not registered, add it to your account through the web API"}
+	}
+	return uuid.Nil, &response{status: statusNotAuthorised, meta: problem.FromError(req.authErr).Detail}
+}
+
+func (s *Server) index(req request) response {
+	boards, err := s.store.Boards(req.ctx)
+	if err != nil {
+		return s.fail(req, err)
+	}
+
+	var b strings.Builder
+	b.WriteString("# Boards\n\n")
+	if req.user != nil {
+		fmt.Fprintf(&b, "Signed in as %s\n\n", text(req.user.Username))
+	} else {
+		b.WriteString("=> /login Sign in with a client certificate\n\n")
+	}
+	for _, bo := range boards {
+		fmt.Fprintf(&b, "=> /board/%s %s\n", bo.ID, text(bo.Name))
+		if bo.Description.String != "" {
+			fmt.Fprintf(&b, "%s\n", text(bo.Description.String))
+		}
+	}
+	return response{status: statusSuccess, meta: gemtext, body: b.String()}
+}
+
+// login asks for a client certificate, clients keep sending it to the server once they were asked for it
+func (s *Server) login(req request) response {
+	_, resp := s.requireUser(req)
+	if resp != nil {
+		return *resp
+	}
+	return response{status: statusRedirect, meta: "/"}
+}
+
+func (s *Server) board(req request, rawID string) response {
+	boardID, err := uuid.Parse(rawID)
+	if err != nil {
+		return response{status: statusNotFound, meta: "Not found"}
+	}
+	bo, summaries, err := s.store.Posts(req.ctx, req.viewerID(), boardID)
+	if err != nil {
+		return s.fail(req, err)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", text(bo.Name))
+	if bo.Description.String != "" {
+		fmt.Fprintf(&b, "%s\n\n", text(bo.Description.String))
+	}
+	for _, summary := range summaries {
+		title := text(summary.Post.Title.String)
+		if summary.Collapsed {
+			title = "(post of a muted or blocked user)"
+		}
+		fmt.Fprintf(&b, "=> /post/%s %s%s · %s · %s\n", summary.Post.ID, flags(summary.Post), title, text(summary.Author), summary.Post.CreateAt.Time.Format(timeFormat))
+	}
+	if len(summaries) == 0 {
+		b.WriteString("No posts yet\n")
+	}
+	b.WriteString("\n=> / Boards\n")
+	return response{status: statusSuccess, meta: gemtext, body: b.String()}
+}
+
+func (s *Server) thread(req request, rawID string) response {
+	postID, err := uuid.Parse(rawID)
+	if err != nil {
+		return response{status: statusNotFound, meta: "Not found"}
+	}
+	thread, err := s.store.Thread(req.ctx, req.viewerID(), postID)
+	if err != nil {
+		return s.fail(req, err)
+	}
+
+	p := thread.Post
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s%s\n\n", flags(p), text(p.Title.String))
+	fmt.Fprintf(&b, "%s · %s\n\n", text(thread.Author), p.CreateAt.Time.Format(timeFormat))
+	fmt.Fprintf(&b, "%s\n\n", markdown.RenderGemtext(p.Content.String))
+	fmt.Fprintf(&b, "=> /post/%s/reply Reply\n", p.ID)
+	if p.BoardID.Valid {
+		fmt.Fprintf(&b, "=> /board/%s Back to the board\n", uuid.UUID(p.BoardID.Bytes))
+	}
+
+	fmt.Fprintf(&b, "\n## %d comments\n", len(thread.Replies))
+	for i, reply := range thread.Replies {
+		fmt.Fprintf(&b, "\n### [%d] %s · %s\n", i+1, text(reply.Author), reply.Comment.CreatedAt.Time.Format(timeFormat))
+		if reply.Parent > 0 {
+			fmt.Fprintf(&b, "In reply to [%d]\n", reply.Parent)
+		}
+		if reply.Collapsed {
+			b.WriteString("(comment of a muted or blocked user)\n")
+		} else {
+			fmt.Fprintf(&b, "%s\n", markdown.RenderGemtext(reply.Comment.Content.String))
+		}
+		fmt.Fprintf(&b, "=> /post/%s/reply/%s Reply to [%d]\n", p.ID, reply.Comment.ID, i+1)
+	}
+	return response{status: statusSuccess, meta: gemtext, body: b.String()}
+}
+
+// reply asks for the markdown content of a comment with an input prompt, clients request the URL again with the
+// content as query
+func (s *Server) reply(req request, rawPostID, rawParentID string) response {
+	userID, resp := s.requireUser(req)
+	if resp != nil {
+		return *resp
+	}
+	postID, err := uuid.Parse(rawPostID)
+	if err != nil {
+		return response{status: statusNotFound, meta: "Not found"}
+	}
+	thread, err := s.store.Thread(req.ctx, userID, postID)
+	if err != nil {
+		return s.fail(req, err)
+	}
+
+	title := thread.Post.Title.String
+	r := comment.CreateRequest{PostID: postID, AuthorID: userID}
+	if rawParentID != "" {
+		parent, ok := findComment(thread, rawParentID)
+		if !ok {
+			return response{status: statusNotFound, meta: "Not found"}
+		}
+		title = parent.Title.String
+		r.ParentID = &parent.ID
+	}
+	r.Title = "Re: " + strings.TrimPrefix(title, "Re: ")
+
+	content, err := url.QueryUnescape(req.url.RawQuery)
+	if err != nil {
+		return response{status: statusBadRequest, meta: "Malformed query"}
+	}
+	r.Content = strings.TrimSpace(content)
+	if r.Content == "" {
+		return response{status: statusInput, meta: text(r.Title) + " (markdown)"}
+	}
+
+	created, err := s.store.Reply(req.ctx, r)
+	if err != nil {
+		return s.fail(req, err)
+	}
+	if created.HiddenAt.Valid {
+		body := fmt.Sprintf("# Reply held for review\n\nA moderator reviews your reply before it is shown.\n\n=> /post/%s Back to the thread\n", postID)
+		return response{status: statusSuccess, meta: gemtext, body: body}
+	}
+	return response{status: statusRedirect, meta: "/post/" + postID.String()}
+}
+
+// fail answers errors of the services like the problem responses of the web API, internal errors are not described
+func (s *Server) fail(req request, err error) response {
+	p := problem.FromError(err)
+	switch {
+	case p.Status == http.StatusNotFound:
+		return response{status: statusNotFound, meta: "Not found"}
+	case p.Status == http.StatusTooManyRequests:
+		return response{status: statusSlowDown, meta: "60"}
+	case p.Status >= http.StatusInternalServerError:
+		internal.LoggerWithContext(req.ctx, s.logger).Error("Failed to serve Gemini request", zap.String("path", req.url.Path), zap.Error(err))
+		return response{status: statusTemporaryFailure, meta: "Internal server error"}
+	}
+	return response{status: statusPermanentFailure, meta: oneLine(p.Detail)}
+}
+
+func findComment(thread Thread, rawID string) (comment.Comment, bool) {
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		return comment.Comment{}, false
+	}
+	for _, reply := range thread.Replies {
+		if reply.Comment.ID == id {
+			return reply.Comment, true
+		}
+	}
+	return comment.Comment{}, false
+}
+
+// flags marks pinned, locked, archived and unpublished posts
+func flags(p post.Post) string {
+	var b strings.Builder
+	if p.PinnedAt.Valid {
+		b.WriteString("[pinned] ")
+	}
+	if p.LockedAt.Valid {
+		b.WriteString("[locked] ")
+	}
+	if p.ArchivedAt.Valid {
+		b.WriteString("[archived] ")
+	}
+	if !p.Published() {
+		b.WriteString("[" + p.Status + "] ")
+	}
+	return b.String()
+}
+
+// text makes names, titles and descriptions a single line of text, a leading marker of a gemtext line type is
+// indented so the line stays text
+func text(s string) string {
+	s = oneLine(s)
+	for _, prefix := range []string{"=>", "#", "* ", ">", "```"} {
+		if strings.HasPrefix(s, prefix) {
+			return " " + s
+		}
+	}
+	return s
+}
+
+// oneLine replaces line breaks and removes other control characters
+func oneLine(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\n' || r == '\r' || r == '\t':
+			return ' '
+		case unicode.IsControl(r):
+			return -1
+		}
+		return r
+	}, s)
+}
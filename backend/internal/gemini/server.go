@@ -0,0 +1,175 @@
+package gemini
+
+import (
+	"backend/internal"
+	"backend/internal/board"
+	"backend/internal/comment"
+	"backend/internal/gateway"
+	"backend/internal/jwt"
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// requestTimeout closes connections that do not complete the handshake, send the request and read the response in
+	// time
+	requestTimeout = 30 * time.Second
+	// maxRequestSize is the longest URL a request may contain
+	maxRequestSize = 1024
+	// certificateValidity is the validity of generated server certificates, clients pin the certificate on first use
+	certificateValidity = 10 * 365 * 24 * time.Hour
+)
+
+//go:generate mockery --name Store
+type Store interface {
+	Authenticate(ctx context.Context, cert *x509.Certificate) (jwt.User, error)
+	Boards(ctx context.Context) ([]board.Board, error)
+	Posts(ctx context.Context, viewerID, boardID uuid.UUID) (board.Board, []Summary, error)
+	Thread(ctx context.Context, viewerID, postID uuid.UUID) (Thread, error)
+	Reply(ctx context.Context, r comment.CreateRequest) (comment.Comment, error)
+}
+
+// Server serves the forum as gemtext over the Gemini protocol. Anyone can read, replying requires a client
+// certificate registered through the web API.
+type Server struct {
+	logger    *zap.Logger
+	tracer    trace.Tracer
+	store     Store
+	addr      string
+	hostname  string
+	tlsConfig *tls.Config
+}
+
+func NewServer(logger *zap.Logger, store Store, addr, hostname string, certificate tls.Certificate) *Server {
+	return &Server{
+		logger:   logger,
+		tracer:   otel.Tracer("gemini/server"),
+		store:    store,
+		addr:     addr,
+		hostname: hostname,
+		tlsConfig: &tls.Config{
+			Certificates: []tls.Certificate{certificate},
+			MinVersion:   tls.VersionTLS12,
+			// Client certificates are self-signed, they are identified by their fingerprint instead of a chain
+			ClientAuth: tls.RequestClientCert,
+		},
+	}
+}
+
+// LoadCertificate reads the server certificate and its key and generates a self-signed certificate for hostname
+// there if they do not exist yet, so clients see the same certificate after a restart
+func LoadCertificate(certPath, keyPath, hostname string) (tls.Certificate, error) {
+	_, certErr := os.Stat(certPath)
+	_, keyErr := os.Stat(keyPath)
+	if errors.Is(certErr, os.ErrNotExist) && errors.Is(keyErr, os.ErrNotExist) {
+		err := generateCertificate(certPath, keyPath, hostname)
+		if err != nil {
+			return tls.Certificate{}, err
+		}
+	}
+
+	return tls.LoadX509KeyPair(certPath, keyPath)
+}
+
+func generateCertificate(certPath, keyPath, hostname string) error {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: hostname},
+		DNSNames:     []string{hostname},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(certificateValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		return err
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range []string{certPath, keyPath} {
+		err = os.MkdirAll(filepath.Dir(path), 0o700)
+		if err != nil {
+			return err
+		}
+	}
+	err = os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0o600)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o644)
+}
+
+// Run serves Gemini requests on the address of the server until ctx is done
+func (s *Server) Run(ctx context.Context) {
+	gateway.Run(ctx, s.logger, "Gemini", s.addr, s.Serve, zap.String("hostname", s.hostname))
+}
+
+// Serve accepts TLS connections on the listener until ctx is done
+func (s *Server) Serve(ctx context.Context, listener net.Listener) {
+	gateway.Serve(ctx, s.logger, "Gemini", tls.NewListener(listener, s.tlsConfig), func(ctx context.Context, conn net.Conn) {
+		s.handle(ctx, conn.(*tls.Conn))
+	})
+}
+
+// handle answers the single request of a connection
+func (s *Server) handle(ctx context.Context, conn *tls.Conn) {
+	_ = conn.SetDeadline(time.Now().Add(requestTimeout))
+
+	host := gateway.ClientIP(conn)
+	ctx = context.WithValue(ctx, internal.ClientIPContextKey, host)
+
+	err := conn.HandshakeContext(ctx)
+	if err != nil {
+		s.logger.Debug("Failed Gemini handshake", zap.String("client_ip", host), zap.Error(err))
+		return
+	}
+
+	// the request is the URL followed by CRLF, the buffer holds the longest valid request
+	reader := bufio.NewReaderSize(conn, maxRequestSize+2)
+	line, err := reader.ReadSlice('\n')
+	var resp response
+	switch {
+	case errors.Is(err, bufio.ErrBufferFull):
+		resp = response{status: statusBadRequest, meta: "Request exceeds 1024 bytes"}
+	case err != nil:
+		s.logger.Debug("Failed to read Gemini request", zap.String("client_ip", host), zap.Error(err))
+		return
+	default:
+		resp = s.serve(ctx, string(line), conn.ConnectionState().PeerCertificates)
+	}
+
+	_, err = fmt.Fprintf(conn, "%d %s\r\n%s", resp.status, resp.meta, resp.body)
+	if err != nil {
+		s.logger.Debug("Failed to write Gemini response", zap.String("client_ip", host), zap.Error(err))
+	}
+}
@@ -0,0 +1,243 @@
+package gemini_test
+
+import (
+	"backend/internal/board"
+	"backend/internal/comment"
+	errorPkg "backend/internal/error"
+	"backend/internal/gemini"
+	"backend/internal/gemini/mocks"
+	"backend/internal/jwt"
+	"backend/internal/post"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"io"
+	"math/big"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	user = jwt.User{
+		ID:       "81c1ecc1-66d7-4134-b5fe-d886a6f418a4",
+		Username: "reader",
+		Role:     jwt.RoleUser,
+	}
+	general = board.Board{ID: uuid.MustParse("1f0b3c8e-3b0a-4d8e-9f3e-9b1b0f6b7c11"), Slug: "general", Name: "General"}
+	release = post.Post{
+		ID:       uuid.MustParse("54a46af2-b454-4746-8ab0-3cf26085a50b"),
+		AuthorID: uuid.MustParse("8d1c4a2e-0f4b-4c55-9a51-3b0c4f2e9d10"),
+		Title:    pgtype.Text{String: "Release", Valid: true},
+		Content:  pgtype.Text{String: "Version **2** is out", Valid: true},
+		BoardID:  pgtype.UUID{Bytes: general.ID, Valid: true},
+		Status:   post.StatusPublished,
+	}
+	question = comment.Comment{
+		ID:      uuid.MustParse("6a0e5f51-4f0c-4d5e-8b4b-2c1d3e4f5a6b"),
+		PostID:  release.ID,
+		Title:   pgtype.Text{String: "Re: Release", Valid: true},
+		Content: pgtype.Text{String: "When does it ship?", Valid: true},
+	}
+	thread = gemini.Thread{Post: release, Author: "alice", Replies: []gemini.Reply{{Comment: question, Author: "bob"}}}
+)
+
+func TestServer_Request(t *testing.T) {
+	tests := []struct {
+		name        string
+		url         string
+		certificate bool
+		setupMock   func(m *mocks.Store)
+		wantHeader  string
+		wantBody    []string
+	}{
+		{
+			name: "Should list boards",
+			url:  "gemini://localhost/",
+			setupMock: func(m *mocks.Store) {
+				m.On("Boards", mock.Anything).Return([]board.Board{general}, nil)
+			},
+			wantHeader: "20 text/gemini; charset=utf-8",
+			wantBody:   []string{"=> /board/" + general.ID.String() + " General", "=> /login"},
+		},
+		{
+			name: "Should render thread",
+			url:  "gemini://localhost:1965/post/" + release.ID.String(),
+			setupMock: func(m *mocks.Store) {
+				m.On("Thread", mock.Anything, uuid.Nil, release.ID).Return(thread, nil)
+			},
+			wantHeader: "20 text/gemini; charset=utf-8",
+			wantBody:   []string{"# Release", "Version 2 is out", "### [1] bob", "=> /post/" + release.ID.String() + "/reply/" + question.ID.String()},
+		},
+		{
+			name: "Should answer unknown board with not found",
+			url:  "gemini://localhost/board/" + general.ID.String(),
+			setupMock: func(m *mocks.Store) {
+				m.On("Posts", mock.Anything, uuid.Nil, general.ID).Return(board.Board{}, nil, errorPkg.NewNotFoundError("board", "id", general.ID.String(), ""))
+			},
+			wantHeader: "51 Not found",
+		},
+		{
+			name:       "Should ask for certificate to reply",
+			url:        "gemini://localhost/post/" + release.ID.String() + "/reply",
+			wantHeader: "60 Sign in with a client certificate",
+		},
+		{
+			name:        "Should reject unregistered certificate",
+			url:         "gemini://localhost/post/" + release.ID.String() + "/reply",
+			certificate: true,
+			setupMock: func(m *mocks.Store) {
+				m.On("Authenticate", mock.Anything, mock.Anything).Return(jwt.User{}, errorPkg.ErrCredentialInvalid)
+			},
+			wantHeader: "61 The certificate is not registered, add it to your account through the web API",
+		},
+		{
+			name:        "Should prompt for reply",
+			url:         "gemini://localhost/post/" + release.ID.String() + "/reply",
+			certificate: true,
+			setupMock: func(m *mocks.Store) {
+				m.On("Authenticate", mock.Anything, mock.Anything).Return(user, nil)
+				m.On("Thread", mock.Anything, uuid.MustParse(user.ID), release.ID).Return(thread, nil)
+			},
+			wantHeader: "10 Re: Release (markdown)",
+		},
+		{
+			name:        "Should reply to comment with a single Re: prefix",
+			url:         "gemini://localhost/post/" + release.ID.String() + "/reply/" + question.ID.String() + "?Next%20week%0A%2A%2Asure%2A%2A",
+			certificate: true,
+			setupMock: func(m *mocks.Store) {
+				m.On("Authenticate", mock.Anything, mock.Anything).Return(user, nil)
+				m.On("Thread", mock.Anything, uuid.MustParse(user.ID), release.ID).Return(thread, nil)
+				m.On("Reply", mock.Anything, comment.CreateRequest{
+					PostID:   release.ID,
+					AuthorID: uuid.MustParse(user.ID),
+					ParentID: &question.ID,
+					Title:    "Re: Release",
+					Content:  "Next week\n**sure**",
+				}).Return(comment.Comment{ID: uuid.New()}, nil)
+			},
+			wantHeader: "30 /post/" + release.ID.String(),
+		},
+		{
+			name:        "Should show error of the services",
+			url:         "gemini://localhost/post/" + release.ID.String() + "/reply?Late",
+			certificate: true,
+			setupMock: func(m *mocks.Store) {
+				m.On("Authenticate", mock.Anything, mock.Anything).Return(user, nil)
+				m.On("Thread", mock.Anything, uuid.MustParse(user.ID), release.ID).Return(thread, nil)
+				m.On("Reply", mock.Anything, mock.Anything).Return(comment.Comment{}, errorPkg.ErrPostLocked)
+			},
+			wantHeader: "50 The thread is locked and does not accept new comments",
+		},
+		{
+			name:       "Should refuse requests for other hosts",
+			url:        "gemini://forum.example.org/",
+			wantHeader: "53 This server only serves gemini://localhost",
+		},
+		{
+			name:       "Should reject relative URL",
+			url:        "/",
+			wantHeader: "59 Malformed request",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewStore(t)
+			if tt.setupMock != nil {
+				tt.setupMock(mockStore)
+			}
+
+			config := &tls.Config{InsecureSkipVerify: true}
+			if tt.certificate {
+				config.Certificates = []tls.Certificate{clientCertificate(t)}
+			}
+			conn, err := tls.Dial("tcp", serve(t, mockStore), config)
+			if err != nil {
+				t.Fatalf("Dial() error = %v", err)
+			}
+			defer conn.Close()
+
+			_, err = io.WriteString(conn, tt.url+"\r\n")
+			if err != nil {
+				t.Fatalf("WriteString() error = %v", err)
+			}
+			response, err := io.ReadAll(conn)
+			if err != nil {
+				t.Fatalf("ReadAll() error = %v", err)
+			}
+
+			header, body, _ := strings.Cut(string(response), "\r\n")
+			assert.Equal(t, tt.wantHeader, header)
+			for _, want := range tt.wantBody {
+				assert.Contains(t, body, want)
+			}
+		})
+	}
+}
+
+func TestLoadCertificate(t *testing.T) {
+	dir := t.TempDir()
+	generated, err := gemini.LoadCertificate(filepath.Join(dir, "gemini.crt"), filepath.Join(dir, "gemini.key"), "localhost")
+	if err != nil {
+		t.Fatalf("LoadCertificate() error = %v", err)
+	}
+	loaded, err := gemini.LoadCertificate(filepath.Join(dir, "gemini.crt"), filepath.Join(dir, "gemini.key"), "localhost")
+	if err != nil {
+		t.Fatalf("LoadCertificate() error = %v", err)
+	}
+
+	assert.Equal(t, generated.Certificate, loaded.Certificate)
+	leaf, err := x509.ParseCertificate(loaded.Certificate[0])
+	if err != nil {
+		t.Fatalf("ParseCertificate() error = %v", err)
+	}
+	assert.Equal(t, []string{"localhost"}, leaf.DNSNames)
+}
+
+// serve starts the server for localhost on a free port
+func serve(t *testing.T, store gemini.Store) string {
+	dir := t.TempDir()
+	certificate, err := gemini.LoadCertificate(filepath.Join(dir, "gemini.crt"), filepath.Join(dir, "gemini.key"), "localhost")
+	if err != nil {
+		t.Fatalf("LoadCertificate() error = %v", err)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go gemini.NewServer(zap.NewNop(), store, "", "localhost", certificate).Serve(ctx, listener)
+	return listener.Addr().String()
+}
+
+// clientCertificate creates a self-signed certificate like Gemini clients do
+func clientCertificate(t *testing.T) tls.Certificate {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: user.Username},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		t.Fatalf("CreateCertificate() error = %v", err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: privateKey}
+}
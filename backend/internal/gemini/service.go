@@ -0,0 +1,226 @@
+package gemini
+
+import (
+	"backend/internal/board"
+	"backend/internal/comment"
+	errorPkg "backend/internal/error"
+	"backend/internal/gateway"
+	"backend/internal/jwt"
+	"backend/internal/post"
+	"backend/internal/ratelimit"
+	"context"
+	"crypto/x509"
+	"fmt"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"time"
+)
+
+// Summary is a post in the list of a board
+type Summary struct {
+	Post      post.Post
+	Author    string
+	Collapsed bool
+}
+
+// Thread is a post with its comments, oldest comment first
+type Thread struct {
+	Post    post.Post
+	Author  string
+	Replies []Reply
+}
+
+type Reply struct {
+	Comment comment.Comment
+	Author  string
+	// Parent is the one-based position of the answered comment in the thread, zero for comments on the post and for
+	// replies to hidden comments
+	Parent    int
+	Collapsed bool
+}
+
+// Certificates finds the user of a client certificate, see clientcert.Service
+type Certificates interface {
+	Authenticate(ctx context.Context, cert *x509.Certificate) (uuid.UUID, error)
+}
+
+type Boards interface {
+	GetAll(ctx context.Context) ([]board.Board, error)
+	GetByID(ctx context.Context, id uuid.UUID) (board.Board, error)
+}
+
+type Posts interface {
+	GetByBoard(ctx context.Context, viewerID, boardID uuid.UUID) ([]post.Listed, error)
+	GetByID(ctx context.Context, id uuid.UUID) (post.Post, error)
+}
+
+type Comments interface {
+	GetByPost(ctx context.Context, viewerID, postId uuid.UUID) ([]comment.Listed, error)
+	Create(ctx context.Context, arg comment.CreateRequest) (comment.Comment, error)
+}
+
+// Service gives the Gemini server the content of the forum through the services of the web API
+type Service struct {
+	logger *zap.Logger
+	tracer trace.Tracer
+
+	certificates Certificates
+	boards       Boards
+	posts        Posts
+	comments     Comments
+	users        gateway.Users
+	limiter      *ratelimit.Limiter
+}
+
+func NewService(logger *zap.Logger, certificates Certificates, boards Boards, posts Posts, comments Comments, users gateway.Users, limiter *ratelimit.Limiter) *Service {
+	return &Service{
+		logger:       logger,
+		tracer:       otel.Tracer("gemini/service"),
+		certificates: certificates,
+		boards:       boards,
+		posts:        posts,
+		comments:     comments,
+		users:        users,
+		limiter:      limiter,
+	}
+}
+
+// Authenticate returns the user a client certificate is registered for, suspended users are rejected like on the web
+// API. Clients send the certificate with every request, so unlike logins to the API it is not recorded in the audit
+// trail.
+func (s *Service) Authenticate(ctx context.Context, cert *x509.Certificate) (jwt.User, error) {
+	traceCtx, span := s.tracer.Start(ctx, "Authenticate")
+	defer span.End()
+
+	userID, err := s.certificates.Authenticate(traceCtx, cert)
+	if err != nil {
+		err = fmt.Errorf("%w: %v", errorPkg.ErrCredentialInvalid, err)
+		span.RecordError(err)
+		return jwt.User{}, err
+	}
+
+	userEntity, err := s.users.GetByID(traceCtx, userID)
+	if err != nil {
+		span.RecordError(err)
+		return jwt.User{}, err
+	}
+	if userEntity.IsSuspended(time.Now()) {
+		err = userEntity.SuspensionError()
+		span.RecordError(err)
+		return jwt.User{}, err
+	}
+
+	return jwt.User{ID: userEntity.ID.String(), Username: userEntity.Name, Role: userEntity.Role, IssuedAt: time.Now()}, nil
+}
+
+func (s *Service) Boards(ctx context.Context) ([]board.Board, error) {
+	traceCtx, span := s.tracer.Start(ctx, "Boards")
+	defer span.End()
+
+	boards, err := s.boards.GetAll(traceCtx)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return boards, nil
+}
+
+// Posts returns a board and its posts in the order of the web API, pinned posts first
+func (s *Service) Posts(ctx context.Context, viewerID, boardID uuid.UUID) (board.Board, []Summary, error) {
+	traceCtx, span := s.tracer.Start(ctx, "Posts")
+	defer span.End()
+
+	b, err := s.boards.GetByID(traceCtx, boardID)
+	if err != nil {
+		span.RecordError(err)
+		return board.Board{}, nil, err
+	}
+
+	listed, err := s.posts.GetByBoard(traceCtx, viewerID, boardID)
+	if err != nil {
+		span.RecordError(err)
+		return board.Board{}, nil, err
+	}
+
+	names := gateway.NewNames(s.users)
+	summaries := make([]Summary, len(listed))
+	for i, l := range listed {
+		author, err := names.Get(traceCtx, l.Post.AuthorID)
+		if err != nil {
+			span.RecordError(err)
+			return board.Board{}, nil, err
+		}
+		summaries[i] = Summary{Post: l.Post, Author: author, Collapsed: l.Collapsed}
+	}
+
+	return b, summaries, nil
+}
+
+// Thread returns a post and its comments, unpublished posts are only found by their author
+func (s *Service) Thread(ctx context.Context, viewerID, postID uuid.UUID) (Thread, error) {
+	traceCtx, span := s.tracer.Start(ctx, "Thread")
+	defer span.End()
+
+	p, err := s.posts.GetByID(traceCtx, postID)
+	if err == nil && !p.Published() && p.AuthorID != viewerID {
+		err = errorPkg.NewNotFoundError("post", "id", postID.String(), "")
+	}
+	if err != nil {
+		span.RecordError(err)
+		return Thread{}, err
+	}
+
+	listed, err := s.comments.GetByPost(traceCtx, viewerID, postID)
+	if err != nil {
+		span.RecordError(err)
+		return Thread{}, err
+	}
+
+	names := gateway.NewNames(s.users)
+	thread := Thread{Post: p}
+	thread.Author, err = names.Get(traceCtx, p.AuthorID)
+	if err != nil {
+		span.RecordError(err)
+		return Thread{}, err
+	}
+	positions := make(map[uuid.UUID]int, len(listed))
+	for i, l := range listed {
+		author, err := names.Get(traceCtx, l.Comment.AuthorID)
+		if err != nil {
+			span.RecordError(err)
+			return Thread{}, err
+		}
+		positions[l.Comment.ID] = i + 1
+		reply := Reply{Comment: l.Comment, Author: author, Collapsed: l.Collapsed}
+		if l.Comment.ParentID.Valid {
+			reply.Parent = positions[l.Comment.ParentID.Bytes]
+		}
+		thread.Replies = append(thread.Replies, reply)
+	}
+
+	return thread, nil
+}
+
+// Reply writes a comment like the web API, which also limits the comments of the user. Suspensions that began after
+// the certificate was looked up are enforced as well.
+func (s *Service) Reply(ctx context.Context, r comment.CreateRequest) (comment.Comment, error) {
+	traceCtx, span := s.tracer.Start(ctx, "Reply")
+	defer span.End()
+
+	err := gateway.CheckWrite(traceCtx, s.users, s.limiter, r.AuthorID, ratelimit.RouteComments)
+	if err != nil {
+		span.RecordError(err)
+		return comment.Comment{}, err
+	}
+
+	created, err := s.comments.Create(traceCtx, r)
+	if err != nil {
+		span.RecordError(err)
+		return comment.Comment{}, err
+	}
+
+	return created, nil
+}
@@ -0,0 +1,101 @@
+package gemini_test
+
+import (
+	"backend/internal"
+	"backend/internal/comment"
+	"backend/internal/config"
+	errorPkg "backend/internal/error"
+	"backend/internal/gemini"
+	"backend/internal/ratelimit"
+	userPkg "backend/internal/user"
+	"context"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+// users finds the users of a map by ID
+type users map[uuid.UUID]userPkg.User
+
+func (u users) GetByID(_ context.Context, id uuid.UUID) (userPkg.User, error) {
+	found, ok := u[id]
+	if !ok {
+		return userPkg.User{}, errorPkg.NewNotFoundError("users", "id", id.String(), "")
+	}
+	return found, nil
+}
+
+// comments counts the comments that reach the service
+type comments struct {
+	created int
+}
+
+func (c *comments) GetByPost(context.Context, uuid.UUID, uuid.UUID) ([]comment.Listed, error) {
+	return nil, nil
+}
+
+func (c *comments) Create(_ context.Context, arg comment.CreateRequest) (comment.Comment, error) {
+	c.created++
+	return comment.Comment{ID: uuid.New(), PostID: arg.PostID}, nil
+}
+
+func TestService_Reply(t *testing.T) {
+	readerID := uuid.MustParse(user.ID)
+	suspendedID := uuid.MustParse("8d1c4a2e-0f4b-4c55-9a51-3b0c4f2e9d10")
+	known := users{
+		readerID:    {ID: readerID, Name: user.Username, Role: user.Role},
+		suspendedID: {ID: suspendedID, Name: "bob", Role: user.Role, SuspendedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true}},
+	}
+
+	tests := []struct {
+		name        string
+		authorID    uuid.UUID
+		attempts    int
+		wantErr     error
+		wantCreated int
+	}{
+		{
+			name:        "Should reply",
+			authorID:    readerID,
+			attempts:    1,
+			wantCreated: 1,
+		},
+		{
+			name:     "Should reject user suspended after the certificate was looked up",
+			authorID: suspendedID,
+			attempts: 1,
+			wantErr:  errorPkg.ErrSuspended,
+		},
+		{
+			name:        "Should limit replies like the web API",
+			authorID:    readerID,
+			attempts:    2,
+			wantErr:     errorPkg.ErrRateLimited,
+			wantCreated: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter, err := ratelimit.NewLimiter([]config.RateLimitRule{{Route: ratelimit.RouteComments, Requests: 1, Per: time.Minute}})
+			assert.NoError(t, err)
+			c := &comments{}
+			service := gemini.NewService(zap.NewNop(), nil, nil, nil, c, known, limiter)
+			ctx := context.WithValue(context.Background(), internal.UserContextKey, user)
+
+			for range tt.attempts {
+				_, err = service.Reply(ctx, comment.CreateRequest{PostID: release.ID, AuthorID: tt.authorID, Title: "Re: Release", Content: "Great"})
+			}
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantCreated, c.created)
+		})
+	}
+}
@@ -8,12 +8,16 @@ import (
 	"github.com/google/uuid"
 	"go.opentelemetry.io/otel"
 	"io"
+	"net"
 	"net/http"
 )
 
 type ContextKey string
 
-const UserContextKey ContextKey = "user"
+const (
+	UserContextKey     ContextKey = "user"
+	ClientIPContextKey ContextKey = "client_ip"
+)
 
 func ParseAndValidateRequestBody(ctx context.Context, v *validator.Validate, r *http.Request, s interface{}) error {
 	_, span := otel.Tracer("internal/handler").Start(ctx, "ParseAndValidateRequestBody")
@@ -69,3 +73,19 @@ func ParseUUID(value string) (uuid.UUID, error) {
 	}
 	return parsedUUID, nil
 }
+
+// ClientIP returns the address of the peer that sent the request without the port. Headers such as X-Forwarded-For
+// are ignored since clients can set them freely.
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// ClientIPFromContext returns the client IP stored by TraceMiddleware, or an empty string outside of requests
+func ClientIPFromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(ClientIPContextKey).(string)
+	return ip
+}
@@ -3,25 +3,40 @@ package jwt
 import (
 	"backend/internal"
 	errorPkg "backend/internal/error"
+	"backend/internal/problem"
 	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"net/http"
+	"time"
 )
 
+//go:generate mockery --name=Verifier
 type Verifier interface {
 	Parse(ctx context.Context, tokenString string) (User, error)
 }
 
+// UserChecker returns the role the user has now, or the suspension as an error if the user was suspended after the
+// given time, see user.Service
+//
+//go:generate mockery --name=UserChecker
+type UserChecker interface {
+	CurrentRole(ctx context.Context, id uuid.UUID, since time.Time) (string, error)
+}
+
 type Middleware struct {
 	logger *zap.Logger
 	tracer trace.Tracer
 
 	verifier Verifier
+	users    UserChecker
 }
 
-func NewMiddleware(verifier Verifier, logger *zap.Logger) Middleware {
+func NewMiddleware(verifier Verifier, users UserChecker, logger *zap.Logger) Middleware {
 	name := "middleware/jwt"
 	tracer := otel.Tracer(name)
 
@@ -29,6 +44,7 @@ func NewMiddleware(verifier Verifier, logger *zap.Logger) Middleware {
 		tracer:   tracer,
 		logger:   logger,
 		verifier: verifier,
+		users:    users,
 	}
 }
 
@@ -38,17 +54,13 @@ func (m Middleware) HandlerFunc(next http.HandlerFunc) http.HandlerFunc {
 		defer span.End()
 		logger := internal.LoggerWithContext(traceCtx, m.logger)
 
-		token := r.Header.Get("Authorization")
-		if token == "" {
-			logger.Warn("Authorization header required")
+		user, err := m.Authenticate(traceCtx, r.Header.Get("Authorization"))
+		if errors.Is(err, errorPkg.ErrUnauthorized) {
 			http.Error(w, "Unauthorized", http.StatusUnauthorized)
 			return
 		}
-
-		user, err := m.verifier.Parse(traceCtx, token)
 		if err != nil {
-			logger.Warn("Authorization header invalid", zap.Error(err))
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			problem.WriteError(traceCtx, w, err, logger)
 			return
 		}
 
@@ -58,6 +70,48 @@ func (m Middleware) HandlerFunc(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+// Authenticate returns the user of a token with the role the user has now, so demoted moderators lose their rights
+// before their token expires. Missing, invalid and expired tokens and tokens of deleted users are reported as
+// errorPkg.ErrUnauthorized, tokens of users suspended after the token was issued as the suspension. Connections that
+// outlive a request, such as WebSockets, use it to check their token again.
+func (m Middleware) Authenticate(ctx context.Context, token string) (User, error) {
+	logger := internal.LoggerWithContext(ctx, m.logger)
+
+	if token == "" {
+		logger.Warn("Authorization header required")
+		return User{}, errorPkg.ErrUnauthorized
+	}
+
+	user, err := m.verifier.Parse(ctx, token)
+	if err != nil {
+		logger.Warn("Authorization header invalid", zap.Error(err))
+		return User{}, fmt.Errorf("%w: %v", errorPkg.ErrUnauthorized, err)
+	}
+
+	// Tokens stay valid until they expire, so suspensions issued and roles changed after login have to be checked on
+	// every request
+	id, err := uuid.Parse(user.ID)
+	if err != nil {
+		logger.Warn("Authorization header contains invalid user id", zap.String("id", user.ID), zap.Error(err))
+		return User{}, fmt.Errorf("%w: %v", errorPkg.ErrUnauthorized, err)
+	}
+	role, err := m.users.CurrentRole(ctx, id, user.IssuedAt)
+	if errors.Is(err, errorPkg.ErrSuspended) {
+		logger.Info("Rejected token of suspended user", zap.String("id", user.ID), zap.Error(err))
+		return User{}, err
+	}
+	if errors.Is(err, errorPkg.ErrNotFound) {
+		logger.Warn("Authorization header belongs to unknown user", zap.String("id", user.ID))
+		return User{}, fmt.Errorf("%w: %v", errorPkg.ErrUnauthorized, err)
+	}
+	if err != nil {
+		return User{}, err
+	}
+
+	user.Role = role
+	return user, nil
+}
+
 func GetUserFromContext(ctx context.Context) (User, error) {
 	user, ok := ctx.Value(internal.UserContextKey).(User)
 	if !ok {
@@ -65,3 +119,13 @@ func GetUserFromContext(ctx context.Context) (User, error) {
 	}
 	return user, nil
 }
+
+// GetUserIDFromContext returns the id of the authenticated user of the request
+func GetUserIDFromContext(ctx context.Context) (uuid.UUID, error) {
+	u, err := GetUserFromContext(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return internal.ParseUUID(u.ID)
+}
@@ -0,0 +1,116 @@
+package jwt_test
+
+import (
+	"backend/internal"
+	errorPkg "backend/internal/error"
+	"backend/internal/jwt"
+	"backend/internal/jwt/mocks"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMiddleware_HandlerFunc(t *testing.T) {
+	issuedAt := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	user := jwt.User{
+		ID:       "81c1ecc1-66d7-4134-b5fe-d886a6f418a4",
+		Username: "test",
+		Role:     "USER",
+		IssuedAt: issuedAt,
+	}
+	until := issuedAt.Add(24 * time.Hour)
+
+	tests := []struct {
+		name       string
+		header     string
+		setupMock  func(v *mocks.Verifier, s *mocks.UserChecker)
+		wantStatus int
+		wantRole   string
+	}{
+		{
+			name:   "Should pass valid token",
+			header: "Bearer valid",
+			setupMock: func(v *mocks.Verifier, s *mocks.UserChecker) {
+				v.On("Parse", mock.Anything, "Bearer valid").Return(user, nil)
+				s.On("CurrentRole", mock.Anything, uuid.MustParse(user.ID), issuedAt).Return(jwt.RoleUser, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantRole:   jwt.RoleUser,
+		},
+		{
+			name:   "Should take role of demoted user from the database",
+			header: "Bearer moderator",
+			setupMock: func(v *mocks.Verifier, s *mocks.UserChecker) {
+				moderator := user
+				moderator.Role = jwt.RoleModerator
+				v.On("Parse", mock.Anything, "Bearer moderator").Return(moderator, nil)
+				s.On("CurrentRole", mock.Anything, uuid.MustParse(user.ID), issuedAt).Return(jwt.RoleUser, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantRole:   jwt.RoleUser,
+		},
+		{
+			name:       "Should reject missing token",
+			setupMock:  func(v *mocks.Verifier, s *mocks.UserChecker) {},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "Should reject invalid token",
+			header: "Bearer invalid",
+			setupMock: func(v *mocks.Verifier, s *mocks.UserChecker) {
+				v.On("Parse", mock.Anything, "Bearer invalid").Return(jwt.User{}, errors.New("token is malformed"))
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "Should reject token of user suspended after issuance",
+			header: "Bearer valid",
+			setupMock: func(v *mocks.Verifier, s *mocks.UserChecker) {
+				v.On("Parse", mock.Anything, "Bearer valid").Return(user, nil)
+				s.On("CurrentRole", mock.Anything, uuid.MustParse(user.ID), issuedAt).
+					Return("", errorPkg.NewSuspendedError(&until, "spam"))
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:   "Should reject token of deleted user",
+			header: "Bearer valid",
+			setupMock: func(v *mocks.Verifier, s *mocks.UserChecker) {
+				v.On("Parse", mock.Anything, "Bearer valid").Return(user, nil)
+				s.On("CurrentRole", mock.Anything, uuid.MustParse(user.ID), issuedAt).
+					Return("", errorPkg.NewNotFoundError("users", "id", user.ID, ""))
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := mocks.NewVerifier(t)
+			s := mocks.NewUserChecker(t)
+			tt.setupMock(v, s)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+			if tt.header != "" {
+				r.Header.Set("Authorization", tt.header)
+			}
+
+			m := jwt.NewMiddleware(v, s, zap.NewNop())
+			m.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				want := user
+				want.Role = tt.wantRole
+				assert.Equal(t, want, r.Context().Value(internal.UserContextKey))
+				w.WriteHeader(http.StatusOK)
+			})(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
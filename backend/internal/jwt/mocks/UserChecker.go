@@ -0,0 +1,60 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	uuid "github.com/google/uuid"
+)
+
+// UserChecker is an autogenerated mock type for the UserChecker type
+type UserChecker struct {
+	mock.Mock
+}
+
+// CurrentRole provides a mock function with given fields: ctx, id, since
+func (_m *UserChecker) CurrentRole(ctx context.Context, id uuid.UUID, since time.Time) (string, error) {
+	ret := _m.Called(ctx, id, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CurrentRole")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time) (string, error)); ok {
+		return rf(ctx, id, since)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time) string); ok {
+		r0 = rf(ctx, id, since)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, time.Time) error); ok {
+		r1 = rf(ctx, id, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewUserChecker creates a new instance of UserChecker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUserChecker(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UserChecker {
+	mock := &UserChecker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
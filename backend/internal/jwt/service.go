@@ -38,10 +38,33 @@ type User struct {
 	ID       string `json:"id"`
 	Username string `json:"user"`
 	Role     string `json:"role"`
+
+	// IssuedAt is the time the token of the user was issued
+	IssuedAt time.Time `json:"-"`
+}
+
+// Roles in ascending order of privilege, every role includes the permissions of the roles before it
+const (
+	RoleUser      = "USER"
+	RoleModerator = "MODERATOR"
+	RoleAdmin     = "ADMIN"
+)
+
+var roleRank = map[string]int{
+	RoleUser:      1,
+	RoleModerator: 2,
+	RoleAdmin:     3,
 }
 
+// HasRole reports whether the user has the role or a more privileged one
 func (u User) HasRole(role string) bool {
-	return u.Role == role
+	return RoleIncludes(u.Role, role)
+}
+
+// RoleIncludes reports whether role grants the permissions of required, unknown roles grant nothing
+func RoleIncludes(role, required string) bool {
+	rank, ok := roleRank[role]
+	return ok && rank >= roleRank[required]
 }
 
 func (s Service) New(ctx context.Context, id, username string, role string) (string, error) {
@@ -122,9 +145,14 @@ func (s Service) Parse(ctx context.Context, tokenString string) (User, error) {
 
 	logger.Debug("Successfully parsed JWT token", zap.String("id", claims.ID), zap.String("username", claims.Username), zap.String("role", claims.Role))
 
-	return User{
+	user := User{
 		ID:       claims.ID,
 		Username: claims.Username,
 		Role:     claims.Role,
-	}, nil
+	}
+	if claims.IssuedAt != nil {
+		user.IssuedAt = claims.IssuedAt.Time
+	}
+
+	return user, nil
 }
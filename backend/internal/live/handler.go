@@ -0,0 +1,167 @@
+package live
+
+import (
+	"backend/internal"
+	errorPkg "backend/internal/error"
+	"backend/internal/jwt"
+	"backend/internal/post"
+	"backend/internal/problem"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// writeTimeout bounds how long a single message may take to reach a client
+	writeTimeout = 10 * time.Second
+	// recheckInterval is how often the token of an open connection is checked again, so suspended users and
+	// expired tokens are disconnected
+	recheckInterval = time.Minute
+)
+
+// Authenticator returns the user of a token like the API does for every request, see jwt.Middleware
+//
+//go:generate mockery --name=Authenticator
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (jwt.User, error)
+}
+
+//go:generate mockery --name=PostStore
+type PostStore interface {
+	GetByID(ctx context.Context, id uuid.UUID) (post.Post, error)
+}
+
+type Handler struct {
+	logger *zap.Logger
+	tracer trace.Tracer
+
+	hub           *Hub
+	authenticator Authenticator
+	postStore     PostStore
+}
+
+func NewHandler(logger *zap.Logger, hub *Hub, authenticator Authenticator, postStore PostStore) *Handler {
+	return &Handler{
+		logger:        logger,
+		tracer:        otel.Tracer("live/handler"),
+		hub:           hub,
+		authenticator: authenticator,
+		postStore:     postStore,
+	}
+}
+
+// LiveHandler upgrades the request to a WebSocket subscribed to the thread of a post. Because browsers cannot set
+// headers on WebSocket requests, the token is also accepted from the token query parameter.
+func (h *Handler) LiveHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "LiveEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	token := r.Header.Get("Authorization")
+	if token == "" {
+		token = r.URL.Query().Get("token")
+	}
+	user, err := h.authenticator.Authenticate(traceCtx, strings.TrimSpace(token))
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	postID, err := internal.ParseUUID(r.PathValue("post_id"))
+	if err != nil {
+		problem.WriteError(traceCtx, w, fmt.Errorf("%w: %v", errorPkg.ErrInvalidUUID, err), logger)
+		return
+	}
+
+	p, err := h.postStore.GetByID(traceCtx, postID)
+	if err == nil && !p.Published() && p.AuthorID.String() != user.ID {
+		err = errorPkg.NewNotFoundError("post", "id", postID.String(), "")
+	}
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	conn, err := websocket.Accept(w, r, nil)
+	if err != nil {
+		logger.Warn("Failed to accept WebSocket connection", zap.Error(err))
+		return
+	}
+	defer conn.CloseNow()
+
+	c := &client{
+		userID:   user.ID,
+		username: user.Username,
+		send:     make(chan Message, clientBuffer),
+	}
+	h.hub.join(postID, c)
+	defer h.hub.leave(postID, c)
+
+	logger.Debug("Live connection opened", zap.String("post_id", postID.String()), zap.String("user_id", user.ID))
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	go h.readLoop(ctx, cancel, conn, postID, c)
+
+	recheck := time.NewTicker(recheckInterval)
+	defer recheck.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			_ = conn.Close(websocket.StatusNormalClosure, "")
+			return
+		case <-recheck.C:
+			_, err = h.authenticator.Authenticate(ctx, strings.TrimSpace(token))
+			if err != nil {
+				logger.Info("Closing live connection of rejected token", zap.String("user_id", user.ID), zap.Error(err))
+				_ = conn.Close(websocket.StatusPolicyViolation, "authorization revoked")
+				return
+			}
+		case message, ok := <-c.send:
+			if !ok {
+				_ = conn.Close(websocket.StatusPolicyViolation, "connection too slow")
+				return
+			}
+
+			writeCtx, writeCancel := context.WithTimeout(ctx, writeTimeout)
+			err = wsjson.Write(writeCtx, conn, message)
+			writeCancel()
+			if err != nil {
+				logger.Debug("Live connection write failed", zap.Error(err))
+				return
+			}
+		}
+	}
+}
+
+// readLoop relays typing indicators from the client to the rest of the room and cancels the connection once the
+// client goes away
+func (h *Handler) readLoop(ctx context.Context, cancel context.CancelFunc, conn *websocket.Conn, postID uuid.UUID, c *client) {
+	defer cancel()
+
+	for {
+		var message Message
+		err := wsjson.Read(ctx, conn, &message)
+		if err != nil {
+			var closeErr websocket.CloseError
+			if !errors.As(err, &closeErr) && ctx.Err() == nil {
+				h.logger.Debug("Live connection read failed", zap.Error(err))
+			}
+			return
+		}
+
+		if message.Type == MessageTyping {
+			h.hub.typing(postID, c)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package live_test
+
+import (
+	errorPkg "backend/internal/error"
+	"backend/internal/jwt"
+	"backend/internal/live"
+	"backend/internal/live/mocks"
+	"backend/internal/post"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var (
+	alice = jwt.User{ID: "81c1ecc1-66d7-4134-b5fe-d886a6f418a4", Username: "alice", Role: jwt.RoleUser}
+	bob   = jwt.User{ID: "8d1c4a2e-0f4b-4c55-9a51-3b0c4f2e9d10", Username: "bob", Role: jwt.RoleUser}
+	draft = post.Post{
+		ID:       uuid.MustParse("54a46af2-b454-4746-8ab0-3cf26085a50b"),
+		AuthorID: uuid.MustParse(alice.ID),
+		Status:   post.StatusDraft,
+	}
+)
+
+func TestHandler_LiveHandler(t *testing.T) {
+	until := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name       string
+		target     string
+		setupMock  func(a *mocks.Authenticator, p *mocks.PostStore)
+		wantStatus int
+	}{
+		{
+			name:   "Should reject missing token",
+			target: "/api/post/" + draft.ID.String() + "/live",
+			setupMock: func(a *mocks.Authenticator, p *mocks.PostStore) {
+				a.On("Authenticate", mock.Anything, "").Return(jwt.User{}, errorPkg.ErrUnauthorized)
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "Should reject suspended user",
+			target: "/api/post/" + draft.ID.String() + "/live?token=alice",
+			setupMock: func(a *mocks.Authenticator, p *mocks.PostStore) {
+				a.On("Authenticate", mock.Anything, "alice").Return(jwt.User{}, errorPkg.NewSuspendedError(&until, "spam"))
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:   "Should hide draft of another user",
+			target: "/api/post/" + draft.ID.String() + "/live?token=bob",
+			setupMock: func(a *mocks.Authenticator, p *mocks.PostStore) {
+				a.On("Authenticate", mock.Anything, "bob").Return(bob, nil)
+				p.On("GetByID", mock.Anything, draft.ID).Return(draft, nil)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:   "Should reject invalid post id",
+			target: "/api/post/nope/live?token=bob",
+			setupMock: func(a *mocks.Authenticator, p *mocks.PostStore) {
+				a.On("Authenticate", mock.Anything, "bob").Return(bob, nil)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authenticator := mocks.NewAuthenticator(t)
+			postStore := mocks.NewPostStore(t)
+			tt.setupMock(authenticator, postStore)
+			handler := live.NewHandler(zap.NewNop(), live.NewHub(zap.NewNop(), newSubscriber()), authenticator, postStore)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /api/post/{post_id}/live", handler.LiveHandler)
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package live
+
+import (
+	"backend/internal/event"
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+const (
+	MessageComment  = "comment"
+	MessageTyping   = "typing"
+	MessagePresence = "presence"
+)
+
+const (
+	// clientBuffer is the number of outgoing messages queued per connection before it is considered too slow and
+	// dropped
+	clientBuffer = 32
+	// typingInterval is the least time between two typing indicators relayed for a connection, so a client sending
+	// them in a loop cannot fill the queues of the others
+	typingInterval = 3 * time.Second
+)
+
+// Message is the envelope of everything sent over a live thread connection
+type Message struct {
+	Type     string          `json:"type"`
+	Comment  json.RawMessage `json:"comment,omitempty"`
+	UserID   string          `json:"user_id,omitempty"`
+	Username string          `json:"username,omitempty"`
+	Count    int             `json:"count,omitempty"`
+}
+
+type Subscriber interface {
+	Subscribe() (<-chan event.Event, func())
+}
+
+type client struct {
+	userID   string
+	username string
+	send     chan Message
+	// typedAt is when the last typing indicator of the client was relayed, only the read loop of the client uses it
+	typedAt time.Time
+}
+
+// Hub tracks the live connections of each post and pushes new comments, typing indicators and presence counts to
+// them. New comments arrive through the event stream and therefore reach connections on every backend instance,
+// typing indicators and presence counts only cover the connections held by this instance.
+type Hub struct {
+	logger     *zap.Logger
+	subscriber Subscriber
+
+	mu    sync.Mutex
+	rooms map[uuid.UUID]map[*client]struct{}
+}
+
+func NewHub(logger *zap.Logger, subscriber Subscriber) *Hub {
+	return &Hub{
+		logger:     logger,
+		subscriber: subscriber,
+		rooms:      make(map[uuid.UUID]map[*client]struct{}),
+	}
+}
+
+// Run forwards comment.created events to the rooms of their posts until ctx is done
+func (h *Hub) Run(ctx context.Context) {
+	for {
+		events, unsubscribe := h.subscriber.Subscribe()
+		if !h.forward(ctx, events) {
+			unsubscribe()
+			return
+		}
+
+		unsubscribe()
+		h.logger.Warn("Live hub event subscription dropped, resubscribing")
+	}
+}
+
+// forward returns false when ctx is done and true when the subscription was closed
+func (h *Hub) forward(ctx context.Context, events <-chan event.Event) bool {
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case e, ok := <-events:
+			if !ok {
+				return ctx.Err() == nil
+			}
+			if e.Type != event.CommentCreated {
+				continue
+			}
+
+			var target struct {
+				PostID string `json:"post_id"`
+			}
+			err := json.Unmarshal(e.Payload, &target)
+			if err != nil {
+				h.logger.Warn("Failed to decode comment event", zap.Int64("event_id", e.ID), zap.Error(err))
+				continue
+			}
+			postID, err := uuid.Parse(target.PostID)
+			if err != nil {
+				continue
+			}
+
+			h.broadcast(postID, nil, Message{Type: MessageComment, Comment: e.Payload})
+		}
+	}
+}
+
+func (h *Hub) join(postID uuid.UUID, c *client) {
+	h.mu.Lock()
+	room, ok := h.rooms[postID]
+	if !ok {
+		room = make(map[*client]struct{})
+		h.rooms[postID] = room
+	}
+	room[c] = struct{}{}
+	count := len(room)
+	h.mu.Unlock()
+
+	h.broadcast(postID, nil, Message{Type: MessagePresence, Count: count})
+}
+
+func (h *Hub) leave(postID uuid.UUID, c *client) {
+	h.mu.Lock()
+	room := h.rooms[postID]
+	if _, ok := room[c]; !ok {
+		h.mu.Unlock()
+		return
+	}
+	delete(room, c)
+	close(c.send)
+	count := len(room)
+	if count == 0 {
+		delete(h.rooms, postID)
+	}
+	h.mu.Unlock()
+
+	if count > 0 {
+		h.broadcast(postID, nil, Message{Type: MessagePresence, Count: count})
+	}
+}
+
+// typing relays a typing indicator of the client to the rest of the room, at most one per typingInterval
+func (h *Hub) typing(postID uuid.UUID, c *client) {
+	now := time.Now()
+	if now.Sub(c.typedAt) < typingInterval {
+		return
+	}
+	c.typedAt = now
+
+	h.broadcast(postID, c, Message{Type: MessageTyping, UserID: c.userID, Username: c.username})
+}
+
+// broadcast queues message for every connection in the room except the sender, connections that cannot keep up
+// are dropped and the rest of the room receives the new presence count
+func (h *Hub) broadcast(postID uuid.UUID, sender *client, message Message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// the presence count may drop further slow connections, which is announced again
+	for h.send(postID, sender, message) {
+		sender, message = nil, Message{Type: MessagePresence, Count: len(h.rooms[postID])}
+	}
+}
+
+// send queues message like broadcast and reports whether connections were dropped, h.mu must be held
+func (h *Hub) send(postID uuid.UUID, sender *client, message Message) bool {
+	room := h.rooms[postID]
+	dropped := false
+	for c := range room {
+		if c == sender {
+			continue
+		}
+
+		select {
+		case c.send <- message:
+		default:
+			h.logger.Warn("Dropping slow live connection", zap.String("post_id", postID.String()), zap.String("user_id", c.userID))
+			delete(room, c)
+			close(c.send)
+			dropped = true
+		}
+	}
+
+	if len(room) == 0 {
+		delete(h.rooms, postID)
+		return false
+	}
+	return dropped
+}
@@ -0,0 +1,104 @@
+package live_test
+
+import (
+	"backend/internal/event"
+	"backend/internal/live"
+	"backend/internal/live/mocks"
+	"context"
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// subscriber delivers the events sent to its channel to the hub
+type subscriber struct {
+	events chan event.Event
+}
+
+func newSubscriber() *subscriber {
+	return &subscriber{events: make(chan event.Event, 1)}
+}
+
+func (s *subscriber) Subscribe() (<-chan event.Event, func()) {
+	return s.events, func() {}
+}
+
+func TestHub(t *testing.T) {
+	authenticator := mocks.NewAuthenticator(t)
+	authenticator.On("Authenticate", mock.Anything, "alice").Return(alice, nil)
+	authenticator.On("Authenticate", mock.Anything, "bob").Return(bob, nil)
+	postStore := mocks.NewPostStore(t)
+	postStore.On("GetByID", mock.Anything, draft.ID).Return(draft, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	events := newSubscriber()
+	hub := live.NewHub(zap.NewNop(), events)
+	go hub.Run(ctx)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/post/{post_id}/live", live.NewHandler(zap.NewNop(), hub, authenticator, postStore).LiveHandler)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/post/" + draft.ID.String() + "/live?token="
+
+	// the author may join the room of a draft
+	author := dial(ctx, t, url+"alice")
+	assert.Equal(t, live.Message{Type: live.MessagePresence, Count: 1}, read(ctx, t, author))
+
+	// other users only share the room once the post is published
+	postStore.ExpectedCalls = nil
+	published := draft
+	published.Status = "published"
+	postStore.On("GetByID", mock.Anything, draft.ID).Return(published, nil)
+	reader := dial(ctx, t, url+"bob")
+	assert.Equal(t, live.Message{Type: live.MessagePresence, Count: 2}, read(ctx, t, author))
+	assert.Equal(t, live.Message{Type: live.MessagePresence, Count: 2}, read(ctx, t, reader))
+
+	// typing indicators sent in quick succession are relayed once
+	for range 3 {
+		err := wsjson.Write(ctx, author, live.Message{Type: live.MessageTyping})
+		if err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	assert.Equal(t, live.Message{Type: live.MessageTyping, UserID: alice.ID, Username: alice.Username}, read(ctx, t, reader))
+
+	payload := `{"id":"6a0e5f51-4f0c-4d5e-8b4b-2c1d3e4f5a6b","post_id":"` + draft.ID.String() + `"}`
+	events.events <- event.Event{ID: 1, Type: event.CommentCreated, Payload: []byte(payload)}
+	for _, conn := range []*websocket.Conn{author, reader} {
+		message := read(ctx, t, conn)
+		assert.Equal(t, live.MessageComment, message.Type)
+		assert.JSONEq(t, payload, string(message.Comment))
+	}
+
+	_ = reader.Close(websocket.StatusNormalClosure, "")
+	assert.Equal(t, live.Message{Type: live.MessagePresence, Count: 1}, read(ctx, t, author))
+}
+
+func dial(ctx context.Context, t *testing.T, url string) *websocket.Conn {
+	conn, _, err := websocket.Dial(ctx, url, nil)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	t.Cleanup(func() {
+		_ = conn.CloseNow()
+	})
+	return conn
+}
+
+func read(ctx context.Context, t *testing.T, conn *websocket.Conn) live.Message {
+	var message live.Message
+	err := wsjson.Read(ctx, conn, &message)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	return message
+}
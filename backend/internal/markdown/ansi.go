@@ -0,0 +1,167 @@
+package markdown
+
+import (
+	"fmt"
+	"github.com/yuin/goldmark/ast"
+	"strings"
+	"unicode"
+)
+
+// SGR sequences, inline styles are switched off individually so they nest
+const (
+	reset         = "\x1b[0m"
+	bold          = "\x1b[1m"
+	boldOff       = "\x1b[22m"
+	italic        = "\x1b[3m"
+	italicOff     = "\x1b[23m"
+	underline     = "\x1b[4m"
+	underlineOff  = "\x1b[24m"
+	dim           = "\x1b[2m"
+	codeColor     = "\x1b[33m"
+	colorOff      = "\x1b[39m"
+	heading1Style = "\x1b[1;35m"
+	heading2Style = "\x1b[1;36m"
+	headingStyle  = "\x1b[1;34m"
+)
+
+// RenderANSI converts content to text for terminals: headings are bold and colored, code is colored, emphasis uses
+// italic and bold, and links are underlined with their target in parentheses. Raw HTML is dropped and control
+// characters in the content are removed, so the content cannot send its own escape sequences.
+func RenderANSI(content string) string {
+	source := []byte(content)
+	r := ansiRenderer{source: source}
+	return r.blocks(parse(source), "\n\n")
+}
+
+type ansiRenderer struct {
+	source []byte
+}
+
+// blocks renders the block children of parent, separated by sep
+func (r ansiRenderer) blocks(parent ast.Node, sep string) string {
+	var parts []string
+	for child := parent.FirstChild(); child != nil; child = child.NextSibling() {
+		if part := r.block(child); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, sep)
+}
+
+func (r ansiRenderer) block(n ast.Node) string {
+	switch n := n.(type) {
+	case *ast.Heading:
+		style := headingStyle
+		switch n.Level {
+		case 1:
+			style = heading1Style
+		case 2:
+			style = heading2Style
+		}
+		return style + strings.Repeat("#", n.Level) + " " + r.inlines(n) + reset
+	case *ast.Paragraph, *ast.TextBlock:
+		return r.inlines(n)
+	case *ast.ThematicBreak:
+		return dim + strings.Repeat("─", 40) + reset
+	case *ast.CodeBlock, *ast.FencedCodeBlock:
+		var lines []string
+		for i := 0; i < n.Lines().Len(); i++ {
+			line := n.Lines().At(i)
+			lines = append(lines, "  "+codeColor+stripControl(strings.TrimRight(string(line.Value(r.source)), "\r\n"))+reset)
+		}
+		return strings.Join(lines, "\n")
+	case *ast.Blockquote:
+		return prefixLines(r.blocks(n, "\n\n"), dim+"│ "+reset, dim+"│ "+reset)
+	case *ast.List:
+		return r.list(n)
+	case *ast.HTMLBlock:
+		return ""
+	default:
+		return r.blocks(n, "\n\n")
+	}
+}
+
+func (r ansiRenderer) list(n *ast.List) string {
+	sep := "\n\n"
+	if n.IsTight {
+		sep = "\n"
+	}
+
+	var items []string
+	number := n.Start
+	for item := n.FirstChild(); item != nil; item = item.NextSibling() {
+		marker := "• "
+		if n.IsOrdered() {
+			marker = fmt.Sprintf("%d. ", number)
+			number++
+		}
+		body := r.blocks(item, sep)
+		items = append(items, prefixLines(body, marker, strings.Repeat(" ", len([]rune(marker)))))
+	}
+	return strings.Join(items, sep)
+}
+
+// inlines renders the inline children of n
+func (r ansiRenderer) inlines(n ast.Node) string {
+	var b strings.Builder
+	for child := n.FirstChild(); child != nil; child = child.NextSibling() {
+		r.inline(&b, child)
+	}
+	return b.String()
+}
+
+func (r ansiRenderer) inline(b *strings.Builder, n ast.Node) {
+	switch n := n.(type) {
+	case *ast.Text:
+		b.WriteString(stripControl(string(n.Segment.Value(r.source))))
+		if n.SoftLineBreak() || n.HardLineBreak() {
+			b.WriteString("\n")
+		}
+	case *ast.String:
+		b.WriteString(stripControl(string(n.Value)))
+	case *ast.CodeSpan:
+		b.WriteString(codeColor + r.inlines(n) + colorOff)
+	case *ast.Emphasis:
+		if n.Level >= 2 {
+			b.WriteString(bold + r.inlines(n) + boldOff)
+		} else {
+			b.WriteString(italic + r.inlines(n) + italicOff)
+		}
+	case *ast.Link:
+		label := r.inlines(n)
+		b.WriteString(underline + label + underlineOff)
+		if destination := stripControl(string(n.Destination)); destination != label {
+			b.WriteString(" (" + destination + ")")
+		}
+	case *ast.Image:
+		b.WriteString("[image: " + r.inlines(n) + "] (" + stripControl(string(n.Destination)) + ")")
+	case *ast.AutoLink:
+		b.WriteString(underline + stripControl(string(n.URL(r.source))) + underlineOff)
+	case *ast.RawHTML:
+	default:
+		b.WriteString(r.inlines(n))
+	}
+}
+
+// prefixLines puts first before the first line of s and rest before every following line
+func prefixLines(s, first, rest string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if i == 0 {
+			lines[i] = first + line
+		} else {
+			lines[i] = rest + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// stripControl removes control characters except line breaks and tabs, so content cannot send escape sequences
+func stripControl(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r != '\n' && r != '\t' && unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
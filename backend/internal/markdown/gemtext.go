@@ -0,0 +1,159 @@
+package markdown
+
+import (
+	"fmt"
+	"github.com/yuin/goldmark/ast"
+	"strings"
+)
+
+// RenderGemtext converts content to gemtext for Gemini clients. Gemtext has no inline markup, so emphasis and code
+// spans become plain text and links keep their label in the text, their targets are listed as link lines after the
+// block they appear in. Lists are flattened to one level, raw HTML is dropped and control characters are removed.
+func RenderGemtext(content string) string {
+	source := []byte(content)
+	r := &gemtextRenderer{source: source}
+
+	var parts []string
+	for child := parse(source).FirstChild(); child != nil; child = child.NextSibling() {
+		part := r.block(child)
+		if len(r.links) > 0 {
+			part = strings.TrimPrefix(part+"\n"+strings.Join(r.links, "\n"), "\n")
+			r.links = nil
+		}
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+type gemtextRenderer struct {
+	source []byte
+	// links are the link lines of the current top level block
+	links []string
+}
+
+// blocks renders the block children of parent, separated by sep
+func (r *gemtextRenderer) blocks(parent ast.Node, sep string) string {
+	var parts []string
+	for child := parent.FirstChild(); child != nil; child = child.NextSibling() {
+		if part := r.block(child); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, sep)
+}
+
+func (r *gemtextRenderer) block(n ast.Node) string {
+	switch n := n.(type) {
+	case *ast.Heading:
+		return strings.Repeat("#", min(n.Level, 3)) + " " + strings.ReplaceAll(r.inlines(n), "\n", " ")
+	case *ast.Paragraph, *ast.TextBlock:
+		return escapeGemtext(r.inlines(n))
+	case *ast.ThematicBreak:
+		return strings.Repeat("─", 40)
+	case *ast.CodeBlock, *ast.FencedCodeBlock:
+		lines := []string{"```"}
+		for i := 0; i < n.Lines().Len(); i++ {
+			segment := n.Lines().At(i)
+			line := stripControl(strings.TrimRight(string(segment.Value(r.source)), "\r\n"))
+			if strings.HasPrefix(line, "```") {
+				line = " " + line
+			}
+			lines = append(lines, line)
+		}
+		return strings.Join(append(lines, "```"), "\n")
+	case *ast.Blockquote:
+		return prefixLines(r.blocks(n, "\n\n"), "> ", "> ")
+	case *ast.List:
+		return strings.Join(r.list(n), "\n")
+	case *ast.HTMLBlock:
+		return ""
+	default:
+		return r.blocks(n, "\n\n")
+	}
+}
+
+// list renders every item as one line, nested lists follow the item they belong to
+func (r *gemtextRenderer) list(n *ast.List) []string {
+	var lines []string
+	number := n.Start
+	for item := n.FirstChild(); item != nil; item = item.NextSibling() {
+		var text, nested []string
+		for child := item.FirstChild(); child != nil; child = child.NextSibling() {
+			if l, ok := child.(*ast.List); ok {
+				nested = append(nested, r.list(l)...)
+				continue
+			}
+			text = append(text, strings.TrimSpace(strings.ReplaceAll(r.block(child), "\n", " ")))
+		}
+
+		marker := "* "
+		if n.IsOrdered() {
+			marker = fmt.Sprintf("* %d. ", number)
+			number++
+		}
+		lines = append(lines, marker+strings.Join(text, " "))
+		lines = append(lines, nested...)
+	}
+	return lines
+}
+
+// inlines renders the inline children of n
+func (r *gemtextRenderer) inlines(n ast.Node) string {
+	var b strings.Builder
+	for child := n.FirstChild(); child != nil; child = child.NextSibling() {
+		r.inline(&b, child)
+	}
+	return b.String()
+}
+
+func (r *gemtextRenderer) inline(b *strings.Builder, n ast.Node) {
+	switch n := n.(type) {
+	case *ast.Text:
+		b.WriteString(stripControl(string(n.Segment.Value(r.source))))
+		if n.SoftLineBreak() || n.HardLineBreak() {
+			b.WriteString("\n")
+		}
+	case *ast.String:
+		b.WriteString(stripControl(string(n.Value)))
+	case *ast.Link:
+		label := strings.ReplaceAll(r.inlines(n), "\n", " ")
+		b.WriteString(label)
+		r.link(string(n.Destination), label)
+	case *ast.Image:
+		label := strings.ReplaceAll(r.inlines(n), "\n", " ")
+		b.WriteString("[image: " + label + "]")
+		r.link(string(n.Destination), "image: "+label)
+	case *ast.AutoLink:
+		url := stripControl(string(n.URL(r.source)))
+		b.WriteString(url)
+		r.link(url, "")
+	case *ast.RawHTML:
+	default:
+		b.WriteString(r.inlines(n))
+	}
+}
+
+func (r *gemtextRenderer) link(destination, label string) {
+	destination = strings.Join(strings.Fields(stripControl(destination)), "%20")
+	if destination == "" {
+		return
+	}
+	r.links = append(r.links, strings.TrimSpace("=> "+destination+" "+label))
+}
+
+// escapeGemtext indents lines of text that a client would read as a link, heading, list item, quote or preformatted
+// toggle
+func escapeGemtext(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		for _, prefix := range []string{"=>", "#", "* ", ">", "```"} {
+			if strings.HasPrefix(line, prefix) {
+				lines[i] = " " + line
+				break
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,142 @@
+// Package markdown treats post and comment content as CommonMark. Content is validated when it is written and
+// rendered on request, either to sanitized HTML for web clients, to ANSI styled text for terminals or to gemtext for
+// Gemini clients.
+package markdown
+
+import (
+	errorPkg "backend/internal/error"
+	"bytes"
+	"fmt"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Formats content can be rendered to, FormatNone leaves the content as written
+const (
+	FormatNone = ""
+	FormatHTML = "html"
+	FormatANSI = "ansi"
+)
+
+var (
+	md     = goldmark.New()
+	policy = newPolicy()
+)
+
+// allowedSchemes are the URL schemes links and images may use, relative URLs have no scheme
+var allowedSchemes = map[string]bool{
+	"":       true,
+	"http":   true,
+	"https":  true,
+	"mailto": true,
+}
+
+// ParseFormat reads the render query parameter
+func ParseFormat(r *http.Request) (string, error) {
+	format := r.URL.Query().Get("render")
+	switch format {
+	case FormatNone, FormatHTML, FormatANSI:
+		return format, nil
+	default:
+		return "", fmt.Errorf("%w: render must be %s or %s", errorPkg.ErrInvalidQuery, FormatHTML, FormatANSI)
+	}
+}
+
+// Validate checks that content can be rendered safely. Raw HTML, links with schemes other than http, https and
+// mailto, and control characters that could drive a terminal are rejected.
+func Validate(content string) error {
+	for _, r := range content {
+		if isControl(r) {
+			return fmt.Errorf("%w: control character %U is not allowed", errorPkg.ErrInvalidMarkdown, r)
+		}
+	}
+
+	source := []byte(content)
+	var err error
+	walkErr := ast.Walk(parse(source), func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch n := n.(type) {
+		case *ast.HTMLBlock, *ast.RawHTML:
+			err = fmt.Errorf("%w: raw HTML is not allowed", errorPkg.ErrInvalidMarkdown)
+		case *ast.Link:
+			err = validateURL(string(n.Destination))
+		case *ast.Image:
+			err = validateURL(string(n.Destination))
+		case *ast.AutoLink:
+			if n.AutoLinkType == ast.AutoLinkURL {
+				err = validateURL(string(n.URL(source)))
+			}
+		}
+		if err != nil {
+			return ast.WalkStop, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	if walkErr != nil {
+		return walkErr
+	}
+
+	return err
+}
+
+// Render converts content to the format, content in FormatNone is returned unchanged
+func Render(content, format string) string {
+	switch format {
+	case FormatHTML:
+		return RenderHTML(content)
+	case FormatANSI:
+		return RenderANSI(content)
+	default:
+		return content
+	}
+}
+
+// RenderHTML converts content to HTML. Raw HTML in the content is dropped and the result is sanitized, so it is safe
+// to embed in a page even for content written before validation.
+func RenderHTML(content string) string {
+	var buf bytes.Buffer
+	err := md.Convert([]byte(content), &buf)
+	if err != nil {
+		// the HTML renderer only fails when writing to buf fails, which it does not
+		return policy.Sanitize(content)
+	}
+	return policy.Sanitize(buf.String())
+}
+
+// newPolicy allows the markup of user generated content plus the language classes of fenced code blocks, which web
+// clients use for syntax highlighting
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+-]+$`)).OnElements("code")
+	return p
+}
+
+func parse(source []byte) ast.Node {
+	return md.Parser().Parse(text.NewReader(source))
+}
+
+func validateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("%w: invalid link %q", errorPkg.ErrInvalidMarkdown, raw)
+	}
+	if !allowedSchemes[strings.ToLower(u.Scheme)] {
+		return fmt.Errorf("%w: links with scheme %q are not allowed", errorPkg.ErrInvalidMarkdown, u.Scheme)
+	}
+	return nil
+}
+
+// isControl reports control characters other than line breaks and tabs
+func isControl(r rune) bool {
+	return unicode.IsControl(r) && r != '\n' && r != '\r' && r != '\t'
+}
@@ -0,0 +1,129 @@
+package markdown_test
+
+import (
+	errorPkg "backend/internal/error"
+	"backend/internal/markdown"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{name: "Should accept CommonMark", content: "# Title\n\n*hello* [docs](https://example.com) `code`\n\n```go\nfmt.Println()\n```"},
+		{name: "Should accept relative and mail links", content: "[post](/api/post/1) <mail@example.com>"},
+		{name: "Should reject raw HTML block", content: "<div>hello</div>", wantErr: true},
+		{name: "Should reject inline HTML", content: "hello <b>world</b>", wantErr: true},
+		{name: "Should reject javascript links", content: "[click](javascript:alert(1))", wantErr: true},
+		{name: "Should reject escape sequences", content: "\x1b[2Jcleared", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := markdown.Validate(tt.content)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, errorPkg.ErrInvalidMarkdown)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "Should render CommonMark",
+			content: "# Title\n\n**bold** [docs](https://example.com)",
+			want:    "<h1>Title</h1>\n<p><strong>bold</strong> <a href=\"https://example.com\" rel=\"nofollow\">docs</a></p>\n",
+		},
+		{
+			name:    "Should keep code language",
+			content: "```go\nx := 1\n```",
+			want:    "<pre><code class=\"language-go\">x := 1\n</code></pre>\n",
+		},
+		{
+			name:    "Should drop raw HTML",
+			content: "hello <script>alert(1)</script>",
+			want:    "<p>hello alert(1)</p>\n",
+		},
+		{
+			name:    "Should drop unsafe links",
+			content: "[click](javascript:alert(1))",
+			want:    "<p>click</p>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, markdown.RenderHTML(tt.content))
+		})
+	}
+}
+
+func TestRenderANSI(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "Should style headings and inline markup",
+			content: "# Title\n\n*em* **bold** `code` [docs](https://example.com)",
+			want:    "\x1b[1;35m# Title\x1b[0m\n\n\x1b[3mem\x1b[23m \x1b[1mbold\x1b[22m \x1b[33mcode\x1b[39m \x1b[4mdocs\x1b[24m (https://example.com)",
+		},
+		{
+			name:    "Should indent code blocks and nested lists",
+			content: "```\nx := 1\n```\n\n- a\n  - b\n1. c",
+			want:    "  \x1b[33mx := 1\x1b[0m\n\n• a\n  • b\n\n1. c",
+		},
+		{
+			name:    "Should strip escape sequences in content",
+			content: "hello \x1b[2Jworld",
+			want:    "hello [2Jworld",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, markdown.RenderANSI(tt.content))
+		})
+	}
+}
+
+func TestRenderGemtext(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "Should list links after their block",
+			content: "#### Title\n\n*em* **bold** `code` [docs](https://example.com/a) <https://go.dev>",
+			want:    "### Title\n\nem bold code docs https://go.dev\n=> https://example.com/a docs\n=> https://go.dev",
+		},
+		{
+			name:    "Should flatten lists and keep code blocks preformatted",
+			content: "```\n# not a heading\n```\n\n- a\n  - b\n1. c",
+			want:    "```\n# not a heading\n```\n\n* a\n* b\n\n* 1. c",
+		},
+		{
+			name:    "Should escape lines that look like gemtext",
+			content: "see\n=> /admin\n\n> quote",
+			want:    "see\n => /admin\n\n> quote",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, markdown.RenderGemtext(tt.content))
+		})
+	}
+}
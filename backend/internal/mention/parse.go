@@ -0,0 +1,50 @@
+package mention
+
+import "unicode"
+
+// Span is a mention of a user inside some content. Start and End are offsets in characters (Unicode code points)
+// into the content, End is exclusive and the span includes the leading @.
+type Span struct {
+	UserID   string `json:"user_id,omitempty"`
+	Username string `json:"username"`
+	Start    int    `json:"start"`
+	End      int    `json:"end"`
+}
+
+// Parse finds @username mentions in content. A mention starts with @ at the beginning of the content or after a
+// character that cannot be part of a username, so e-mail addresses are not mistaken for mentions. Usernames consist
+// of letters, digits, underscores, hyphens and dots, trailing dots are treated as punctuation.
+func Parse(content string) []Span {
+	var spans []Span
+
+	runes := []rune(content)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '@' || (i > 0 && isNameRune(runes[i-1])) {
+			continue
+		}
+
+		end := i + 1
+		for end < len(runes) && isNameRune(runes[end]) {
+			end++
+		}
+		for end > i+1 && runes[end-1] == '.' {
+			end--
+		}
+		if end == i+1 {
+			continue
+		}
+
+		spans = append(spans, Span{
+			Username: string(runes[i+1 : end]),
+			Start:    i,
+			End:      end,
+		})
+		i = end - 1
+	}
+
+	return spans
+}
+
+func isNameRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.'
+}
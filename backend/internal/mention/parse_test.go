@@ -0,0 +1,55 @@
+package mention_test
+
+import (
+	"backend/internal/mention"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []mention.Span
+	}{
+		{
+			name:    "Should find mention at the start",
+			content: "@alice hello",
+			want:    []mention.Span{{Username: "alice", Start: 0, End: 6}},
+		},
+		{
+			name:    "Should find multiple mentions",
+			content: "thanks @bob and @carol_1!",
+			want: []mention.Span{
+				{Username: "bob", Start: 7, End: 11},
+				{Username: "carol_1", Start: 16, End: 24},
+			},
+		},
+		{
+			name:    "Should not treat trailing dot as part of the name",
+			content: "ask @dave.",
+			want:    []mention.Span{{Username: "dave", Start: 4, End: 9}},
+		},
+		{
+			name:    "Should ignore e-mail addresses",
+			content: "mail me at erin@example.com",
+			want:    nil,
+		},
+		{
+			name:    "Should ignore lone at sign",
+			content: "meet @ noon",
+			want:    nil,
+		},
+		{
+			name:    "Should count offsets in characters",
+			content: "你好 @小明",
+			want:    []mention.Span{{Username: "小明", Start: 3, End: 6}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, mention.Parse(tt.content))
+		})
+	}
+}
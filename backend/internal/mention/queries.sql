@@ -0,0 +1,20 @@
+-- name: Create :one
+INSERT INTO mentions (user_id, post_id, comment_id, start_offset, end_offset) VALUES ($1, $2, $3, $4, $5) RETURNING *;
+
+-- name: DeleteByPost :exec
+DELETE FROM mentions WHERE post_id = $1 AND comment_id IS NULL;
+
+-- name: DeleteByComment :exec
+DELETE FROM mentions WHERE comment_id = $1;
+
+-- name: FindByPosts :many
+SELECT m.id, m.user_id, m.post_id, m.comment_id, m.start_offset, m.end_offset, u.name AS username
+FROM mentions m JOIN users u ON u.id = m.user_id
+WHERE m.post_id = ANY(@post_ids::uuid[]) AND m.comment_id IS NULL
+ORDER BY m.start_offset;
+
+-- name: FindByComments :many
+SELECT m.id, m.user_id, m.post_id, m.comment_id, m.start_offset, m.end_offset, u.name AS username
+FROM mentions m JOIN users u ON u.id = m.user_id
+WHERE m.comment_id = ANY(@comment_ids::uuid[])
+ORDER BY m.start_offset;
\ No newline at end of file
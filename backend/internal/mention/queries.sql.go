@@ -0,0 +1,154 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: queries.sql
+
+package mention
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const create = `-- name: Create :one
+INSERT INTO mentions (user_id, post_id, comment_id, start_offset, end_offset) VALUES ($1, $2, $3, $4, $5) RETURNING id, user_id, post_id, comment_id, start_offset, end_offset, created_at
+`
+
+type CreateParams struct {
+	UserID      uuid.UUID
+	PostID      uuid.UUID
+	CommentID   pgtype.UUID
+	StartOffset int32
+	EndOffset   int32
+}
+
+func (q *Queries) Create(ctx context.Context, arg CreateParams) (Mention, error) {
+	row := q.db.QueryRow(ctx, create,
+		arg.UserID,
+		arg.PostID,
+		arg.CommentID,
+		arg.StartOffset,
+		arg.EndOffset,
+	)
+	var i Mention
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.PostID,
+		&i.CommentID,
+		&i.StartOffset,
+		&i.EndOffset,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteByComment = `-- name: DeleteByComment :exec
+DELETE FROM mentions WHERE comment_id = $1
+`
+
+func (q *Queries) DeleteByComment(ctx context.Context, commentID pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deleteByComment, commentID)
+	return err
+}
+
+const deleteByPost = `-- name: DeleteByPost :exec
+DELETE FROM mentions WHERE post_id = $1 AND comment_id IS NULL
+`
+
+func (q *Queries) DeleteByPost(ctx context.Context, postID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteByPost, postID)
+	return err
+}
+
+const findByComments = `-- name: FindByComments :many
+SELECT m.id, m.user_id, m.post_id, m.comment_id, m.start_offset, m.end_offset, u.name AS username
+FROM mentions m JOIN users u ON u.id = m.user_id
+WHERE m.comment_id = ANY($1::uuid[])
+ORDER BY m.start_offset
+`
+
+type FindByCommentsRow struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	PostID      uuid.UUID
+	CommentID   pgtype.UUID
+	StartOffset int32
+	EndOffset   int32
+	Username    string
+}
+
+func (q *Queries) FindByComments(ctx context.Context, commentIds []uuid.UUID) ([]FindByCommentsRow, error) {
+	rows, err := q.db.Query(ctx, findByComments, commentIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FindByCommentsRow
+	for rows.Next() {
+		var i FindByCommentsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.PostID,
+			&i.CommentID,
+			&i.StartOffset,
+			&i.EndOffset,
+			&i.Username,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findByPosts = `-- name: FindByPosts :many
+SELECT m.id, m.user_id, m.post_id, m.comment_id, m.start_offset, m.end_offset, u.name AS username
+FROM mentions m JOIN users u ON u.id = m.user_id
+WHERE m.post_id = ANY($1::uuid[]) AND m.comment_id IS NULL
+ORDER BY m.start_offset
+`
+
+type FindByPostsRow struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	PostID      uuid.UUID
+	CommentID   pgtype.UUID
+	StartOffset int32
+	EndOffset   int32
+	Username    string
+}
+
+func (q *Queries) FindByPosts(ctx context.Context, postIds []uuid.UUID) ([]FindByPostsRow, error) {
+	rows, err := q.db.Query(ctx, findByPosts, postIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FindByPostsRow
+	for rows.Next() {
+		var i FindByPostsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.PostID,
+			&i.CommentID,
+			&i.StartOffset,
+			&i.EndOffset,
+			&i.Username,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
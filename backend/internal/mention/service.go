@@ -0,0 +1,207 @@
+package mention
+
+import (
+	"backend/internal"
+	"backend/internal/database"
+	errorPkg "backend/internal/error"
+	"backend/internal/notification"
+	"backend/internal/user"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// Target identifies the content a mention appears in, a zero CommentID refers to the post itself
+type Target struct {
+	PostID    uuid.UUID
+	CommentID uuid.UUID
+}
+
+type UserResolver interface {
+	GetByName(ctx context.Context, name string) (user.User, error)
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, recipientID, actorID uuid.UUID, notificationType string, postID, commentID uuid.UUID) error
+}
+
+// MuteChecker tells whether a user muted a thread, see watch.Service
+type MuteChecker interface {
+	IsMuted(ctx context.Context, userID, postID uuid.UUID) (bool, error)
+}
+
+type Service struct {
+	logger *zap.Logger
+	tracer trace.Tracer
+	query  *Queries
+
+	users    UserResolver
+	notifier Notifier
+	mutes    MuteChecker
+}
+
+func NewService(logger *zap.Logger, db DBTX, users UserResolver, notifier Notifier, mutes MuteChecker) *Service {
+	return &Service{
+		logger:   logger,
+		tracer:   otel.Tracer("mention/service"),
+		query:    New(db),
+		users:    users,
+		notifier: notifier,
+		mutes:    mutes,
+	}
+}
+
+// Sync parses the mentions in content, replaces the stored mentions of target with them and notifies users that were
+// not mentioned in the previous version of the content, unless they muted the thread. Mentions of unknown users are
+// ignored.
+func (s *Service) Sync(ctx context.Context, target Target, authorID uuid.UUID, content string) ([]Span, error) {
+	traceCtx, span := s.tracer.Start(ctx, "Sync")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	previous, err := s.get(traceCtx, target)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	alreadyMentioned := make(map[string]bool, len(previous))
+	for _, p := range previous {
+		alreadyMentioned[p.UserID] = true
+	}
+
+	if target.CommentID == uuid.Nil {
+		err = s.query.DeleteByPost(traceCtx, target.PostID)
+	} else {
+		err = s.query.DeleteByComment(traceCtx, pgtype.UUID{Bytes: target.CommentID, Valid: true})
+	}
+	if err != nil {
+		err = database.WrapDBError(err, logger, "delete mentions")
+		span.RecordError(err)
+		return nil, err
+	}
+
+	resolved := make(map[string]user.User)
+	var spans []Span
+	for _, parsed := range Parse(content) {
+		u, ok := resolved[parsed.Username]
+		if !ok {
+			u, err = s.users.GetByName(traceCtx, parsed.Username)
+			if errors.Is(err, errorPkg.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				span.RecordError(err)
+				return nil, err
+			}
+			resolved[parsed.Username] = u
+		}
+
+		_, err = s.query.Create(traceCtx, CreateParams{
+			UserID:      u.ID,
+			PostID:      target.PostID,
+			CommentID:   pgtype.UUID{Bytes: target.CommentID, Valid: target.CommentID != uuid.Nil},
+			StartOffset: int32(parsed.Start),
+			EndOffset:   int32(parsed.End),
+		})
+		if err != nil {
+			err = database.WrapDBError(err, logger, "create mention")
+			span.RecordError(err)
+			return nil, err
+		}
+
+		parsed.UserID = u.ID.String()
+		parsed.Username = u.Name
+		spans = append(spans, parsed)
+	}
+
+	notified := make(map[uuid.UUID]bool)
+	for _, u := range resolved {
+		if alreadyMentioned[u.ID.String()] || notified[u.ID] {
+			continue
+		}
+		notified[u.ID] = true
+
+		muted, err := s.mutes.IsMuted(traceCtx, u.ID, target.PostID)
+		if err != nil {
+			logger.Warn("Failed to check whether mentioned user muted the thread", zap.String("user_id", u.ID.String()), zap.Error(err))
+		}
+		if muted {
+			continue
+		}
+
+		err = s.notifier.Notify(traceCtx, u.ID, authorID, notification.TypeMention, target.PostID, target.CommentID)
+		if err != nil {
+			logger.Warn("Failed to notify mentioned user", zap.String("user_id", u.ID.String()), zap.Error(err))
+		}
+	}
+
+	return spans, nil
+}
+
+// GetByPosts returns the mentions in the content of the given posts, keyed by post ID
+func (s *Service) GetByPosts(ctx context.Context, postIDs []uuid.UUID) (map[uuid.UUID][]Span, error) {
+	traceCtx, span := s.tracer.Start(ctx, "GetByPosts")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	rows, err := s.query.FindByPosts(traceCtx, postIDs)
+	if err != nil {
+		err = database.WrapDBError(err, logger, "get mentions by posts")
+		span.RecordError(err)
+		return nil, err
+	}
+
+	result := make(map[uuid.UUID][]Span)
+	for _, row := range rows {
+		result[row.PostID] = append(result[row.PostID], newSpan(row.UserID, row.Username, row.StartOffset, row.EndOffset))
+	}
+
+	return result, nil
+}
+
+// GetByComments returns the mentions in the content of the given comments, keyed by comment ID
+func (s *Service) GetByComments(ctx context.Context, commentIDs []uuid.UUID) (map[uuid.UUID][]Span, error) {
+	traceCtx, span := s.tracer.Start(ctx, "GetByComments")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	rows, err := s.query.FindByComments(traceCtx, commentIDs)
+	if err != nil {
+		err = database.WrapDBError(err, logger, "get mentions by comments")
+		span.RecordError(err)
+		return nil, err
+	}
+
+	result := make(map[uuid.UUID][]Span)
+	for _, row := range rows {
+		commentID := uuid.UUID(row.CommentID.Bytes)
+		result[commentID] = append(result[commentID], newSpan(row.UserID, row.Username, row.StartOffset, row.EndOffset))
+	}
+
+	return result, nil
+}
+
+func (s *Service) get(ctx context.Context, target Target) ([]Span, error) {
+	var result map[uuid.UUID][]Span
+	var err error
+	if target.CommentID == uuid.Nil {
+		result, err = s.GetByPosts(ctx, []uuid.UUID{target.PostID})
+		return result[target.PostID], err
+	}
+
+	result, err = s.GetByComments(ctx, []uuid.UUID{target.CommentID})
+	return result[target.CommentID], err
+}
+
+func newSpan(userID uuid.UUID, username string, start, end int32) Span {
+	return Span{
+		UserID:   userID.String(),
+		Username: username,
+		Start:    int(start),
+		End:      int(end),
+	}
+}
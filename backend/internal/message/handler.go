@@ -0,0 +1,223 @@
+package message
+
+import (
+	"backend/internal"
+	errorPkg "backend/internal/error"
+	"backend/internal/jwt"
+	"backend/internal/problem"
+	"context"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+type StartRequest struct {
+	UserIDs []uuid.UUID `json:"user_ids" validate:"required,min=1,max=50"`
+	Content string      `json:"content"  validate:"required"`
+}
+
+type SendRequest struct {
+	Content string `json:"content" validate:"required"`
+}
+
+type ConversationResponse struct {
+	ID        string   `json:"id"`
+	CreatedBy string   `json:"created_by"`
+	MemberIDs []string `json:"member_ids"`
+	CreatedAt string   `json:"created_at"`
+	UpdatedAt string   `json:"updated_at"`
+}
+
+type MessageResponse struct {
+	ID             string `json:"id"`
+	ConversationID string `json:"conversation_id"`
+	AuthorID       string `json:"author_id"`
+	Content        string `json:"content"`
+	CreatedAt      string `json:"created_at"`
+}
+
+//go:generate mockery --name=Store
+type Store interface {
+	StartConversation(ctx context.Context, creatorID uuid.UUID, request StartRequest) (ConversationWithMembers, error)
+	GetConversations(ctx context.Context, userID uuid.UUID, pagination internal.Pagination) ([]ConversationWithMembers, error)
+	Send(ctx context.Context, authorID, conversationID uuid.UUID, request SendRequest) (Message, error)
+	GetMessages(ctx context.Context, userID, conversationID uuid.UUID, pagination internal.Pagination) ([]Message, error)
+}
+
+type Handler struct {
+	validator *validator.Validate
+	logger    *zap.Logger
+	tracer    trace.Tracer
+	store     Store
+}
+
+func NewHandler(v *validator.Validate, logger *zap.Logger, store Store) *Handler {
+	return &Handler{
+		validator: v,
+		logger:    logger,
+		tracer:    otel.Tracer("message/handler"),
+		store:     store,
+	}
+}
+
+// StartHandler starts a conversation between the current user and the given users with a first message
+func (h *Handler) StartHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "StartConversationEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	userID, err := jwt.GetUserIDFromContext(traceCtx)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	var request StartRequest
+	err = internal.ParseAndValidateRequestBody(traceCtx, h.validator, r, &request)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	conversation, err := h.store.StartConversation(traceCtx, userID, request)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	logger.Info("Started conversation", zap.String("id", conversation.ID.String()))
+	internal.WriteJSONResponse(w, http.StatusOK, GenerateConversationResponse(conversation))
+}
+
+// GetConversationsHandler lists the conversations of the current user, most recently active first
+func (h *Handler) GetConversationsHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "GetConversationsEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	userID, err := jwt.GetUserIDFromContext(traceCtx)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	pagination, err := internal.ParsePagination(r)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	conversations, err := h.store.GetConversations(traceCtx, userID, pagination)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	response := make([]ConversationResponse, len(conversations))
+	for i, c := range conversations {
+		response[i] = GenerateConversationResponse(c)
+	}
+
+	internal.WriteJSONResponse(w, http.StatusOK, response)
+}
+
+func (h *Handler) SendHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "SendMessageEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	userID, err := jwt.GetUserIDFromContext(traceCtx)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	conversationID, err := internal.ParseUUID(r.PathValue("id"))
+	if err != nil {
+		problem.WriteError(traceCtx, w, fmt.Errorf("%w: %v", errorPkg.ErrInvalidUUID, err), logger)
+		return
+	}
+
+	var request SendRequest
+	err = internal.ParseAndValidateRequestBody(traceCtx, h.validator, r, &request)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	message, err := h.store.Send(traceCtx, userID, conversationID, request)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	internal.WriteJSONResponse(w, http.StatusOK, GenerateMessageResponse(message))
+}
+
+// GetMessagesHandler lists the messages of a conversation of the current user, newest first
+func (h *Handler) GetMessagesHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "GetMessagesEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	userID, err := jwt.GetUserIDFromContext(traceCtx)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	conversationID, err := internal.ParseUUID(r.PathValue("id"))
+	if err != nil {
+		problem.WriteError(traceCtx, w, fmt.Errorf("%w: %v", errorPkg.ErrInvalidUUID, err), logger)
+		return
+	}
+
+	pagination, err := internal.ParsePagination(r)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	messages, err := h.store.GetMessages(traceCtx, userID, conversationID, pagination)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	response := make([]MessageResponse, len(messages))
+	for i, m := range messages {
+		response[i] = GenerateMessageResponse(m)
+	}
+
+	internal.WriteJSONResponse(w, http.StatusOK, response)
+}
+
+func GenerateConversationResponse(c ConversationWithMembers) ConversationResponse {
+	members := make([]string, len(c.MemberIds))
+	for i, id := range c.MemberIds {
+		members[i] = id.String()
+	}
+
+	return ConversationResponse{
+		ID:        c.ID.String(),
+		CreatedBy: c.CreatedBy.String(),
+		MemberIDs: members,
+		CreatedAt: c.CreatedAt.Time.Format(time.RFC3339),
+		UpdatedAt: c.UpdatedAt.Time.Format(time.RFC3339),
+	}
+}
+
+func GenerateMessageResponse(m Message) MessageResponse {
+	return MessageResponse{
+		ID:             m.ID.String(),
+		ConversationID: m.ConversationID.String(),
+		AuthorID:       m.AuthorID.String(),
+		Content:        m.Content,
+		CreatedAt:      m.CreatedAt.Time.Format(time.RFC3339),
+	}
+}
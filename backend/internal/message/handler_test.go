@@ -0,0 +1,99 @@
+package message_test
+
+import (
+	"backend/internal"
+	errorPkg "backend/internal/error"
+	"backend/internal/jwt"
+	"backend/internal/message"
+	"backend/internal/message/mocks"
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandler_StartHandler(t *testing.T) {
+	user := jwt.User{
+		ID:       "81c1ecc1-66d7-4134-b5fe-d886a6f418a4",
+		Username: "test",
+		Role:     "USER",
+	}
+	recipient := uuid.MustParse("7942c917-4770-43c1-a56a-952186b9970e")
+	request := message.StartRequest{UserIDs: []uuid.UUID{recipient}, Content: "Hello"}
+
+	tests := []struct {
+		name       string
+		body       string
+		setupMock  func(m *mocks.Store)
+		wantStatus int
+		wantResult message.ConversationResponse
+	}{
+		{
+			name: "Should start conversation",
+			body: `{"user_ids":["7942c917-4770-43c1-a56a-952186b9970e"],"content":"Hello"}`,
+			setupMock: func(m *mocks.Store) {
+				m.On("StartConversation", mock.Anything, uuid.MustParse(user.ID), request).Return(message.ConversationWithMembers{
+					ID:        uuid.MustParse("54a46af2-b454-4746-8ab0-3cf26085a50b"),
+					CreatedBy: uuid.MustParse(user.ID),
+					CreatedAt: pgtype.Timestamptz{Time: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)},
+					UpdatedAt: pgtype.Timestamptz{Time: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)},
+					MemberIds: []uuid.UUID{uuid.MustParse(user.ID), recipient},
+				}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantResult: message.ConversationResponse{
+				ID:        "54a46af2-b454-4746-8ab0-3cf26085a50b",
+				CreatedBy: user.ID,
+				MemberIDs: []string{user.ID, recipient.String()},
+				CreatedAt: "2000-01-01T00:00:00Z",
+				UpdatedAt: "2000-01-01T00:00:00Z",
+			},
+		},
+		{
+			name:       "Should reject conversation without users",
+			body:       `{"user_ids":[],"content":"Hello"}`,
+			setupMock:  func(m *mocks.Store) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Should refuse when blocked by recipient",
+			body: `{"user_ids":["7942c917-4770-43c1-a56a-952186b9970e"],"content":"Hello"}`,
+			setupMock: func(m *mocks.Store) {
+				m.On("StartConversation", mock.Anything, uuid.MustParse(user.ID), request).
+					Return(message.ConversationWithMembers{}, errorPkg.ErrBlocked)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mocks.NewStore(t)
+			tt.setupMock(m)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/api/conversations", strings.NewReader(tt.body))
+			r = r.WithContext(context.WithValue(r.Context(), internal.UserContextKey, user))
+
+			h := message.NewHandler(internal.NewValidator(), zap.NewNop(), m)
+			h.StartHandler(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusOK {
+				jsonWant, err := json.Marshal(tt.wantResult)
+				if err != nil {
+					t.Fatalf("failed to marshal expected response: %v", err)
+				}
+				assert.Equal(t, string(jsonWant), w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	internal "backend/internal"
+	context "context"
+
+	message "backend/internal/message"
+
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// Store is an autogenerated mock type for the Store type
+type Store struct {
+	mock.Mock
+}
+
+// GetConversations provides a mock function with given fields: ctx, userID, pagination
+func (_m *Store) GetConversations(ctx context.Context, userID uuid.UUID, pagination internal.Pagination) ([]message.FindConversationsByUserRow, error) {
+	ret := _m.Called(ctx, userID, pagination)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetConversations")
+	}
+
+	var r0 []message.FindConversationsByUserRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, internal.Pagination) ([]message.FindConversationsByUserRow, error)); ok {
+		return rf(ctx, userID, pagination)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, internal.Pagination) []message.FindConversationsByUserRow); ok {
+		r0 = rf(ctx, userID, pagination)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]message.FindConversationsByUserRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, internal.Pagination) error); ok {
+		r1 = rf(ctx, userID, pagination)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMessages provides a mock function with given fields: ctx, userID, conversationID, pagination
+func (_m *Store) GetMessages(ctx context.Context, userID uuid.UUID, conversationID uuid.UUID, pagination internal.Pagination) ([]message.Message, error) {
+	ret := _m.Called(ctx, userID, conversationID, pagination)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMessages")
+	}
+
+	var r0 []message.Message
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, internal.Pagination) ([]message.Message, error)); ok {
+		return rf(ctx, userID, conversationID, pagination)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, internal.Pagination) []message.Message); ok {
+		r0 = rf(ctx, userID, conversationID, pagination)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]message.Message)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID, internal.Pagination) error); ok {
+		r1 = rf(ctx, userID, conversationID, pagination)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Send provides a mock function with given fields: ctx, authorID, conversationID, request
+func (_m *Store) Send(ctx context.Context, authorID uuid.UUID, conversationID uuid.UUID, request message.SendRequest) (message.Message, error) {
+	ret := _m.Called(ctx, authorID, conversationID, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Send")
+	}
+
+	var r0 message.Message
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, message.SendRequest) (message.Message, error)); ok {
+		return rf(ctx, authorID, conversationID, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, message.SendRequest) message.Message); ok {
+		r0 = rf(ctx, authorID, conversationID, request)
+	} else {
+		r0 = ret.Get(0).(message.Message)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID, message.SendRequest) error); ok {
+		r1 = rf(ctx, authorID, conversationID, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StartConversation provides a mock function with given fields: ctx, creatorID, request
+func (_m *Store) StartConversation(ctx context.Context, creatorID uuid.UUID, request message.StartRequest) (message.FindConversationsByUserRow, error) {
+	ret := _m.Called(ctx, creatorID, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StartConversation")
+	}
+
+	var r0 message.FindConversationsByUserRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, message.StartRequest) (message.FindConversationsByUserRow, error)); ok {
+		return rf(ctx, creatorID, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, message.StartRequest) message.FindConversationsByUserRow); ok {
+		r0 = rf(ctx, creatorID, request)
+	} else {
+		r0 = ret.Get(0).(message.FindConversationsByUserRow)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, message.StartRequest) error); ok {
+		r1 = rf(ctx, creatorID, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewStore creates a new instance of Store. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Store {
+	mock := &Store{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,31 @@
+-- name: CreateConversation :one
+INSERT INTO conversations (created_by) VALUES ($1) RETURNING *;
+
+-- name: AddMember :exec
+INSERT INTO conversation_members (conversation_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING;
+
+-- name: FindConversationsByUser :many
+SELECT c.*,
+       (SELECT array_agg(cm.user_id ORDER BY cm.joined_at, cm.user_id)
+        FROM conversation_members cm
+        WHERE cm.conversation_id = c.id)::uuid[] AS member_ids
+FROM conversations c
+JOIN conversation_members m ON m.conversation_id = c.id
+WHERE m.user_id = @user_id
+ORDER BY c.updated_at DESC
+LIMIT @size OFFSET @skip;
+
+-- name: FindMemberIDs :many
+SELECT user_id FROM conversation_members WHERE conversation_id = $1 ORDER BY joined_at, user_id;
+
+-- name: TouchConversation :exec
+UPDATE conversations SET updated_at = now() WHERE id = $1;
+
+-- name: CreateMessage :one
+INSERT INTO messages (conversation_id, author_id, content) VALUES ($1, $2, $3) RETURNING *;
+
+-- name: FindMessages :many
+SELECT * FROM messages
+WHERE conversation_id = @conversation_id
+ORDER BY created_at DESC
+LIMIT @size OFFSET @skip;
@@ -0,0 +1,190 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: queries.sql
+
+package message
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const addMember = `-- name: AddMember :exec
+INSERT INTO conversation_members (conversation_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING
+`
+
+type AddMemberParams struct {
+	ConversationID uuid.UUID
+	UserID         uuid.UUID
+}
+
+func (q *Queries) AddMember(ctx context.Context, arg AddMemberParams) error {
+	_, err := q.db.Exec(ctx, addMember, arg.ConversationID, arg.UserID)
+	return err
+}
+
+const createConversation = `-- name: CreateConversation :one
+INSERT INTO conversations (created_by) VALUES ($1) RETURNING id, created_by, created_at, updated_at
+`
+
+func (q *Queries) CreateConversation(ctx context.Context, createdBy uuid.UUID) (Conversation, error) {
+	row := q.db.QueryRow(ctx, createConversation, createdBy)
+	var i Conversation
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createMessage = `-- name: CreateMessage :one
+INSERT INTO messages (conversation_id, author_id, content) VALUES ($1, $2, $3) RETURNING id, conversation_id, author_id, content, created_at
+`
+
+type CreateMessageParams struct {
+	ConversationID uuid.UUID
+	AuthorID       uuid.UUID
+	Content        string
+}
+
+func (q *Queries) CreateMessage(ctx context.Context, arg CreateMessageParams) (Message, error) {
+	row := q.db.QueryRow(ctx, createMessage, arg.ConversationID, arg.AuthorID, arg.Content)
+	var i Message
+	err := row.Scan(
+		&i.ID,
+		&i.ConversationID,
+		&i.AuthorID,
+		&i.Content,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const findConversationsByUser = `-- name: FindConversationsByUser :many
+SELECT c.id, c.created_by, c.created_at, c.updated_at,
+       (SELECT array_agg(cm.user_id ORDER BY cm.joined_at, cm.user_id)
+        FROM conversation_members cm
+        WHERE cm.conversation_id = c.id)::uuid[] AS member_ids
+FROM conversations c
+JOIN conversation_members m ON m.conversation_id = c.id
+WHERE m.user_id = $1
+ORDER BY c.updated_at DESC
+LIMIT $3 OFFSET $2
+`
+
+type FindConversationsByUserParams struct {
+	UserID uuid.UUID
+	Skip   int32
+	Size   int32
+}
+
+type FindConversationsByUserRow struct {
+	ID        uuid.UUID
+	CreatedBy uuid.UUID
+	CreatedAt pgtype.Timestamptz
+	UpdatedAt pgtype.Timestamptz
+	MemberIds []uuid.UUID
+}
+
+func (q *Queries) FindConversationsByUser(ctx context.Context, arg FindConversationsByUserParams) ([]FindConversationsByUserRow, error) {
+	rows, err := q.db.Query(ctx, findConversationsByUser, arg.UserID, arg.Skip, arg.Size)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FindConversationsByUserRow
+	for rows.Next() {
+		var i FindConversationsByUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.MemberIds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findMemberIDs = `-- name: FindMemberIDs :many
+SELECT user_id FROM conversation_members WHERE conversation_id = $1 ORDER BY joined_at, user_id
+`
+
+func (q *Queries) FindMemberIDs(ctx context.Context, conversationID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, findMemberIDs, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var user_id uuid.UUID
+		if err := rows.Scan(&user_id); err != nil {
+			return nil, err
+		}
+		items = append(items, user_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findMessages = `-- name: FindMessages :many
+SELECT id, conversation_id, author_id, content, created_at FROM messages
+WHERE conversation_id = $1
+ORDER BY created_at DESC
+LIMIT $3 OFFSET $2
+`
+
+type FindMessagesParams struct {
+	ConversationID uuid.UUID
+	Skip           int32
+	Size           int32
+}
+
+func (q *Queries) FindMessages(ctx context.Context, arg FindMessagesParams) ([]Message, error) {
+	rows, err := q.db.Query(ctx, findMessages, arg.ConversationID, arg.Skip, arg.Size)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Message
+	for rows.Next() {
+		var i Message
+		if err := rows.Scan(
+			&i.ID,
+			&i.ConversationID,
+			&i.AuthorID,
+			&i.Content,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const touchConversation = `-- name: TouchConversation :exec
+UPDATE conversations SET updated_at = now() WHERE id = $1
+`
+
+func (q *Queries) TouchConversation(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, touchConversation, id)
+	return err
+}
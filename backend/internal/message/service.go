@@ -0,0 +1,230 @@
+package message
+
+import (
+	"backend/internal"
+	"backend/internal/database"
+	errorPkg "backend/internal/error"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"slices"
+)
+
+// Blocker knows who refuses contact with whom, see block.Service
+type Blocker interface {
+	IsBlockedByAny(ctx context.Context, userID uuid.UUID, blockerIDs []uuid.UUID) (bool, error)
+}
+
+// ConversationWithMembers is a conversation together with the IDs of its members
+type ConversationWithMembers = FindConversationsByUserRow
+
+type Service struct {
+	logger  *zap.Logger
+	tracer  trace.Tracer
+	pool    *pgxpool.Pool
+	query   *Queries
+	blocker Blocker
+}
+
+func NewService(logger *zap.Logger, pool *pgxpool.Pool, blocker Blocker) *Service {
+	return &Service{
+		logger:  logger,
+		tracer:  otel.Tracer("message/service"),
+		pool:    pool,
+		query:   New(pool),
+		blocker: blocker,
+	}
+}
+
+// StartConversation creates a conversation between the creator and the given users and posts the first message.
+// It fails with errorPkg.ErrBlocked if any of the users blocked the creator.
+func (s *Service) StartConversation(ctx context.Context, creatorID uuid.UUID, r StartRequest) (ConversationWithMembers, error) {
+	traceCtx, span := s.tracer.Start(ctx, "StartConversation")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	var recipients []uuid.UUID
+	for _, id := range r.UserIDs {
+		if id != creatorID && !slices.Contains(recipients, id) {
+			recipients = append(recipients, id)
+		}
+	}
+	if len(recipients) == 0 {
+		err := fmt.Errorf("%w: a conversation needs at least one other user", errorPkg.ErrInvalidRequest)
+		span.RecordError(err)
+		return ConversationWithMembers{}, err
+	}
+
+	err := s.checkBlocked(traceCtx, creatorID, recipients)
+	if err != nil {
+		span.RecordError(err)
+		return ConversationWithMembers{}, err
+	}
+
+	tx, err := s.pool.Begin(traceCtx)
+	if err != nil {
+		err = database.WrapDBError(err, logger, "begin conversation transaction")
+		span.RecordError(err)
+		return ConversationWithMembers{}, err
+	}
+	defer func() {
+		_ = tx.Rollback(context.WithoutCancel(traceCtx))
+	}()
+	query := s.query.WithTx(tx)
+
+	conversation, err := query.CreateConversation(traceCtx, creatorID)
+	if err != nil {
+		err = database.WrapDBError(err, logger, "create conversation")
+		span.RecordError(err)
+		return ConversationWithMembers{}, err
+	}
+
+	members := append([]uuid.UUID{creatorID}, recipients...)
+	for _, memberID := range members {
+		err = query.AddMember(traceCtx, AddMemberParams{ConversationID: conversation.ID, UserID: memberID})
+		if err != nil {
+			err = database.WrapDBError(err, logger, "add conversation member")
+			if errors.Is(err, database.ErrForeignKeyViolation) {
+				err = errorPkg.NewNotFoundError("users", "id", memberID.String(), "")
+			}
+			span.RecordError(err)
+			return ConversationWithMembers{}, err
+		}
+	}
+
+	_, err = query.CreateMessage(traceCtx, CreateMessageParams{ConversationID: conversation.ID, AuthorID: creatorID, Content: r.Content})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "create first message")
+		span.RecordError(err)
+		return ConversationWithMembers{}, err
+	}
+
+	err = tx.Commit(traceCtx)
+	if err != nil {
+		err = database.WrapDBError(err, logger, "commit conversation")
+		span.RecordError(err)
+		return ConversationWithMembers{}, err
+	}
+
+	return ConversationWithMembers{
+		ID:        conversation.ID,
+		CreatedBy: conversation.CreatedBy,
+		CreatedAt: conversation.CreatedAt,
+		UpdatedAt: conversation.UpdatedAt,
+		MemberIds: members,
+	}, nil
+}
+
+// GetConversations lists the conversations of the user, most recently active first
+func (s *Service) GetConversations(ctx context.Context, userID uuid.UUID, pagination internal.Pagination) ([]ConversationWithMembers, error) {
+	traceCtx, span := s.tracer.Start(ctx, "GetConversations")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	conversations, err := s.query.FindConversationsByUser(traceCtx, FindConversationsByUserParams{
+		UserID: userID,
+		Skip:   pagination.Offset(),
+		Size:   pagination.Size,
+	})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "get conversations by user")
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return conversations, nil
+}
+
+// Send posts a message to a conversation of the author. It fails with errorPkg.ErrBlocked if any other member blocked
+// the author.
+func (s *Service) Send(ctx context.Context, authorID, conversationID uuid.UUID, r SendRequest) (Message, error) {
+	traceCtx, span := s.tracer.Start(ctx, "Send")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	members, err := s.requireMember(traceCtx, authorID, conversationID)
+	if err != nil {
+		span.RecordError(err)
+		return Message{}, err
+	}
+
+	others := slices.DeleteFunc(members, func(id uuid.UUID) bool { return id == authorID })
+	err = s.checkBlocked(traceCtx, authorID, others)
+	if err != nil {
+		span.RecordError(err)
+		return Message{}, err
+	}
+
+	message, err := s.query.CreateMessage(traceCtx, CreateMessageParams{ConversationID: conversationID, AuthorID: authorID, Content: r.Content})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "create message")
+		span.RecordError(err)
+		return Message{}, err
+	}
+
+	err = s.query.TouchConversation(traceCtx, conversationID)
+	if err != nil {
+		logger.Warn("Failed to update conversation activity", zap.String("conversation_id", conversationID.String()), zap.Error(err))
+	}
+
+	return message, nil
+}
+
+// GetMessages lists the messages of a conversation of the user, newest first
+func (s *Service) GetMessages(ctx context.Context, userID, conversationID uuid.UUID, pagination internal.Pagination) ([]Message, error) {
+	traceCtx, span := s.tracer.Start(ctx, "GetMessages")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	_, err := s.requireMember(traceCtx, userID, conversationID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	messages, err := s.query.FindMessages(traceCtx, FindMessagesParams{
+		ConversationID: conversationID,
+		Skip:           pagination.Offset(),
+		Size:           pagination.Size,
+	})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "get messages")
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// requireMember returns the members of the conversation. Conversations the user is not part of are reported as not
+// found so their existence is not disclosed.
+func (s *Service) requireMember(ctx context.Context, userID, conversationID uuid.UUID) ([]uuid.UUID, error) {
+	logger := internal.LoggerWithContext(ctx, s.logger)
+
+	members, err := s.query.FindMemberIDs(ctx, conversationID)
+	if err != nil {
+		return nil, database.WrapDBError(err, logger, "get conversation members")
+	}
+	if !slices.Contains(members, userID) {
+		return nil, errorPkg.NewNotFoundError("conversations", "id", conversationID.String(), "")
+	}
+
+	return members, nil
+}
+
+func (s *Service) checkBlocked(ctx context.Context, senderID uuid.UUID, recipients []uuid.UUID) error {
+	blocked, err := s.blocker.IsBlockedByAny(ctx, senderID, recipients)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return errorPkg.ErrBlocked
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package message_test
+
+import (
+	errorPkg "backend/internal/error"
+	"backend/internal/message"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"testing"
+)
+
+func TestService_StartConversation(t *testing.T) {
+	creatorID := uuid.MustParse("81c1ecc1-66d7-4134-b5fe-d886a6f418a4")
+
+	tests := []struct {
+		name    string
+		userIDs []uuid.UUID
+	}{
+		{
+			name:    "Should reject conversation without users",
+			userIDs: nil,
+		},
+		{
+			name:    "Should reject conversation with only the creator",
+			userIDs: []uuid.UUID{creatorID, creatorID},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the request is rejected before the database is used
+			s := message.NewService(zap.NewNop(), nil, nil)
+
+			_, err := s.StartConversation(context.Background(), creatorID, message.StartRequest{UserIDs: tt.userIDs, Content: "Hi"})
+			if !errors.Is(err, errorPkg.ErrInvalidRequest) {
+				t.Fatalf("StartConversation() error = %v, want %v", err, errorPkg.ErrInvalidRequest)
+			}
+		})
+	}
+}
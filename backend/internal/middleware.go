@@ -40,6 +40,7 @@ func TraceMiddleware(next http.HandlerFunc, logger *zap.Logger) http.HandlerFunc
 			logger.Debug("No upstream trace available, creating a new one", zap.String("trace_id", span.SpanContext().TraceID().String()))
 		}
 
+		ctx = context.WithValue(ctx, ClientIPContextKey, ClientIP(r))
 		next(w, r.WithContext(ctx))
 	}
 }
@@ -0,0 +1,107 @@
+package nntp
+
+import (
+	errorPkg "backend/internal/error"
+	"bufio"
+	"fmt"
+	"io"
+	"mime"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxArticleSize limits the articles newsreaders can post, larger than any post the web API accepts
+const MaxArticleSize = 1 << 20
+
+// WriteHead writes the header lines of an article, without the empty line separating them from the body
+func WriteHead(w io.Writer, article Article) error {
+	headers := [][2]string{
+		{"From", article.From},
+		{"Newsgroups", article.Newsgroup},
+		{"Subject", mime.QEncoding.Encode("utf-8", oneLine(article.Subject))},
+		{"Date", article.Date.UTC().Format(time.RFC1123Z)},
+		{"Message-ID", article.MessageID},
+	}
+	if len(article.References) > 0 {
+		headers = append(headers, [2]string{"References", strings.Join(article.References, " ")})
+	}
+	// The content is markdown which reads well as plain text
+	headers = append(headers,
+		[2]string{"Content-Type", "text/plain; charset=utf-8"},
+		[2]string{"Content-Transfer-Encoding", "8bit"},
+	)
+
+	for _, header := range headers {
+		_, err := fmt.Fprintf(w, "%s: %s\n", header[0], header[1])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteBody writes the body of an article with normalized line endings
+func WriteBody(w io.Writer, article Article) error {
+	body := strings.ReplaceAll(article.Body, "\r\n", "\n")
+	if !strings.HasSuffix(body, "\n") {
+		body += "\n"
+	}
+	_, err := io.WriteString(w, body)
+	return err
+}
+
+// OverviewLine returns the fields of an article in the order of the overview format, joined by tabs
+func OverviewLine(article Article) string {
+	fields := []string{
+		strconv.FormatInt(article.Number, 10),
+		oneLine(mime.QEncoding.Encode("utf-8", article.Subject)),
+		oneLine(article.From),
+		article.Date.UTC().Format(time.RFC1123Z),
+		article.MessageID,
+		strings.Join(article.References, " "),
+		strconv.Itoa(len(article.Body)),
+		strconv.Itoa(strings.Count(strings.TrimSuffix(article.Body, "\n"), "\n") + 1),
+	}
+	return strings.Join(fields, "\t")
+}
+
+// ParseSubmission reads an article posted by a newsreader, the From header is ignored in favor of the session user
+func ParseSubmission(r io.Reader) (Submission, error) {
+	message, err := mail.ReadMessage(bufio.NewReader(r))
+	if err != nil {
+		return Submission{}, fmt.Errorf("%w: malformed article: %v", errorPkg.ErrInvalidRequest, err)
+	}
+
+	var decoder mime.WordDecoder
+	subject, err := decoder.DecodeHeader(message.Header.Get("Subject"))
+	if err != nil {
+		return Submission{}, fmt.Errorf("%w: malformed subject: %v", errorPkg.ErrInvalidRequest, err)
+	}
+	body, err := io.ReadAll(message.Body)
+	if err != nil {
+		return Submission{}, fmt.Errorf("%w: malformed body: %v", errorPkg.ErrInvalidRequest, err)
+	}
+
+	submission := Submission{
+		Subject:    strings.TrimSpace(subject),
+		References: strings.Fields(message.Header.Get("References")),
+		Body:       strings.TrimRight(strings.ReplaceAll(string(body), "\r\n", "\n"), "\n"),
+	}
+	for _, group := range strings.Split(message.Header.Get("Newsgroups"), ",") {
+		if group = strings.TrimSpace(group); group != "" {
+			submission.Newsgroups = append(submission.Newsgroups, group)
+		}
+	}
+
+	if submission.Subject == "" || submission.Body == "" {
+		return Submission{}, fmt.Errorf("%w: an article needs a subject and a body", errorPkg.ErrInvalidRequest)
+	}
+	return submission, nil
+}
+
+// oneLine replaces the characters that would break a header or an overview line
+func oneLine(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ", "\t", " ").Replace(s)
+}
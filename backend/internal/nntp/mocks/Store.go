@@ -0,0 +1,233 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	jwt "backend/internal/jwt"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	nntp "backend/internal/nntp"
+
+	uuid "github.com/google/uuid"
+)
+
+// Store is an autogenerated mock type for the Store type
+type Store struct {
+	mock.Mock
+}
+
+// ArticleByMessageID provides a mock function with given fields: ctx, messageID
+func (_m *Store) ArticleByMessageID(ctx context.Context, messageID string) (nntp.Article, error) {
+	ret := _m.Called(ctx, messageID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ArticleByMessageID")
+	}
+
+	var r0 nntp.Article
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (nntp.Article, error)); ok {
+		return rf(ctx, messageID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) nntp.Article); ok {
+		r0 = rf(ctx, messageID)
+	} else {
+		r0 = ret.Get(0).(nntp.Article)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, messageID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ArticleByNumber provides a mock function with given fields: ctx, group, number
+func (_m *Store) ArticleByNumber(ctx context.Context, group nntp.Group, number int64) (nntp.Article, error) {
+	ret := _m.Called(ctx, group, number)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ArticleByNumber")
+	}
+
+	var r0 nntp.Article
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, nntp.Group, int64) (nntp.Article, error)); ok {
+		return rf(ctx, group, number)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, nntp.Group, int64) nntp.Article); ok {
+		r0 = rf(ctx, group, number)
+	} else {
+		r0 = ret.Get(0).(nntp.Article)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, nntp.Group, int64) error); ok {
+		r1 = rf(ctx, group, number)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Group provides a mock function with given fields: ctx, name
+func (_m *Store) Group(ctx context.Context, name string) (nntp.Group, error) {
+	ret := _m.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Group")
+	}
+
+	var r0 nntp.Group
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (nntp.Group, error)); ok {
+		return rf(ctx, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) nntp.Group); ok {
+		r0 = rf(ctx, name)
+	} else {
+		r0 = ret.Get(0).(nntp.Group)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Groups provides a mock function with given fields: ctx
+func (_m *Store) Groups(ctx context.Context) ([]nntp.Group, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Groups")
+	}
+
+	var r0 []nntp.Group
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]nntp.Group, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []nntp.Group); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]nntp.Group)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Login provides a mock function with given fields: ctx, name, password
+func (_m *Store) Login(ctx context.Context, name string, password string) (jwt.User, error) {
+	ret := _m.Called(ctx, name, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Login")
+	}
+
+	var r0 jwt.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (jwt.User, error)); ok {
+		return rf(ctx, name, password)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) jwt.User); ok {
+		r0 = rf(ctx, name, password)
+	} else {
+		r0 = ret.Get(0).(jwt.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, name, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Overview provides a mock function with given fields: ctx, group, low, high
+func (_m *Store) Overview(ctx context.Context, group nntp.Group, low int64, high int64) ([]nntp.Article, error) {
+	ret := _m.Called(ctx, group, low, high)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Overview")
+	}
+
+	var r0 []nntp.Article
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, nntp.Group, int64, int64) ([]nntp.Article, error)); ok {
+		return rf(ctx, group, low, high)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, nntp.Group, int64, int64) []nntp.Article); ok {
+		r0 = rf(ctx, group, low, high)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]nntp.Article)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, nntp.Group, int64, int64) error); ok {
+		r1 = rf(ctx, group, low, high)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Post provides a mock function with given fields: ctx, authorID, submission
+func (_m *Store) Post(ctx context.Context, authorID uuid.UUID, submission nntp.Submission) (string, error) {
+	ret := _m.Called(ctx, authorID, submission)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Post")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, nntp.Submission) (string, error)); ok {
+		return rf(ctx, authorID, submission)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, nntp.Submission) string); ok {
+		r0 = rf(ctx, authorID, submission)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, nntp.Submission) error); ok {
+		r1 = rf(ctx, authorID, submission)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewStore creates a new instance of Store. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Store {
+	mock := &Store{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,51 @@
+-- name: CreateGroup :exec
+INSERT INTO nntp_groups (board_id) VALUES ($1) ON CONFLICT DO NOTHING;
+
+-- name: LockGroup :one
+-- Serializes the numbering of a group between sessions and backend instances
+SELECT last_number FROM nntp_groups WHERE board_id = $1 FOR UPDATE;
+
+-- name: NumberArticles :execrows
+-- Numbers the published posts of the board and their comments that have no number yet, oldest first
+INSERT INTO nntp_articles (board_id, number, post_id, comment_id)
+SELECT @board_id::uuid, sqlc.arg(last_number)::bigint + row_number() OVER (ORDER BY a.created_at, a.id), a.post_id, a.comment_id
+FROM (
+    SELECT p.id, p.id AS post_id, NULL::uuid AS comment_id, p.create_at AS created_at
+    FROM posts p
+    WHERE p.board_id = @board_id::uuid AND p.hidden_at IS NULL AND p.status = 'published'
+      AND NOT EXISTS (SELECT 1 FROM nntp_articles n WHERE n.post_id = p.id AND n.comment_id IS NULL)
+    UNION ALL
+    SELECT c.id, c.post_id, c.id AS comment_id, c.created_at
+    FROM comments c
+    JOIN posts p ON p.id = c.post_id
+    WHERE p.board_id = @board_id::uuid AND p.hidden_at IS NULL AND p.status = 'published' AND c.hidden_at IS NULL
+      AND NOT EXISTS (SELECT 1 FROM nntp_articles n WHERE n.comment_id = c.id)
+) a;
+
+-- name: AdvanceGroup :exec
+UPDATE nntp_groups SET last_number = last_number + @count::bigint WHERE board_id = @board_id;
+
+-- name: FindGroupStats :one
+-- Articles that were hidden since they got their number are left out
+SELECT count(*)::bigint AS count, COALESCE(min(n.number), 0)::bigint AS low, COALESCE(max(n.number), 0)::bigint AS high
+FROM nntp_articles n
+JOIN posts p ON p.id = n.post_id AND p.board_id = n.board_id AND p.hidden_at IS NULL AND p.status = 'published'
+LEFT JOIN comments c ON c.id = n.comment_id
+WHERE n.board_id = @board_id AND (n.comment_id IS NULL OR c.hidden_at IS NULL);
+
+-- name: FindArticle :one
+SELECT n.post_id, n.comment_id FROM nntp_articles n WHERE n.board_id = $1 AND n.number = $2;
+
+-- name: FindOverview :many
+-- Overview of the visible articles in the number range, in one query instead of fetching every article
+SELECT n.number, n.post_id, n.comment_id, c.parent_id,
+       COALESCE(c.title, p.title, '')::text AS subject,
+       u.name AS author_name,
+       COALESCE(c.created_at, p.create_at)::timestamptz AS created_at,
+       COALESCE(CASE WHEN n.comment_id IS NULL THEN p.content ELSE c.content END, '')::text AS content
+FROM nntp_articles n
+JOIN posts p ON p.id = n.post_id AND p.board_id = n.board_id AND p.hidden_at IS NULL AND p.status = 'published'
+LEFT JOIN comments c ON c.id = n.comment_id
+JOIN users u ON u.id = COALESCE(c.author_id, p.author_id)
+WHERE n.board_id = @board_id AND n.number BETWEEN @low AND @high AND (n.comment_id IS NULL OR c.hidden_at IS NULL)
+ORDER BY n.number;
@@ -0,0 +1,183 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: queries.sql
+
+package nntp
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const advanceGroup = `-- name: AdvanceGroup :exec
+UPDATE nntp_groups SET last_number = last_number + $1::bigint WHERE board_id = $2
+`
+
+type AdvanceGroupParams struct {
+	Count   int64
+	BoardID uuid.UUID
+}
+
+func (q *Queries) AdvanceGroup(ctx context.Context, arg AdvanceGroupParams) error {
+	_, err := q.db.Exec(ctx, advanceGroup, arg.Count, arg.BoardID)
+	return err
+}
+
+const createGroup = `-- name: CreateGroup :exec
+INSERT INTO nntp_groups (board_id) VALUES ($1) ON CONFLICT DO NOTHING
+`
+
+func (q *Queries) CreateGroup(ctx context.Context, boardID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, createGroup, boardID)
+	return err
+}
+
+const findArticle = `-- name: FindArticle :one
+SELECT n.post_id, n.comment_id FROM nntp_articles n WHERE n.board_id = $1 AND n.number = $2
+`
+
+type FindArticleParams struct {
+	BoardID uuid.UUID
+	Number  int64
+}
+
+type FindArticleRow struct {
+	PostID    uuid.UUID
+	CommentID pgtype.UUID
+}
+
+func (q *Queries) FindArticle(ctx context.Context, arg FindArticleParams) (FindArticleRow, error) {
+	row := q.db.QueryRow(ctx, findArticle, arg.BoardID, arg.Number)
+	var i FindArticleRow
+	err := row.Scan(&i.PostID, &i.CommentID)
+	return i, err
+}
+
+const findGroupStats = `-- name: FindGroupStats :one
+SELECT count(*)::bigint AS count, COALESCE(min(n.number), 0)::bigint AS low, COALESCE(max(n.number), 0)::bigint AS high
+FROM nntp_articles n
+JOIN posts p ON p.id = n.post_id AND p.board_id = n.board_id AND p.hidden_at IS NULL AND p.status = 'published'
+LEFT JOIN comments c ON c.id = n.comment_id
+WHERE n.board_id = $1 AND (n.comment_id IS NULL OR c.hidden_at IS NULL)
+`
+
+type FindGroupStatsRow struct {
+	Count int64
+	Low   int64
+	High  int64
+}
+
+// Articles that were hidden since they got their number are left out
+func (q *Queries) FindGroupStats(ctx context.Context, boardID uuid.UUID) (FindGroupStatsRow, error) {
+	row := q.db.QueryRow(ctx, findGroupStats, boardID)
+	var i FindGroupStatsRow
+	err := row.Scan(&i.Count, &i.Low, &i.High)
+	return i, err
+}
+
+const findOverview = `-- name: FindOverview :many
+SELECT n.number, n.post_id, n.comment_id, c.parent_id,
+       COALESCE(c.title, p.title, '')::text AS subject,
+       u.name AS author_name,
+       COALESCE(c.created_at, p.create_at)::timestamptz AS created_at,
+       COALESCE(CASE WHEN n.comment_id IS NULL THEN p.content ELSE c.content END, '')::text AS content
+FROM nntp_articles n
+JOIN posts p ON p.id = n.post_id AND p.board_id = n.board_id AND p.hidden_at IS NULL AND p.status = 'published'
+LEFT JOIN comments c ON c.id = n.comment_id
+JOIN users u ON u.id = COALESCE(c.author_id, p.author_id)
+WHERE n.board_id = $1 AND n.number BETWEEN $2 AND $3 AND (n.comment_id IS NULL OR c.hidden_at IS NULL)
+ORDER BY n.number
+`
+
+type FindOverviewParams struct {
+	BoardID uuid.UUID
+	Low     int64
+	High    int64
+}
+
+type FindOverviewRow struct {
+	Number     int64
+	PostID     uuid.UUID
+	CommentID  pgtype.UUID
+	ParentID   pgtype.UUID
+	Subject    string
+	AuthorName string
+	CreatedAt  pgtype.Timestamptz
+	Content    string
+}
+
+// Overview of the visible articles in the number range, in one query instead of fetching every article
+func (q *Queries) FindOverview(ctx context.Context, arg FindOverviewParams) ([]FindOverviewRow, error) {
+	rows, err := q.db.Query(ctx, findOverview, arg.BoardID, arg.Low, arg.High)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FindOverviewRow
+	for rows.Next() {
+		var i FindOverviewRow
+		if err := rows.Scan(
+			&i.Number,
+			&i.PostID,
+			&i.CommentID,
+			&i.ParentID,
+			&i.Subject,
+			&i.AuthorName,
+			&i.CreatedAt,
+			&i.Content,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockGroup = `-- name: LockGroup :one
+SELECT last_number FROM nntp_groups WHERE board_id = $1 FOR UPDATE
+`
+
+// Serializes the numbering of a group between sessions and backend instances
+func (q *Queries) LockGroup(ctx context.Context, boardID uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, lockGroup, boardID)
+	var last_number int64
+	err := row.Scan(&last_number)
+	return last_number, err
+}
+
+const numberArticles = `-- name: NumberArticles :execrows
+INSERT INTO nntp_articles (board_id, number, post_id, comment_id)
+SELECT $1::uuid, $2::bigint + row_number() OVER (ORDER BY a.created_at, a.id), a.post_id, a.comment_id
+FROM (
+    SELECT p.id, p.id AS post_id, NULL::uuid AS comment_id, p.create_at AS created_at
+    FROM posts p
+    WHERE p.board_id = $1::uuid AND p.hidden_at IS NULL AND p.status = 'published'
+      AND NOT EXISTS (SELECT 1 FROM nntp_articles n WHERE n.post_id = p.id AND n.comment_id IS NULL)
+    UNION ALL
+    SELECT c.id, c.post_id, c.id AS comment_id, c.created_at
+    FROM comments c
+    JOIN posts p ON p.id = c.post_id
+    WHERE p.board_id = $1::uuid AND p.hidden_at IS NULL AND p.status = 'published' AND c.hidden_at IS NULL
+      AND NOT EXISTS (SELECT 1 FROM nntp_articles n WHERE n.comment_id = c.id)
+) a
+`
+
+type NumberArticlesParams struct {
+	BoardID    uuid.UUID
+	LastNumber int64
+}
+
+// Numbers the published posts of the board and their comments that have no number yet, oldest first
+func (q *Queries) NumberArticles(ctx context.Context, arg NumberArticlesParams) (int64, error) {
+	result, err := q.db.Exec(ctx, numberArticles, arg.BoardID, arg.LastNumber)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
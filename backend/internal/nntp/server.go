@@ -0,0 +1,550 @@
+package nntp
+
+import (
+	"backend/internal"
+	errorPkg "backend/internal/error"
+	"backend/internal/gateway"
+	"backend/internal/jwt"
+	"backend/internal/problem"
+	"backend/internal/ratelimit"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"io"
+	"math"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// idleTimeout closes the sessions of newsreaders that went away without QUIT
+	idleTimeout = 10 * time.Minute
+	// maxLoginFailures closes sessions after as many wrong passwords, the login rate limit counts across sessions
+	maxLoginFailures = 3
+)
+
+//go:generate mockery --name Store
+type Store interface {
+	Login(ctx context.Context, name, password string) (jwt.User, error)
+	Groups(ctx context.Context) ([]Group, error)
+	Group(ctx context.Context, name string) (Group, error)
+	Overview(ctx context.Context, group Group, low, high int64) ([]Article, error)
+	ArticleByNumber(ctx context.Context, group Group, number int64) (Article, error)
+	ArticleByMessageID(ctx context.Context, messageID string) (Article, error)
+	Post(ctx context.Context, authorID uuid.UUID, submission Submission) (string, error)
+}
+
+// Server speaks the reader subset of NNTP (RFC 3977) with STARTTLS (RFC 4642) and AUTHINFO USER/PASS (RFC 4643), so
+// newsreaders can read and post to the boards. Newsreaders have to log in before anything else, like clients of the
+// web API. Logins and articles count against the rate limits of the login and the post routes of the web API.
+type Server struct {
+	logger  *zap.Logger
+	tracer  trace.Tracer
+	store   Store
+	limiter *ratelimit.Limiter
+	addr    string
+	// tlsConfig enables STARTTLS while it is set
+	tlsConfig *tls.Config
+	// insecureAuth accepts passwords on connections without TLS, for local networks and TLS terminating proxies
+	insecureAuth bool
+}
+
+func NewServer(logger *zap.Logger, store Store, limiter *ratelimit.Limiter, addr string, tlsConfig *tls.Config, insecureAuth bool) *Server {
+	return &Server{
+		logger:       logger,
+		tracer:       otel.Tracer("nntp/server"),
+		store:        store,
+		limiter:      limiter,
+		addr:         addr,
+		tlsConfig:    tlsConfig,
+		insecureAuth: insecureAuth,
+	}
+}
+
+// Run serves newsreaders on the address of the server until ctx is done
+func (s *Server) Run(ctx context.Context) {
+	gateway.Run(ctx, s.logger, "NNTP", s.addr, s.Serve)
+}
+
+// Serve accepts sessions on the listener until ctx is done, open sessions are closed with it
+func (s *Server) Serve(ctx context.Context, listener net.Listener) {
+	gateway.Serve(ctx, s.logger, "NNTP", listener, s.handle)
+}
+
+func (s *Server) handle(ctx context.Context, conn net.Conn) {
+	host := gateway.ClientIP(conn)
+	sess := &session{
+		server: s,
+		conn:   conn,
+		text:   textproto.NewConn(conn),
+		ctx:    context.WithValue(ctx, internal.ClientIPContextKey, host),
+	}
+
+	err := sess.reply(200, "Service available, posting allowed")
+	for err == nil {
+		_ = sess.conn.SetReadDeadline(time.Now().Add(idleTimeout))
+		var line string
+		line, err = sess.text.ReadLine()
+		if err != nil {
+			break
+		}
+		err = sess.serve(line)
+	}
+
+	if !errors.Is(err, errQuit) && !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+		s.logger.Debug("Closed newsreader session", zap.String("client_ip", host), zap.Error(err))
+	}
+}
+
+var (
+	// errQuit ends a session after QUIT
+	errQuit = errors.New("quit")
+	// errLoginFailures ends a session after maxLoginFailures wrong passwords
+	errLoginFailures = errors.New("too many failed logins")
+)
+
+type command struct {
+	handle func(s *session, ctx context.Context, args []string) error
+	// public commands are available before AUTHINFO
+	public bool
+}
+
+var commands = map[string]command{
+	"CAPABILITIES": {handle: (*session).capabilities, public: true},
+	"MODE":         {handle: (*session).mode, public: true},
+	"QUIT":         {handle: (*session).quit, public: true},
+	"STARTTLS":     {handle: (*session).starttls, public: true},
+	"AUTHINFO":     {handle: (*session).authinfo, public: true},
+	"LIST":         {handle: (*session).list},
+	"GROUP":        {handle: (*session).selectGroup},
+	"ARTICLE":      {handle: (*session).article},
+	"HEAD":         {handle: (*session).head},
+	"BODY":         {handle: (*session).body},
+	"OVER":         {handle: (*session).over},
+	"XOVER":        {handle: (*session).over},
+	"POST":         {handle: (*session).post},
+}
+
+// session is the state of one newsreader connection, errors returned by its methods end the session
+type session struct {
+	server *Server
+	conn   net.Conn
+	text   *textproto.Conn
+	// ctx carries the client IP and after AUTHINFO the user for the services, like the context of a request
+	ctx context.Context
+	// secure is set once STARTTLS completed
+	secure bool
+
+	username string
+	userID   uuid.UUID
+	failures int
+	group    *Group
+	// current is the current article number of the selected group, 0 if there is none
+	current int64
+}
+
+func (s *session) serve(line string) error {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return s.reply(500, "Unknown command")
+	}
+	name := strings.ToUpper(fields[0])
+	cmd, ok := commands[name]
+	if !ok {
+		return s.reply(500, "Unknown command")
+	}
+	if !cmd.public && s.userID == uuid.Nil {
+		return s.reply(480, "Authentication required")
+	}
+
+	traceCtx, span := s.server.tracer.Start(s.ctx, name+"Command")
+	defer span.End()
+
+	err := cmd.handle(s, traceCtx, fields[1:])
+	if err != nil && !errors.Is(err, errQuit) && !errors.Is(err, errLoginFailures) {
+		span.RecordError(err)
+	}
+	return err
+}
+
+func (s *session) capabilities(_ context.Context, _ []string) error {
+	capabilities := "VERSION 2\nREADER\nPOST\nLIST ACTIVE NEWSGROUPS\nOVER\n"
+	if s.canStartTLS() {
+		capabilities += "STARTTLS\n"
+	}
+	// AUTHINFO without arguments announces that a login is only possible after STARTTLS
+	if s.canLogin() {
+		capabilities += "AUTHINFO USER\n"
+	} else if s.userID == uuid.Nil {
+		capabilities += "AUTHINFO\n"
+	}
+
+	return s.multiline(101, "Capability list follows", func(w io.Writer) error {
+		_, err := io.WriteString(w, capabilities)
+		return err
+	})
+}
+
+func (s *session) mode(_ context.Context, args []string) error {
+	if len(args) != 1 || !strings.EqualFold(args[0], "READER") {
+		return s.reply(501, "Syntax error")
+	}
+	return s.reply(200, "Reader mode, posting permitted")
+}
+
+func (s *session) quit(_ context.Context, _ []string) error {
+	err := s.reply(205, "Connection closing")
+	if err != nil {
+		return err
+	}
+	return errQuit
+}
+
+// starttls switches the session to TLS, the session starts over as the newsreader forgets what it learned before
+func (s *session) starttls(ctx context.Context, args []string) error {
+	if len(args) != 0 {
+		return s.reply(501, "Syntax error")
+	}
+	if !s.canStartTLS() {
+		return s.reply(502, "STARTTLS not available")
+	}
+
+	err := s.reply(382, "Continue with TLS negotiation")
+	if err != nil {
+		return err
+	}
+
+	conn := tls.Server(s.conn, s.server.tlsConfig)
+	_ = conn.SetDeadline(time.Now().Add(idleTimeout))
+	err = conn.HandshakeContext(ctx)
+	if err != nil {
+		return err
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	s.conn = conn
+	s.text = textproto.NewConn(conn)
+	s.secure = true
+	s.username = ""
+	s.group = nil
+	s.current = 0
+	return nil
+}
+
+func (s *session) authinfo(ctx context.Context, args []string) error {
+	if len(args) != 2 {
+		return s.reply(501, "Syntax error")
+	}
+	if s.userID != uuid.Nil {
+		return s.reply(502, "Already authenticated")
+	}
+	if !s.canLogin() {
+		return s.reply(483, "Encryption required, use STARTTLS first")
+	}
+
+	switch strings.ToUpper(args[0]) {
+	case "USER":
+		s.username = args[1]
+		return s.reply(381, "Password required")
+	case "PASS":
+		if s.username == "" {
+			return s.reply(482, "Authentication commands issued out of sequence")
+		}
+		err := s.server.limiter.Check(ctx, ratelimit.RouteLogin)
+		if err != nil {
+			s.username = ""
+			return s.reply(481, "Too many login attempts, try again later")
+		}
+
+		user, err := s.server.store.Login(ctx, s.username, args[1])
+		s.username = ""
+		if err != nil {
+			s.failures++
+			replyErr := s.reply(481, problem.FromError(err).Detail)
+			if replyErr == nil && s.failures >= maxLoginFailures {
+				return errLoginFailures
+			}
+			return replyErr
+		}
+		s.userID, err = uuid.Parse(user.ID)
+		if err != nil {
+			return err
+		}
+		s.ctx = context.WithValue(s.ctx, internal.UserContextKey, user)
+		return s.reply(281, "Authentication accepted")
+	default:
+		return s.reply(501, "Syntax error")
+	}
+}
+
+func (s *session) list(ctx context.Context, args []string) error {
+	// A wildmat after the keyword is accepted but every group is listed
+	keyword := "ACTIVE"
+	if len(args) > 0 {
+		keyword = strings.ToUpper(args[0])
+	}
+	if keyword != "ACTIVE" && keyword != "NEWSGROUPS" {
+		return s.reply(501, "Syntax error")
+	}
+
+	groups, err := s.server.store.Groups(ctx)
+	if err != nil {
+		return s.fault(ctx, err)
+	}
+
+	return s.multiline(215, "List of newsgroups follows", func(w io.Writer) error {
+		for _, group := range groups {
+			line := group.Name + " " + strconv.FormatInt(group.High, 10) + " " + strconv.FormatInt(group.Low, 10) + " y"
+			if keyword == "NEWSGROUPS" {
+				line = group.Name + "\t" + oneLine(group.Description)
+			}
+			_, err := io.WriteString(w, line+"\n")
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *session) selectGroup(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return s.reply(501, "Syntax error")
+	}
+
+	group, err := s.server.store.Group(ctx, args[0])
+	if isNotFound(err) {
+		return s.reply(411, "No such newsgroup")
+	}
+	if err != nil {
+		return s.fault(ctx, err)
+	}
+
+	s.group = &group
+	s.current = 0
+	if group.Count > 0 {
+		s.current = group.Low
+	}
+	return s.reply(211, strconv.FormatInt(group.Count, 10)+" "+strconv.FormatInt(group.Low, 10)+" "+strconv.FormatInt(group.High, 10)+" "+group.Name)
+}
+
+func (s *session) article(ctx context.Context, args []string) error {
+	return s.retrieve(ctx, args, 220, func(w io.Writer, article Article) error {
+		err := WriteHead(w, article)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, "\n")
+		if err != nil {
+			return err
+		}
+		return WriteBody(w, article)
+	})
+}
+
+func (s *session) head(ctx context.Context, args []string) error {
+	return s.retrieve(ctx, args, 221, WriteHead)
+}
+
+func (s *session) body(ctx context.Context, args []string) error {
+	return s.retrieve(ctx, args, 222, WriteBody)
+}
+
+// retrieve looks up the article of ARTICLE, HEAD and BODY by message id, number or the current article number
+func (s *session) retrieve(ctx context.Context, args []string, code int, write func(w io.Writer, article Article) error) error {
+	if len(args) > 1 {
+		return s.reply(501, "Syntax error")
+	}
+
+	var article Article
+	var err error
+	switch {
+	case len(args) == 1 && strings.HasPrefix(args[0], "<"):
+		article, err = s.server.store.ArticleByMessageID(ctx, args[0])
+		if isNotFound(err) {
+			return s.reply(430, "No article with that message-id")
+		}
+	case s.group == nil:
+		return s.reply(412, "No newsgroup selected")
+	case len(args) == 1:
+		number, parseErr := strconv.ParseInt(args[0], 10, 64)
+		if parseErr != nil {
+			return s.reply(501, "Syntax error")
+		}
+		article, err = s.server.store.ArticleByNumber(ctx, *s.group, number)
+		if isNotFound(err) {
+			return s.reply(423, "No article with that number")
+		}
+		if err == nil {
+			s.current = number
+		}
+	case s.current == 0:
+		return s.reply(420, "Current article number is invalid")
+	default:
+		article, err = s.server.store.ArticleByNumber(ctx, *s.group, s.current)
+		if isNotFound(err) {
+			return s.reply(420, "Current article number is invalid")
+		}
+	}
+	if err != nil {
+		return s.fault(ctx, err)
+	}
+
+	return s.multiline(code, strconv.FormatInt(article.Number, 10)+" "+article.MessageID, func(w io.Writer) error {
+		return write(w, article)
+	})
+}
+
+func (s *session) over(ctx context.Context, args []string) error {
+	if len(args) > 1 {
+		return s.reply(501, "Syntax error")
+	}
+	if s.group == nil {
+		return s.reply(412, "No newsgroup selected")
+	}
+
+	low, high := s.current, s.current
+	if len(args) == 0 && s.current == 0 {
+		return s.reply(420, "Current article number is invalid")
+	}
+	if len(args) == 1 {
+		var ok bool
+		low, high, ok = parseRange(args[0])
+		if !ok {
+			return s.reply(501, "Syntax error")
+		}
+	}
+
+	articles, err := s.server.store.Overview(ctx, *s.group, low, high)
+	if err != nil {
+		return s.fault(ctx, err)
+	}
+	if len(articles) == 0 {
+		return s.reply(423, "No articles in that range")
+	}
+
+	return s.multiline(224, "Overview information follows", func(w io.Writer) error {
+		for _, article := range articles {
+			_, err := io.WriteString(w, OverviewLine(article)+"\n")
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *session) post(ctx context.Context, args []string) error {
+	if len(args) != 0 {
+		return s.reply(501, "Syntax error")
+	}
+
+	err := s.reply(340, "Input article; end with <CR-LF>.<CR-LF>")
+	if err != nil {
+		return err
+	}
+
+	_ = s.conn.SetReadDeadline(time.Now().Add(idleTimeout))
+	reader := s.text.DotReader()
+	data, err := io.ReadAll(io.LimitReader(reader, MaxArticleSize+1))
+	if err != nil {
+		return err
+	}
+	if len(data) > MaxArticleSize {
+		_, err = io.Copy(io.Discard, reader)
+		if err != nil {
+			return err
+		}
+		return s.reply(441, "Article too large")
+	}
+
+	submission, err := ParseSubmission(bytes.NewReader(data))
+	if err != nil {
+		return s.reply(441, problem.FromError(err).Detail)
+	}
+
+	route := ratelimit.RoutePosts
+	if len(submission.References) > 0 {
+		route = ratelimit.RouteComments
+	}
+	err = s.server.limiter.Check(ctx, route)
+	if err != nil {
+		return s.reply(441, "Too many articles, try again later")
+	}
+
+	messageID, err := s.server.store.Post(ctx, s.userID, submission)
+	if err != nil {
+		internal.LoggerWithContext(ctx, s.server.logger).Info("Rejected article of newsreader", zap.Error(err))
+		return s.reply(441, problem.FromError(err).Detail)
+	}
+
+	return s.reply(240, "Article received "+messageID)
+}
+
+func (s *session) canStartTLS() bool {
+	return s.server.tlsConfig != nil && !s.secure && s.userID == uuid.Nil
+}
+
+// canLogin reports whether AUTHINFO is accepted, passwords are only sent over TLS unless the server allows otherwise
+func (s *session) canLogin() bool {
+	return s.userID == uuid.Nil && (s.secure || s.server.insecureAuth)
+}
+
+func (s *session) reply(code int, text string) error {
+	return s.text.PrintfLine("%d %s", code, text)
+}
+
+// multiline replies with a status line followed by a dot-terminated block written by write
+func (s *session) multiline(code int, text string, write func(w io.Writer) error) error {
+	err := s.reply(code, text)
+	if err != nil {
+		return err
+	}
+
+	w := s.text.DotWriter()
+	err = write(w)
+	if err != nil {
+		_ = w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// fault reports an unexpected error of the services, the session stays open
+func (s *session) fault(ctx context.Context, err error) error {
+	internal.LoggerWithContext(ctx, s.server.logger).Error("Failed to serve newsreader command", zap.Error(err))
+	return s.reply(403, problem.FromError(err).Detail)
+}
+
+func isNotFound(err error) bool {
+	var notFoundError errorPkg.NotFoundError
+	return errors.As(err, &notFoundError)
+}
+
+// parseRange parses the article ranges n, n- and n-m of OVER
+func parseRange(arg string) (int64, int64, bool) {
+	rawLow, rawHigh, isRange := strings.Cut(arg, "-")
+	low, err := strconv.ParseInt(rawLow, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	if !isRange {
+		return low, low, true
+	}
+	if rawHigh == "" {
+		return low, math.MaxInt64, true
+	}
+	high, err := strconv.ParseInt(rawHigh, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return low, high, true
+}
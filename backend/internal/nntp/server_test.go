@@ -0,0 +1,379 @@
+package nntp_test
+
+import (
+	"backend/internal/config"
+	errorPkg "backend/internal/error"
+	"backend/internal/jwt"
+	"backend/internal/nntp"
+	"backend/internal/nntp/mocks"
+	"backend/internal/ratelimit"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"io"
+	"math/big"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	userID  = uuid.MustParse("8d1c4a2e-0f4b-4c55-9a51-3b0c4f2e9d10")
+	postID  = uuid.MustParse("54a46af2-b454-4746-8ab0-3cf26085a50b")
+	group   = nntp.Group{BoardID: uuid.MustParse("1f0b3c8e-3b0a-4d8e-9f3e-9b1b0f6b7c11"), Name: "general", Count: 2, Low: 1, High: 2}
+	article = nntp.Article{
+		Number:    1,
+		MessageID: "<post." + postID.String() + "@example.com>",
+		Newsgroup: "general",
+		From:      "\"alice\" <alice@example.com>",
+		Subject:   "Release",
+		Date:      time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC),
+		Body:      "Version **2** is out",
+	}
+)
+
+type step struct {
+	send string
+	// want is the start of the status line, wantBody a part of the multi-line block following it
+	want     string
+	wantBody string
+}
+
+var login = []step{
+	{send: "AUTHINFO USER alice", want: "381"},
+	{send: "AUTHINFO PASS secret", want: "281"},
+}
+
+func TestServer_Session(t *testing.T) {
+	tests := []struct {
+		name string
+		// login runs AUTHINFO before the steps
+		login     bool
+		steps     []step
+		setupMock func(m *mocks.Store)
+	}{
+		{
+			name:  "Should require authentication before reading",
+			steps: []step{{send: "GROUP general", want: "480"}, {send: "CAPABILITIES", want: "101", wantBody: "AUTHINFO USER"}},
+		},
+		{
+			name: "Should reject wrong password",
+			steps: []step{
+				{send: "AUTHINFO USER alice", want: "381"},
+				{send: "AUTHINFO PASS wrong", want: "481 Invalid username or password"},
+				{send: "LIST", want: "480"},
+			},
+			setupMock: func(m *mocks.Store) {
+				m.On("Login", mock.Anything, "alice", "wrong").Return(jwt.User{}, errorPkg.ErrCredentialInvalid)
+			},
+		},
+		{
+			name:  "Should list groups",
+			login: true,
+			steps: []step{{send: "LIST ACTIVE", want: "215", wantBody: "general 2 1 y"}},
+			setupMock: func(m *mocks.Store) {
+				m.On("Groups", mock.Anything).Return([]nntp.Group{group}, nil)
+			},
+		},
+		{
+			name:  "Should answer unknown group",
+			login: true,
+			steps: []step{{send: "GROUP missing", want: "411"}},
+			setupMock: func(m *mocks.Store) {
+				m.On("Group", mock.Anything, "missing").Return(nntp.Group{}, errorPkg.NewNotFoundError("boards", "slug", "missing", ""))
+			},
+		},
+		{
+			name:  "Should select group and serve current article",
+			login: true,
+			steps: []step{
+				{send: "GROUP general", want: "211 2 1 2 general"},
+				{send: "ARTICLE", want: "220 1 " + article.MessageID, wantBody: "Subject: Release"},
+				{send: "BODY 1", want: "222 1", wantBody: "Version **2** is out"},
+			},
+			setupMock: func(m *mocks.Store) {
+				m.On("Group", mock.Anything, "general").Return(group, nil)
+				m.On("ArticleByNumber", mock.Anything, group, int64(1)).Return(article, nil)
+			},
+		},
+		{
+			name:  "Should require group for article number",
+			login: true,
+			steps: []step{{send: "HEAD 1", want: "412"}},
+		},
+		{
+			name:  "Should answer unknown message id",
+			login: true,
+			steps: []step{{send: "HEAD <post.missing@example.com>", want: "430"}},
+			setupMock: func(m *mocks.Store) {
+				m.On("ArticleByMessageID", mock.Anything, "<post.missing@example.com>").Return(nntp.Article{}, errorPkg.NewNotFoundError("articles", "message_id", "<post.missing@example.com>", ""))
+			},
+		},
+		{
+			name:  "Should serve overview",
+			login: true,
+			steps: []step{
+				{send: "GROUP general", want: "211"},
+				{send: "XOVER 1-", want: "224", wantBody: "1\tRelease\t\"alice\" <alice@example.com>"},
+			},
+			setupMock: func(m *mocks.Store) {
+				m.On("Group", mock.Anything, "general").Return(group, nil)
+				m.On("Overview", mock.Anything, group, int64(1), mock.Anything).Return([]nntp.Article{article}, nil)
+			},
+		},
+		{
+			name:  "Should post followup",
+			login: true,
+			steps: []step{
+				{send: "POST", want: "340"},
+				{send: "Newsgroups: general\r\nSubject: Re: Release\r\nReferences: " + article.MessageID + "\r\n\r\nAgreed\r\n.", want: "240 Article received <comment."},
+			},
+			setupMock: func(m *mocks.Store) {
+				m.On("Post", mock.Anything, userID, nntp.Submission{
+					Newsgroups: []string{"general"},
+					Subject:    "Re: Release",
+					References: []string{article.MessageID},
+					Body:       "Agreed",
+				}).Return("<comment."+uuid.NewString()+"@example.com>", nil)
+			},
+		},
+		{
+			name:  "Should reject article without subject",
+			login: true,
+			steps: []step{
+				{send: "POST", want: "340"},
+				{send: "Newsgroups: general\r\n\r\nAgreed\r\n.", want: "441"},
+			},
+		},
+		{
+			name:  "Should reject article refused by the services",
+			login: true,
+			steps: []step{
+				{send: "POST", want: "340"},
+				{send: "Newsgroups: general\r\nSubject: Buy\r\n\r\nCasino\r\n.", want: "441"},
+			},
+			setupMock: func(m *mocks.Store) {
+				m.On("Post", mock.Anything, userID, mock.Anything).Return("", errorPkg.ErrContentRejected)
+			},
+		},
+		{
+			name: "Should close session after repeated wrong passwords",
+			steps: []step{
+				{send: "AUTHINFO USER alice", want: "381"},
+				{send: "AUTHINFO PASS wrong", want: "481"},
+				{send: "AUTHINFO USER alice", want: "381"},
+				{send: "AUTHINFO PASS wrong", want: "481"},
+				{send: "AUTHINFO USER alice", want: "381"},
+				{send: "AUTHINFO PASS wrong", want: "481"},
+				{send: "AUTHINFO USER alice", want: ""},
+			},
+			setupMock: func(m *mocks.Store) {
+				m.On("Login", mock.Anything, "alice", "wrong").Return(jwt.User{}, errorPkg.ErrCredentialInvalid)
+			},
+		},
+		{
+			name:  "Should close session on quit",
+			steps: []step{{send: "QUIT", want: "205"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewStore(t)
+			steps := tt.steps
+			if tt.login {
+				mockStore.On("Login", mock.Anything, "alice", "secret").Return(jwt.User{ID: userID.String(), Username: "alice", Role: "USER"}, nil)
+				steps = append(append([]step{}, login...), tt.steps...)
+			}
+			if tt.setupMock != nil {
+				tt.setupMock(mockStore)
+			}
+
+			conn := dial(t, nntp.NewServer(zap.NewNop(), mockStore, newLimiter(t), "", nil, true))
+			for _, s := range steps {
+				status, body := exchange(t, conn, s.send)
+				assert.True(t, strings.HasPrefix(status, s.want), "%q: want %q, got %q", s.send, s.want, status)
+				assert.Contains(t, body, s.wantBody)
+			}
+		})
+	}
+}
+
+func TestServer_Limits(t *testing.T) {
+	limiter, err := ratelimit.NewLimiter([]config.RateLimitRule{
+		{Route: ratelimit.RouteLogin, Requests: 1, Per: time.Minute},
+		{Route: ratelimit.RouteComments, Requests: 1, Per: time.Minute},
+	})
+	if err != nil {
+		t.Fatalf("NewLimiter() error = %v", err)
+	}
+	mockStore := mocks.NewStore(t)
+	mockStore.On("Login", mock.Anything, "alice", "wrong").Return(jwt.User{}, errorPkg.ErrCredentialInvalid).Once()
+	mockStore.On("Post", mock.Anything, userID, mock.Anything).Return("<comment."+uuid.NewString()+"@example.com>", nil).Once()
+	server := nntp.NewServer(zap.NewNop(), mockStore, limiter, "", nil, true)
+
+	// the login limit counts the attempts of every session of the client IP
+	conn := dial(t, server)
+	exchange(t, conn, "AUTHINFO USER alice")
+	status, _ := exchange(t, conn, "AUTHINFO PASS wrong")
+	assert.True(t, strings.HasPrefix(status, "481 Invalid"), status)
+	conn = dial(t, server)
+	exchange(t, conn, "AUTHINFO USER alice")
+	status, _ = exchange(t, conn, "AUTHINFO PASS secret")
+	assert.Equal(t, "481 Too many login attempts, try again later", status)
+
+	// followups count against the comment limit of the web API
+	mockStore.On("Login", mock.Anything, "alice", "secret").Return(jwt.User{ID: userID.String(), Username: "alice", Role: "USER"}, nil)
+	limiter, err = ratelimit.NewLimiter([]config.RateLimitRule{{Route: ratelimit.RouteComments, Requests: 1, Per: time.Minute}})
+	if err != nil {
+		t.Fatalf("NewLimiter() error = %v", err)
+	}
+	conn = dial(t, nntp.NewServer(zap.NewNop(), mockStore, limiter, "", nil, true))
+	for _, s := range login {
+		exchange(t, conn, s.send)
+	}
+	followup := "Newsgroups: general\r\nSubject: Re: Release\r\nReferences: " + article.MessageID + "\r\n\r\nAgreed\r\n."
+	for _, want := range []string{"240", "441 Too many articles"} {
+		exchange(t, conn, "POST")
+		status, _ = exchange(t, conn, followup)
+		assert.True(t, strings.HasPrefix(status, want), "want %q, got %q", want, status)
+	}
+}
+
+func TestServer_StartTLS(t *testing.T) {
+	mockStore := mocks.NewStore(t)
+	mockStore.On("Login", mock.Anything, "alice", "secret").Return(jwt.User{ID: userID.String(), Username: "alice", Role: "USER"}, nil)
+	certificate := newCertificate(t)
+	server := nntp.NewServer(zap.NewNop(), mockStore, newLimiter(t), "", &tls.Config{Certificates: []tls.Certificate{certificate}}, false)
+
+	tcpConn, conn := dialTCP(t, server)
+	_, capabilities := exchange(t, conn, "CAPABILITIES")
+	assert.Contains(t, capabilities, "STARTTLS")
+	assert.NotContains(t, capabilities, "AUTHINFO USER")
+	status, _ := exchange(t, conn, "AUTHINFO USER alice")
+	assert.True(t, strings.HasPrefix(status, "483"), status)
+
+	status, _ = exchange(t, conn, "STARTTLS")
+	assert.True(t, strings.HasPrefix(status, "382"), status)
+	conn = textproto.NewConn(tls.Client(tcpConn, &tls.Config{InsecureSkipVerify: true}))
+
+	_, capabilities = exchange(t, conn, "CAPABILITIES")
+	assert.Contains(t, capabilities, "AUTHINFO USER")
+	assert.NotContains(t, capabilities, "STARTTLS")
+	for _, s := range login {
+		status, _ = exchange(t, conn, s.send)
+		assert.True(t, strings.HasPrefix(status, s.want), "%q: want %q, got %q", s.send, s.want, status)
+	}
+}
+
+func TestParseSubmission(t *testing.T) {
+	submission, err := nntp.ParseSubmission(strings.NewReader(
+		"From: alice <alice@example.com>\r\nNewsgroups: general, news\r\nSubject: =?utf-8?q?Gr=C3=BC=C3=9Fe?=\r\nReferences: <a@x> <b@x>\r\n\r\nHello\r\n\r\n"))
+	if err != nil {
+		t.Fatalf("ParseSubmission() error = %v", err)
+	}
+
+	assert.Equal(t, nntp.Submission{
+		Newsgroups: []string{"general", "news"},
+		Subject:    "Grüße",
+		References: []string{"<a@x>", "<b@x>"},
+		Body:       "Hello",
+	}, submission)
+}
+
+// newLimiter returns a limiter without limits
+func newLimiter(t *testing.T) *ratelimit.Limiter {
+	limiter, err := ratelimit.NewLimiter(nil)
+	if err != nil {
+		t.Fatalf("NewLimiter() error = %v", err)
+	}
+	return limiter
+}
+
+// newCertificate returns a self-signed certificate for STARTTLS
+func newCertificate(t *testing.T) tls.Certificate {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		t.Fatalf("CreateCertificate() error = %v", err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: privateKey}
+}
+
+// dial starts the server on a free port and returns a connection after the greeting
+func dial(t *testing.T, server *nntp.Server) *textproto.Conn {
+	_, conn := dialTCP(t, server)
+	return conn
+}
+
+// dialTCP is dial that also returns the TCP connection under the text protocol
+func dialTCP(t *testing.T, server *nntp.Server) (net.Conn, *textproto.Conn) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go server.Serve(ctx, listener)
+
+	tcpConn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	t.Cleanup(func() {
+		_ = tcpConn.Close()
+	})
+
+	conn := textproto.NewConn(tcpConn)
+	greeting, err := conn.ReadLine()
+	if err != nil || !strings.HasPrefix(greeting, "200") {
+		t.Fatalf("greeting = %q, error = %v", greeting, err)
+	}
+	return tcpConn, conn
+}
+
+// exchange sends a command and returns the status line and the multi-line block of the status codes that have one,
+// the status line is empty once the server closed the session
+func exchange(t *testing.T, conn *textproto.Conn, send string) (string, string) {
+	err := conn.PrintfLine("%s", send)
+	if err != nil {
+		t.Fatalf("PrintfLine() error = %v", err)
+	}
+	status, err := conn.ReadLine()
+	if errors.Is(err, io.EOF) {
+		// the server closed the session
+		return "", ""
+	}
+	if err != nil {
+		t.Fatalf("ReadLine() error = %v", err)
+	}
+
+	switch status[:3] {
+	case "101", "215", "220", "221", "222", "224":
+		lines, err := conn.ReadDotLines()
+		if err != nil {
+			t.Fatalf("ReadDotLines() error = %v", err)
+		}
+		return status, strings.Join(lines, "\n")
+	}
+	return status, ""
+}
@@ -0,0 +1,514 @@
+package nntp
+
+import (
+	"backend/internal"
+	"backend/internal/audit"
+	"backend/internal/auth"
+	"backend/internal/board"
+	"backend/internal/comment"
+	"backend/internal/database"
+	errorPkg "backend/internal/error"
+	"backend/internal/gateway"
+	"backend/internal/jwt"
+	"backend/internal/post"
+	"backend/internal/user"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kinds of content in message ids
+const (
+	kindPost    = "post"
+	kindComment = "comment"
+)
+
+// Group is a board as newsgroup, Low and High are the numbers of its oldest and newest visible article
+type Group struct {
+	BoardID     uuid.UUID
+	Name        string
+	Description string
+	Count       int64
+	Low         int64
+	High        int64
+}
+
+// Article is a post or a comment as news article, comments are followups of their post or parent comment
+type Article struct {
+	// Number is the article number in its group, 0 if the article was looked up by message id
+	Number    int64
+	MessageID string
+	Newsgroup string
+	// From is the author as mail address, newsreaders show the name
+	From    string
+	Subject string
+	Date    time.Time
+	// References are the message ids of the post and the parent comment, in thread order
+	References []string
+	Body       string
+}
+
+// Submission is an article posted by a newsreader, articles without References become posts
+type Submission struct {
+	Newsgroups []string
+	Subject    string
+	References []string
+	Body       string
+}
+
+type Boards interface {
+	GetAll(ctx context.Context) ([]board.Board, error)
+	GetByID(ctx context.Context, id uuid.UUID) (board.Board, error)
+	GetBySlug(ctx context.Context, slug string) (board.Board, error)
+}
+
+type Posts interface {
+	GetByID(ctx context.Context, id uuid.UUID) (post.Post, error)
+	Create(ctx context.Context, r post.CreateRequest) (post.Post, error)
+}
+
+type Comments interface {
+	GetById(ctx context.Context, id uuid.UUID) (comment.Comment, error)
+	Create(ctx context.Context, arg comment.CreateRequest) (comment.Comment, error)
+}
+
+type Users interface {
+	GetByID(ctx context.Context, id uuid.UUID) (user.User, error)
+	GetByName(ctx context.Context, name string) (user.User, error)
+}
+
+// Auditor keeps the trail of logins, see audit.Service
+type Auditor interface {
+	Record(ctx context.Context, entry audit.Entry) error
+}
+
+// Service maps boards, posts and comments to newsgroups and articles. Reads and writes of content go through the
+// services of the web API, only the article numbers newsreaders rely on are stored here.
+type Service struct {
+	logger *zap.Logger
+	tracer trace.Tracer
+	pool   *pgxpool.Pool
+	query  *Queries
+
+	// domain is the right side of the message ids
+	domain   string
+	boards   Boards
+	posts    Posts
+	comments Comments
+	users    Users
+	login    *auth.Login
+}
+
+func NewService(logger *zap.Logger, db *pgxpool.Pool, domain string, boards Boards, posts Posts, comments Comments, users Users, auditor Auditor) *Service {
+	return &Service{
+		logger:   logger,
+		tracer:   otel.Tracer("nntp/service"),
+		pool:     db,
+		query:    New(db),
+		domain:   domain,
+		boards:   boards,
+		posts:    posts,
+		comments: comments,
+		users:    users,
+		login:    auth.NewLogin(logger, users, auditor),
+	}
+}
+
+// Login checks the credentials of a newsreader the same way as the login of the web API
+func (s *Service) Login(ctx context.Context, name, password string) (jwt.User, error) {
+	traceCtx, span := s.tracer.Start(ctx, "Login")
+	defer span.End()
+
+	userEntity, err := s.login.Check(traceCtx, "nntp", name, password)
+	if err != nil {
+		span.RecordError(err)
+		return jwt.User{}, err
+	}
+
+	return jwt.User{ID: userEntity.ID.String(), Username: userEntity.Name, Role: userEntity.Role, IssuedAt: time.Now()}, nil
+}
+
+// Groups returns all boards as newsgroups
+func (s *Service) Groups(ctx context.Context) ([]Group, error) {
+	traceCtx, span := s.tracer.Start(ctx, "Groups")
+	defer span.End()
+
+	boards, err := s.boards.GetAll(traceCtx)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	groups := make([]Group, 0, len(boards))
+	for _, b := range boards {
+		group, err := s.group(traceCtx, b)
+		if err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+		groups = append(groups, group)
+	}
+
+	return groups, nil
+}
+
+// Group returns the newsgroup of the board with the slug name
+func (s *Service) Group(ctx context.Context, name string) (Group, error) {
+	traceCtx, span := s.tracer.Start(ctx, "Group")
+	defer span.End()
+
+	b, err := s.boards.GetBySlug(traceCtx, name)
+	if err != nil {
+		span.RecordError(err)
+		return Group{}, err
+	}
+
+	group, err := s.group(traceCtx, b)
+	if err != nil {
+		span.RecordError(err)
+		return Group{}, err
+	}
+
+	return group, nil
+}
+
+// Overview returns the visible articles of the group in the number range, without looking up every article
+func (s *Service) Overview(ctx context.Context, group Group, low, high int64) ([]Article, error) {
+	traceCtx, span := s.tracer.Start(ctx, "Overview")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	rows, err := s.query.FindOverview(traceCtx, FindOverviewParams{BoardID: group.BoardID, Low: low, High: high})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "get overview")
+		span.RecordError(err)
+		return nil, err
+	}
+
+	articles := make([]Article, 0, len(rows))
+	for _, row := range rows {
+		article := Article{
+			Number:    row.Number,
+			MessageID: s.messageID(kindPost, row.PostID),
+			Newsgroup: group.Name,
+			From:      s.from(row.AuthorName),
+			Subject:   row.Subject,
+			Date:      row.CreatedAt.Time,
+			Body:      row.Content,
+		}
+		if row.CommentID.Valid {
+			article.MessageID = s.messageID(kindComment, row.CommentID.Bytes)
+			article.References = s.references(row.PostID, row.ParentID.Bytes, row.ParentID.Valid)
+		}
+		articles = append(articles, article)
+	}
+
+	return articles, nil
+}
+
+// ArticleByNumber returns the article with the number in the group
+func (s *Service) ArticleByNumber(ctx context.Context, group Group, number int64) (Article, error) {
+	traceCtx, span := s.tracer.Start(ctx, "ArticleByNumber")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	row, err := s.query.FindArticle(traceCtx, FindArticleParams{BoardID: group.BoardID, Number: number})
+	if err != nil {
+		err = database.WrapDBErrorWithKeyValue(err, "nntp_articles", "number", strconv.FormatInt(number, 10), logger, "get article by number")
+		span.RecordError(err)
+		return Article{}, err
+	}
+
+	var article Article
+	if row.CommentID.Valid {
+		article, err = s.commentArticle(traceCtx, row.CommentID.Bytes)
+	} else {
+		article, err = s.postArticle(traceCtx, row.PostID)
+	}
+	if err != nil {
+		span.RecordError(err)
+		return Article{}, err
+	}
+
+	article.Number = number
+	return article, nil
+}
+
+// ArticleByMessageID returns the post or comment of a message id as returned in Article.MessageID
+func (s *Service) ArticleByMessageID(ctx context.Context, messageID string) (Article, error) {
+	traceCtx, span := s.tracer.Start(ctx, "ArticleByMessageID")
+	defer span.End()
+
+	kind, id, err := s.parseMessageID(messageID)
+	if err != nil {
+		span.RecordError(err)
+		return Article{}, err
+	}
+
+	var article Article
+	if kind == kindComment {
+		article, err = s.commentArticle(traceCtx, id)
+	} else {
+		article, err = s.postArticle(traceCtx, id)
+	}
+	if err != nil {
+		span.RecordError(err)
+		return Article{}, err
+	}
+
+	return article, nil
+}
+
+// Post creates a post from a new article or a comment from a followup and returns the message id of the article.
+// The last reference is the article answered, followups must be posted to the newsgroup of their thread.
+func (s *Service) Post(ctx context.Context, authorID uuid.UUID, submission Submission) (string, error) {
+	traceCtx, span := s.tracer.Start(ctx, "Post")
+	defer span.End()
+
+	messageID, err := s.post(traceCtx, authorID, submission)
+	if err != nil {
+		span.RecordError(err)
+		return "", err
+	}
+
+	return messageID, nil
+}
+
+func (s *Service) post(ctx context.Context, authorID uuid.UUID, submission Submission) (string, error) {
+	err := gateway.CheckSuspension(ctx, s.users, authorID)
+	if err != nil {
+		return "", err
+	}
+
+	if len(submission.Newsgroups) != 1 {
+		return "", fmt.Errorf("%w: an article must be posted to exactly one newsgroup", errorPkg.ErrInvalidRequest)
+	}
+	b, err := s.boards.GetBySlug(ctx, submission.Newsgroups[0])
+	if err != nil {
+		return "", err
+	}
+
+	if len(submission.References) == 0 {
+		created, err := s.posts.Create(ctx, post.CreateRequest{
+			AuthorID: authorID,
+			BoardID:  &b.ID,
+			Title:    submission.Subject,
+			Content:  submission.Body,
+		})
+		if err != nil {
+			return "", err
+		}
+		return s.messageID(kindPost, created.ID), nil
+	}
+
+	kind, id, err := s.parseMessageID(submission.References[len(submission.References)-1])
+	if err != nil {
+		return "", err
+	}
+	postID := id
+	var parentID *uuid.UUID
+	if kind == kindComment {
+		parent, err := s.comments.GetById(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		postID = parent.PostID
+		parentID = &parent.ID
+	}
+
+	p, err := s.posts.GetByID(ctx, postID)
+	if err != nil {
+		return "", err
+	}
+	if !p.Published() || p.BoardID.Bytes != b.ID {
+		return "", fmt.Errorf("%w: a followup must be posted to the newsgroup of its thread", errorPkg.ErrInvalidRequest)
+	}
+
+	created, err := s.comments.Create(ctx, comment.CreateRequest{
+		PostID:   postID,
+		AuthorID: authorID,
+		ParentID: parentID,
+		Title:    submission.Subject,
+		Content:  submission.Body,
+	})
+	if err != nil {
+		return "", err
+	}
+	return s.messageID(kindComment, created.ID), nil
+}
+
+// group numbers the new articles of the board and returns its newsgroup
+func (s *Service) group(ctx context.Context, b board.Board) (Group, error) {
+	logger := internal.LoggerWithContext(ctx, s.logger)
+
+	err := s.numberArticles(ctx, b.ID)
+	if err != nil {
+		return Group{}, err
+	}
+
+	stats, err := s.query.FindGroupStats(ctx, b.ID)
+	if err != nil {
+		return Group{}, database.WrapDBErrorWithKeyValue(err, "boards", "id", b.ID.String(), logger, "get group stats")
+	}
+
+	group := Group{
+		BoardID:     b.ID,
+		Name:        b.Slug,
+		Description: b.Description.String,
+		Count:       stats.Count,
+		Low:         stats.Low,
+		High:        stats.High,
+	}
+	// RFC 3977 reports empty groups with a high water mark below the low one
+	if group.Count == 0 {
+		group.Low, group.High = 1, 0
+	}
+	return group, nil
+}
+
+// numberArticles gives the posts and comments of the board that were published since the last call their number
+func (s *Service) numberArticles(ctx context.Context, boardID uuid.UUID) error {
+	logger := internal.LoggerWithContext(ctx, s.logger)
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return database.WrapDBError(err, logger, "begin numbering transaction")
+	}
+	defer func() {
+		_ = tx.Rollback(context.WithoutCancel(ctx))
+	}()
+	query := s.query.WithTx(tx)
+
+	err = query.CreateGroup(ctx, boardID)
+	if err != nil {
+		return database.WrapDBError(err, logger, "create group")
+	}
+
+	lastNumber, err := query.LockGroup(ctx, boardID)
+	if err != nil {
+		return database.WrapDBError(err, logger, "lock group")
+	}
+
+	count, err := query.NumberArticles(ctx, NumberArticlesParams{BoardID: boardID, LastNumber: lastNumber})
+	if err != nil {
+		return database.WrapDBError(err, logger, "number articles")
+	}
+	if count == 0 {
+		return nil
+	}
+
+	err = query.AdvanceGroup(ctx, AdvanceGroupParams{BoardID: boardID, Count: count})
+	if err != nil {
+		return database.WrapDBError(err, logger, "advance group")
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return database.WrapDBError(err, logger, "commit numbering")
+	}
+
+	return nil
+}
+
+func (s *Service) postArticle(ctx context.Context, id uuid.UUID) (Article, error) {
+	p, err := s.posts.GetByID(ctx, id)
+	if err != nil {
+		return Article{}, err
+	}
+	// Drafts and posts outside of boards have no newsgroup
+	if !p.Published() || !p.BoardID.Valid {
+		return Article{}, errorPkg.NewNotFoundError("post", "id", id.String(), "")
+	}
+
+	article := Article{
+		MessageID: s.messageID(kindPost, p.ID),
+		Subject:   p.Title.String,
+		Date:      p.CreateAt.Time,
+		Body:      p.Content.String,
+	}
+	return s.complete(ctx, article, p, p.AuthorID)
+}
+
+func (s *Service) commentArticle(ctx context.Context, id uuid.UUID) (Article, error) {
+	c, err := s.comments.GetById(ctx, id)
+	if err != nil {
+		return Article{}, err
+	}
+	p, err := s.posts.GetByID(ctx, c.PostID)
+	if err != nil {
+		return Article{}, err
+	}
+	if !p.Published() || !p.BoardID.Valid {
+		return Article{}, errorPkg.NewNotFoundError("comments", "id", id.String(), "")
+	}
+
+	article := Article{
+		MessageID:  s.messageID(kindComment, c.ID),
+		Subject:    c.Title.String,
+		Date:       c.CreatedAt.Time,
+		References: s.references(c.PostID, c.ParentID.Bytes, c.ParentID.Valid),
+		Body:       c.Content.String,
+	}
+	return s.complete(ctx, article, p, c.AuthorID)
+}
+
+// complete adds the newsgroup of the post and the name of the author to the article
+func (s *Service) complete(ctx context.Context, article Article, p post.Post, authorID uuid.UUID) (Article, error) {
+	b, err := s.boards.GetByID(ctx, p.BoardID.Bytes)
+	if err != nil {
+		return Article{}, err
+	}
+	author, err := s.users.GetByID(ctx, authorID)
+	if err != nil {
+		return Article{}, err
+	}
+
+	article.Newsgroup = b.Slug
+	article.From = s.from(author.Name)
+	return article, nil
+}
+
+func (s *Service) references(postID uuid.UUID, parentID uuid.UUID, hasParent bool) []string {
+	references := []string{s.messageID(kindPost, postID)}
+	if hasParent {
+		references = append(references, s.messageID(kindComment, parentID))
+	}
+	return references
+}
+
+// from returns the address of an author, the mailbox does not exist but From requires an address
+func (s *Service) from(name string) string {
+	return (&mail.Address{Name: name, Address: name + "@" + s.domain}).String()
+}
+
+func (s *Service) messageID(kind string, id uuid.UUID) string {
+	return "<" + kind + "." + id.String() + "@" + s.domain + ">"
+}
+
+// parseMessageID is the reverse of messageID, message ids of other domains are reported as not found
+func (s *Service) parseMessageID(messageID string) (string, uuid.UUID, error) {
+	notFound := errorPkg.NewNotFoundError("articles", "message_id", messageID, "")
+
+	local, domain, ok := strings.Cut(strings.TrimSuffix(strings.TrimPrefix(messageID, "<"), ">"), "@")
+	if !ok || domain != s.domain {
+		return "", uuid.Nil, notFound
+	}
+	kind, rawID, ok := strings.Cut(local, ".")
+	if !ok || (kind != kindPost && kind != kindComment) {
+		return "", uuid.Nil, notFound
+	}
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		return "", uuid.Nil, notFound
+	}
+
+	return kind, id, nil
+}
@@ -0,0 +1,152 @@
+package notification
+
+import (
+	"backend/internal"
+	errorPkg "backend/internal/error"
+	"backend/internal/jwt"
+	"backend/internal/problem"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type Response struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	ActorID   string `json:"actor_id"`
+	PostID    string `json:"post_id"`
+	CommentID string `json:"comment_id,omitempty"`
+	Read      bool   `json:"read"`
+	CreatedAt string `json:"created_at"`
+}
+
+//go:generate mockery --name=Store
+type Store interface {
+	GetByUser(ctx context.Context, userID uuid.UUID, unreadOnly bool, pagination internal.Pagination) ([]Notification, error)
+	MarkRead(ctx context.Context, userID, id uuid.UUID) error
+	MarkAllRead(ctx context.Context, userID uuid.UUID) error
+}
+
+type Handler struct {
+	logger *zap.Logger
+	tracer trace.Tracer
+	store  Store
+}
+
+func NewHandler(logger *zap.Logger, store Store) *Handler {
+	return &Handler{
+		logger: logger,
+		tracer: otel.Tracer("notification/handler"),
+		store:  store,
+	}
+}
+
+// GetAllHandler lists the notifications of the current user, newest first. The unread query parameter restricts
+// the list to unread notifications.
+func (h *Handler) GetAllHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "GetAllNotificationEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	userID, err := jwt.GetUserIDFromContext(traceCtx)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	pagination, err := internal.ParsePagination(r)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	unreadOnly := false
+	if value := r.URL.Query().Get("unread"); value != "" {
+		unreadOnly, err = strconv.ParseBool(value)
+		if err != nil {
+			problem.WriteError(traceCtx, w, fmt.Errorf("%w: unread must be a boolean", errorPkg.ErrInvalidQuery), logger)
+			return
+		}
+	}
+
+	notifications, err := h.store.GetByUser(traceCtx, userID, unreadOnly, pagination)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	response := make([]Response, len(notifications))
+	for i, n := range notifications {
+		response[i] = GenerateResponse(n)
+	}
+
+	internal.WriteJSONResponse(w, http.StatusOK, response)
+}
+
+func (h *Handler) MarkReadHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "MarkNotificationReadEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	userID, err := jwt.GetUserIDFromContext(traceCtx)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	id, err := internal.ParseUUID(r.PathValue("id"))
+	if err != nil {
+		problem.WriteError(traceCtx, w, fmt.Errorf("%w: %v", errorPkg.ErrInvalidUUID, err), logger)
+		return
+	}
+
+	err = h.store.MarkRead(traceCtx, userID, id)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) MarkAllReadHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "MarkAllNotificationsReadEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	userID, err := jwt.GetUserIDFromContext(traceCtx)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	err = h.store.MarkAllRead(traceCtx, userID)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func GenerateResponse(n Notification) Response {
+	response := Response{
+		ID:        n.ID.String(),
+		Type:      n.Type,
+		ActorID:   n.ActorID.String(),
+		PostID:    n.PostID.String(),
+		Read:      n.ReadAt.Valid,
+		CreatedAt: n.CreatedAt.Time.Format(time.RFC3339),
+	}
+	if n.CommentID.Valid {
+		response.CommentID = uuid.UUID(n.CommentID.Bytes).String()
+	}
+
+	return response
+}
@@ -0,0 +1,100 @@
+package notification_test
+
+import (
+	"backend/internal"
+	"backend/internal/jwt"
+	"backend/internal/notification"
+	"backend/internal/notification/mocks"
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHandler_GetAllHandler(t *testing.T) {
+	user := jwt.User{
+		ID:       "81c1ecc1-66d7-4134-b5fe-d886a6f418a4",
+		Username: "test",
+		Role:     "USER",
+	}
+	stored := notification.Notification{
+		ID:        uuid.MustParse("54a46af2-b454-4746-8ab0-3cf26085a50b"),
+		UserID:    uuid.MustParse(user.ID),
+		Type:      notification.TypeReplyToComment,
+		ActorID:   uuid.MustParse("7942c917-4770-43c1-a56a-952186b9970e"),
+		PostID:    uuid.MustParse("1f0b3c8e-3b0a-4d8e-9f3e-9b1b0f6b7c11"),
+		CommentID: pgtype.UUID{Bytes: uuid.MustParse("2a7d1b7e-6c53-4f6b-8d9e-0c1b2a3d4e5f"), Valid: true},
+		CreatedAt: pgtype.Timestamptz{Time: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		setupMock  func(m *mocks.Store)
+		wantStatus int
+		wantResult []notification.Response
+	}{
+		{
+			name:  "Should list unread notifications",
+			query: "?unread=true&page=2&size=10",
+			setupMock: func(m *mocks.Store) {
+				m.On("GetByUser", mock.Anything, uuid.MustParse(user.ID), true, internal.Pagination{Page: 2, Size: 10}).
+					Return([]notification.Notification{stored}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantResult: []notification.Response{
+				{
+					ID:        "54a46af2-b454-4746-8ab0-3cf26085a50b",
+					Type:      notification.TypeReplyToComment,
+					ActorID:   "7942c917-4770-43c1-a56a-952186b9970e",
+					PostID:    "1f0b3c8e-3b0a-4d8e-9f3e-9b1b0f6b7c11",
+					CommentID: "2a7d1b7e-6c53-4f6b-8d9e-0c1b2a3d4e5f",
+					Read:      false,
+					CreatedAt: "2000-01-01T00:00:00Z",
+				},
+			},
+		},
+		{
+			name:       "Should reject invalid unread filter",
+			query:      "?unread=maybe",
+			setupMock:  func(m *mocks.Store) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Should reject oversized page",
+			query:      "?size=1000",
+			setupMock:  func(m *mocks.Store) {},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mocks.NewStore(t)
+			tt.setupMock(m)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/api/notifications"+tt.query, nil)
+			r = r.WithContext(context.WithValue(r.Context(), internal.UserContextKey, user))
+
+			h := notification.NewHandler(zap.NewNop(), m)
+			h.GetAllHandler(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusOK {
+				jsonWant, err := json.Marshal(tt.wantResult)
+				if err != nil {
+					t.Fatalf("failed to marshal expected response: %v", err)
+				}
+				assert.Equal(t, string(jsonWant), w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	internal "backend/internal"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	notification "backend/internal/notification"
+
+	uuid "github.com/google/uuid"
+)
+
+// Store is an autogenerated mock type for the Store type
+type Store struct {
+	mock.Mock
+}
+
+// GetByUser provides a mock function with given fields: ctx, userID, unreadOnly, pagination
+func (_m *Store) GetByUser(ctx context.Context, userID uuid.UUID, unreadOnly bool, pagination internal.Pagination) ([]notification.Notification, error) {
+	ret := _m.Called(ctx, userID, unreadOnly, pagination)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByUser")
+	}
+
+	var r0 []notification.Notification
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, bool, internal.Pagination) ([]notification.Notification, error)); ok {
+		return rf(ctx, userID, unreadOnly, pagination)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, bool, internal.Pagination) []notification.Notification); ok {
+		r0 = rf(ctx, userID, unreadOnly, pagination)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]notification.Notification)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, bool, internal.Pagination) error); ok {
+		r1 = rf(ctx, userID, unreadOnly, pagination)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkAllRead provides a mock function with given fields: ctx, userID
+func (_m *Store) MarkAllRead(ctx context.Context, userID uuid.UUID) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkAllRead")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MarkRead provides a mock function with given fields: ctx, userID, id
+func (_m *Store) MarkRead(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
+	ret := _m.Called(ctx, userID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkRead")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(ctx, userID, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewStore creates a new instance of Store. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Store {
+	mock := &Store{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
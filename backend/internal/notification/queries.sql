@@ -0,0 +1,20 @@
+-- name: Create :one
+INSERT INTO notifications (user_id, type, actor_id, post_id, comment_id) VALUES ($1, $2, $3, $4, $5) RETURNING *;
+
+-- name: FindByUser :many
+SELECT * FROM notifications
+WHERE user_id = @user_id AND (NOT @unread_only::bool OR read_at IS NULL)
+ORDER BY created_at DESC
+LIMIT @size OFFSET @skip;
+
+-- name: CountUnread :one
+SELECT count(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL;
+
+-- name: MarkRead :execrows
+UPDATE notifications SET read_at = now() WHERE id = $1 AND user_id = $2 AND read_at IS NULL;
+
+-- name: MarkAllRead :execrows
+UPDATE notifications SET read_at = now() WHERE user_id = $1 AND read_at IS NULL;
+
+-- name: Exists :one
+SELECT EXISTS (SELECT 1 FROM notifications WHERE id = $1 AND user_id = $2);
\ No newline at end of file
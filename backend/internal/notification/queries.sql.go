@@ -0,0 +1,151 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: queries.sql
+
+package notification
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const countUnread = `-- name: CountUnread :one
+SELECT count(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL
+`
+
+func (q *Queries) CountUnread(ctx context.Context, userID uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countUnread, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const create = `-- name: Create :one
+INSERT INTO notifications (user_id, type, actor_id, post_id, comment_id) VALUES ($1, $2, $3, $4, $5) RETURNING id, user_id, type, actor_id, post_id, comment_id, read_at, created_at
+`
+
+type CreateParams struct {
+	UserID    uuid.UUID
+	Type      string
+	ActorID   uuid.UUID
+	PostID    uuid.UUID
+	CommentID pgtype.UUID
+}
+
+func (q *Queries) Create(ctx context.Context, arg CreateParams) (Notification, error) {
+	row := q.db.QueryRow(ctx, create,
+		arg.UserID,
+		arg.Type,
+		arg.ActorID,
+		arg.PostID,
+		arg.CommentID,
+	)
+	var i Notification
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Type,
+		&i.ActorID,
+		&i.PostID,
+		&i.CommentID,
+		&i.ReadAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const exists = `-- name: Exists :one
+SELECT EXISTS (SELECT 1 FROM notifications WHERE id = $1 AND user_id = $2)
+`
+
+type ExistsParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) Exists(ctx context.Context, arg ExistsParams) (bool, error) {
+	row := q.db.QueryRow(ctx, exists, arg.ID, arg.UserID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const findByUser = `-- name: FindByUser :many
+SELECT id, user_id, type, actor_id, post_id, comment_id, read_at, created_at FROM notifications
+WHERE user_id = $1 AND (NOT $2::bool OR read_at IS NULL)
+ORDER BY created_at DESC
+LIMIT $4 OFFSET $3
+`
+
+type FindByUserParams struct {
+	UserID     uuid.UUID
+	UnreadOnly bool
+	Skip       int32
+	Size       int32
+}
+
+func (q *Queries) FindByUser(ctx context.Context, arg FindByUserParams) ([]Notification, error) {
+	rows, err := q.db.Query(ctx, findByUser,
+		arg.UserID,
+		arg.UnreadOnly,
+		arg.Skip,
+		arg.Size,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Notification
+	for rows.Next() {
+		var i Notification
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Type,
+			&i.ActorID,
+			&i.PostID,
+			&i.CommentID,
+			&i.ReadAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markAllRead = `-- name: MarkAllRead :execrows
+UPDATE notifications SET read_at = now() WHERE user_id = $1 AND read_at IS NULL
+`
+
+func (q *Queries) MarkAllRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, markAllRead, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const markRead = `-- name: MarkRead :execrows
+UPDATE notifications SET read_at = now() WHERE id = $1 AND user_id = $2 AND read_at IS NULL
+`
+
+type MarkReadParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) MarkRead(ctx context.Context, arg MarkReadParams) (int64, error) {
+	result, err := q.db.Exec(ctx, markRead, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
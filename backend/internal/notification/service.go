@@ -0,0 +1,145 @@
+package notification
+
+import (
+	"backend/internal"
+	"backend/internal/database"
+	errorPkg "backend/internal/error"
+	"context"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+const (
+	TypeCommentOnPost  = "comment_on_post"
+	TypeReplyToComment = "reply_to_comment"
+	TypeMention        = "mention"
+	// TypeWarning is a moderator warning about the post or comment of the recipient
+	TypeWarning = "warning"
+)
+
+type Service struct {
+	logger *zap.Logger
+	tracer trace.Tracer
+	query  *Queries
+}
+
+func NewService(logger *zap.Logger, db DBTX) *Service {
+	return &Service{
+		logger: logger,
+		tracer: otel.Tracer("notification/service"),
+		query:  New(db),
+	}
+}
+
+// Notify creates a notification for recipientID about an action of actorID. Users are never notified about their
+// own actions. A zero commentID stores a notification that is only about the post.
+func (s *Service) Notify(ctx context.Context, recipientID, actorID uuid.UUID, notificationType string, postID, commentID uuid.UUID) error {
+	traceCtx, span := s.tracer.Start(ctx, "Notify")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	if recipientID == actorID {
+		return nil
+	}
+
+	n, err := s.query.Create(traceCtx, CreateParams{
+		UserID:    recipientID,
+		Type:      notificationType,
+		ActorID:   actorID,
+		PostID:    postID,
+		CommentID: pgtype.UUID{Bytes: commentID, Valid: commentID != uuid.Nil},
+	})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "create notification")
+		span.RecordError(err)
+		return err
+	}
+
+	logger.Debug("Created notification", zap.String("id", n.ID.String()), zap.String("user_id", recipientID.String()), zap.String("type", notificationType))
+	return nil
+}
+
+func (s *Service) GetByUser(ctx context.Context, userID uuid.UUID, unreadOnly bool, pagination internal.Pagination) ([]Notification, error) {
+	traceCtx, span := s.tracer.Start(ctx, "GetByUser")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	notifications, err := s.query.FindByUser(traceCtx, FindByUserParams{
+		UserID:     userID,
+		UnreadOnly: unreadOnly,
+		Skip:       pagination.Offset(),
+		Size:       pagination.Size,
+	})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "get notifications by user")
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+func (s *Service) CountUnread(ctx context.Context, userID uuid.UUID) (int64, error) {
+	traceCtx, span := s.tracer.Start(ctx, "CountUnread")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	count, err := s.query.CountUnread(traceCtx, userID)
+	if err != nil {
+		err = database.WrapDBError(err, logger, "count unread notifications")
+		span.RecordError(err)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// MarkRead marks a notification of the user as read, marking an already read notification is not an error
+func (s *Service) MarkRead(ctx context.Context, userID, id uuid.UUID) error {
+	traceCtx, span := s.tracer.Start(ctx, "MarkRead")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	count, err := s.query.MarkRead(traceCtx, MarkReadParams{ID: id, UserID: userID})
+	if err != nil {
+		err = database.WrapDBErrorWithKeyValue(err, "notifications", "id", id.String(), logger, "mark notification read")
+		span.RecordError(err)
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	exists, err := s.query.Exists(traceCtx, ExistsParams{ID: id, UserID: userID})
+	if err != nil {
+		err = database.WrapDBErrorWithKeyValue(err, "notifications", "id", id.String(), logger, "check notification exists")
+		span.RecordError(err)
+		return err
+	}
+	if !exists {
+		err = errorPkg.NewNotFoundError("notifications", "id", id.String(), "")
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *Service) MarkAllRead(ctx context.Context, userID uuid.UUID) error {
+	traceCtx, span := s.tracer.Start(ctx, "MarkAllRead")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	count, err := s.query.MarkAllRead(traceCtx, userID)
+	if err != nil {
+		err = database.WrapDBError(err, logger, "mark all notifications read")
+		span.RecordError(err)
+		return err
+	}
+
+	logger.Debug("Marked notifications read", zap.String("user_id", userID.String()), zap.Int64("affected_rows", count))
+	return nil
+}
@@ -0,0 +1,127 @@
+package internal
+
+import (
+	errorPkg "backend/internal/error"
+	"encoding/base64"
+	"fmt"
+	"github.com/google/uuid"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// Pagination is the limit and offset parsed from the page and size query parameters
+type Pagination struct {
+	Page int32
+	Size int32
+}
+
+func (p Pagination) Offset() int32 {
+	return (p.Page - 1) * p.Size
+}
+
+// ParsePagination reads the 1-based page and the size query parameters, falling back to the first page of
+// DefaultPageSize items. Sizes above MaxPageSize are rejected.
+func ParsePagination(r *http.Request) (Pagination, error) {
+	pagination := Pagination{Page: 1, Size: DefaultPageSize}
+
+	if value := r.URL.Query().Get("page"); value != "" {
+		page, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || page < 1 || page > math.MaxInt32/MaxPageSize {
+			return Pagination{}, fmt.Errorf("%w: page must be a positive integer", errorPkg.ErrInvalidQuery)
+		}
+		pagination.Page = int32(page)
+	}
+
+	size, err := parseSize(r)
+	if err != nil {
+		return Pagination{}, err
+	}
+	pagination.Size = size
+
+	return pagination, nil
+}
+
+// Cursor is the keyset position of an item in a list ordered by time and ID, it is handed to clients as an opaque
+// token
+type Cursor struct {
+	Time time.Time
+	ID   uuid.UUID
+}
+
+func (c Cursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(c.Time.UTC().Format(time.RFC3339Nano) + "," + c.ID.String()))
+}
+
+// CursorPagination is the position and size parsed from the cursor and size query parameters
+type CursorPagination struct {
+	// After is the cursor of the last item of the previous page, nil for the first page
+	After *Cursor
+	Size  int32
+}
+
+// ParseCursorPagination reads the cursor returned with the previous page and the size query parameter, falling back
+// to the first page of DefaultPageSize items.
+func ParseCursorPagination(r *http.Request) (CursorPagination, error) {
+	pagination := CursorPagination{}
+
+	if value := r.URL.Query().Get("cursor"); value != "" {
+		cursor, err := parseCursor(value)
+		if err != nil {
+			return CursorPagination{}, fmt.Errorf("%w: malformed cursor", errorPkg.ErrInvalidQuery)
+		}
+		pagination.After = &cursor
+	}
+
+	size, err := parseSize(r)
+	if err != nil {
+		return CursorPagination{}, err
+	}
+	pagination.Size = size
+
+	return pagination, nil
+}
+
+func parseCursor(value string) (Cursor, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return Cursor{}, err
+	}
+
+	timePart, idPart, found := strings.Cut(string(decoded), ",")
+	if !found {
+		return Cursor{}, fmt.Errorf("missing separator")
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, timePart)
+	if err != nil {
+		return Cursor{}, err
+	}
+	id, err := uuid.Parse(idPart)
+	if err != nil {
+		return Cursor{}, err
+	}
+
+	return Cursor{Time: t, ID: id}, nil
+}
+
+func parseSize(r *http.Request) (int32, error) {
+	value := r.URL.Query().Get("size")
+	if value == "" {
+		return DefaultPageSize, nil
+	}
+
+	size, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || size < 1 || size > MaxPageSize {
+		return 0, fmt.Errorf("%w: size must be between 1 and %d", errorPkg.ErrInvalidQuery, MaxPageSize)
+	}
+
+	return int32(size), nil
+}
@@ -0,0 +1,154 @@
+package internal_test
+
+import (
+	"backend/internal"
+	errorPkg "backend/internal/error"
+	"encoding/base64"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParsePagination(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    internal.Pagination
+		wantErr error
+	}{
+		{
+			name:  "Should default to the first page",
+			query: "",
+			want:  internal.Pagination{Page: 1, Size: internal.DefaultPageSize},
+		},
+		{
+			name:  "Should parse page and size",
+			query: "?page=3&size=50",
+			want:  internal.Pagination{Page: 3, Size: 50},
+		},
+		{
+			name:  "Should accept the maximum size",
+			query: "?size=100",
+			want:  internal.Pagination{Page: 1, Size: internal.MaxPageSize},
+		},
+		{
+			name:    "Should reject page zero",
+			query:   "?page=0",
+			wantErr: errorPkg.ErrInvalidQuery,
+		},
+		{
+			name:    "Should reject negative page",
+			query:   "?page=-1",
+			wantErr: errorPkg.ErrInvalidQuery,
+		},
+		{
+			name:    "Should reject page whose offset overflows",
+			query:   "?page=21474837",
+			wantErr: errorPkg.ErrInvalidQuery,
+		},
+		{
+			name:    "Should reject non numeric page",
+			query:   "?page=abc",
+			wantErr: errorPkg.ErrInvalidQuery,
+		},
+		{
+			name:    "Should reject size zero",
+			query:   "?size=0",
+			wantErr: errorPkg.ErrInvalidQuery,
+		},
+		{
+			name:    "Should reject size above the maximum",
+			query:   "?size=101",
+			wantErr: errorPkg.ErrInvalidQuery,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := internal.ParsePagination(httptest.NewRequest("GET", "/"+tt.query, nil))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParsePagination() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPagination_Offset(t *testing.T) {
+	assert.Equal(t, int32(0), internal.Pagination{Page: 1, Size: 20}.Offset())
+	assert.Equal(t, int32(40), internal.Pagination{Page: 3, Size: 20}.Offset())
+}
+
+func TestParseCursorPagination(t *testing.T) {
+	cursor := internal.Cursor{
+		Time: time.Date(2024, 5, 1, 12, 30, 0, 123456789, time.FixedZone("CEST", 2*60*60)),
+		ID:   uuid.MustParse("54a46af2-b454-4746-8ab0-3cf26085a50b"),
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		want     internal.CursorPagination
+		wantTime time.Time
+		wantErr  error
+	}{
+		{
+			name:  "Should default to the first page",
+			query: "",
+			want:  internal.CursorPagination{Size: internal.DefaultPageSize},
+		},
+		{
+			name:     "Should parse the cursor it returned",
+			query:    "?size=10&cursor=" + cursor.String(),
+			want:     internal.CursorPagination{Size: 10},
+			wantTime: cursor.Time,
+		},
+		{
+			name:    "Should reject cursor that is not base64",
+			query:   "?cursor=!!!",
+			wantErr: errorPkg.ErrInvalidQuery,
+		},
+		{
+			name:    "Should reject cursor without separator",
+			query:   "?cursor=" + base64.RawURLEncoding.EncodeToString([]byte("2024-05-01T12:30:00Z")),
+			wantErr: errorPkg.ErrInvalidQuery,
+		},
+		{
+			name:    "Should reject cursor with malformed time",
+			query:   "?cursor=" + base64.RawURLEncoding.EncodeToString([]byte("yesterday,"+cursor.ID.String())),
+			wantErr: errorPkg.ErrInvalidQuery,
+		},
+		{
+			name:    "Should reject cursor with malformed id",
+			query:   "?cursor=" + base64.RawURLEncoding.EncodeToString([]byte("2024-05-01T12:30:00Z,nope")),
+			wantErr: errorPkg.ErrInvalidQuery,
+		},
+		{
+			name:    "Should reject size above the maximum",
+			query:   "?size=101&cursor=" + cursor.String(),
+			wantErr: errorPkg.ErrInvalidQuery,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := internal.ParseCursorPagination(httptest.NewRequest("GET", "/"+tt.query, nil))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseCursorPagination() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantTime.IsZero() {
+				assert.Equal(t, tt.want, got)
+				return
+			}
+			if got.After == nil {
+				t.Fatalf("ParseCursorPagination() After = nil, want %v", cursor)
+			}
+			assert.Equal(t, tt.want.Size, got.Size)
+			assert.True(t, tt.wantTime.Equal(got.After.Time), "time %v, want %v", got.After.Time, tt.wantTime)
+			assert.Equal(t, cursor.ID, got.After.ID)
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package poll
+
+import (
+	"backend/internal"
+	errorPkg "backend/internal/error"
+	"backend/internal/jwt"
+	"backend/internal/problem"
+	"context"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+type CreateRequest struct {
+	Question       string   `json:"question"        validate:"required,max=300"`
+	Options        []string `json:"options"         validate:"required,min=2,max=20,dive,required,max=200"`
+	MultipleChoice bool     `json:"multiple_choice"`
+	// ResultsVisibility defaults to always
+	ResultsVisibility string     `json:"results_visibility" validate:"omitempty,oneof=always after_vote after_close"`
+	ClosesAt          *time.Time `json:"closes_at,omitempty"`
+}
+
+type VoteRequest struct {
+	OptionIDs []uuid.UUID `json:"option_ids" validate:"required,min=1,max=20"`
+}
+
+type OptionResponse struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+	// Votes is omitted while the results are hidden from the user
+	Votes *int64 `json:"votes,omitempty"`
+}
+
+type Response struct {
+	ID                string           `json:"id"`
+	PostID            string           `json:"post_id"`
+	Question          string           `json:"question"`
+	MultipleChoice    bool             `json:"multiple_choice"`
+	ResultsVisibility string           `json:"results_visibility"`
+	ClosesAt          string           `json:"closes_at,omitempty"`
+	Closed            bool             `json:"closed"`
+	Options           []OptionResponse `json:"options"`
+	// Voted are the option IDs the current user voted for
+	Voted          []string `json:"voted"`
+	ResultsVisible bool     `json:"results_visible"`
+	// TotalVoters is omitted while the results are hidden from the user
+	TotalVoters *int64 `json:"total_voters,omitempty"`
+	CreatedAt   string `json:"created_at"`
+}
+
+//go:generate mockery --name=Store
+type Store interface {
+	Create(ctx context.Context, authorID, postID uuid.UUID, request CreateRequest) (Result, error)
+	Get(ctx context.Context, userID, postID uuid.UUID) (Result, error)
+	Vote(ctx context.Context, userID, postID uuid.UUID, request VoteRequest) (Result, error)
+}
+
+type Handler struct {
+	validator *validator.Validate
+	logger    *zap.Logger
+	tracer    trace.Tracer
+	store     Store
+}
+
+func NewHandler(v *validator.Validate, logger *zap.Logger, store Store) *Handler {
+	return &Handler{
+		validator: v,
+		logger:    logger,
+		tracer:    otel.Tracer("poll/handler"),
+		store:     store,
+	}
+}
+
+// CreateHandler attaches a poll to a post of the current user
+func (h *Handler) CreateHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "CreatePollEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	userID, postID, err := parseRequest(traceCtx, r)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	var request CreateRequest
+	err = internal.ParseAndValidateRequestBody(traceCtx, h.validator, r, &request)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	result, err := h.store.Create(traceCtx, userID, postID, request)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	internal.WriteJSONResponse(w, http.StatusCreated, GenerateResponse(result))
+}
+
+func (h *Handler) GetHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "GetPollEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	userID, postID, err := parseRequest(traceCtx, r)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	result, err := h.store.Get(traceCtx, userID, postID)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	internal.WriteJSONResponse(w, http.StatusOK, GenerateResponse(result))
+}
+
+// VoteHandler casts the vote of the current user and responds with the poll including the results if visible
+func (h *Handler) VoteHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "VotePollEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	userID, postID, err := parseRequest(traceCtx, r)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	var request VoteRequest
+	err = internal.ParseAndValidateRequestBody(traceCtx, h.validator, r, &request)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	result, err := h.store.Vote(traceCtx, userID, postID, request)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	internal.WriteJSONResponse(w, http.StatusOK, GenerateResponse(result))
+}
+
+// parseRequest returns the current user and the post in the path
+func parseRequest(ctx context.Context, r *http.Request) (uuid.UUID, uuid.UUID, error) {
+	postID, err := internal.ParseUUID(r.PathValue("id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("%w: %v", errorPkg.ErrInvalidUUID, err)
+	}
+
+	user, err := jwt.GetUserFromContext(ctx)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	userID, err := internal.ParseUUID(user.ID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	return userID, postID, nil
+}
+
+func GenerateResponse(result Result) Response {
+	poll := result.Poll
+	response := Response{
+		ID:                poll.ID.String(),
+		PostID:            poll.PostID.String(),
+		Question:          poll.Question,
+		MultipleChoice:    poll.MultipleChoice,
+		ResultsVisibility: poll.ResultsVisibility,
+		Closed:            poll.Closed(time.Now()),
+		Options:           make([]OptionResponse, len(result.Options)),
+		Voted:             make([]string, len(result.Voted)),
+		ResultsVisible:    result.ResultsVisible,
+		CreatedAt:         poll.CreatedAt.Time.Format(time.RFC3339),
+	}
+	if poll.ClosesAt.Valid {
+		response.ClosesAt = poll.ClosesAt.Time.Format(time.RFC3339)
+	}
+
+	for i, option := range result.Options {
+		response.Options[i] = OptionResponse{ID: option.ID.String(), Label: option.Label}
+		if result.ResultsVisible {
+			votes := result.Votes[option.ID]
+			response.Options[i].Votes = &votes
+		}
+	}
+	for i, id := range result.Voted {
+		response.Voted[i] = id.String()
+	}
+	if result.ResultsVisible {
+		ballots := result.Ballots
+		response.TotalVoters = &ballots
+	}
+
+	return response
+}
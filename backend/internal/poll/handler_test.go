@@ -0,0 +1,147 @@
+package poll_test
+
+import (
+	"backend/internal"
+	errorPkg "backend/internal/error"
+	"backend/internal/jwt"
+	"backend/internal/poll"
+	"backend/internal/poll/mocks"
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var (
+	voter = jwt.User{
+		ID:       "81c1ecc1-66d7-4134-b5fe-d886a6f418a4",
+		Username: "voter",
+		Role:     jwt.RoleUser,
+	}
+	postID  = uuid.MustParse("1f0b3c8e-3b0a-4d8e-9f3e-9b1b0f6b7c11")
+	yesID   = uuid.MustParse("2a7d1b7e-6c53-4f6b-8d9e-0c1b2a3d4e5f")
+	noID    = uuid.MustParse("3b8e2c8f-7d64-4a7c-9e0f-1d2c3b4e5f60")
+	options = []poll.PollOption{{ID: yesID, Label: "Yes"}, {ID: noID, Label: "No", Position: 1}}
+)
+
+func TestHandler_VoteHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		setupMock  func(m *mocks.Store)
+		wantStatus int
+	}{
+		{
+			name: "Should vote",
+			body: `{"option_ids":["` + yesID.String() + `"]}`,
+			setupMock: func(m *mocks.Store) {
+				m.On("Vote", mock.Anything, uuid.MustParse(voter.ID), postID, poll.VoteRequest{OptionIDs: []uuid.UUID{yesID}}).
+					Return(poll.Result{Options: options, Voted: []uuid.UUID{yesID}, ResultsVisible: true, Votes: map[uuid.UUID]int64{yesID: 1}, Ballots: 1}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "Should reject vote without options",
+			body:       `{"option_ids":[]}`,
+			setupMock:  func(m *mocks.Store) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Should reject second vote",
+			body: `{"option_ids":["` + noID.String() + `"]}`,
+			setupMock: func(m *mocks.Store) {
+				m.On("Vote", mock.Anything, uuid.MustParse(voter.ID), postID, poll.VoteRequest{OptionIDs: []uuid.UUID{noID}}).
+					Return(poll.Result{}, errorPkg.ErrAlreadyVoted)
+			},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name: "Should reject vote in closed poll",
+			body: `{"option_ids":["` + noID.String() + `"]}`,
+			setupMock: func(m *mocks.Store) {
+				m.On("Vote", mock.Anything, uuid.MustParse(voter.ID), postID, poll.VoteRequest{OptionIDs: []uuid.UUID{noID}}).
+					Return(poll.Result{}, errorPkg.ErrPollClosed)
+			},
+			wantStatus: http.StatusLocked,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mocks.NewStore(t)
+			tt.setupMock(m)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/api/post/"+postID.String()+"/poll/vote", strings.NewReader(tt.body))
+			r.SetPathValue("id", postID.String())
+			r = r.WithContext(context.WithValue(r.Context(), internal.UserContextKey, voter))
+
+			h := poll.NewHandler(internal.NewValidator(), zap.NewNop(), m)
+			h.VoteHandler(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestHandler_GetHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		result      poll.Result
+		wantVotes   []*int64
+		wantVoters  *int64
+		wantVisible bool
+	}{
+		{
+			name:        "Should hide results",
+			result:      poll.Result{Poll: poll.Poll{ResultsVisibility: poll.ResultsAfterVote}, Options: options},
+			wantVotes:   []*int64{nil, nil},
+			wantVisible: false,
+		},
+		{
+			name: "Should show results including options without votes",
+			result: poll.Result{Options: options, Voted: []uuid.UUID{yesID}, ResultsVisible: true,
+				Votes: map[uuid.UUID]int64{yesID: 3}, Ballots: 3},
+			wantVotes:   []*int64{ptr(int64(3)), ptr(int64(0))},
+			wantVoters:  ptr(int64(3)),
+			wantVisible: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mocks.NewStore(t)
+			m.On("Get", mock.Anything, uuid.MustParse(voter.ID), postID).Return(tt.result, nil)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/api/post/"+postID.String()+"/poll", nil)
+			r.SetPathValue("id", postID.String())
+			r = r.WithContext(context.WithValue(r.Context(), internal.UserContextKey, voter))
+
+			h := poll.NewHandler(internal.NewValidator(), zap.NewNop(), m)
+			h.GetHandler(w, r)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var response poll.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			assert.Equal(t, tt.wantVisible, response.ResultsVisible)
+			assert.Equal(t, tt.wantVoters, response.TotalVoters)
+			for i, option := range response.Options {
+				assert.Equal(t, tt.wantVotes[i], option.Votes)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
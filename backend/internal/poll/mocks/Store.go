@@ -0,0 +1,115 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	poll "backend/internal/poll"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// Store is an autogenerated mock type for the Store type
+type Store struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, authorID, postID, request
+func (_m *Store) Create(ctx context.Context, authorID uuid.UUID, postID uuid.UUID, request poll.CreateRequest) (poll.Result, error) {
+	ret := _m.Called(ctx, authorID, postID, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 poll.Result
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, poll.CreateRequest) (poll.Result, error)); ok {
+		return rf(ctx, authorID, postID, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, poll.CreateRequest) poll.Result); ok {
+		r0 = rf(ctx, authorID, postID, request)
+	} else {
+		r0 = ret.Get(0).(poll.Result)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID, poll.CreateRequest) error); ok {
+		r1 = rf(ctx, authorID, postID, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Get provides a mock function with given fields: ctx, userID, postID
+func (_m *Store) Get(ctx context.Context, userID uuid.UUID, postID uuid.UUID) (poll.Result, error) {
+	ret := _m.Called(ctx, userID, postID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 poll.Result
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) (poll.Result, error)); ok {
+		return rf(ctx, userID, postID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) poll.Result); ok {
+		r0 = rf(ctx, userID, postID)
+	} else {
+		r0 = ret.Get(0).(poll.Result)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID, postID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Vote provides a mock function with given fields: ctx, userID, postID, request
+func (_m *Store) Vote(ctx context.Context, userID uuid.UUID, postID uuid.UUID, request poll.VoteRequest) (poll.Result, error) {
+	ret := _m.Called(ctx, userID, postID, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Vote")
+	}
+
+	var r0 poll.Result
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, poll.VoteRequest) (poll.Result, error)); ok {
+		return rf(ctx, userID, postID, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, poll.VoteRequest) poll.Result); ok {
+		r0 = rf(ctx, userID, postID, request)
+	} else {
+		r0 = ret.Get(0).(poll.Result)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID, poll.VoteRequest) error); ok {
+		r1 = rf(ctx, userID, postID, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewStore creates a new instance of Store. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Store {
+	mock := &Store{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
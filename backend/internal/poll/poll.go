@@ -0,0 +1,41 @@
+package poll
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// Result visibilities of polls
+const (
+	ResultsAlways     = "always"
+	ResultsAfterVote  = "after_vote"
+	ResultsAfterClose = "after_close"
+)
+
+// Result is a poll as seen by one user, Votes and Ballots are only set while the results are visible to the user
+type Result struct {
+	Poll    Poll
+	Options []PollOption
+	// Voted are the options the user voted for, empty before the user voted
+	Voted          []uuid.UUID
+	ResultsVisible bool
+	Votes          map[uuid.UUID]int64
+	Ballots        int64
+}
+
+// Closed reports whether the poll stopped accepting votes at now
+func (p Poll) Closed(now time.Time) bool {
+	return p.ClosesAt.Valid && !now.Before(p.ClosesAt.Time)
+}
+
+// ResultsVisible reports whether a user who voted or did not vote yet may see the results at now
+func (p Poll) ResultsVisible(voted bool, now time.Time) bool {
+	switch p.ResultsVisibility {
+	case ResultsAfterVote:
+		return voted || p.Closed(now)
+	case ResultsAfterClose:
+		return p.Closed(now)
+	default:
+		return true
+	}
+}
@@ -0,0 +1,46 @@
+package poll_test
+
+import (
+	"backend/internal/poll"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestPoll_ResultsVisible(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	open := pgtype.Timestamptz{Time: now.Add(time.Hour), Valid: true}
+	closed := pgtype.Timestamptz{Time: now.Add(-time.Hour), Valid: true}
+
+	tests := []struct {
+		name       string
+		visibility string
+		closesAt   pgtype.Timestamptz
+		voted      bool
+		want       bool
+	}{
+		{name: "Should always show results", visibility: poll.ResultsAlways, closesAt: open, want: true},
+		{name: "Should hide results before voting", visibility: poll.ResultsAfterVote, closesAt: open, want: false},
+		{name: "Should show results after voting", visibility: poll.ResultsAfterVote, closesAt: open, voted: true, want: true},
+		{name: "Should show results of closed poll without vote", visibility: poll.ResultsAfterVote, closesAt: closed, want: true},
+		{name: "Should hide results of open poll after voting", visibility: poll.ResultsAfterClose, closesAt: open, voted: true, want: false},
+		{name: "Should hide results of poll without close time", visibility: poll.ResultsAfterClose, voted: true, want: false},
+		{name: "Should show results of closed poll", visibility: poll.ResultsAfterClose, closesAt: closed, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := poll.Poll{ResultsVisibility: tt.visibility, ClosesAt: tt.closesAt}
+			assert.Equal(t, tt.want, p.ResultsVisible(tt.voted, now))
+		})
+	}
+}
+
+func TestPoll_Closed(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.False(t, poll.Poll{}.Closed(now), "polls without close time never close")
+	assert.False(t, poll.Poll{ClosesAt: pgtype.Timestamptz{Time: now.Add(time.Second), Valid: true}}.Closed(now))
+	assert.True(t, poll.Poll{ClosesAt: pgtype.Timestamptz{Time: now, Valid: true}}.Closed(now))
+}
@@ -0,0 +1,34 @@
+-- name: Create :one
+INSERT INTO polls (post_id, question, multiple_choice, results_visibility, closes_at)
+VALUES (@post_id, @question, @multiple_choice, @results_visibility, @closes_at)
+RETURNING *;
+
+-- name: CreateOption :one
+INSERT INTO poll_options (poll_id, position, label)
+VALUES (@poll_id, @position, @label)
+RETURNING *;
+
+-- name: FindByPost :one
+SELECT * FROM polls WHERE post_id = $1;
+
+-- name: FindOptions :many
+SELECT * FROM poll_options WHERE poll_id = $1 ORDER BY position;
+
+-- name: CountVotes :many
+-- Votes per option, options without votes are missing
+SELECT option_id, count(*) AS votes FROM poll_votes WHERE poll_id = $1 GROUP BY option_id;
+
+-- name: CountBallots :one
+SELECT count(*) FROM poll_ballots WHERE poll_id = $1;
+
+-- name: FindVotesByUser :many
+SELECT option_id FROM poll_votes WHERE poll_id = @poll_id AND user_id = @user_id;
+
+-- name: CreateBallot :exec
+INSERT INTO poll_ballots (poll_id, user_id) VALUES (@poll_id, @user_id);
+
+-- name: CreateVote :exec
+INSERT INTO poll_votes (poll_id, user_id, option_id) VALUES (@poll_id, @user_id, @option_id);
+
+-- name: FindPost :one
+SELECT id, author_id, archived_at, status FROM posts WHERE id = $1 AND hidden_at IS NULL;
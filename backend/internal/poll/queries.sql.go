@@ -0,0 +1,242 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: queries.sql
+
+package poll
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const countBallots = `-- name: CountBallots :one
+SELECT count(*) FROM poll_ballots WHERE poll_id = $1
+`
+
+func (q *Queries) CountBallots(ctx context.Context, pollID uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countBallots, pollID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countVotes = `-- name: CountVotes :many
+SELECT option_id, count(*) AS votes FROM poll_votes WHERE poll_id = $1 GROUP BY option_id
+`
+
+type CountVotesRow struct {
+	OptionID uuid.UUID
+	Votes    int64
+}
+
+// Votes per option, options without votes are missing
+func (q *Queries) CountVotes(ctx context.Context, pollID uuid.UUID) ([]CountVotesRow, error) {
+	rows, err := q.db.Query(ctx, countVotes, pollID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountVotesRow
+	for rows.Next() {
+		var i CountVotesRow
+		if err := rows.Scan(&i.OptionID, &i.Votes); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const create = `-- name: Create :one
+INSERT INTO polls (post_id, question, multiple_choice, results_visibility, closes_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, post_id, question, multiple_choice, results_visibility, closes_at, created_at
+`
+
+type CreateParams struct {
+	PostID            uuid.UUID
+	Question          string
+	MultipleChoice    bool
+	ResultsVisibility string
+	ClosesAt          pgtype.Timestamptz
+}
+
+func (q *Queries) Create(ctx context.Context, arg CreateParams) (Poll, error) {
+	row := q.db.QueryRow(ctx, create,
+		arg.PostID,
+		arg.Question,
+		arg.MultipleChoice,
+		arg.ResultsVisibility,
+		arg.ClosesAt,
+	)
+	var i Poll
+	err := row.Scan(
+		&i.ID,
+		&i.PostID,
+		&i.Question,
+		&i.MultipleChoice,
+		&i.ResultsVisibility,
+		&i.ClosesAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createBallot = `-- name: CreateBallot :exec
+INSERT INTO poll_ballots (poll_id, user_id) VALUES ($1, $2)
+`
+
+type CreateBallotParams struct {
+	PollID uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) CreateBallot(ctx context.Context, arg CreateBallotParams) error {
+	_, err := q.db.Exec(ctx, createBallot, arg.PollID, arg.UserID)
+	return err
+}
+
+const createOption = `-- name: CreateOption :one
+INSERT INTO poll_options (poll_id, position, label)
+VALUES ($1, $2, $3)
+RETURNING id, poll_id, position, label
+`
+
+type CreateOptionParams struct {
+	PollID   uuid.UUID
+	Position int32
+	Label    string
+}
+
+func (q *Queries) CreateOption(ctx context.Context, arg CreateOptionParams) (PollOption, error) {
+	row := q.db.QueryRow(ctx, createOption, arg.PollID, arg.Position, arg.Label)
+	var i PollOption
+	err := row.Scan(
+		&i.ID,
+		&i.PollID,
+		&i.Position,
+		&i.Label,
+	)
+	return i, err
+}
+
+const createVote = `-- name: CreateVote :exec
+INSERT INTO poll_votes (poll_id, user_id, option_id) VALUES ($1, $2, $3)
+`
+
+type CreateVoteParams struct {
+	PollID   uuid.UUID
+	UserID   uuid.UUID
+	OptionID uuid.UUID
+}
+
+func (q *Queries) CreateVote(ctx context.Context, arg CreateVoteParams) error {
+	_, err := q.db.Exec(ctx, createVote, arg.PollID, arg.UserID, arg.OptionID)
+	return err
+}
+
+const findByPost = `-- name: FindByPost :one
+SELECT id, post_id, question, multiple_choice, results_visibility, closes_at, created_at FROM polls WHERE post_id = $1
+`
+
+func (q *Queries) FindByPost(ctx context.Context, postID uuid.UUID) (Poll, error) {
+	row := q.db.QueryRow(ctx, findByPost, postID)
+	var i Poll
+	err := row.Scan(
+		&i.ID,
+		&i.PostID,
+		&i.Question,
+		&i.MultipleChoice,
+		&i.ResultsVisibility,
+		&i.ClosesAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const findOptions = `-- name: FindOptions :many
+SELECT id, poll_id, position, label FROM poll_options WHERE poll_id = $1 ORDER BY position
+`
+
+func (q *Queries) FindOptions(ctx context.Context, pollID uuid.UUID) ([]PollOption, error) {
+	rows, err := q.db.Query(ctx, findOptions, pollID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PollOption
+	for rows.Next() {
+		var i PollOption
+		if err := rows.Scan(
+			&i.ID,
+			&i.PollID,
+			&i.Position,
+			&i.Label,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findPost = `-- name: FindPost :one
+SELECT id, author_id, archived_at, status FROM posts WHERE id = $1 AND hidden_at IS NULL
+`
+
+type FindPostRow struct {
+	ID         uuid.UUID
+	AuthorID   uuid.UUID
+	ArchivedAt pgtype.Timestamptz
+	Status     string
+}
+
+func (q *Queries) FindPost(ctx context.Context, id uuid.UUID) (FindPostRow, error) {
+	row := q.db.QueryRow(ctx, findPost, id)
+	var i FindPostRow
+	err := row.Scan(
+		&i.ID,
+		&i.AuthorID,
+		&i.ArchivedAt,
+		&i.Status,
+	)
+	return i, err
+}
+
+const findVotesByUser = `-- name: FindVotesByUser :many
+SELECT option_id FROM poll_votes WHERE poll_id = $1 AND user_id = $2
+`
+
+type FindVotesByUserParams struct {
+	PollID uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) FindVotesByUser(ctx context.Context, arg FindVotesByUserParams) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, findVotesByUser, arg.PollID, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var option_id uuid.UUID
+		if err := rows.Scan(&option_id); err != nil {
+			return nil, err
+		}
+		items = append(items, option_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,286 @@
+package poll
+
+import (
+	"backend/internal"
+	"backend/internal/database"
+	errorPkg "backend/internal/error"
+	postPkg "backend/internal/post"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"slices"
+	"time"
+)
+
+type Service struct {
+	logger *zap.Logger
+	tracer trace.Tracer
+	pool   *pgxpool.Pool
+	query  *Queries
+}
+
+func NewService(logger *zap.Logger, pool *pgxpool.Pool) *Service {
+	return &Service{
+		logger: logger,
+		tracer: otel.Tracer("poll/service"),
+		pool:   pool,
+		query:  New(pool),
+	}
+}
+
+// Create attaches a poll to a post of the author, a post carries at most one poll
+func (s *Service) Create(ctx context.Context, authorID, postID uuid.UUID, r CreateRequest) (Result, error) {
+	traceCtx, span := s.tracer.Start(ctx, "Create")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	post, err := s.query.FindPost(traceCtx, postID)
+	if err != nil {
+		err = database.WrapDBErrorWithKeyValue(err, "post", "id", postID.String(), logger, "get post of poll")
+		span.RecordError(err)
+		return Result{}, err
+	}
+	if post.AuthorID != authorID {
+		err = errorPkg.ErrForbidden
+		span.RecordError(err)
+		return Result{}, err
+	}
+	if post.ArchivedAt.Valid {
+		err = errorPkg.ErrPostArchived
+		span.RecordError(err)
+		return Result{}, err
+	}
+	if r.ClosesAt != nil && !r.ClosesAt.After(time.Now()) {
+		err = fmt.Errorf("%w: closes_at must be in the future", errorPkg.ErrInvalidRequest)
+		span.RecordError(err)
+		return Result{}, err
+	}
+
+	visibility := r.ResultsVisibility
+	if visibility == "" {
+		visibility = ResultsAlways
+	}
+	closesAt := pgtype.Timestamptz{}
+	if r.ClosesAt != nil {
+		closesAt = pgtype.Timestamptz{Time: *r.ClosesAt, Valid: true}
+	}
+
+	tx, err := s.pool.Begin(traceCtx)
+	if err != nil {
+		err = database.WrapDBError(err, logger, "begin poll transaction")
+		span.RecordError(err)
+		return Result{}, err
+	}
+	defer func() {
+		_ = tx.Rollback(context.WithoutCancel(traceCtx))
+	}()
+	query := s.query.WithTx(tx)
+
+	poll, err := query.Create(traceCtx, CreateParams{
+		PostID:            postID,
+		Question:          r.Question,
+		MultipleChoice:    r.MultipleChoice,
+		ResultsVisibility: visibility,
+		ClosesAt:          closesAt,
+	})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "create poll")
+		span.RecordError(err)
+		return Result{}, err
+	}
+
+	options := make([]PollOption, len(r.Options))
+	for i, label := range r.Options {
+		options[i], err = query.CreateOption(traceCtx, CreateOptionParams{PollID: poll.ID, Position: int32(i), Label: label})
+		if err != nil {
+			err = database.WrapDBError(err, logger, "create poll option")
+			span.RecordError(err)
+			return Result{}, err
+		}
+	}
+
+	err = tx.Commit(traceCtx)
+	if err != nil {
+		err = database.WrapDBError(err, logger, "commit poll")
+		span.RecordError(err)
+		return Result{}, err
+	}
+
+	logger.Info("Created poll", zap.String("id", poll.ID.String()), zap.String("post_id", postID.String()))
+	return s.result(traceCtx, authorID, poll, options)
+}
+
+// Get returns the poll of a post as seen by the user
+func (s *Service) Get(ctx context.Context, userID, postID uuid.UUID) (Result, error) {
+	traceCtx, span := s.tracer.Start(ctx, "Get")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	poll, err := s.findByPost(traceCtx, userID, postID)
+	if err != nil {
+		span.RecordError(err)
+		return Result{}, err
+	}
+
+	options, err := s.query.FindOptions(traceCtx, poll.ID)
+	if err != nil {
+		err = database.WrapDBError(err, logger, "get poll options")
+		span.RecordError(err)
+		return Result{}, err
+	}
+
+	return s.result(traceCtx, userID, poll, options)
+}
+
+// Vote casts the single ballot of the user, which holds exactly one option or, in multiple choice polls, several
+func (s *Service) Vote(ctx context.Context, userID, postID uuid.UUID, r VoteRequest) (Result, error) {
+	traceCtx, span := s.tracer.Start(ctx, "Vote")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	post, err := s.query.FindPost(traceCtx, postID)
+	if err != nil {
+		err = database.WrapDBErrorWithKeyValue(err, "post", "id", postID.String(), logger, "get post of poll")
+		span.RecordError(err)
+		return Result{}, err
+	}
+	if post.Status != postPkg.StatusPublished {
+		// votes in polls of drafts would be lost to the author
+		err = errorPkg.NewNotFoundError("post", "id", postID.String(), "")
+		span.RecordError(err)
+		return Result{}, err
+	}
+	if post.ArchivedAt.Valid {
+		err = errorPkg.ErrPostArchived
+		span.RecordError(err)
+		return Result{}, err
+	}
+
+	poll, err := s.findByPost(traceCtx, userID, postID)
+	if err != nil {
+		span.RecordError(err)
+		return Result{}, err
+	}
+	if poll.Closed(time.Now()) {
+		err = errorPkg.ErrPollClosed
+		span.RecordError(err)
+		return Result{}, err
+	}
+
+	var optionIDs []uuid.UUID
+	for _, id := range r.OptionIDs {
+		if !slices.Contains(optionIDs, id) {
+			optionIDs = append(optionIDs, id)
+		}
+	}
+	if !poll.MultipleChoice && len(optionIDs) != 1 {
+		err = fmt.Errorf("%w: the poll allows only one option", errorPkg.ErrInvalidRequest)
+		span.RecordError(err)
+		return Result{}, err
+	}
+
+	tx, err := s.pool.Begin(traceCtx)
+	if err != nil {
+		err = database.WrapDBError(err, logger, "begin vote transaction")
+		span.RecordError(err)
+		return Result{}, err
+	}
+	defer func() {
+		_ = tx.Rollback(context.WithoutCancel(traceCtx))
+	}()
+	query := s.query.WithTx(tx)
+
+	// the primary key of the ballot allows one vote per user
+	err = query.CreateBallot(traceCtx, CreateBallotParams{PollID: poll.ID, UserID: userID})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "create poll ballot")
+		if errors.Is(err, database.ErrUniqueViolation) {
+			err = errorPkg.ErrAlreadyVoted
+		}
+		span.RecordError(err)
+		return Result{}, err
+	}
+
+	for _, optionID := range optionIDs {
+		err = query.CreateVote(traceCtx, CreateVoteParams{PollID: poll.ID, UserID: userID, OptionID: optionID})
+		if err != nil {
+			err = database.WrapDBError(err, logger, "create poll vote")
+			if errors.Is(err, database.ErrForeignKeyViolation) {
+				err = fmt.Errorf("%w: option %s does not belong to the poll", errorPkg.ErrInvalidRequest, optionID)
+			}
+			span.RecordError(err)
+			return Result{}, err
+		}
+	}
+
+	err = tx.Commit(traceCtx)
+	if err != nil {
+		err = database.WrapDBError(err, logger, "commit vote")
+		span.RecordError(err)
+		return Result{}, err
+	}
+
+	logger.Info("Voted in poll", zap.String("id", poll.ID.String()), zap.Int("options", len(optionIDs)))
+	return s.Get(traceCtx, userID, postID)
+}
+
+// findByPost returns the poll of a post visible to the user, polls of hidden posts and of drafts of other users are
+// hidden as well
+func (s *Service) findByPost(ctx context.Context, userID, postID uuid.UUID) (Poll, error) {
+	logger := internal.LoggerWithContext(ctx, s.logger)
+
+	post, err := s.query.FindPost(ctx, postID)
+	if err != nil {
+		return Poll{}, database.WrapDBErrorWithKeyValue(err, "post", "id", postID.String(), logger, "get post of poll")
+	}
+	if post.Status != postPkg.StatusPublished && post.AuthorID != userID {
+		return Poll{}, errorPkg.NewNotFoundError("post", "id", postID.String(), "")
+	}
+
+	poll, err := s.query.FindByPost(ctx, postID)
+	if err != nil {
+		return Poll{}, database.WrapDBErrorWithKeyValue(err, "poll", "post_id", postID.String(), logger, "get poll by post")
+	}
+
+	return poll, nil
+}
+
+// result loads the choices of the user and, if visible to the user, the vote counts
+func (s *Service) result(ctx context.Context, userID uuid.UUID, poll Poll, options []PollOption) (Result, error) {
+	logger := internal.LoggerWithContext(ctx, s.logger)
+
+	result := Result{Poll: poll, Options: options}
+
+	var err error
+	result.Voted, err = s.query.FindVotesByUser(ctx, FindVotesByUserParams{PollID: poll.ID, UserID: userID})
+	if err != nil {
+		return Result{}, database.WrapDBError(err, logger, "get votes of user")
+	}
+
+	result.ResultsVisible = poll.ResultsVisible(len(result.Voted) > 0, time.Now())
+	if !result.ResultsVisible {
+		return result, nil
+	}
+
+	counts, err := s.query.CountVotes(ctx, poll.ID)
+	if err != nil {
+		return Result{}, database.WrapDBError(err, logger, "count poll votes")
+	}
+	result.Votes = make(map[uuid.UUID]int64, len(counts))
+	for _, count := range counts {
+		result.Votes[count.OptionID] = count.Votes
+	}
+
+	result.Ballots, err = s.query.CountBallots(ctx, poll.ID)
+	if err != nil {
+		return Result{}, database.WrapDBError(err, logger, "count poll ballots")
+	}
+
+	return result, nil
+}
@@ -2,9 +2,13 @@ package post
 
 import (
 	"backend/internal"
+	errorPkg "backend/internal/error"
 	"backend/internal/jwt"
+	"backend/internal/markdown"
+	"backend/internal/mention"
 	"backend/internal/problem"
 	"context"
+	"fmt"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"go.opentelemetry.io/otel"
@@ -15,24 +19,89 @@ import (
 )
 
 type CreateRequest struct {
-	AuthorID uuid.UUID `json:"author_id"`
-	Title    string    `json:"title"   validate:"required"`
-	Content  string    `json:"content" validate:"required"`
+	AuthorID uuid.UUID  `json:"author_id"`
+	BoardID  *uuid.UUID `json:"board_id,omitempty"`
+	Title    string     `json:"title"   validate:"required"`
+	Content  string     `json:"content" validate:"required"`
+	// Draft saves the post for its author only, PublishAt schedules the publication
+	Draft     bool       `json:"draft"`
+	PublishAt *time.Time `json:"publish_at,omitempty"`
+	// Tags list the post in the feed of each tag
+	Tags []string `json:"tags,omitempty" validate:"max=10,dive,max=32,slug"`
+}
+
+type UpdateRequest struct {
+	Title   string `json:"title"   validate:"required"`
+	Content string `json:"content" validate:"required"`
+	// Tags replace the tags of the post, the tags are kept if the field is missing and removed by an empty list
+	Tags []string `json:"tags" validate:"max=10,dive,max=32,slug"`
+}
+
+// PublishRequest publishes a draft or scheduled post now, or at PublishAt if given
+type PublishRequest struct {
+	PublishAt *time.Time `json:"publish_at,omitempty"`
+}
+
+// PinRequest pins a post globally or only within its board
+type PinRequest struct {
+	Scope string `json:"scope" validate:"required,oneof=global board"`
 }
 
 type Response struct {
 	ID       string `json:"id"`
 	AuthorID string `json:"author_id"`
+	BoardID  string `json:"board_id,omitempty"`
 	Title    string `json:"title"`
 	Content  string `json:"content"`
 	CreateAt string `json:"create_at"`
+	// Status is draft, scheduled or published, only authors see their unpublished posts
+	Status    string   `json:"status"`
+	PublishAt string   `json:"publish_at,omitempty"`
+	Tags      []string `json:"tags"`
+	// Rendered is Content in the format of the render query parameter, html or ansi
+	Rendered string `json:"rendered,omitempty"`
+
+	// Locked posts accept no new comments, archived posts are read-only
+	Locked   bool `json:"locked"`
+	Archived bool `json:"archived"`
+	// Pinned is the pin scope of a pinned post
+	Pinned string `json:"pinned,omitempty"`
+	// Held posts were stopped by the content filter and stay hidden until a moderator approves them
+	Held bool `json:"held,omitempty"`
+
+	// Mentions are the resolved @mentions in Content, for clients to highlight
+	Mentions []mention.Span `json:"mentions,omitempty"`
+
+	// UnreadComments is the number of comments the current user has not read, only set in post lists
+	UnreadComments *int64 `json:"unread_comments,omitempty"`
+	// Collapsed posts are by authors the current user muted or blocked, only set in post lists
+	Collapsed bool `json:"collapsed,omitempty"`
+}
+
+// FeedResponse is a page of the feed, NextCursor is passed as cursor to get the following page
+type FeedResponse struct {
+	Posts      []Response `json:"posts"`
+	NextCursor string     `json:"next_cursor,omitempty"`
 }
 
 //go:generate mockery --name Store
 type Store interface {
-	GetAll(ctx context.Context) ([]Post, error)
+	GetAll(ctx context.Context, viewerID uuid.UUID) ([]Listed, error)
+	GetByBoard(ctx context.Context, viewerID, boardID uuid.UUID) ([]Listed, error)
+	GetFeed(ctx context.Context, viewerID uuid.UUID, pagination internal.CursorPagination) ([]Listed, *internal.Cursor, error)
 	GetByID(ctx context.Context, id uuid.UUID) (Post, error)
 	Create(ctx context.Context, request CreateRequest) (Post, error)
+	Update(ctx context.Context, id uuid.UUID, request UpdateRequest) (Post, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	GetMentions(ctx context.Context, postIDs []uuid.UUID) (map[uuid.UUID][]mention.Span, error)
+	CountUnreadComments(ctx context.Context, userID uuid.UUID, postIDs []uuid.UUID) (map[uuid.UUID]int64, error)
+	Lock(ctx context.Context, actorID, id uuid.UUID) (Post, error)
+	Unlock(ctx context.Context, actorID, id uuid.UUID) (Post, error)
+	Pin(ctx context.Context, actorID, id uuid.UUID, scope string) (Post, error)
+	Unpin(ctx context.Context, actorID, id uuid.UUID) (Post, error)
+	Archive(ctx context.Context, actorID, id uuid.UUID) (Post, error)
+	Unarchive(ctx context.Context, actorID, id uuid.UUID) (Post, error)
+	Publish(ctx context.Context, id uuid.UUID, publishAt *time.Time) (Post, error)
 }
 
 type Handler struct {
@@ -57,17 +126,98 @@ func (h Handler) GetAllHandler(w http.ResponseWriter, r *http.Request) {
 	defer span.End()
 	logger := internal.LoggerWithContext(traceCtx, h.logger)
 
-	posts, err := h.postStore.GetAll(traceCtx)
+	format, err := markdown.ParseFormat(r)
 	if err != nil {
 		problem.WriteError(traceCtx, w, err, logger)
 		return
 	}
-	response := make([]Response, len(posts))
-	for index, post := range posts {
-		response[index] = GenerateResponse(post)
+
+	user, err := jwt.GetUserFromContext(traceCtx)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+	userID, err := internal.ParseUUID(user.ID)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
 	}
 
-	internal.WriteJSONResponse(w, http.StatusOK, response)
+	// ?board_id= lists the posts of a single board with the pins of that board on top
+	var posts []Listed
+	if boardParam := r.URL.Query().Get("board_id"); boardParam != "" {
+		var boardID uuid.UUID
+		boardID, err = internal.ParseUUID(boardParam)
+		if err != nil {
+			problem.WriteError(traceCtx, w, fmt.Errorf("%w: board_id: %v", errorPkg.ErrInvalidQuery, err), logger)
+			return
+		}
+		posts, err = h.postStore.GetByBoard(traceCtx, userID, boardID)
+	} else {
+		posts, err = h.postStore.GetAll(traceCtx, userID)
+	}
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+	response, err := h.generateListResponses(traceCtx, format, userID, posts)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	// Unread counts change with every new comment without touching the posts, so the list is only validated by
+	// its ETag and carries no Last-Modified
+	internal.WriteConditionalJSONResponse(w, r, http.StatusOK, response, time.Time{})
+}
+
+// FeedHandler lists the posts of followed users and watched boards, newest first. The page continues after the
+// cursor query parameter, next_cursor is omitted on the last page.
+func (h Handler) FeedHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "FeedEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	format, err := markdown.ParseFormat(r)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	pagination, err := internal.ParseCursorPagination(r)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	user, err := jwt.GetUserFromContext(traceCtx)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+	userID, err := internal.ParseUUID(user.ID)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	posts, next, err := h.postStore.GetFeed(traceCtx, userID, pagination)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+	response, err := h.generateListResponses(traceCtx, format, userID, posts)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	feed := FeedResponse{Posts: response}
+	if next != nil {
+		feed.NextCursor = next.String()
+	}
+
+	internal.WriteJSONResponse(w, http.StatusOK, feed)
 }
 
 func (h Handler) GetHandler(w http.ResponseWriter, r *http.Request) {
@@ -75,6 +225,12 @@ func (h Handler) GetHandler(w http.ResponseWriter, r *http.Request) {
 	defer span.End()
 	logger := internal.LoggerWithContext(traceCtx, h.logger)
 
+	format, err := markdown.ParseFormat(r)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
 	pathID := r.PathValue("id")
 	postID, err := internal.ParseUUID(pathID)
 	if err != nil {
@@ -87,9 +243,18 @@ func (h Handler) GetHandler(w http.ResponseWriter, r *http.Request) {
 		problem.WriteError(traceCtx, w, err, logger)
 		return
 	}
+	if !post.Published() && !isAuthor(traceCtx, post) {
+		problem.WriteError(traceCtx, w, errorPkg.NewNotFoundError("post", "id", postID.String(), ""), logger)
+		return
+	}
 
-	response := GenerateResponse(post)
-	internal.WriteJSONResponse(w, http.StatusOK, response)
+	response, err := h.generateResponses(traceCtx, format, post)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	internal.WriteConditionalJSONResponse(w, r, http.StatusOK, response[0], LastModified(post))
 }
 
 func (h Handler) CreateHandler(w http.ResponseWriter, r *http.Request) {
@@ -97,8 +262,14 @@ func (h Handler) CreateHandler(w http.ResponseWriter, r *http.Request) {
 	defer span.End()
 	logger := internal.LoggerWithContext(traceCtx, h.logger)
 
+	format, err := markdown.ParseFormat(r)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
 	var request CreateRequest
-	err := internal.ParseAndValidateRequestBody(traceCtx, h.validator, r, &request)
+	err = internal.ParseAndValidateRequestBody(traceCtx, h.validator, r, &request)
 	if err != nil {
 		problem.WriteError(traceCtx, w, err, logger)
 		return
@@ -126,16 +297,332 @@ func (h Handler) CreateHandler(w http.ResponseWriter, r *http.Request) {
 
 	logger.Info("Created post", zap.String("id", post.ID.String()))
 
-	response := GenerateResponse(post)
-	internal.WriteJSONResponse(w, http.StatusOK, response)
+	response, err := h.generateResponses(traceCtx, format, post)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	status := http.StatusOK
+	if response[0].Held {
+		status = http.StatusAccepted
+	}
+	internal.WriteJSONResponse(w, status, response[0])
+}
+
+func (h Handler) UpdateHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "UpdateEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	format, err := markdown.ParseFormat(r)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	postID, err := internal.ParseUUID(r.PathValue("id"))
+	if err != nil {
+		problem.WriteError(traceCtx, w, fmt.Errorf("%w: %v", errorPkg.ErrInvalidUUID, err), logger)
+		return
+	}
+
+	var request UpdateRequest
+	err = internal.ParseAndValidateRequestBody(traceCtx, h.validator, r, &request)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	err = h.requireAuthor(traceCtx, postID)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	post, err := h.postStore.Update(traceCtx, postID, request)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	logger.Info("Updated post", zap.String("id", post.ID.String()))
+
+	response, err := h.generateResponses(traceCtx, format, post)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	internal.WriteJSONResponse(w, http.StatusOK, response[0])
+}
+
+func (h Handler) DeleteHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "DeleteEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	postID, err := internal.ParseUUID(r.PathValue("id"))
+	if err != nil {
+		problem.WriteError(traceCtx, w, fmt.Errorf("%w: %v", errorPkg.ErrInvalidUUID, err), logger)
+		return
+	}
+
+	err = h.requireAuthor(traceCtx, postID)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	err = h.postStore.Delete(traceCtx, postID)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	logger.Info("Deleted post", zap.String("id", postID.String()))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PublishHandler publishes a draft of the current user now or schedules it
+func (h Handler) PublishHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "PublishEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	format, err := markdown.ParseFormat(r)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	postID, err := internal.ParseUUID(r.PathValue("id"))
+	if err != nil {
+		problem.WriteError(traceCtx, w, fmt.Errorf("%w: %v", errorPkg.ErrInvalidUUID, err), logger)
+		return
+	}
+
+	var request PublishRequest
+	if r.ContentLength != 0 {
+		err = internal.ParseAndValidateRequestBody(traceCtx, h.validator, r, &request)
+		if err != nil {
+			problem.WriteError(traceCtx, w, err, logger)
+			return
+		}
+	}
+
+	err = h.requireAuthor(traceCtx, postID)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	post, err := h.postStore.Publish(traceCtx, postID, request.PublishAt)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	response, err := h.generateResponses(traceCtx, format, post)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	internal.WriteJSONResponse(w, http.StatusOK, response[0])
+}
+
+func (h Handler) LockHandler(w http.ResponseWriter, r *http.Request) {
+	h.moderate(w, r, "LockEndpoint", h.postStore.Lock)
+}
+
+func (h Handler) UnlockHandler(w http.ResponseWriter, r *http.Request) {
+	h.moderate(w, r, "UnlockEndpoint", h.postStore.Unlock)
+}
+
+func (h Handler) PinHandler(w http.ResponseWriter, r *http.Request) {
+	var request PinRequest
+	err := internal.ParseAndValidateRequestBody(r.Context(), h.validator, r, &request)
+	if err != nil {
+		problem.WriteError(r.Context(), w, err, internal.LoggerWithContext(r.Context(), h.logger))
+		return
+	}
+
+	h.moderate(w, r, "PinEndpoint", func(ctx context.Context, actorID, id uuid.UUID) (Post, error) {
+		return h.postStore.Pin(ctx, actorID, id, request.Scope)
+	})
+}
+
+func (h Handler) UnpinHandler(w http.ResponseWriter, r *http.Request) {
+	h.moderate(w, r, "UnpinEndpoint", h.postStore.Unpin)
+}
+
+func (h Handler) ArchiveHandler(w http.ResponseWriter, r *http.Request) {
+	h.moderate(w, r, "ArchiveEndpoint", h.postStore.Archive)
+}
+
+func (h Handler) UnarchiveHandler(w http.ResponseWriter, r *http.Request) {
+	h.moderate(w, r, "UnarchiveEndpoint", h.postStore.Unarchive)
+}
+
+// moderate applies a moderation action of the current user to the post in the id path value and writes the result
+func (h Handler) moderate(w http.ResponseWriter, r *http.Request, spanName string, action func(ctx context.Context, actorID, id uuid.UUID) (Post, error)) {
+	traceCtx, span := h.tracer.Start(r.Context(), spanName)
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	format, err := markdown.ParseFormat(r)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	postID, err := internal.ParseUUID(r.PathValue("id"))
+	if err != nil {
+		problem.WriteError(traceCtx, w, fmt.Errorf("%w: %v", errorPkg.ErrInvalidUUID, err), logger)
+		return
+	}
+
+	user, err := jwt.GetUserFromContext(traceCtx)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+	actorID, err := internal.ParseUUID(user.ID)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	post, err := action(traceCtx, actorID, postID)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	response, err := h.generateResponses(traceCtx, format, post)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	internal.WriteJSONResponse(w, http.StatusOK, response[0])
+}
+
+// requireAuthor returns errorPkg.ErrForbidden unless the user in the context wrote the post
+func (h Handler) requireAuthor(ctx context.Context, postID uuid.UUID) error {
+	user, err := jwt.GetUserFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	post, err := h.postStore.GetByID(ctx, postID)
+	if err != nil {
+		return err
+	}
+
+	if post.AuthorID.String() != user.ID {
+		return errorPkg.ErrForbidden
+	}
+
+	return nil
+}
+
+// isAuthor reports whether the current user wrote the post
+func isAuthor(ctx context.Context, post Post) bool {
+	user, err := jwt.GetUserFromContext(ctx)
+	return err == nil && post.AuthorID.String() == user.ID
+}
+
+// generateResponses converts posts to responses including their resolved mentions and the content rendered to format
+func (h Handler) generateResponses(ctx context.Context, format string, posts ...Post) ([]Response, error) {
+	ids := make([]uuid.UUID, len(posts))
+	for index, post := range posts {
+		ids[index] = post.ID
+	}
+
+	mentions, err := h.postStore.GetMentions(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]Response, len(posts))
+	for index, post := range posts {
+		response[index] = GenerateResponse(post)
+		response[index].Mentions = mentions[post.ID]
+		if format != markdown.FormatNone {
+			response[index].Rendered = markdown.Render(response[index].Content, format)
+		}
+	}
+
+	return response, nil
+}
+
+// generateListResponses converts listed posts like generateResponses and adds whether they are collapsed and the
+// number of comments userID has not read
+func (h Handler) generateListResponses(ctx context.Context, format string, userID uuid.UUID, listed []Listed) ([]Response, error) {
+	posts := make([]Post, len(listed))
+	ids := make([]uuid.UUID, len(listed))
+	for index, l := range listed {
+		posts[index] = l.Post
+		ids[index] = l.Post.ID
+	}
+
+	response, err := h.generateResponses(ctx, format, posts...)
+	if err != nil {
+		return nil, err
+	}
+
+	unread, err := h.postStore.CountUnreadComments(ctx, userID, ids)
+	if err != nil {
+		return nil, err
+	}
+	for index, l := range listed {
+		count := unread[l.Post.ID]
+		response[index].UnreadComments = &count
+		response[index].Collapsed = l.Collapsed
+	}
+
+	return response, nil
+}
+
+// LastModified returns the latest modification time among the given posts
+func LastModified(posts ...Post) time.Time {
+	var latest time.Time
+	for _, post := range posts {
+		modified := post.CreateAt.Time
+		if post.UpdatedAt.Valid && post.UpdatedAt.Time.After(modified) {
+			modified = post.UpdatedAt.Time
+		}
+		if modified.After(latest) {
+			latest = modified
+		}
+	}
+	return latest
 }
 
 func GenerateResponse(post Post) Response {
-	return Response{
+	response := Response{
 		ID:       post.ID.String(),
 		AuthorID: post.AuthorID.String(),
 		Title:    post.Title.String,
 		Content:  post.Content.String,
 		CreateAt: post.CreateAt.Time.Format(time.RFC3339),
+		Locked:   post.LockedAt.Valid,
+		Archived: post.ArchivedAt.Valid,
+		Held:     post.HiddenAt.Valid,
+		Status:   post.Status,
+		Tags:     post.Tags,
 	}
+	if post.PublishAt.Valid {
+		response.PublishAt = post.PublishAt.Time.Format(time.RFC3339)
+	}
+	if post.PinnedAt.Valid {
+		response.Pinned = post.PinScope.String
+	}
+	if post.BoardID.Valid {
+		response.BoardID = uuid.UUID(post.BoardID.Bytes).String()
+	}
+
+	return response
 }
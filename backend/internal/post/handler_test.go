@@ -2,13 +2,16 @@ package post_test
 
 import (
 	"backend/internal"
+	"backend/internal/database/databasetest"
+	errorPkg "backend/internal/error"
 	"backend/internal/jwt"
+	"backend/internal/mention"
 	"backend/internal/post"
 	"backend/internal/post/mocks"
 	"bytes"
 	"context"
 	"encoding/json"
-	"github.com/go-playground/validator/v10"
+	"fmt"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/assert"
@@ -16,6 +19,7 @@ import (
 	"go.uber.org/zap"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -58,6 +62,8 @@ func TestHandler_CreateHandler(t *testing.T) {
 					Content:  pgtype.Text{String: "Content"},
 					CreateAt: pgtype.Timestamptz{Time: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)},
 				}, nil)
+				m.On("GetMentions", mock.Anything, []uuid.UUID{uuid.MustParse("54a46af2-b454-4746-8ab0-3cf26085a50b")}).
+					Return(map[uuid.UUID][]mention.Span{}, nil)
 			},
 			wantResult: post.Response{
 				ID:       "54a46af2-b454-4746-8ab0-3cf26085a50b",
@@ -126,6 +132,24 @@ func TestHandler_CreateHandler(t *testing.T) {
 			wantResult: post.Response{},
 			wantStatus: http.StatusBadRequest,
 		},
+		{
+			name: "Should return error when a tag is not a slug",
+			args: args{
+				user: jwt.User{
+					ID:       "81c1ecc1-66d7-4134-b5fe-d886a6f418a4",
+					Username: "test",
+					Role:     "user",
+				},
+				request: post.CreateRequest{
+					Title:   "Title",
+					Content: "Content",
+					Tags:    []string{"go", "Not A Slug"},
+				},
+			},
+			setupMock:  func(m *mocks.Store) {},
+			wantResult: post.Response{},
+			wantStatus: http.StatusBadRequest,
+		},
 	}
 
 	for _, tt := range tests {
@@ -147,7 +171,7 @@ func TestHandler_CreateHandler(t *testing.T) {
 			if err != nil {
 				assert.Failf(t, "Failed to marshal request body", "%+v", err)
 			}
-			h := post.NewHandler(validator.New(), logger, m)
+			h := post.NewHandler(internal.NewValidator(), logger, m)
 
 			h.CreateHandler(w, r)
 
@@ -198,3 +222,430 @@ func TestGenerateResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_GetHandler(t *testing.T) {
+	storedPost := post.Post{
+		ID:        uuid.MustParse("54a46af2-b454-4746-8ab0-3cf26085a50b"),
+		AuthorID:  uuid.MustParse("81c1ecc1-66d7-4134-b5fe-d886a6f418a4"),
+		Title:     pgtype.Text{String: "Title"},
+		Content:   pgtype.Text{String: "Content for @alice"},
+		CreateAt:  pgtype.Timestamptz{Time: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)},
+		UpdatedAt: pgtype.Timestamptz{Time: time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC), Valid: true},
+		Status:    post.StatusPublished,
+	}
+	mentions := []mention.Span{{UserID: "7942c917-4770-43c1-a56a-952186b9970e", Username: "alice", Start: 12, End: 18}}
+	wantResponse := post.GenerateResponse(storedPost)
+	wantResponse.Mentions = mentions
+	responseBody, err := json.Marshal(wantResponse)
+	if err != nil {
+		t.Fatalf("failed to marshal expected response: %v", err)
+	}
+	etag := internal.WeakETag(responseBody)
+
+	tests := []struct {
+		name       string
+		header     map[string]string
+		wantStatus int
+	}{
+		{
+			name:       "Should return post with validators",
+			header:     map[string]string{},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "Should return not modified when ETag matches",
+			header:     map[string]string{"If-None-Match": etag},
+			wantStatus: http.StatusNotModified,
+		},
+		{
+			name:       "Should return post when ETag does not match",
+			header:     map[string]string{"If-None-Match": `W/"stale"`},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "Should return not modified when unchanged since given time",
+			header:     map[string]string{"If-Modified-Since": "Sun, 02 Jan 2000 00:00:00 GMT"},
+			wantStatus: http.StatusNotModified,
+		},
+		{
+			name:       "Should return post when updated after given time",
+			header:     map[string]string{"If-Modified-Since": "Sat, 01 Jan 2000 12:00:00 GMT"},
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(mocks.Store)
+			m.On("GetByID", mock.Anything, storedPost.ID).Return(storedPost, nil)
+			m.On("GetMentions", mock.Anything, []uuid.UUID{storedPost.ID}).
+				Return(map[uuid.UUID][]mention.Span{storedPost.ID: mentions}, nil)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/api/post/"+storedPost.ID.String(), nil)
+			r.SetPathValue("id", storedPost.ID.String())
+			for key, value := range tt.header {
+				r.Header.Set(key, value)
+			}
+
+			h := post.NewHandler(internal.NewValidator(), zap.NewNop(), m)
+			h.GetHandler(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, etag, w.Header().Get("ETag"))
+			assert.Equal(t, "Sun, 02 Jan 2000 00:00:00 GMT", w.Header().Get("Last-Modified"))
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, string(responseBody), w.Body.String())
+			} else {
+				assert.Empty(t, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_GetHandler_Moderated(t *testing.T) {
+	pool := databasetest.Open(t)
+	ctx := context.Background()
+	r := &recorder{}
+	s := post.NewService(zap.NewNop(), pool, r, nil, nil, nil, r, nil)
+	postID := databasetest.CreatePost(t, pool, databasetest.CreateUser(t, pool))
+	// Last-Modified has a resolution of seconds, the post is older so the lock below is a later second
+	_, err := pool.Exec(ctx, "UPDATE posts SET create_at = now() - interval '1 hour', updated_at = now() - interval '1 hour' WHERE id = $1", postID)
+	if err != nil {
+		t.Fatalf("failed to date post: %v", err)
+	}
+
+	// the handler reads the post through the service, so it sees what the queries store
+	m := new(mocks.Store)
+	m.On("GetByID", mock.Anything, postID).Return(func(ctx context.Context, id uuid.UUID) (post.Post, error) {
+		return s.GetByID(ctx, id)
+	})
+	m.On("GetMentions", mock.Anything, []uuid.UUID{postID}).Return(map[uuid.UUID][]mention.Span{}, nil)
+	h := post.NewHandler(internal.NewValidator(), zap.NewNop(), m)
+	get := func(ifModifiedSince string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/api/post/"+postID.String(), nil)
+		r.SetPathValue("id", postID.String())
+		if ifModifiedSince != "" {
+			r.Header.Set("If-Modified-Since", ifModifiedSince)
+		}
+		h.GetHandler(w, r)
+		return w
+	}
+
+	lastModified := get("").Header().Get("Last-Modified")
+	assert.Equal(t, http.StatusNotModified, get(lastModified).Code)
+
+	_, err = s.Lock(ctx, databasetest.CreateUser(t, pool), postID)
+	if err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+
+	w := get(lastModified)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"locked":true`)
+}
+
+func TestHandler_GetAllHandler(t *testing.T) {
+	user := jwt.User{
+		ID:       "81c1ecc1-66d7-4134-b5fe-d886a6f418a4",
+		Username: "test",
+		Role:     "USER",
+	}
+	read := post.Post{
+		ID:       uuid.MustParse("54a46af2-b454-4746-8ab0-3cf26085a50b"),
+		AuthorID: uuid.MustParse(user.ID),
+		Title:    pgtype.Text{String: "Read"},
+		Content:  pgtype.Text{String: "Content"},
+		CreateAt: pgtype.Timestamptz{Time: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	unread := post.Post{
+		ID:       uuid.MustParse("1f0b3c8e-3b0a-4d8e-9f3e-9b1b0f6b7c11"),
+		AuthorID: uuid.MustParse("7942c917-4770-43c1-a56a-952186b9970e"),
+		Title:    pgtype.Text{String: "Unread"},
+		Content:  pgtype.Text{String: "Content"},
+		CreateAt: pgtype.Timestamptz{Time: time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC)},
+	}
+
+	m := mocks.NewStore(t)
+	m.On("GetAll", mock.Anything, uuid.MustParse(user.ID)).Return([]post.Listed{{Post: read}, {Post: unread, Collapsed: true}}, nil)
+	m.On("GetMentions", mock.Anything, []uuid.UUID{read.ID, unread.ID}).Return(map[uuid.UUID][]mention.Span{}, nil)
+	m.On("CountUnreadComments", mock.Anything, uuid.MustParse(user.ID), []uuid.UUID{read.ID, unread.ID}).
+		Return(map[uuid.UUID]int64{unread.ID: 3}, nil)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/api/posts", nil)
+	r = r.WithContext(context.WithValue(r.Context(), internal.UserContextKey, user))
+
+	h := post.NewHandler(internal.NewValidator(), zap.NewNop(), m)
+	h.GetAllHandler(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Last-Modified"))
+
+	var got []post.Response
+	err := json.Unmarshal(w.Body.Bytes(), &got)
+	if err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if assert.Len(t, got, 2) {
+		assert.Equal(t, int64(0), *got[0].UnreadComments)
+		assert.Equal(t, int64(3), *got[1].UnreadComments)
+		assert.False(t, got[0].Collapsed)
+		assert.True(t, got[1].Collapsed)
+	}
+}
+
+func TestHandler_PinHandler(t *testing.T) {
+	moderator := jwt.User{
+		ID:       "81c1ecc1-66d7-4134-b5fe-d886a6f418a4",
+		Username: "moderator",
+		Role:     "MODERATOR",
+	}
+	postID := uuid.MustParse("54a46af2-b454-4746-8ab0-3cf26085a50b")
+
+	tests := []struct {
+		name       string
+		body       string
+		setupMock  func(m *mocks.Store)
+		wantStatus int
+		wantPinned string
+	}{
+		{
+			name: "Should pin post to its board",
+			body: `{"scope":"board"}`,
+			setupMock: func(m *mocks.Store) {
+				m.On("Pin", mock.Anything, uuid.MustParse(moderator.ID), postID, post.PinBoard).Return(post.Post{
+					ID:       postID,
+					AuthorID: uuid.MustParse("7942c917-4770-43c1-a56a-952186b9970e"),
+					CreateAt: pgtype.Timestamptz{Time: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)},
+					PinnedAt: pgtype.Timestamptz{Time: time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC), Valid: true},
+					PinScope: pgtype.Text{String: post.PinBoard, Valid: true},
+				}, nil)
+				m.On("GetMentions", mock.Anything, []uuid.UUID{postID}).Return(map[uuid.UUID][]mention.Span{}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantPinned: post.PinBoard,
+		},
+		{
+			name:       "Should reject unknown scope",
+			body:       `{"scope":"everywhere"}`,
+			setupMock:  func(m *mocks.Store) {},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mocks.NewStore(t)
+			tt.setupMock(m)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPut, "/api/post/"+postID.String()+"/pin", bytes.NewBufferString(tt.body))
+			r.SetPathValue("id", postID.String())
+			r = r.WithContext(context.WithValue(r.Context(), internal.UserContextKey, moderator))
+
+			h := post.NewHandler(internal.NewValidator(), zap.NewNop(), m)
+			h.PinHandler(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusOK {
+				var got post.Response
+				err := json.Unmarshal(w.Body.Bytes(), &got)
+				if err != nil {
+					t.Fatalf("failed to unmarshal response: %v", err)
+				}
+				assert.Equal(t, tt.wantPinned, got.Pinned)
+			}
+		})
+	}
+}
+
+func TestHandler_GetHandler_Draft(t *testing.T) {
+	author := jwt.User{ID: "81c1ecc1-66d7-4134-b5fe-d886a6f418a4", Username: "author", Role: jwt.RoleUser}
+	other := jwt.User{ID: "7942c917-4770-43c1-a56a-952186b9970e", Username: "other", Role: jwt.RoleUser}
+	draft := post.Post{
+		ID:       uuid.MustParse("54a46af2-b454-4746-8ab0-3cf26085a50b"),
+		AuthorID: uuid.MustParse(author.ID),
+		Title:    pgtype.Text{String: "Title"},
+		Content:  pgtype.Text{String: "Content"},
+		Status:   post.StatusDraft,
+	}
+
+	tests := []struct {
+		name       string
+		user       jwt.User
+		wantStatus int
+	}{
+		{name: "Should return draft to its author", user: author, wantStatus: http.StatusOK},
+		{name: "Should hide draft from other users", user: other, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mocks.NewStore(t)
+			m.On("GetByID", mock.Anything, draft.ID).Return(draft, nil)
+			if tt.wantStatus == http.StatusOK {
+				m.On("GetMentions", mock.Anything, []uuid.UUID{draft.ID}).Return(map[uuid.UUID][]mention.Span{}, nil)
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/api/post/"+draft.ID.String(), nil)
+			r.SetPathValue("id", draft.ID.String())
+			r = r.WithContext(context.WithValue(r.Context(), internal.UserContextKey, tt.user))
+
+			h := post.NewHandler(internal.NewValidator(), zap.NewNop(), m)
+			h.GetHandler(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestHandler_PublishHandler(t *testing.T) {
+	author := jwt.User{ID: "81c1ecc1-66d7-4134-b5fe-d886a6f418a4", Username: "author", Role: jwt.RoleUser}
+	draft := post.Post{
+		ID:       uuid.MustParse("54a46af2-b454-4746-8ab0-3cf26085a50b"),
+		AuthorID: uuid.MustParse(author.ID),
+		Status:   post.StatusDraft,
+	}
+	publishAt := time.Date(2100, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		body       string
+		setupMock  func(m *mocks.Store)
+		wantStatus int
+	}{
+		{
+			name: "Should publish draft now",
+			setupMock: func(m *mocks.Store) {
+				published := draft
+				published.Status = post.StatusPublished
+				m.On("Publish", mock.Anything, draft.ID, (*time.Time)(nil)).Return(published, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Should schedule draft",
+			body: `{"publish_at":"2100-01-01T09:00:00Z"}`,
+			setupMock: func(m *mocks.Store) {
+				scheduled := draft
+				scheduled.Status = post.StatusScheduled
+				scheduled.PublishAt = pgtype.Timestamptz{Time: publishAt, Valid: true}
+				m.On("Publish", mock.Anything, draft.ID, &publishAt).Return(scheduled, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "Should reject publishing a published post",
+			setupMock: func(m *mocks.Store) {
+				m.On("Publish", mock.Anything, draft.ID, (*time.Time)(nil)).
+					Return(post.Post{}, fmt.Errorf("%w: the post is already published", errorPkg.ErrInvalidRequest))
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mocks.NewStore(t)
+			m.On("GetByID", mock.Anything, draft.ID).Return(draft, nil)
+			m.On("GetMentions", mock.Anything, []uuid.UUID{draft.ID}).Return(map[uuid.UUID][]mention.Span{}, nil).Maybe()
+			tt.setupMock(m)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/api/post/"+draft.ID.String()+"/publish", strings.NewReader(tt.body))
+			r.SetPathValue("id", draft.ID.String())
+			r = r.WithContext(context.WithValue(r.Context(), internal.UserContextKey, author))
+
+			h := post.NewHandler(internal.NewValidator(), zap.NewNop(), m)
+			h.PublishHandler(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestHandler_FeedHandler(t *testing.T) {
+	user := jwt.User{
+		ID:       "81c1ecc1-66d7-4134-b5fe-d886a6f418a4",
+		Username: "test",
+		Role:     "USER",
+	}
+	followed := post.Post{
+		ID:       uuid.MustParse("1f0b3c8e-3b0a-4d8e-9f3e-9b1b0f6b7c11"),
+		AuthorID: uuid.MustParse("7942c917-4770-43c1-a56a-952186b9970e"),
+		Title:    pgtype.Text{String: "Followed"},
+		Content:  pgtype.Text{String: "Content"},
+		CreateAt: pgtype.Timestamptz{Time: time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC)},
+		Status:   post.StatusPublished,
+	}
+	cursor := internal.Cursor{Time: followed.CreateAt.Time, ID: followed.ID}
+
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func(m *mocks.Store)
+		wantStatus     int
+		wantNextCursor string
+	}{
+		{
+			name: "Should return first page with next cursor",
+			setupMock: func(m *mocks.Store) {
+				m.On("GetFeed", mock.Anything, uuid.MustParse(user.ID), internal.CursorPagination{Size: internal.DefaultPageSize}).
+					Return([]post.Listed{{Post: followed}}, &cursor, nil)
+				m.On("GetMentions", mock.Anything, []uuid.UUID{followed.ID}).Return(map[uuid.UUID][]mention.Span{}, nil)
+				m.On("CountUnreadComments", mock.Anything, uuid.MustParse(user.ID), []uuid.UUID{followed.ID}).
+					Return(map[uuid.UUID]int64{}, nil)
+			},
+			wantStatus:     http.StatusOK,
+			wantNextCursor: cursor.String(),
+		},
+		{
+			name:  "Should continue after cursor",
+			query: "?size=5&cursor=" + cursor.String(),
+			setupMock: func(m *mocks.Store) {
+				m.On("GetFeed", mock.Anything, uuid.MustParse(user.ID), mock.MatchedBy(func(p internal.CursorPagination) bool {
+					return p.Size == 5 && p.After != nil && p.After.ID == followed.ID && p.After.Time.Equal(followed.CreateAt.Time)
+				})).Return([]post.Listed{}, nil, nil)
+				m.On("GetMentions", mock.Anything, []uuid.UUID{}).Return(map[uuid.UUID][]mention.Span{}, nil)
+				m.On("CountUnreadComments", mock.Anything, uuid.MustParse(user.ID), []uuid.UUID{}).
+					Return(map[uuid.UUID]int64{}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "Should reject malformed cursor",
+			query:      "?cursor=not-a-cursor",
+			setupMock:  func(m *mocks.Store) {},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mocks.NewStore(t)
+			tt.setupMock(m)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/api/feed"+tt.query, nil)
+			r = r.WithContext(context.WithValue(r.Context(), internal.UserContextKey, user))
+
+			h := post.NewHandler(internal.NewValidator(), zap.NewNop(), m)
+			h.FeedHandler(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var got post.FeedResponse
+			err := json.Unmarshal(w.Body.Bytes(), &got)
+			if err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			assert.Equal(t, tt.wantNextCursor, got.NextCursor)
+		})
+	}
+}
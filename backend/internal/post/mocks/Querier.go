@@ -16,6 +16,34 @@ type Querier struct {
 	mock.Mock
 }
 
+// Archive provides a mock function with given fields: ctx, id
+func (_m *Querier) Archive(ctx context.Context, id uuid.UUID) (post.Post, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Archive")
+	}
+
+	var r0 post.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (post.Post, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) post.Post); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(post.Post)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: ctx, arg
 func (_m *Querier) Create(ctx context.Context, arg post.CreateParams) (post.Post, error) {
 	ret := _m.Called(ctx, arg)
@@ -45,46 +73,86 @@ func (_m *Querier) Create(ctx context.Context, arg post.CreateParams) (post.Post
 }
 
 // Delete provides a mock function with given fields: ctx, id
-func (_m *Querier) Delete(ctx context.Context, id uuid.UUID) error {
+func (_m *Querier) Delete(ctx context.Context, id uuid.UUID) (int64, error) {
 	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Delete")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (int64, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) int64); ok {
 		r0 = rf(ctx, id)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
 	}
 
-	return r0
+	return r0, r1
 }
 
-// FindAll provides a mock function with given fields: ctx
-func (_m *Querier) FindAll(ctx context.Context) ([]post.Post, error) {
-	ret := _m.Called(ctx)
+// FindAll provides a mock function with given fields: ctx, viewerID
+func (_m *Querier) FindAll(ctx context.Context, viewerID uuid.UUID) ([]post.FindAllRow, error) {
+	ret := _m.Called(ctx, viewerID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for FindAll")
 	}
 
-	var r0 []post.Post
+	var r0 []post.FindAllRow
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]post.Post, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]post.FindAllRow, error)); ok {
+		return rf(ctx, viewerID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []post.Post); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []post.FindAllRow); ok {
+		r0 = rf(ctx, viewerID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]post.Post)
+			r0 = ret.Get(0).([]post.FindAllRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, viewerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByBoard provides a mock function with given fields: ctx, arg
+func (_m *Querier) FindByBoard(ctx context.Context, arg post.FindByBoardParams) ([]post.FindByBoardRow, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByBoard")
+	}
+
+	var r0 []post.FindByBoardRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, post.FindByBoardParams) ([]post.FindByBoardRow, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, post.FindByBoardParams) []post.FindByBoardRow); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]post.FindByBoardRow)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, post.FindByBoardParams) error); ok {
+		r1 = rf(ctx, arg)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -120,6 +188,318 @@ func (_m *Querier) FindByID(ctx context.Context, id uuid.UUID) (post.Post, error
 	return r0, r1
 }
 
+// FindFeed provides a mock function with given fields: ctx, arg
+func (_m *Querier) FindFeed(ctx context.Context, arg post.FindFeedParams) ([]post.FindFeedRow, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindFeed")
+	}
+
+	var r0 []post.FindFeedRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, post.FindFeedParams) ([]post.FindFeedRow, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, post.FindFeedParams) []post.FindFeedRow); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]post.FindFeedRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, post.FindFeedParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Hide provides a mock function with given fields: ctx, id
+func (_m *Querier) Hide(ctx context.Context, id uuid.UUID) (int64, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Hide")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (int64, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) int64); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Lock provides a mock function with given fields: ctx, id
+func (_m *Querier) Lock(ctx context.Context, id uuid.UUID) (post.Post, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Lock")
+	}
+
+	var r0 post.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (post.Post, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) post.Post); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(post.Post)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Pin provides a mock function with given fields: ctx, arg
+func (_m *Querier) Pin(ctx context.Context, arg post.PinParams) (post.Post, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Pin")
+	}
+
+	var r0 post.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, post.PinParams) (post.Post, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, post.PinParams) post.Post); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(post.Post)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, post.PinParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Publish provides a mock function with given fields: ctx, id
+func (_m *Querier) Publish(ctx context.Context, id uuid.UUID) (post.Post, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Publish")
+	}
+
+	var r0 post.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (post.Post, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) post.Post); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(post.Post)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PublishDue provides a mock function with given fields: ctx, size
+func (_m *Querier) PublishDue(ctx context.Context, size int32) ([]post.Post, error) {
+	ret := _m.Called(ctx, size)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublishDue")
+	}
+
+	var r0 []post.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int32) ([]post.Post, error)); ok {
+		return rf(ctx, size)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int32) []post.Post); ok {
+		r0 = rf(ctx, size)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]post.Post)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int32) error); ok {
+		r1 = rf(ctx, size)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Schedule provides a mock function with given fields: ctx, arg
+func (_m *Querier) Schedule(ctx context.Context, arg post.ScheduleParams) (post.Post, error) {
+	ret := _m.Called(ctx, arg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Schedule")
+	}
+
+	var r0 post.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, post.ScheduleParams) (post.Post, error)); ok {
+		return rf(ctx, arg)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, post.ScheduleParams) post.Post); ok {
+		r0 = rf(ctx, arg)
+	} else {
+		r0 = ret.Get(0).(post.Post)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, post.ScheduleParams) error); ok {
+		r1 = rf(ctx, arg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Unarchive provides a mock function with given fields: ctx, id
+func (_m *Querier) Unarchive(ctx context.Context, id uuid.UUID) (post.Post, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Unarchive")
+	}
+
+	var r0 post.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (post.Post, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) post.Post); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(post.Post)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Unhide provides a mock function with given fields: ctx, id
+func (_m *Querier) Unhide(ctx context.Context, id uuid.UUID) (post.Post, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Unhide")
+	}
+
+	var r0 post.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (post.Post, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) post.Post); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(post.Post)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Unlock provides a mock function with given fields: ctx, id
+func (_m *Querier) Unlock(ctx context.Context, id uuid.UUID) (post.Post, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Unlock")
+	}
+
+	var r0 post.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (post.Post, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) post.Post); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(post.Post)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Unpin provides a mock function with given fields: ctx, id
+func (_m *Querier) Unpin(ctx context.Context, id uuid.UUID) (post.Post, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Unpin")
+	}
+
+	var r0 post.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (post.Post, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) post.Post); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(post.Post)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, arg
 func (_m *Querier) Update(ctx context.Context, arg post.UpdateParams) (post.Post, error) {
 	ret := _m.Called(ctx, arg)
@@ -3,11 +3,17 @@
 package mocks
 
 import (
-	post "backend/internal/post"
+	internal "backend/internal"
 	context "context"
 
+	mention "backend/internal/mention"
+
 	mock "github.com/stretchr/testify/mock"
 
+	post "backend/internal/post"
+
+	time "time"
+
 	uuid "github.com/google/uuid"
 )
 
@@ -16,6 +22,64 @@ type Store struct {
 	mock.Mock
 }
 
+// Archive provides a mock function with given fields: ctx, actorID, id
+func (_m *Store) Archive(ctx context.Context, actorID uuid.UUID, id uuid.UUID) (post.Post, error) {
+	ret := _m.Called(ctx, actorID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Archive")
+	}
+
+	var r0 post.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) (post.Post, error)); ok {
+		return rf(ctx, actorID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) post.Post); ok {
+		r0 = rf(ctx, actorID, id)
+	} else {
+		r0 = ret.Get(0).(post.Post)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(ctx, actorID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountUnreadComments provides a mock function with given fields: ctx, userID, postIDs
+func (_m *Store) CountUnreadComments(ctx context.Context, userID uuid.UUID, postIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	ret := _m.Called(ctx, userID, postIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountUnreadComments")
+	}
+
+	var r0 map[uuid.UUID]int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, []uuid.UUID) (map[uuid.UUID]int64, error)); ok {
+		return rf(ctx, userID, postIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, []uuid.UUID) map[uuid.UUID]int64); ok {
+		r0 = rf(ctx, userID, postIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[uuid.UUID]int64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, []uuid.UUID) error); ok {
+		r1 = rf(ctx, userID, postIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: ctx, request
 func (_m *Store) Create(ctx context.Context, request post.CreateRequest) (post.Post, error) {
 	ret := _m.Called(ctx, request)
@@ -44,29 +108,77 @@ func (_m *Store) Create(ctx context.Context, request post.CreateRequest) (post.P
 	return r0, r1
 }
 
-// GetAll provides a mock function with given fields: ctx
-func (_m *Store) GetAll(ctx context.Context) ([]post.Post, error) {
-	ret := _m.Called(ctx)
+// Delete provides a mock function with given fields: ctx, id
+func (_m *Store) Delete(ctx context.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAll provides a mock function with given fields: ctx, viewerID
+func (_m *Store) GetAll(ctx context.Context, viewerID uuid.UUID) ([]post.Listed, error) {
+	ret := _m.Called(ctx, viewerID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetAll")
 	}
 
-	var r0 []post.Post
+	var r0 []post.Listed
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]post.Post, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]post.Listed, error)); ok {
+		return rf(ctx, viewerID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []post.Post); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []post.Listed); ok {
+		r0 = rf(ctx, viewerID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]post.Post)
+			r0 = ret.Get(0).([]post.Listed)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, viewerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByBoard provides a mock function with given fields: ctx, viewerID, boardID
+func (_m *Store) GetByBoard(ctx context.Context, viewerID uuid.UUID, boardID uuid.UUID) ([]post.Listed, error) {
+	ret := _m.Called(ctx, viewerID, boardID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByBoard")
+	}
+
+	var r0 []post.Listed
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) ([]post.Listed, error)); ok {
+		return rf(ctx, viewerID, boardID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) []post.Listed); ok {
+		r0 = rf(ctx, viewerID, boardID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]post.Listed)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(ctx, viewerID, boardID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -102,6 +214,271 @@ func (_m *Store) GetByID(ctx context.Context, id uuid.UUID) (post.Post, error) {
 	return r0, r1
 }
 
+// GetFeed provides a mock function with given fields: ctx, viewerID, pagination
+func (_m *Store) GetFeed(ctx context.Context, viewerID uuid.UUID, pagination internal.CursorPagination) ([]post.Listed, *internal.Cursor, error) {
+	ret := _m.Called(ctx, viewerID, pagination)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFeed")
+	}
+
+	var r0 []post.Listed
+	var r1 *internal.Cursor
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, internal.CursorPagination) ([]post.Listed, *internal.Cursor, error)); ok {
+		return rf(ctx, viewerID, pagination)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, internal.CursorPagination) []post.Listed); ok {
+		r0 = rf(ctx, viewerID, pagination)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]post.Listed)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, internal.CursorPagination) *internal.Cursor); ok {
+		r1 = rf(ctx, viewerID, pagination)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*internal.Cursor)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uuid.UUID, internal.CursorPagination) error); ok {
+		r2 = rf(ctx, viewerID, pagination)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetMentions provides a mock function with given fields: ctx, postIDs
+func (_m *Store) GetMentions(ctx context.Context, postIDs []uuid.UUID) (map[uuid.UUID][]mention.Span, error) {
+	ret := _m.Called(ctx, postIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMentions")
+	}
+
+	var r0 map[uuid.UUID][]mention.Span
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []uuid.UUID) (map[uuid.UUID][]mention.Span, error)); ok {
+		return rf(ctx, postIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []uuid.UUID) map[uuid.UUID][]mention.Span); ok {
+		r0 = rf(ctx, postIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[uuid.UUID][]mention.Span)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []uuid.UUID) error); ok {
+		r1 = rf(ctx, postIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Lock provides a mock function with given fields: ctx, actorID, id
+func (_m *Store) Lock(ctx context.Context, actorID uuid.UUID, id uuid.UUID) (post.Post, error) {
+	ret := _m.Called(ctx, actorID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Lock")
+	}
+
+	var r0 post.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) (post.Post, error)); ok {
+		return rf(ctx, actorID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) post.Post); ok {
+		r0 = rf(ctx, actorID, id)
+	} else {
+		r0 = ret.Get(0).(post.Post)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(ctx, actorID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Pin provides a mock function with given fields: ctx, actorID, id, scope
+func (_m *Store) Pin(ctx context.Context, actorID uuid.UUID, id uuid.UUID, scope string) (post.Post, error) {
+	ret := _m.Called(ctx, actorID, id, scope)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Pin")
+	}
+
+	var r0 post.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, string) (post.Post, error)); ok {
+		return rf(ctx, actorID, id, scope)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, string) post.Post); ok {
+		r0 = rf(ctx, actorID, id, scope)
+	} else {
+		r0 = ret.Get(0).(post.Post)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID, string) error); ok {
+		r1 = rf(ctx, actorID, id, scope)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Publish provides a mock function with given fields: ctx, id, publishAt
+func (_m *Store) Publish(ctx context.Context, id uuid.UUID, publishAt *time.Time) (post.Post, error) {
+	ret := _m.Called(ctx, id, publishAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Publish")
+	}
+
+	var r0 post.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *time.Time) (post.Post, error)); ok {
+		return rf(ctx, id, publishAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *time.Time) post.Post); ok {
+		r0 = rf(ctx, id, publishAt)
+	} else {
+		r0 = ret.Get(0).(post.Post)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, *time.Time) error); ok {
+		r1 = rf(ctx, id, publishAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Unarchive provides a mock function with given fields: ctx, actorID, id
+func (_m *Store) Unarchive(ctx context.Context, actorID uuid.UUID, id uuid.UUID) (post.Post, error) {
+	ret := _m.Called(ctx, actorID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Unarchive")
+	}
+
+	var r0 post.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) (post.Post, error)); ok {
+		return rf(ctx, actorID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) post.Post); ok {
+		r0 = rf(ctx, actorID, id)
+	} else {
+		r0 = ret.Get(0).(post.Post)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(ctx, actorID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Unlock provides a mock function with given fields: ctx, actorID, id
+func (_m *Store) Unlock(ctx context.Context, actorID uuid.UUID, id uuid.UUID) (post.Post, error) {
+	ret := _m.Called(ctx, actorID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Unlock")
+	}
+
+	var r0 post.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) (post.Post, error)); ok {
+		return rf(ctx, actorID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) post.Post); ok {
+		r0 = rf(ctx, actorID, id)
+	} else {
+		r0 = ret.Get(0).(post.Post)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(ctx, actorID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Unpin provides a mock function with given fields: ctx, actorID, id
+func (_m *Store) Unpin(ctx context.Context, actorID uuid.UUID, id uuid.UUID) (post.Post, error) {
+	ret := _m.Called(ctx, actorID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Unpin")
+	}
+
+	var r0 post.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) (post.Post, error)); ok {
+		return rf(ctx, actorID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) post.Post); ok {
+		r0 = rf(ctx, actorID, id)
+	} else {
+		r0 = ret.Get(0).(post.Post)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(ctx, actorID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, id, request
+func (_m *Store) Update(ctx context.Context, id uuid.UUID, request post.UpdateRequest) (post.Post, error) {
+	ret := _m.Called(ctx, id, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 post.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, post.UpdateRequest) (post.Post, error)); ok {
+		return rf(ctx, id, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, post.UpdateRequest) post.Post); ok {
+		r0 = rf(ctx, id, request)
+	} else {
+		r0 = ret.Get(0).(post.Post)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, post.UpdateRequest) error); ok {
+		r1 = rf(ctx, id, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewStore creates a new instance of Store. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewStore(t interface {
@@ -1,14 +1,111 @@
 -- name: FindAll :many
-SELECT * FROM posts;
+-- Globally pinned posts first, most recently pinned on top, then the newest posts. Unpublished posts are only listed
+-- for their author. Posts of authors the viewer muted or blocked are collapsed.
+SELECT sqlc.embed(p), EXISTS (
+    SELECT 1 FROM user_mutes m WHERE m.muter_id = @viewer_id::uuid AND m.muted_id = p.author_id
+    UNION ALL
+    SELECT 1 FROM user_blocks b WHERE b.blocker_id = @viewer_id::uuid AND b.blocked_id = p.author_id
+) AS collapsed
+FROM posts p
+WHERE p.hidden_at IS NULL AND (p.status = 'published' OR p.author_id = @viewer_id::uuid)
+ORDER BY CASE WHEN p.pin_scope = 'global' THEN p.pinned_at END DESC NULLS LAST, p.create_at DESC, p.id;
+
+-- name: FindByBoard :many
+-- Posts pinned to the board or globally first, then the newest posts of the board, collapsed like FindAll
+SELECT sqlc.embed(p), EXISTS (
+    SELECT 1 FROM user_mutes m WHERE m.muter_id = @viewer_id::uuid AND m.muted_id = p.author_id
+    UNION ALL
+    SELECT 1 FROM user_blocks b WHERE b.blocker_id = @viewer_id::uuid AND b.blocked_id = p.author_id
+) AS collapsed
+FROM posts p
+WHERE p.board_id = @board_id::uuid AND p.hidden_at IS NULL AND (p.status = 'published' OR p.author_id = @viewer_id::uuid)
+ORDER BY p.pinned_at DESC NULLS LAST, p.create_at DESC, p.id;
 
 -- name: FindByID :one
-SELECT * FROM posts WHERE id = $1;
+SELECT * FROM posts WHERE id = $1 AND hidden_at IS NULL;
 
 -- name: Create :one
-INSERT INTO posts (author_id, title, content) VALUES ($1, $2, $3) RETURNING *;
+-- Held posts are created hidden until a moderator approves them
+INSERT INTO posts (author_id, title, content, board_id, hidden_at, status, publish_at, tags)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
 
 -- name: Update :one
-UPDATE posts SET title = $2, content = $3 WHERE id = $1 RETURNING *;
+-- Edits held by the content filter hide the post until a moderator approves it, tags are kept when none are given
+UPDATE posts
+SET title = @title, content = @content, updated_at = now(), tags = COALESCE(sqlc.narg(tags)::varchar[], tags),
+    hidden_at = CASE WHEN @hold::boolean THEN COALESCE(hidden_at, now()) ELSE hidden_at END
+WHERE id = @id RETURNING *;
+
+-- name: Lock :one
+-- Moderation changes updated_at like an edit, so conditional requests see the new state
+UPDATE posts SET locked_at = COALESCE(locked_at, now()), updated_at = now() WHERE id = $1 AND hidden_at IS NULL RETURNING *;
+
+-- name: Unlock :one
+UPDATE posts SET locked_at = NULL, updated_at = now() WHERE id = $1 AND hidden_at IS NULL RETURNING *;
+
+-- name: Pin :one
+UPDATE posts SET pinned_at = now(), pin_scope = $2, updated_at = now() WHERE id = $1 AND hidden_at IS NULL RETURNING *;
+
+-- name: Unpin :one
+UPDATE posts SET pinned_at = NULL, pin_scope = NULL, updated_at = now() WHERE id = $1 AND hidden_at IS NULL RETURNING *;
+
+-- name: Archive :one
+UPDATE posts SET archived_at = COALESCE(archived_at, now()), updated_at = now() WHERE id = $1 AND hidden_at IS NULL RETURNING *;
+
+-- name: Unarchive :one
+UPDATE posts SET archived_at = NULL, updated_at = now() WHERE id = $1 AND hidden_at IS NULL RETURNING *;
+
+-- name: Delete :execrows
+DELETE FROM posts WHERE id = $1;
+
+-- name: Hide :execrows
+UPDATE posts SET hidden_at = COALESCE(hidden_at, now()) WHERE id = $1;
+
+-- name: Unhide :one
+UPDATE posts SET hidden_at = NULL WHERE id = $1 AND hidden_at IS NOT NULL RETURNING *;
+
+-- name: Publish :one
+-- A published post is dated to its publication, not to when the draft was started
+UPDATE posts SET status = 'published', publish_at = NULL, create_at = now()
+WHERE id = $1 AND status <> 'published'
+RETURNING *;
+
+-- name: Schedule :one
+UPDATE posts SET status = 'scheduled', publish_at = @publish_at
+WHERE id = @id AND status <> 'published'
+RETURNING *;
+
+-- name: PublishDue :many
+-- SKIP LOCKED lets several backend instances run the scheduler without publishing a post twice
+UPDATE posts SET status = 'published', publish_at = NULL, create_at = publish_at
+WHERE id IN (
+    SELECT id FROM posts
+    WHERE status = 'scheduled' AND publish_at <= now()
+    ORDER BY publish_at
+    LIMIT @size
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
 
--- name: Delete :exec
-DELETE FROM posts WHERE id = $1;
\ No newline at end of file
+-- name: FindFeed :many
+-- Published posts of followed users and of boards watched without muting, newest first. The page starts after the
+-- (create_at, id) keyset of the cursor, a NULL cursor starts at the newest post. Posts are collapsed like FindAll.
+SELECT sqlc.embed(p), EXISTS (
+    SELECT 1 FROM user_mutes m WHERE m.muter_id = @viewer_id::uuid AND m.muted_id = p.author_id
+    UNION ALL
+    SELECT 1 FROM user_blocks b WHERE b.blocker_id = @viewer_id::uuid AND b.blocked_id = p.author_id
+) AS collapsed
+FROM posts p
+WHERE p.hidden_at IS NULL AND p.status = 'published'
+  AND (
+    p.author_id IN (SELECT f.followee_id FROM follows f WHERE f.follower_id = @viewer_id::uuid)
+    OR p.board_id IN (
+        SELECT w.board_id FROM watches w
+        WHERE w.user_id = @viewer_id::uuid AND w.board_id IS NOT NULL AND w.level <> 'muted'
+    )
+  )
+  AND (sqlc.narg(after_create_at)::timestamptz IS NULL
+    OR (p.create_at, p.id) < (sqlc.narg(after_create_at)::timestamptz, @after_id::uuid))
+ORDER BY p.create_at DESC, p.id DESC
+LIMIT @size;
@@ -12,18 +12,62 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const archive = `-- name: Archive :one
+UPDATE posts SET archived_at = COALESCE(archived_at, now()), updated_at = now() WHERE id = $1 AND hidden_at IS NULL RETURNING id, author_id, title, content, create_at, updated_at, board_id, hidden_at, locked_at, pinned_at, pin_scope, archived_at, status, publish_at, tags
+`
+
+func (q *Queries) Archive(ctx context.Context, id uuid.UUID) (Post, error) {
+	row := q.db.QueryRow(ctx, archive, id)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.AuthorID,
+		&i.Title,
+		&i.Content,
+		&i.CreateAt,
+		&i.UpdatedAt,
+		&i.BoardID,
+		&i.HiddenAt,
+		&i.LockedAt,
+		&i.PinnedAt,
+		&i.PinScope,
+		&i.ArchivedAt,
+		&i.Status,
+		&i.PublishAt,
+		&i.Tags,
+	)
+	return i, err
+}
+
 const create = `-- name: Create :one
-INSERT INTO posts (author_id, title, content) VALUES ($1, $2, $3) RETURNING id, author_id, title, content, create_at
+INSERT INTO posts (author_id, title, content, board_id, hidden_at, status, publish_at, tags)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, author_id, title, content, create_at, updated_at, board_id, hidden_at, locked_at, pinned_at, pin_scope, archived_at, status, publish_at, tags
 `
 
 type CreateParams struct {
-	AuthorID uuid.UUID
-	Title    pgtype.Text
-	Content  pgtype.Text
+	AuthorID  uuid.UUID
+	Title     pgtype.Text
+	Content   pgtype.Text
+	BoardID   pgtype.UUID
+	HiddenAt  pgtype.Timestamptz
+	Status    string
+	PublishAt pgtype.Timestamptz
+	Tags      []string
 }
 
+// Held posts are created hidden until a moderator approves them
 func (q *Queries) Create(ctx context.Context, arg CreateParams) (Post, error) {
-	row := q.db.QueryRow(ctx, create, arg.AuthorID, arg.Title, arg.Content)
+	row := q.db.QueryRow(ctx, create,
+		arg.AuthorID,
+		arg.Title,
+		arg.Content,
+		arg.BoardID,
+		arg.HiddenAt,
+		arg.Status,
+		arg.PublishAt,
+		arg.Tags,
+	)
 	var i Post
 	err := row.Scan(
 		&i.ID,
@@ -31,25 +75,367 @@ func (q *Queries) Create(ctx context.Context, arg CreateParams) (Post, error) {
 		&i.Title,
 		&i.Content,
 		&i.CreateAt,
+		&i.UpdatedAt,
+		&i.BoardID,
+		&i.HiddenAt,
+		&i.LockedAt,
+		&i.PinnedAt,
+		&i.PinScope,
+		&i.ArchivedAt,
+		&i.Status,
+		&i.PublishAt,
+		&i.Tags,
 	)
 	return i, err
 }
 
-const delete = `-- name: Delete :exec
+const delete = `-- name: Delete :execrows
 DELETE FROM posts WHERE id = $1
 `
 
-func (q *Queries) Delete(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.Exec(ctx, delete, id)
-	return err
+func (q *Queries) Delete(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, delete, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
 const findAll = `-- name: FindAll :many
-SELECT id, author_id, title, content, create_at FROM posts
+SELECT p.id, p.author_id, p.title, p.content, p.create_at, p.updated_at, p.board_id, p.hidden_at, p.locked_at, p.pinned_at, p.pin_scope, p.archived_at, p.status, p.publish_at, p.tags, EXISTS (
+    SELECT 1 FROM user_mutes m WHERE m.muter_id = $1::uuid AND m.muted_id = p.author_id
+    UNION ALL
+    SELECT 1 FROM user_blocks b WHERE b.blocker_id = $1::uuid AND b.blocked_id = p.author_id
+) AS collapsed
+FROM posts p
+WHERE p.hidden_at IS NULL AND (p.status = 'published' OR p.author_id = $1::uuid)
+ORDER BY CASE WHEN p.pin_scope = 'global' THEN p.pinned_at END DESC NULLS LAST, p.create_at DESC, p.id
+`
+
+type FindAllRow struct {
+	Post      Post
+	Collapsed bool
+}
+
+// Globally pinned posts first, most recently pinned on top, then the newest posts. Unpublished posts are only listed
+// for their author. Posts of authors the viewer muted or blocked are collapsed.
+func (q *Queries) FindAll(ctx context.Context, viewerID uuid.UUID) ([]FindAllRow, error) {
+	rows, err := q.db.Query(ctx, findAll, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FindAllRow
+	for rows.Next() {
+		var i FindAllRow
+		if err := rows.Scan(
+			&i.Post.ID,
+			&i.Post.AuthorID,
+			&i.Post.Title,
+			&i.Post.Content,
+			&i.Post.CreateAt,
+			&i.Post.UpdatedAt,
+			&i.Post.BoardID,
+			&i.Post.HiddenAt,
+			&i.Post.LockedAt,
+			&i.Post.PinnedAt,
+			&i.Post.PinScope,
+			&i.Post.ArchivedAt,
+			&i.Post.Status,
+			&i.Post.PublishAt,
+			&i.Post.Tags,
+			&i.Collapsed,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findByBoard = `-- name: FindByBoard :many
+SELECT p.id, p.author_id, p.title, p.content, p.create_at, p.updated_at, p.board_id, p.hidden_at, p.locked_at, p.pinned_at, p.pin_scope, p.archived_at, p.status, p.publish_at, p.tags, EXISTS (
+    SELECT 1 FROM user_mutes m WHERE m.muter_id = $1::uuid AND m.muted_id = p.author_id
+    UNION ALL
+    SELECT 1 FROM user_blocks b WHERE b.blocker_id = $1::uuid AND b.blocked_id = p.author_id
+) AS collapsed
+FROM posts p
+WHERE p.board_id = $2::uuid AND p.hidden_at IS NULL AND (p.status = 'published' OR p.author_id = $1::uuid)
+ORDER BY p.pinned_at DESC NULLS LAST, p.create_at DESC, p.id
+`
+
+type FindByBoardParams struct {
+	ViewerID uuid.UUID
+	BoardID  uuid.UUID
+}
+
+type FindByBoardRow struct {
+	Post      Post
+	Collapsed bool
+}
+
+// Posts pinned to the board or globally first, then the newest posts of the board, collapsed like FindAll
+func (q *Queries) FindByBoard(ctx context.Context, arg FindByBoardParams) ([]FindByBoardRow, error) {
+	rows, err := q.db.Query(ctx, findByBoard, arg.ViewerID, arg.BoardID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FindByBoardRow
+	for rows.Next() {
+		var i FindByBoardRow
+		if err := rows.Scan(
+			&i.Post.ID,
+			&i.Post.AuthorID,
+			&i.Post.Title,
+			&i.Post.Content,
+			&i.Post.CreateAt,
+			&i.Post.UpdatedAt,
+			&i.Post.BoardID,
+			&i.Post.HiddenAt,
+			&i.Post.LockedAt,
+			&i.Post.PinnedAt,
+			&i.Post.PinScope,
+			&i.Post.ArchivedAt,
+			&i.Post.Status,
+			&i.Post.PublishAt,
+			&i.Post.Tags,
+			&i.Collapsed,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findByID = `-- name: FindByID :one
+SELECT id, author_id, title, content, create_at, updated_at, board_id, hidden_at, locked_at, pinned_at, pin_scope, archived_at, status, publish_at, tags FROM posts WHERE id = $1 AND hidden_at IS NULL
+`
+
+func (q *Queries) FindByID(ctx context.Context, id uuid.UUID) (Post, error) {
+	row := q.db.QueryRow(ctx, findByID, id)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.AuthorID,
+		&i.Title,
+		&i.Content,
+		&i.CreateAt,
+		&i.UpdatedAt,
+		&i.BoardID,
+		&i.HiddenAt,
+		&i.LockedAt,
+		&i.PinnedAt,
+		&i.PinScope,
+		&i.ArchivedAt,
+		&i.Status,
+		&i.PublishAt,
+		&i.Tags,
+	)
+	return i, err
+}
+
+const findFeed = `-- name: FindFeed :many
+SELECT p.id, p.author_id, p.title, p.content, p.create_at, p.updated_at, p.board_id, p.hidden_at, p.locked_at, p.pinned_at, p.pin_scope, p.archived_at, p.status, p.publish_at, p.tags, EXISTS (
+    SELECT 1 FROM user_mutes m WHERE m.muter_id = $1::uuid AND m.muted_id = p.author_id
+    UNION ALL
+    SELECT 1 FROM user_blocks b WHERE b.blocker_id = $1::uuid AND b.blocked_id = p.author_id
+) AS collapsed
+FROM posts p
+WHERE p.hidden_at IS NULL AND p.status = 'published'
+  AND (
+    p.author_id IN (SELECT f.followee_id FROM follows f WHERE f.follower_id = $1::uuid)
+    OR p.board_id IN (
+        SELECT w.board_id FROM watches w
+        WHERE w.user_id = $1::uuid AND w.board_id IS NOT NULL AND w.level <> 'muted'
+    )
+  )
+  AND ($2::timestamptz IS NULL
+    OR (p.create_at, p.id) < ($2::timestamptz, $3::uuid))
+ORDER BY p.create_at DESC, p.id DESC
+LIMIT $4
+`
+
+type FindFeedParams struct {
+	ViewerID      uuid.UUID
+	AfterCreateAt pgtype.Timestamptz
+	AfterID       uuid.UUID
+	Size          int32
+}
+
+type FindFeedRow struct {
+	Post      Post
+	Collapsed bool
+}
+
+// Published posts of followed users and of boards watched without muting, newest first. The page starts after the
+// (create_at, id) keyset of the cursor, a NULL cursor starts at the newest post. Posts are collapsed like FindAll.
+func (q *Queries) FindFeed(ctx context.Context, arg FindFeedParams) ([]FindFeedRow, error) {
+	rows, err := q.db.Query(ctx, findFeed,
+		arg.ViewerID,
+		arg.AfterCreateAt,
+		arg.AfterID,
+		arg.Size,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FindFeedRow
+	for rows.Next() {
+		var i FindFeedRow
+		if err := rows.Scan(
+			&i.Post.ID,
+			&i.Post.AuthorID,
+			&i.Post.Title,
+			&i.Post.Content,
+			&i.Post.CreateAt,
+			&i.Post.UpdatedAt,
+			&i.Post.BoardID,
+			&i.Post.HiddenAt,
+			&i.Post.LockedAt,
+			&i.Post.PinnedAt,
+			&i.Post.PinScope,
+			&i.Post.ArchivedAt,
+			&i.Post.Status,
+			&i.Post.PublishAt,
+			&i.Post.Tags,
+			&i.Collapsed,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const hide = `-- name: Hide :execrows
+UPDATE posts SET hidden_at = COALESCE(hidden_at, now()) WHERE id = $1
+`
+
+func (q *Queries) Hide(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, hide, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const lock = `-- name: Lock :one
+UPDATE posts SET locked_at = COALESCE(locked_at, now()), updated_at = now() WHERE id = $1 AND hidden_at IS NULL RETURNING id, author_id, title, content, create_at, updated_at, board_id, hidden_at, locked_at, pinned_at, pin_scope, archived_at, status, publish_at, tags
+`
+
+// Moderation changes updated_at like an edit, so conditional requests see the new state
+func (q *Queries) Lock(ctx context.Context, id uuid.UUID) (Post, error) {
+	row := q.db.QueryRow(ctx, lock, id)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.AuthorID,
+		&i.Title,
+		&i.Content,
+		&i.CreateAt,
+		&i.UpdatedAt,
+		&i.BoardID,
+		&i.HiddenAt,
+		&i.LockedAt,
+		&i.PinnedAt,
+		&i.PinScope,
+		&i.ArchivedAt,
+		&i.Status,
+		&i.PublishAt,
+		&i.Tags,
+	)
+	return i, err
+}
+
+const pin = `-- name: Pin :one
+UPDATE posts SET pinned_at = now(), pin_scope = $2, updated_at = now() WHERE id = $1 AND hidden_at IS NULL RETURNING id, author_id, title, content, create_at, updated_at, board_id, hidden_at, locked_at, pinned_at, pin_scope, archived_at, status, publish_at, tags
+`
+
+type PinParams struct {
+	ID       uuid.UUID
+	PinScope pgtype.Text
+}
+
+func (q *Queries) Pin(ctx context.Context, arg PinParams) (Post, error) {
+	row := q.db.QueryRow(ctx, pin, arg.ID, arg.PinScope)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.AuthorID,
+		&i.Title,
+		&i.Content,
+		&i.CreateAt,
+		&i.UpdatedAt,
+		&i.BoardID,
+		&i.HiddenAt,
+		&i.LockedAt,
+		&i.PinnedAt,
+		&i.PinScope,
+		&i.ArchivedAt,
+		&i.Status,
+		&i.PublishAt,
+		&i.Tags,
+	)
+	return i, err
+}
+
+const publish = `-- name: Publish :one
+UPDATE posts SET status = 'published', publish_at = NULL, create_at = now()
+WHERE id = $1 AND status <> 'published'
+RETURNING id, author_id, title, content, create_at, updated_at, board_id, hidden_at, locked_at, pinned_at, pin_scope, archived_at, status, publish_at, tags
+`
+
+// A published post is dated to its publication, not to when the draft was started
+func (q *Queries) Publish(ctx context.Context, id uuid.UUID) (Post, error) {
+	row := q.db.QueryRow(ctx, publish, id)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.AuthorID,
+		&i.Title,
+		&i.Content,
+		&i.CreateAt,
+		&i.UpdatedAt,
+		&i.BoardID,
+		&i.HiddenAt,
+		&i.LockedAt,
+		&i.PinnedAt,
+		&i.PinScope,
+		&i.ArchivedAt,
+		&i.Status,
+		&i.PublishAt,
+		&i.Tags,
+	)
+	return i, err
+}
+
+const publishDue = `-- name: PublishDue :many
+UPDATE posts SET status = 'published', publish_at = NULL, create_at = publish_at
+WHERE id IN (
+    SELECT id FROM posts
+    WHERE status = 'scheduled' AND publish_at <= now()
+    ORDER BY publish_at
+    LIMIT $1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, author_id, title, content, create_at, updated_at, board_id, hidden_at, locked_at, pinned_at, pin_scope, archived_at, status, publish_at, tags
 `
 
-func (q *Queries) FindAll(ctx context.Context) ([]Post, error) {
-	rows, err := q.db.Query(ctx, findAll)
+// SKIP LOCKED lets several backend instances run the scheduler without publishing a post twice
+func (q *Queries) PublishDue(ctx context.Context, size int32) ([]Post, error) {
+	rows, err := q.db.Query(ctx, publishDue, size)
 	if err != nil {
 		return nil, err
 	}
@@ -63,6 +449,16 @@ func (q *Queries) FindAll(ctx context.Context) ([]Post, error) {
 			&i.Title,
 			&i.Content,
 			&i.CreateAt,
+			&i.UpdatedAt,
+			&i.BoardID,
+			&i.HiddenAt,
+			&i.LockedAt,
+			&i.PinnedAt,
+			&i.PinScope,
+			&i.ArchivedAt,
+			&i.Status,
+			&i.PublishAt,
+			&i.Tags,
 		); err != nil {
 			return nil, err
 		}
@@ -74,12 +470,127 @@ func (q *Queries) FindAll(ctx context.Context) ([]Post, error) {
 	return items, nil
 }
 
-const findByID = `-- name: FindByID :one
-SELECT id, author_id, title, content, create_at FROM posts WHERE id = $1
+const schedule = `-- name: Schedule :one
+UPDATE posts SET status = 'scheduled', publish_at = $1
+WHERE id = $2 AND status <> 'published'
+RETURNING id, author_id, title, content, create_at, updated_at, board_id, hidden_at, locked_at, pinned_at, pin_scope, archived_at, status, publish_at, tags
 `
 
-func (q *Queries) FindByID(ctx context.Context, id uuid.UUID) (Post, error) {
-	row := q.db.QueryRow(ctx, findByID, id)
+type ScheduleParams struct {
+	PublishAt pgtype.Timestamptz
+	ID        uuid.UUID
+}
+
+func (q *Queries) Schedule(ctx context.Context, arg ScheduleParams) (Post, error) {
+	row := q.db.QueryRow(ctx, schedule, arg.PublishAt, arg.ID)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.AuthorID,
+		&i.Title,
+		&i.Content,
+		&i.CreateAt,
+		&i.UpdatedAt,
+		&i.BoardID,
+		&i.HiddenAt,
+		&i.LockedAt,
+		&i.PinnedAt,
+		&i.PinScope,
+		&i.ArchivedAt,
+		&i.Status,
+		&i.PublishAt,
+		&i.Tags,
+	)
+	return i, err
+}
+
+const unarchive = `-- name: Unarchive :one
+UPDATE posts SET archived_at = NULL, updated_at = now() WHERE id = $1 AND hidden_at IS NULL RETURNING id, author_id, title, content, create_at, updated_at, board_id, hidden_at, locked_at, pinned_at, pin_scope, archived_at, status, publish_at, tags
+`
+
+func (q *Queries) Unarchive(ctx context.Context, id uuid.UUID) (Post, error) {
+	row := q.db.QueryRow(ctx, unarchive, id)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.AuthorID,
+		&i.Title,
+		&i.Content,
+		&i.CreateAt,
+		&i.UpdatedAt,
+		&i.BoardID,
+		&i.HiddenAt,
+		&i.LockedAt,
+		&i.PinnedAt,
+		&i.PinScope,
+		&i.ArchivedAt,
+		&i.Status,
+		&i.PublishAt,
+		&i.Tags,
+	)
+	return i, err
+}
+
+const unhide = `-- name: Unhide :one
+UPDATE posts SET hidden_at = NULL WHERE id = $1 AND hidden_at IS NOT NULL RETURNING id, author_id, title, content, create_at, updated_at, board_id, hidden_at, locked_at, pinned_at, pin_scope, archived_at, status, publish_at, tags
+`
+
+func (q *Queries) Unhide(ctx context.Context, id uuid.UUID) (Post, error) {
+	row := q.db.QueryRow(ctx, unhide, id)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.AuthorID,
+		&i.Title,
+		&i.Content,
+		&i.CreateAt,
+		&i.UpdatedAt,
+		&i.BoardID,
+		&i.HiddenAt,
+		&i.LockedAt,
+		&i.PinnedAt,
+		&i.PinScope,
+		&i.ArchivedAt,
+		&i.Status,
+		&i.PublishAt,
+		&i.Tags,
+	)
+	return i, err
+}
+
+const unlock = `-- name: Unlock :one
+UPDATE posts SET locked_at = NULL, updated_at = now() WHERE id = $1 AND hidden_at IS NULL RETURNING id, author_id, title, content, create_at, updated_at, board_id, hidden_at, locked_at, pinned_at, pin_scope, archived_at, status, publish_at, tags
+`
+
+func (q *Queries) Unlock(ctx context.Context, id uuid.UUID) (Post, error) {
+	row := q.db.QueryRow(ctx, unlock, id)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.AuthorID,
+		&i.Title,
+		&i.Content,
+		&i.CreateAt,
+		&i.UpdatedAt,
+		&i.BoardID,
+		&i.HiddenAt,
+		&i.LockedAt,
+		&i.PinnedAt,
+		&i.PinScope,
+		&i.ArchivedAt,
+		&i.Status,
+		&i.PublishAt,
+		&i.Tags,
+	)
+	return i, err
+}
+
+const unpin = `-- name: Unpin :one
+UPDATE posts SET pinned_at = NULL, pin_scope = NULL, updated_at = now() WHERE id = $1 AND hidden_at IS NULL RETURNING id, author_id, title, content, create_at, updated_at, board_id, hidden_at, locked_at, pinned_at, pin_scope, archived_at, status, publish_at, tags
+`
+
+func (q *Queries) Unpin(ctx context.Context, id uuid.UUID) (Post, error) {
+	row := q.db.QueryRow(ctx, unpin, id)
 	var i Post
 	err := row.Scan(
 		&i.ID,
@@ -87,22 +598,44 @@ func (q *Queries) FindByID(ctx context.Context, id uuid.UUID) (Post, error) {
 		&i.Title,
 		&i.Content,
 		&i.CreateAt,
+		&i.UpdatedAt,
+		&i.BoardID,
+		&i.HiddenAt,
+		&i.LockedAt,
+		&i.PinnedAt,
+		&i.PinScope,
+		&i.ArchivedAt,
+		&i.Status,
+		&i.PublishAt,
+		&i.Tags,
 	)
 	return i, err
 }
 
 const update = `-- name: Update :one
-UPDATE posts SET title = $2, content = $3 WHERE id = $1 RETURNING id, author_id, title, content, create_at
+UPDATE posts
+SET title = $1, content = $2, updated_at = now(), tags = COALESCE($3::varchar[], tags),
+    hidden_at = CASE WHEN $4::boolean THEN COALESCE(hidden_at, now()) ELSE hidden_at END
+WHERE id = $5 RETURNING id, author_id, title, content, create_at, updated_at, board_id, hidden_at, locked_at, pinned_at, pin_scope, archived_at, status, publish_at, tags
 `
 
 type UpdateParams struct {
-	ID      uuid.UUID
 	Title   pgtype.Text
 	Content pgtype.Text
+	Tags    []string
+	Hold    bool
+	ID      uuid.UUID
 }
 
+// Edits held by the content filter hide the post until a moderator approves it, tags are kept when none are given
 func (q *Queries) Update(ctx context.Context, arg UpdateParams) (Post, error) {
-	row := q.db.QueryRow(ctx, update, arg.ID, arg.Title, arg.Content)
+	row := q.db.QueryRow(ctx, update,
+		arg.Title,
+		arg.Content,
+		arg.Tags,
+		arg.Hold,
+		arg.ID,
+	)
 	var i Post
 	err := row.Scan(
 		&i.ID,
@@ -110,6 +643,16 @@ func (q *Queries) Update(ctx context.Context, arg UpdateParams) (Post, error) {
 		&i.Title,
 		&i.Content,
 		&i.CreateAt,
+		&i.UpdatedAt,
+		&i.BoardID,
+		&i.HiddenAt,
+		&i.LockedAt,
+		&i.PinnedAt,
+		&i.PinScope,
+		&i.ArchivedAt,
+		&i.Status,
+		&i.PublishAt,
+		&i.Tags,
 	)
 	return i, err
 }
@@ -5,5 +5,22 @@ CREATE TABLE IF NOT EXISTS posts (
      author_id UUID REFERENCES users(id) NOT NULL,
      title VARCHAR(200),
      content TEXT,
-     create_at TIMESTAMPTZ DEFAULT now()
-);
\ No newline at end of file
+     create_at TIMESTAMPTZ DEFAULT now(),
+     updated_at TIMESTAMPTZ DEFAULT now(),
+     board_id UUID REFERENCES boards(id) ON DELETE SET NULL,
+     hidden_at TIMESTAMPTZ,
+     locked_at TIMESTAMPTZ,
+     pinned_at TIMESTAMPTZ,
+     pin_scope VARCHAR(16) CHECK (pin_scope IN ('global', 'board')),
+     archived_at TIMESTAMPTZ,
+     -- drafts are only visible to their author, scheduled posts are published at publish_at
+     status VARCHAR(16) DEFAULT 'published' NOT NULL CHECK (status IN ('draft', 'scheduled', 'published')),
+     publish_at TIMESTAMPTZ,
+     -- lowercase slugs, posts are listed in the feed of each of their tags
+     tags VARCHAR(32)[] DEFAULT '{}' NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS posts_scheduled_idx ON posts (publish_at) WHERE status = 'scheduled';
+CREATE INDEX IF NOT EXISTS posts_author_feed_idx ON posts (author_id, create_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS posts_board_feed_idx ON posts (board_id, create_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS posts_tags_idx ON posts USING GIN (tags);
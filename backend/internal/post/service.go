@@ -2,82 +2,747 @@ package post
 
 import (
 	"backend/internal"
+	"backend/internal/audit"
+	"backend/internal/cache"
 	"backend/internal/database"
+	errorPkg "backend/internal/error"
+	"backend/internal/event"
+	"backend/internal/filter"
+	"backend/internal/markdown"
+	"backend/internal/mention"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
+	"slices"
+	"time"
 )
 
 //go:generate mockery --name Querier
 type Querier interface {
-	FindAll(ctx context.Context) ([]Post, error)
+	FindAll(ctx context.Context, viewerID uuid.UUID) ([]FindAllRow, error)
+	FindByBoard(ctx context.Context, arg FindByBoardParams) ([]FindByBoardRow, error)
+	FindFeed(ctx context.Context, arg FindFeedParams) ([]FindFeedRow, error)
 	FindByID(ctx context.Context, id uuid.UUID) (Post, error)
 	Create(ctx context.Context, arg CreateParams) (Post, error)
-	Delete(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, id uuid.UUID) (int64, error)
 	Update(ctx context.Context, arg UpdateParams) (Post, error)
+	Hide(ctx context.Context, id uuid.UUID) (int64, error)
+	Unhide(ctx context.Context, id uuid.UUID) (Post, error)
+	Lock(ctx context.Context, id uuid.UUID) (Post, error)
+	Unlock(ctx context.Context, id uuid.UUID) (Post, error)
+	Pin(ctx context.Context, arg PinParams) (Post, error)
+	Unpin(ctx context.Context, id uuid.UUID) (Post, error)
+	Archive(ctx context.Context, id uuid.UUID) (Post, error)
+	Unarchive(ctx context.Context, id uuid.UUID) (Post, error)
+	Publish(ctx context.Context, id uuid.UUID) (Post, error)
+	Schedule(ctx context.Context, arg ScheduleParams) (Post, error)
+	PublishDue(ctx context.Context, size int32) ([]Post, error)
 }
 
+// Listed is a post in a listing for a viewer, Collapsed posts are by authors the viewer muted or blocked
+type Listed struct {
+	Post      Post
+	Collapsed bool
+}
+
+// Publication states of posts, drafts and scheduled posts are only visible to their author
+const (
+	StatusDraft     = "draft"
+	StatusScheduled = "scheduled"
+	StatusPublished = "published"
+)
+
+// Pin scopes, a globally pinned post is on top of every post list, a board pin only applies to the list of its board
+const (
+	PinGlobal = "global"
+	PinBoard  = "board"
+)
+
+// Actions recorded in the audit trail
+const (
+	ActionDelete    = "post.delete"
+	ActionLock      = "post.lock"
+	ActionUnlock    = "post.unlock"
+	ActionPin       = "post.pin"
+	ActionUnpin     = "post.unpin"
+	ActionArchive   = "post.archive"
+	ActionUnarchive = "post.unarchive"
+)
+
+// Publisher announces changes to posts, see event.Service
+type Publisher interface {
+	Publish(ctx context.Context, eventType string, payload any) error
+}
+
+// Subscriber receives the events of all backend instances, see event.Service
+type Subscriber interface {
+	Subscribe() (<-chan event.Event, func())
+}
+
+// Mentioner keeps the @mentions of post content, see mention.Service
+type Mentioner interface {
+	Sync(ctx context.Context, target mention.Target, authorID uuid.UUID, content string) ([]mention.Span, error)
+	GetByPosts(ctx context.Context, postIDs []uuid.UUID) (map[uuid.UUID][]mention.Span, error)
+}
+
+// Watcher subscribes users to threads, see watch.Service
+type Watcher interface {
+	WatchIfAbsent(ctx context.Context, userID, postID uuid.UUID) error
+}
+
+// ReadTracker knows which comments users have read, see readmarker.Service
+type ReadTracker interface {
+	CountUnread(ctx context.Context, userID uuid.UUID, postIDs []uuid.UUID) (map[uuid.UUID]int64, error)
+}
+
+// ContentFilter screens new and edited posts, see filter.Pipeline
+type ContentFilter interface {
+	Check(ctx context.Context, content filter.Content) filter.Verdict
+	Report(ctx context.Context, target filter.Target, verdict filter.Verdict) error
+}
+
+// Auditor keeps the trail of deletions and moderation actions, see audit.Service
+type Auditor interface {
+	Record(ctx context.Context, entry audit.Entry) error
+}
+
+const (
+	// cacheSize is the number of posts kept in the in-process GetByID cache
+	cacheSize = 1024
+	// cacheTTL bounds how long another instance may serve a post after it changed, changes that are announced as
+	// events are removed from the caches of all instances right away, see RunCacheInvalidation
+	cacheTTL = 30 * time.Second
+)
+
+const (
+	// scheduleInterval is how often the scheduler looks for scheduled posts that are due
+	scheduleInterval = 15 * time.Second
+	scheduleBatch    = 100
+)
+
 type Service struct {
 	logger *zap.Logger
 	tracer trace.Tracer
 	query  Querier
+
+	// cache holds recently read posts by ID, it is shared between copies of the Service. Entries are removed after
+	// every write to a post, after the write so a concurrent read cannot cache the old row again.
+	cache *cache.LRU[uuid.UUID, Post]
+
+	publisher Publisher
+	mentioner Mentioner
+	watcher   Watcher
+	reads     ReadTracker
+	auditor   Auditor
+	filter    ContentFilter
 }
 
-func NewService(logger *zap.Logger, db *pgxpool.Pool) Service {
+func NewService(logger *zap.Logger, db *pgxpool.Pool, publisher Publisher, mentioner Mentioner, watcher Watcher, reads ReadTracker, auditor Auditor, contentFilter ContentFilter) Service {
 	return Service{
-		logger: logger,
-		tracer: otel.Tracer("post/service"),
-		query:  New(db),
+		logger:    logger,
+		tracer:    otel.Tracer("post/service"),
+		query:     New(db),
+		cache:     cache.NewLRU[uuid.UUID, Post](cacheSize, cacheTTL),
+		publisher: publisher,
+		mentioner: mentioner,
+		watcher:   watcher,
+		reads:     reads,
+		auditor:   auditor,
+		filter:    contentFilter,
 	}
 }
 
-func (s Service) GetAll(ctx context.Context) ([]Post, error) {
+// GetAll returns the published posts and the unpublished posts of the viewer, pinned posts first
+func (s Service) GetAll(ctx context.Context, viewerID uuid.UUID) ([]Listed, error) {
 	traceCtx, span := s.tracer.Start(ctx, "GetAll")
 	defer span.End()
 	logger := internal.LoggerWithContext(traceCtx, s.logger)
 
-	posts, err := s.query.FindAll(traceCtx)
+	rows, err := s.query.FindAll(traceCtx, viewerID)
 	if err != nil {
 		err = database.WrapDBError(err, logger, "Failed to get all posts")
 		span.RecordError(err)
 		return nil, err
 	}
 
+	posts := make([]Listed, len(rows))
+	for i, row := range rows {
+		posts[i] = Listed(row)
+	}
 	return posts, nil
 }
 
+// GetByBoard returns the posts of a board like GetAll, pinned posts first
+func (s Service) GetByBoard(ctx context.Context, viewerID, boardID uuid.UUID) ([]Listed, error) {
+	traceCtx, span := s.tracer.Start(ctx, "GetByBoard")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	rows, err := s.query.FindByBoard(traceCtx, FindByBoardParams{BoardID: boardID, ViewerID: viewerID})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "get posts by board")
+		span.RecordError(err)
+		return nil, err
+	}
+
+	posts := make([]Listed, len(rows))
+	for i, row := range rows {
+		posts[i] = Listed(row)
+	}
+	return posts, nil
+}
+
+// GetFeed returns a page of the published posts of users and boards the viewer follows or watches, newest first.
+// The returned cursor points at the last post of the page and is nil on the last page.
+func (s Service) GetFeed(ctx context.Context, viewerID uuid.UUID, pagination internal.CursorPagination) ([]Listed, *internal.Cursor, error) {
+	traceCtx, span := s.tracer.Start(ctx, "GetFeed")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	// One post more than requested tells whether another page follows
+	params := FindFeedParams{ViewerID: viewerID, Size: pagination.Size + 1}
+	if pagination.After != nil {
+		params.AfterCreateAt = pgtype.Timestamptz{Time: pagination.After.Time, Valid: true}
+		params.AfterID = pagination.After.ID
+	}
+
+	rows, err := s.query.FindFeed(traceCtx, params)
+	if err != nil {
+		err = database.WrapDBError(err, logger, "get feed")
+		span.RecordError(err)
+		return nil, nil, err
+	}
+
+	posts := make([]Listed, len(rows))
+	for i, row := range rows {
+		posts[i] = Listed(row)
+	}
+	if int32(len(posts)) <= pagination.Size {
+		return posts, nil, nil
+	}
+	posts = posts[:pagination.Size]
+	last := posts[len(posts)-1].Post
+	return posts, &internal.Cursor{Time: last.CreateAt.Time, ID: last.ID}, nil
+}
+
 func (s Service) GetByID(ctx context.Context, id uuid.UUID) (Post, error) {
 	traceCtx, span := s.tracer.Start(ctx, "GetByID")
 	defer span.End()
 	logger := internal.LoggerWithContext(traceCtx, s.logger)
 
+	if post, ok := s.cache.Get(id); ok {
+		span.AddEvent("CacheHit")
+		return post, nil
+	}
+
 	post, err := s.query.FindByID(traceCtx, id)
 	if err != nil {
 		err = database.WrapDBErrorWithKeyValue(err, "post", "id", id.String(), logger, "get post by id")
 		span.RecordError(err)
 		return Post{}, err
 	}
+
+	s.cache.Add(id, post)
 	return post, err
 }
 
+// Create stores a post and announces it, drafts and scheduled posts are announced once they are published
 func (s Service) Create(ctx context.Context, r CreateRequest) (Post, error) {
 	traceCtx, span := s.tracer.Start(ctx, "Create")
 	defer span.End()
 	logger := internal.LoggerWithContext(traceCtx, s.logger)
 
+	err := markdown.Validate(r.Content)
+	if err != nil {
+		span.RecordError(err)
+		return Post{}, err
+	}
+
+	status := StatusPublished
+	publishAt := pgtype.Timestamptz{}
+	switch {
+	case r.Draft && r.PublishAt != nil:
+		err = fmt.Errorf("%w: a draft cannot be scheduled", errorPkg.ErrInvalidRequest)
+	case r.Draft:
+		status = StatusDraft
+	case r.PublishAt != nil:
+		status = StatusScheduled
+		publishAt, err = futureTime(*r.PublishAt)
+	}
+	if err != nil {
+		span.RecordError(err)
+		return Post{}, err
+	}
+
+	verdict := s.filter.Check(traceCtx, filter.Content{AuthorID: r.AuthorID, Title: r.Title, Body: r.Content})
+	if verdict.Action == filter.ActionReject {
+		err = fmt.Errorf("%w: %s", errorPkg.ErrContentRejected, verdict.Reason)
+		span.RecordError(err)
+		return Post{}, err
+	}
+	held := verdict.Action == filter.ActionHold
+
+	tags := normalizeTags(r.Tags)
+	if tags == nil {
+		tags = []string{}
+	}
 	createdPost, err := s.query.Create(traceCtx, CreateParams{
-		AuthorID: r.AuthorID,
-		Title:    pgtype.Text{String: r.Title, Valid: true},
-		Content:  pgtype.Text{String: r.Content, Valid: true},
+		AuthorID:  r.AuthorID,
+		Title:     pgtype.Text{String: r.Title, Valid: true},
+		Content:   pgtype.Text{String: r.Content, Valid: true},
+		BoardID:   optionalUUID(r.BoardID),
+		HiddenAt:  pgtype.Timestamptz{Time: time.Now(), Valid: held},
+		Status:    status,
+		PublishAt: publishAt,
+		Tags:      tags,
 	})
 	if err != nil {
 		err = database.WrapDBError(err, logger, "create post")
 		span.RecordError(err)
 		return Post{}, err
 	}
+
+	if verdict.Action != filter.ActionAllow {
+		err = s.filter.Report(traceCtx, filter.Target{PostID: createdPost.ID}, verdict)
+		if err != nil {
+			logger.Error("Failed to report filtered post", zap.String("id", createdPost.ID.String()), zap.String("action", verdict.Action), zap.Error(err))
+		}
+	}
+	if held {
+		logger.Info("Held post for review", zap.String("id", createdPost.ID.String()), zap.String("reason", verdict.Reason))
+		return createdPost, nil
+	}
+	if status != StatusPublished {
+		logger.Info("Saved unpublished post", zap.String("id", createdPost.ID.String()), zap.String("status", status))
+		return createdPost, nil
+	}
+
+	s.announce(traceCtx, createdPost)
 	return createdPost, nil
 }
+
+func (s Service) Update(ctx context.Context, id uuid.UUID, r UpdateRequest) (Post, error) {
+	traceCtx, span := s.tracer.Start(ctx, "Update")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	err := markdown.Validate(r.Content)
+	if err != nil {
+		span.RecordError(err)
+		return Post{}, err
+	}
+
+	post, err := s.GetByID(traceCtx, id)
+	if err != nil {
+		span.RecordError(err)
+		return Post{}, err
+	}
+	if post.ArchivedAt.Valid {
+		err = errorPkg.ErrPostArchived
+		span.RecordError(err)
+		return Post{}, err
+	}
+
+	target := filter.Target{PostID: id}
+	verdict := s.filter.Check(traceCtx, filter.Content{AuthorID: post.AuthorID, Title: r.Title, Body: r.Content, Edited: target})
+	if verdict.Action == filter.ActionReject {
+		err = fmt.Errorf("%w: %s", errorPkg.ErrContentRejected, verdict.Reason)
+		span.RecordError(err)
+		return Post{}, err
+	}
+	held := verdict.Action == filter.ActionHold
+
+	updatedPost, err := s.query.Update(traceCtx, UpdateParams{
+		ID:      id,
+		Title:   pgtype.Text{String: r.Title, Valid: true},
+		Content: pgtype.Text{String: r.Content, Valid: true},
+		Tags:    normalizeTags(r.Tags),
+		Hold:    held,
+	})
+	if err != nil {
+		err = database.WrapDBErrorWithKeyValue(err, "post", "id", id.String(), logger, "update post")
+		span.RecordError(err)
+		return Post{}, err
+	}
+
+	s.cache.Remove(id)
+	if verdict.Action != filter.ActionAllow {
+		err = s.filter.Report(traceCtx, target, verdict)
+		if err != nil {
+			logger.Error("Failed to report filtered post", zap.String("id", id.String()), zap.String("action", verdict.Action), zap.Error(err))
+		}
+	}
+	if held {
+		logger.Info("Held edited post for review", zap.String("id", id.String()), zap.String("reason", verdict.Reason))
+		if post.Published() && !post.HiddenAt.Valid {
+			// the post disappears for everyone else like a hidden post
+			s.publish(traceCtx, event.PostDeleted, map[string]string{"id": id.String()})
+		}
+		return updatedPost, nil
+	}
+	if updatedPost.HiddenAt.Valid {
+		// hidden posts are announced again once a moderator approves them
+		return updatedPost, nil
+	}
+	if !updatedPost.Published() {
+		// the changes are announced with the post once it is published
+		return updatedPost, nil
+	}
+
+	s.publish(traceCtx, event.PostUpdated, GenerateResponse(updatedPost))
+	s.syncMentions(traceCtx, updatedPost)
+	return updatedPost, nil
+}
+
+func (s Service) Delete(ctx context.Context, id uuid.UUID) error {
+	traceCtx, span := s.tracer.Start(ctx, "Delete")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	count, err := s.query.Delete(traceCtx, id)
+	s.cache.Remove(id)
+	if err != nil {
+		err = database.WrapDBErrorWithKeyValue(err, "post", "id", id.String(), logger, "delete post")
+		span.RecordError(err)
+		return err
+	}
+	if count == 0 {
+		err = errorPkg.NewNotFoundError("post", "id", id.String(), "")
+		span.RecordError(err)
+		return err
+	}
+
+	logger.Debug("Deleted post", zap.String("id", id.String()), zap.Int64("affected_rows", count))
+	s.record(traceCtx, audit.Entry{Action: ActionDelete, TargetType: audit.TargetPost, TargetID: id})
+	s.publish(traceCtx, event.PostDeleted, map[string]string{"id": id.String()})
+	return nil
+}
+
+// Hide removes the post from every read endpoint without deleting it, clients see it as deleted
+func (s Service) Hide(ctx context.Context, id uuid.UUID) error {
+	traceCtx, span := s.tracer.Start(ctx, "Hide")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	count, err := s.query.Hide(traceCtx, id)
+	s.cache.Remove(id)
+	if err != nil {
+		err = database.WrapDBErrorWithKeyValue(err, "post", "id", id.String(), logger, "hide post")
+		span.RecordError(err)
+		return err
+	}
+	if count == 0 {
+		err = errorPkg.NewNotFoundError("post", "id", id.String(), "")
+		span.RecordError(err)
+		return err
+	}
+
+	s.publish(traceCtx, event.PostDeleted, map[string]string{"id": id.String()})
+	return nil
+}
+
+// Lock stops new comments on the post
+func (s Service) Lock(ctx context.Context, actorID, id uuid.UUID) (Post, error) {
+	return s.moderate(ctx, "Lock", audit.Entry{ActorID: actorID, Action: ActionLock}, id, s.query.Lock)
+}
+
+func (s Service) Unlock(ctx context.Context, actorID, id uuid.UUID) (Post, error) {
+	return s.moderate(ctx, "Unlock", audit.Entry{ActorID: actorID, Action: ActionUnlock}, id, s.query.Unlock)
+}
+
+// Pin puts the post on top of the post list of the scope, pinning a pinned post again moves it to the top
+func (s Service) Pin(ctx context.Context, actorID, id uuid.UUID, scope string) (Post, error) {
+	if scope == PinBoard {
+		post, err := s.GetByID(ctx, id)
+		if err != nil {
+			return Post{}, err
+		}
+		if !post.BoardID.Valid {
+			return Post{}, fmt.Errorf("%w: post %s belongs to no board", errorPkg.ErrInvalidRequest, id)
+		}
+	}
+
+	return s.moderate(ctx, "Pin", audit.Entry{ActorID: actorID, Action: ActionPin, Details: scope}, id, func(ctx context.Context, id uuid.UUID) (Post, error) {
+		return s.query.Pin(ctx, PinParams{ID: id, PinScope: pgtype.Text{String: scope, Valid: true}})
+	})
+}
+
+func (s Service) Unpin(ctx context.Context, actorID, id uuid.UUID) (Post, error) {
+	return s.moderate(ctx, "Unpin", audit.Entry{ActorID: actorID, Action: ActionUnpin}, id, s.query.Unpin)
+}
+
+// Archive makes the post read-only, it can no longer be edited or commented on
+func (s Service) Archive(ctx context.Context, actorID, id uuid.UUID) (Post, error) {
+	return s.moderate(ctx, "Archive", audit.Entry{ActorID: actorID, Action: ActionArchive}, id, s.query.Archive)
+}
+
+func (s Service) Unarchive(ctx context.Context, actorID, id uuid.UUID) (Post, error) {
+	return s.moderate(ctx, "Unarchive", audit.Entry{ActorID: actorID, Action: ActionUnarchive}, id, s.query.Unarchive)
+}
+
+// moderate applies a moderation update to the post and records it in the audit trail. The trail is written after
+// the update, a failure is logged but does not fail the action that already happened.
+func (s Service) moderate(ctx context.Context, spanName string, entry audit.Entry, id uuid.UUID, update func(ctx context.Context, id uuid.UUID) (Post, error)) (Post, error) {
+	traceCtx, span := s.tracer.Start(ctx, spanName)
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	post, err := update(traceCtx, id)
+	s.cache.Remove(id)
+	if err != nil {
+		err = database.WrapDBErrorWithKeyValue(err, "post", "id", id.String(), logger, entry.Action)
+		span.RecordError(err)
+		return Post{}, err
+	}
+
+	entry.TargetType = audit.TargetPost
+	entry.TargetID = id
+	s.record(traceCtx, entry)
+
+	if post.Published() {
+		// like Update, unpublished posts stay unannounced until they are published
+		s.publish(traceCtx, event.PostUpdated, GenerateResponse(post))
+	}
+	return post, nil
+}
+
+// record adds the entry to the audit trail, a failure is logged but does not undo the action
+func (s Service) record(ctx context.Context, entry audit.Entry) {
+	err := s.auditor.Record(ctx, entry)
+	if err != nil {
+		internal.LoggerWithContext(ctx, s.logger).Error("Failed to record audit entry", zap.String("id", entry.TargetID.String()), zap.String("action", entry.Action), zap.Error(err))
+	}
+}
+
+// Unhide makes a hidden post visible again and announces it like a new post, so approving a held post notifies its
+// mentions. Posts that are not hidden are left alone.
+func (s Service) Unhide(ctx context.Context, id uuid.UUID) error {
+	traceCtx, span := s.tracer.Start(ctx, "Unhide")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	post, err := s.query.Unhide(traceCtx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		_, err = s.GetByID(traceCtx, id)
+		if err != nil {
+			span.RecordError(err)
+		}
+		return err
+	}
+	if err != nil {
+		err = database.WrapDBErrorWithKeyValue(err, "post", "id", id.String(), logger, "unhide post")
+		span.RecordError(err)
+		return err
+	}
+
+	s.cache.Remove(id)
+	if post.Published() {
+		s.announce(traceCtx, post)
+	}
+	return nil
+}
+
+// Publish publishes a draft or scheduled post now, or schedules it if publishAt is in the future. Published posts
+// are announced like new posts.
+func (s Service) Publish(ctx context.Context, id uuid.UUID, publishAt *time.Time) (Post, error) {
+	traceCtx, span := s.tracer.Start(ctx, "Publish")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	var post Post
+	var err error
+	if publishAt != nil && publishAt.After(time.Now()) {
+		post, err = s.query.Schedule(traceCtx, ScheduleParams{ID: id, PublishAt: pgtype.Timestamptz{Time: *publishAt, Valid: true}})
+	} else {
+		post, err = s.query.Publish(traceCtx, id)
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		// the post is missing or already published
+		_, err = s.GetByID(traceCtx, id)
+		if err == nil {
+			err = fmt.Errorf("%w: the post is already published", errorPkg.ErrInvalidRequest)
+		}
+		span.RecordError(err)
+		return Post{}, err
+	}
+	if err != nil {
+		err = database.WrapDBErrorWithKeyValue(err, "post", "id", id.String(), logger, "publish post")
+		span.RecordError(err)
+		return Post{}, err
+	}
+
+	s.cache.Remove(id)
+	logger.Info("Changed post status", zap.String("id", id.String()), zap.String("status", post.Status))
+	if post.Published() && !post.HiddenAt.Valid {
+		s.announce(traceCtx, post)
+	}
+	return post, nil
+}
+
+// RunCacheInvalidation removes posts from the cache when an event announces a change, including changes made by
+// other backend instances, until ctx is done. Events missed while resubscribing clear the whole cache.
+func (s Service) RunCacheInvalidation(ctx context.Context, subscriber Subscriber) {
+	for ctx.Err() == nil {
+		events, cancel := subscriber.Subscribe()
+		s.cache.Clear()
+		s.invalidate(ctx, events)
+		cancel()
+	}
+}
+
+// invalidate handles events until ctx is done or the subscription is closed
+func (s Service) invalidate(ctx context.Context, events <-chan event.Event) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			if e.Type != event.PostCreated && e.Type != event.PostUpdated && e.Type != event.PostDeleted {
+				continue
+			}
+			var payload struct {
+				ID uuid.UUID `json:"id"`
+			}
+			err := json.Unmarshal(e.Payload, &payload)
+			if err != nil {
+				s.logger.Warn("Failed to read post event", zap.Int64("event_id", e.ID), zap.Error(err))
+				continue
+			}
+			s.cache.Remove(payload.ID)
+		}
+	}
+}
+
+// RunScheduler publishes scheduled posts once they are due until the context is done
+func (s Service) RunScheduler(ctx context.Context) {
+	ticker := time.NewTicker(scheduleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.publishDue(ctx)
+		}
+	}
+}
+
+func (s Service) publishDue(ctx context.Context) {
+	traceCtx, span := s.tracer.Start(ctx, "PublishDue")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	posts, err := s.query.PublishDue(traceCtx, scheduleBatch)
+	if err != nil {
+		err = database.WrapDBError(err, logger, "publish due posts")
+		span.RecordError(err)
+		return
+	}
+
+	for _, post := range posts {
+		s.cache.Remove(post.ID)
+		logger.Info("Published scheduled post", zap.String("id", post.ID.String()))
+		if !post.HiddenAt.Valid {
+			s.announce(traceCtx, post)
+		}
+	}
+}
+
+// GetMentions returns the resolved mentions in the content of the given posts, keyed by post ID
+func (s Service) GetMentions(ctx context.Context, postIDs []uuid.UUID) (map[uuid.UUID][]mention.Span, error) {
+	traceCtx, span := s.tracer.Start(ctx, "GetMentions")
+	defer span.End()
+
+	mentions, err := s.mentioner.GetByPosts(traceCtx, postIDs)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return mentions, nil
+}
+
+// CountUnreadComments returns the number of comments the user has not read for each of the given posts, posts
+// without unread comments are missing from the result
+func (s Service) CountUnreadComments(ctx context.Context, userID uuid.UUID, postIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	traceCtx, span := s.tracer.Start(ctx, "CountUnreadComments")
+	defer span.End()
+
+	counts, err := s.reads.CountUnread(traceCtx, userID, postIDs)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// announce runs the side effects of a post becoming visible: the author watches the thread, clients hear about the
+// post and mentioned users are notified
+func (s Service) announce(ctx context.Context, post Post) {
+	// the author hears about comments on the new thread until they change the watch level
+	err := s.watcher.WatchIfAbsent(ctx, post.AuthorID, post.ID)
+	if err != nil {
+		internal.LoggerWithContext(ctx, s.logger).Warn("Failed to watch created post", zap.String("id", post.ID.String()), zap.Error(err))
+	}
+
+	s.publish(ctx, event.PostCreated, GenerateResponse(post))
+	s.syncMentions(ctx, post)
+}
+
+// syncMentions stores the mentions of the post content, a failure is logged but does not fail the write
+func (s Service) syncMentions(ctx context.Context, post Post) {
+	_, err := s.mentioner.Sync(ctx, mention.Target{PostID: post.ID}, post.AuthorID, post.Content.String)
+	if err != nil {
+		internal.LoggerWithContext(ctx, s.logger).Warn("Failed to sync post mentions", zap.String("id", post.ID.String()), zap.Error(err))
+	}
+}
+
+// publish announces a change, a failure is logged but does not fail the write that already happened
+func (s Service) publish(ctx context.Context, eventType string, payload any) {
+	err := s.publisher.Publish(ctx, eventType, payload)
+	if err != nil {
+		internal.LoggerWithContext(ctx, s.logger).Warn("Failed to publish post event", zap.String("type", eventType), zap.Error(err))
+	}
+}
+
+// Published reports whether the post is visible to everyone and not only to its author
+func (p Post) Published() bool {
+	return p.Status == StatusPublished
+}
+
+func futureTime(t time.Time) (pgtype.Timestamptz, error) {
+	if !t.After(time.Now()) {
+		return pgtype.Timestamptz{}, fmt.Errorf("%w: publish_at must be in the future", errorPkg.ErrInvalidRequest)
+	}
+	return pgtype.Timestamptz{Time: t, Valid: true}, nil
+}
+
+// normalizeTags sorts the tags and removes duplicates, nil stays nil so an update keeps the tags of the post
+func normalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+	tags = slices.Clone(tags)
+	slices.Sort(tags)
+	return slices.Compact(tags)
+}
+
+func optionalUUID(id *uuid.UUID) pgtype.UUID {
+	if id == nil {
+		return pgtype.UUID{}
+	}
+	return pgtype.UUID{Bytes: *id, Valid: true}
+}
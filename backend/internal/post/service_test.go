@@ -0,0 +1,67 @@
+package post_test
+
+import (
+	"backend/internal/audit"
+	"backend/internal/database/databasetest"
+	"backend/internal/event"
+	"backend/internal/post"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+)
+
+// recorder keeps the types of the published events
+type recorder struct {
+	events []string
+}
+
+func (r *recorder) Publish(_ context.Context, eventType string, _ any) error {
+	r.events = append(r.events, eventType)
+	return nil
+}
+
+func (r *recorder) Record(context.Context, audit.Entry) error {
+	return nil
+}
+
+func TestService_Pin(t *testing.T) {
+	pool := databasetest.Open(t)
+	ctx := context.Background()
+	moderator := databasetest.CreateUser(t, pool)
+
+	tests := []struct {
+		name       string
+		status     string
+		wantEvents []string
+	}{
+		{
+			name:       "Should announce pinned published post",
+			status:     post.StatusPublished,
+			wantEvents: []string{event.PostUpdated},
+		},
+		{
+			name:   "Should not announce pinned draft",
+			status: post.StatusDraft,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{}
+			s := post.NewService(zap.NewNop(), pool, r, nil, nil, nil, r, nil)
+			postID := databasetest.CreatePost(t, pool, databasetest.CreateUser(t, pool))
+			_, err := pool.Exec(ctx, "UPDATE posts SET status = $2 WHERE id = $1", postID, tt.status)
+			if err != nil {
+				t.Fatalf("failed to set status: %v", err)
+			}
+
+			pinned, err := s.Pin(ctx, moderator, postID, post.PinGlobal)
+			if err != nil {
+				t.Fatalf("Pin() error = %v", err)
+			}
+			assert.True(t, pinned.PinnedAt.Valid)
+			assert.Equal(t, tt.wantEvents, r.events)
+		})
+	}
+}
@@ -32,32 +32,7 @@ func WriteError(ctx context.Context, w http.ResponseWriter, err error, logger *z
 		return
 	}
 
-	var problem Problem
-	var notFoundError errorPkg.NotFoundError
-	var validationErrors validator.ValidationErrors
-	var internalDbError database.InternalServerError
-	switch {
-	case errors.As(err, &notFoundError):
-		problem = NewNotFoundProblem(err.Error())
-	case errors.As(err, &validationErrors):
-		problem = NewValidateProblem(validationErrors.Error())
-	case errors.Is(err, errorPkg.ErrUserAlreadyExists):
-		problem = NewValidateProblem("User already exists")
-	case errors.Is(err, errorPkg.ErrCredentialInvalid):
-		problem = NewUnauthorizedProblem("Invalid username or password")
-	case errors.Is(err, errorPkg.ErrForbidden):
-		problem = NewForbiddenProblem("Make sure you have the right permissions")
-	case errors.Is(err, errorPkg.ErrUnauthorized):
-		problem = NewUnauthorizedProblem("You must be logged in to access this resource")
-	case errors.Is(err, errorPkg.ErrInvalidUUID):
-		problem = NewValidateProblem(err.Error())
-	case errors.As(err, &internalDbError):
-		problem = NewInternalServerProblem("Internal server error")
-	case errors.Is(err, errorPkg.ErrInvalidUUID):
-		problem = NewValidateProblem("Invalid UUID format")
-	default:
-		problem = NewInternalServerProblem("Internal server error")
-	}
+	problem := FromError(err)
 
 	logger.Warn("Handling "+problem.Title, zap.String("problem", problem.Title), zap.Error(err), zap.Int("status", problem.Status), zap.String("type", problem.Type), zap.String("detail", problem.Detail))
 
@@ -78,6 +53,68 @@ func WriteError(ctx context.Context, w http.ResponseWriter, err error, logger *z
 	}
 }
 
+// FromError maps an error of the services to the problem reported to the client, also used outside of HTTP to phrase errors
+func FromError(err error) Problem {
+	var notFoundError errorPkg.NotFoundError
+	var validationErrors validator.ValidationErrors
+	var internalDbError database.InternalServerError
+	var suspendedError errorPkg.SuspendedError
+	switch {
+	case errors.As(err, &notFoundError):
+		return NewNotFoundProblem(err.Error())
+	case errors.As(err, &validationErrors):
+		return NewValidateProblem(validationErrors.Error())
+	case errors.Is(err, errorPkg.ErrUserAlreadyExists):
+		return NewValidateProblem("User already exists")
+	case errors.Is(err, errorPkg.ErrCredentialInvalid):
+		return NewUnauthorizedProblem("Invalid username or password")
+	case errors.Is(err, errorPkg.ErrForbidden):
+		return NewForbiddenProblem("Make sure you have the right permissions")
+	case errors.Is(err, errorPkg.ErrBlocked):
+		return NewForbiddenProblem("The user has blocked you")
+	case errors.As(err, &suspendedError):
+		return NewForbiddenProblem("Your account is " + suspendedError.Error())
+	case errors.Is(err, errorPkg.ErrPostLocked):
+		return NewLockedProblem("The thread is locked and does not accept new comments")
+	case errors.Is(err, errorPkg.ErrPostArchived):
+		return NewLockedProblem("The thread is archived and read-only")
+	case errors.Is(err, errorPkg.ErrPollClosed):
+		return NewLockedProblem("The poll is closed and accepts no more votes")
+	case errors.Is(err, errorPkg.ErrRateLimited):
+		return NewTooManyRequestsProblem("Too many requests, retry after the time given in the Retry-After header")
+	case errors.Is(err, errorPkg.ErrAlreadyReported):
+		return NewConflictProblem("You already reported this content")
+	case errors.Is(err, errorPkg.ErrAlreadyVoted):
+		return NewConflictProblem("You already voted in this poll")
+	case errors.Is(err, errorPkg.ErrUnauthorized):
+		return NewUnauthorizedProblem("You must be logged in to access this resource")
+	case errors.Is(err, errorPkg.ErrInvalidUUID):
+		return NewValidateProblem(err.Error())
+	case errors.Is(err, errorPkg.ErrInvalidQuery):
+		return NewValidateProblem(err.Error())
+	case errors.Is(err, errorPkg.ErrInvalidRequest):
+		return NewValidateProblem(err.Error())
+	case errors.Is(err, errorPkg.ErrContentRejected):
+		return NewValidateProblem(err.Error())
+	case errors.Is(err, errorPkg.ErrInvalidMarkdown):
+		return NewValidateProblem(err.Error())
+	case errors.Is(err, errorPkg.ErrFileTooLarge):
+		return NewContentTooLargeProblem(err.Error())
+	case errors.Is(err, errorPkg.ErrUnsupportedType):
+		return NewUnsupportedMediaTypeProblem(err.Error())
+	case errors.Is(err, database.ErrUniqueViolation):
+		return NewConflictProblem("Resource already exists")
+	case errors.Is(err, database.ErrForeignKeyViolation):
+		return NewValidateProblem("Referenced resource does not exist")
+	case errors.As(err, &internalDbError):
+		return NewInternalServerProblem("Internal server error")
+	case errors.Is(err, errorPkg.ErrInvalidUUID):
+		return NewValidateProblem("Invalid UUID format")
+	default:
+		return NewInternalServerProblem("Internal server error")
+	}
+}
+
 func NewInternalServerProblem(detail string) Problem {
 	return Problem{
 		Title:  "Internal Server Error",
@@ -105,6 +142,52 @@ func NewValidateProblem(detail string) Problem {
 	}
 }
 
+func NewConflictProblem(detail string) Problem {
+	return Problem{
+		Title:  "Conflict",
+		Status: http.StatusConflict,
+		Type:   "https://developer.mozilla.org/en-US/docs/Web/HTTP/Status/409",
+		Detail: detail,
+	}
+}
+
+// NewLockedProblem is used for writes to threads that moderators locked or archived
+func NewLockedProblem(detail string) Problem {
+	return Problem{
+		Title:  "Locked",
+		Status: http.StatusLocked,
+		Type:   "https://developer.mozilla.org/en-US/docs/Web/HTTP/Status/423",
+		Detail: detail,
+	}
+}
+
+func NewTooManyRequestsProblem(detail string) Problem {
+	return Problem{
+		Title:  "Too Many Requests",
+		Status: http.StatusTooManyRequests,
+		Type:   "https://developer.mozilla.org/en-US/docs/Web/HTTP/Status/429",
+		Detail: detail,
+	}
+}
+
+func NewContentTooLargeProblem(detail string) Problem {
+	return Problem{
+		Title:  "Content Too Large",
+		Status: http.StatusRequestEntityTooLarge,
+		Type:   "https://developer.mozilla.org/en-US/docs/Web/HTTP/Status/413",
+		Detail: detail,
+	}
+}
+
+func NewUnsupportedMediaTypeProblem(detail string) Problem {
+	return Problem{
+		Title:  "Unsupported Media Type",
+		Status: http.StatusUnsupportedMediaType,
+		Type:   "https://developer.mozilla.org/en-US/docs/Web/HTTP/Status/415",
+		Detail: detail,
+	}
+}
+
 func NewUnauthorizedProblem(detail string) Problem {
 	return Problem{
 		Title:  "Unauthorized",
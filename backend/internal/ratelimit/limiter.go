@@ -0,0 +1,174 @@
+package ratelimit
+
+import (
+	"backend/internal/config"
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// pruneInterval is how often Run drops the buckets of clients that went quiet
+const pruneInterval = time.Minute
+
+// Result describes the bucket of a client after a request
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is the time until the bucket is full again
+	Reset time.Duration
+	// RetryAfter is the time until the next request is allowed, zero for allowed requests
+	RetryAfter time.Duration
+}
+
+type limit struct {
+	rate  float64 // tokens per second
+	burst float64
+}
+
+type bucketKey struct {
+	route  string
+	client string
+}
+
+type bucket struct {
+	limit  limit
+	tokens float64
+	last   time.Time
+}
+
+// fill adds the tokens refilled since the last request, capped at the burst
+func (b *bucket) fill(now time.Time) {
+	elapsed := now.Sub(b.last).Seconds()
+	if elapsed > 0 {
+		b.tokens = math.Min(b.limit.burst, b.tokens+elapsed*b.limit.rate)
+		b.last = now
+	}
+}
+
+// Limiter keeps a token bucket per route and client in memory, every instance of the backend limits on its own
+type Limiter struct {
+	// limits maps a route to its limits by role, the empty role holds the default
+	limits map[string]map[string]*limit
+
+	mu      sync.Mutex
+	buckets map[bucketKey]*bucket
+}
+
+func NewLimiter(rules []config.RateLimitRule) (*Limiter, error) {
+	limits := make(map[string]map[string]*limit)
+	for _, rule := range rules {
+		if rule.Route == "" {
+			return nil, fmt.Errorf("rate limit without route")
+		}
+		if rule.Requests < 0 || rule.Burst < 0 {
+			return nil, fmt.Errorf("rate limit of %q: requests and burst must not be negative", rule.Route)
+		}
+		if _, ok := limits[rule.Route][rule.Role]; ok {
+			return nil, fmt.Errorf("rate limit of %q: duplicate rule for role %q", rule.Route, rule.Role)
+		}
+		if limits[rule.Route] == nil {
+			limits[rule.Route] = make(map[string]*limit)
+		}
+
+		// a nil limit exempts the role
+		if rule.Requests == 0 {
+			limits[rule.Route][rule.Role] = nil
+			continue
+		}
+		if rule.Per <= 0 {
+			return nil, fmt.Errorf("rate limit of %q: per must be positive", rule.Route)
+		}
+
+		burst := rule.Burst
+		if burst == 0 {
+			burst = rule.Requests
+		}
+		limits[rule.Route][rule.Role] = &limit{
+			rate:  float64(rule.Requests) / rule.Per.Seconds(),
+			burst: float64(burst),
+		}
+	}
+
+	return &Limiter{
+		limits:  limits,
+		buckets: make(map[bucketKey]*bucket),
+	}, nil
+}
+
+// Allow takes a token from the bucket of the client for the route. The second result is false if no limit applies
+// to the route and role.
+func (l *Limiter) Allow(route, role, client string, now time.Time) (Result, bool) {
+	lim := l.lookup(route, role)
+	if lim == nil {
+		return Result{}, false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := bucketKey{route: route, client: client}
+	b, ok := l.buckets[key]
+	if !ok || b.limit != *lim {
+		b = &bucket{limit: *lim, tokens: lim.burst, last: now}
+		l.buckets[key] = b
+	}
+	b.fill(now)
+
+	result := Result{Limit: int(lim.burst)}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - b.tokens) / lim.rate)
+	}
+	result.Remaining = int(b.tokens)
+	result.Reset = seconds((lim.burst - b.tokens) / lim.rate)
+
+	return result, true
+}
+
+// Run drops full buckets until the context is done, a full bucket behaves the same as a missing one
+func (l *Limiter) Run(ctx context.Context) {
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			l.prune(now)
+		}
+	}
+}
+
+func (l *Limiter) prune(now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for key, b := range l.buckets {
+		b.fill(now)
+		if b.tokens >= b.limit.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+func (l *Limiter) lookup(route, role string) *limit {
+	byRole, ok := l.limits[route]
+	if !ok {
+		return nil
+	}
+	if lim, ok := byRole[role]; ok {
+		return lim
+	}
+	return byRole[""]
+}
+
+// seconds converts a duration in seconds to a time.Duration
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
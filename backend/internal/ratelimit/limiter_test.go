@@ -0,0 +1,73 @@
+package ratelimit_test
+
+import (
+	"backend/internal/config"
+	"backend/internal/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	const route = "POST /api/posts"
+	start := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	limiter, err := ratelimit.NewLimiter([]config.RateLimitRule{
+		{Route: route, Requests: 1, Per: time.Minute, Burst: 2},
+		{Route: route, Role: "ADMIN", Requests: 0},
+	})
+	assert.NoError(t, err)
+
+	// the burst is available at once
+	for i := 1; i >= 0; i-- {
+		result, limited := limiter.Allow(route, "USER", "user:a", start)
+		assert.True(t, limited)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, i, result.Remaining)
+	}
+
+	result, _ := limiter.Allow(route, "USER", "user:a", start)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Minute, result.RetryAfter)
+	assert.Equal(t, 2*time.Minute, result.Reset)
+
+	// other clients have their own bucket
+	result, _ = limiter.Allow(route, "USER", "user:b", start)
+	assert.True(t, result.Allowed)
+
+	// one token is refilled per minute
+	result, _ = limiter.Allow(route, "USER", "user:a", start.Add(time.Minute))
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	// the role exemption and unknown routes are not limited
+	_, limited := limiter.Allow(route, "ADMIN", "user:c", start)
+	assert.False(t, limited)
+	_, limited = limiter.Allow("GET /api/posts", "USER", "user:a", start)
+	assert.False(t, limited)
+}
+
+func TestNewLimiter(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []config.RateLimitRule
+	}{
+		{name: "Should reject rule without route", rules: []config.RateLimitRule{{Requests: 1, Per: time.Second}}},
+		{name: "Should reject rule without period", rules: []config.RateLimitRule{{Route: "POST /api/posts", Requests: 1}}},
+		{name: "Should reject negative burst", rules: []config.RateLimitRule{{Route: "POST /api/posts", Requests: 1, Per: time.Second, Burst: -1}}},
+		{
+			name: "Should reject duplicate rule",
+			rules: []config.RateLimitRule{
+				{Route: "POST /api/posts", Role: "USER", Requests: 1, Per: time.Second},
+				{Route: "POST /api/posts", Role: "USER", Requests: 2, Per: time.Second},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ratelimit.NewLimiter(tt.rules)
+			assert.Error(t, err)
+		})
+	}
+}
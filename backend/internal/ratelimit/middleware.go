@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"backend/internal"
+	errorPkg "backend/internal/error"
+	"backend/internal/jwt"
+	"backend/internal/problem"
+	"context"
+	"go.uber.org/zap"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Routes of the web API whose limits also apply to the servers of other protocols, so clients cannot avoid a limit
+// by switching protocol
+const (
+	RouteLogin    = "POST /api/login"
+	RoutePosts    = "POST /api/posts"
+	RouteComments = "POST /api/post/{post_id}/comments"
+)
+
+// Middleware limits requests by the route pattern the mux matched, so it has to run inside the mux. Authenticated
+// requests are counted per user, which requires the jwt.Middleware to run first, other requests per client IP.
+// Limited routes report their bucket in the RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers.
+func (l *Limiter) Middleware(next http.HandlerFunc, logger *zap.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		role, client := "", "ip:"+internal.ClientIP(r)
+		if u, err := jwt.GetUserFromContext(r.Context()); err == nil {
+			role, client = u.Role, "user:"+u.ID
+		}
+
+		result, limited := l.Allow(r.Pattern, role, client, time.Now())
+		if !limited {
+			next(w, r)
+			return
+		}
+
+		w.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+		w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		w.Header().Set("RateLimit-Reset", ceilSeconds(result.Reset))
+
+		if !result.Allowed {
+			w.Header().Set("Retry-After", ceilSeconds(result.RetryAfter))
+			logger := internal.LoggerWithContext(r.Context(), logger)
+			logger.Info("Rate limited request", zap.String("route", r.Pattern), zap.String("client", client))
+			problem.WriteError(r.Context(), w, errorPkg.ErrRateLimited, logger)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// Check takes a token for the route like the Middleware, for the servers of other protocols. The user in ctx is
+// counted, or the client IP before login. It returns errorPkg.ErrRateLimited once the bucket is empty.
+func (l *Limiter) Check(ctx context.Context, route string) error {
+	role, client := "", "ip:"+internal.ClientIPFromContext(ctx)
+	if u, err := jwt.GetUserFromContext(ctx); err == nil {
+		role, client = u.Role, "user:"+u.ID
+	}
+
+	result, limited := l.Allow(route, role, client, time.Now())
+	if limited && !result.Allowed {
+		return errorPkg.ErrRateLimited
+	}
+	return nil
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,69 @@
+package ratelimit_test
+
+import (
+	"backend/internal"
+	"backend/internal/config"
+	errorPkg "backend/internal/error"
+	"backend/internal/jwt"
+	"backend/internal/ratelimit"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLimiter_Middleware(t *testing.T) {
+	limiter, err := ratelimit.NewLimiter([]config.RateLimitRule{
+		{Route: "POST /api/posts", Requests: 1, Per: time.Minute},
+	})
+	assert.NoError(t, err)
+
+	handler := limiter.Middleware(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}, zap.NewNop())
+
+	request := func(userID string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/api/posts", nil)
+		r.Pattern = "POST /api/posts"
+		if userID != "" {
+			r = r.WithContext(context.WithValue(r.Context(), internal.UserContextKey, jwt.User{ID: userID, Role: jwt.RoleUser}))
+		}
+		handler(w, r)
+		return w
+	}
+
+	w := request("81c1ecc1-66d7-4134-b5fe-d886a6f418a4")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "60", w.Header().Get("RateLimit-Reset"))
+
+	w = request("81c1ecc1-66d7-4134-b5fe-d886a6f418a4")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	assert.Equal(t, "60", w.Header().Get("Retry-After"))
+
+	// anonymous requests are counted per client IP
+	w = request("")
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
+
+func TestLimiter_Check(t *testing.T) {
+	limiter, err := ratelimit.NewLimiter([]config.RateLimitRule{
+		{Route: ratelimit.RouteLogin, Requests: 1, Per: time.Minute},
+	})
+	assert.NoError(t, err)
+
+	ctx := context.WithValue(context.Background(), internal.ClientIPContextKey, "192.0.2.1")
+	assert.NoError(t, limiter.Check(ctx, ratelimit.RouteLogin))
+	assert.ErrorIs(t, limiter.Check(ctx, ratelimit.RouteLogin), errorPkg.ErrRateLimited)
+	assert.NoError(t, limiter.Check(ctx, ratelimit.RoutePosts))
+
+	// a user has a bucket of its own
+	userCtx := context.WithValue(ctx, internal.UserContextKey, jwt.User{ID: "81c1ecc1-66d7-4134-b5fe-d886a6f418a4", Role: jwt.RoleUser})
+	assert.NoError(t, limiter.Check(userCtx, ratelimit.RouteLogin))
+}
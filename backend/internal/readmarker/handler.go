@@ -0,0 +1,88 @@
+package readmarker
+
+import (
+	"backend/internal"
+	errorPkg "backend/internal/error"
+	"backend/internal/jwt"
+	"backend/internal/problem"
+	"context"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+// MarkReadRequest optionally names the last comment the user read, without it the whole thread is marked read
+type MarkReadRequest struct {
+	CommentID *uuid.UUID `json:"comment_id,omitempty"`
+}
+
+//go:generate mockery --name=Store
+type Store interface {
+	MarkAllRead(ctx context.Context, userID, postID uuid.UUID) error
+	MarkReadUpTo(ctx context.Context, userID, postID, commentID uuid.UUID) error
+}
+
+type Handler struct {
+	validator *validator.Validate
+	logger    *zap.Logger
+	tracer    trace.Tracer
+	store     Store
+}
+
+func NewHandler(v *validator.Validate, logger *zap.Logger, store Store) *Handler {
+	return &Handler{
+		validator: v,
+		logger:    logger,
+		tracer:    otel.Tracer("readmarker/handler"),
+		store:     store,
+	}
+}
+
+// MarkReadHandler moves the read marker of the current user on the post forward. The request body is optional.
+func (h *Handler) MarkReadHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "MarkPostReadEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	u, err := jwt.GetUserFromContext(traceCtx)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+	userID, err := internal.ParseUUID(u.ID)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	postID, err := internal.ParseUUID(r.PathValue("post_id"))
+	if err != nil {
+		problem.WriteError(traceCtx, w, fmt.Errorf("%w: %v", errorPkg.ErrInvalidUUID, err), logger)
+		return
+	}
+
+	var request MarkReadRequest
+	if r.ContentLength != 0 {
+		err = internal.ParseAndValidateRequestBody(traceCtx, h.validator, r, &request)
+		if err != nil {
+			problem.WriteError(traceCtx, w, err, logger)
+			return
+		}
+	}
+
+	if request.CommentID != nil {
+		err = h.store.MarkReadUpTo(traceCtx, userID, postID, *request.CommentID)
+	} else {
+		err = h.store.MarkAllRead(traceCtx, userID, postID)
+	}
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,20 @@
+-- name: MarkAllRead :exec
+INSERT INTO read_markers (user_id, post_id, last_read_at) VALUES (@user_id, @post_id, now())
+ON CONFLICT (user_id, post_id) DO UPDATE SET last_read_at = GREATEST(read_markers.last_read_at, EXCLUDED.last_read_at);
+
+-- name: MarkReadUpTo :execrows
+-- Markers only move forward, reading an older comment again does not make newer ones unread
+INSERT INTO read_markers (user_id, post_id, last_read_at)
+SELECT @user_id, c.post_id, c.created_at FROM comments c WHERE c.id = @comment_id AND c.post_id = @post_id
+ON CONFLICT (user_id, post_id) DO UPDATE SET last_read_at = GREATEST(read_markers.last_read_at, EXCLUDED.last_read_at);
+
+-- name: CountUnread :many
+-- Comments of the user count as read
+SELECT c.post_id, count(*) AS unread
+FROM comments c
+LEFT JOIN read_markers m ON m.post_id = c.post_id AND m.user_id = @user_id
+WHERE c.post_id = ANY(@post_ids::uuid[])
+  AND c.author_id <> @user_id
+  AND c.hidden_at IS NULL
+  AND (m.last_read_at IS NULL OR c.created_at > m.last_read_at)
+GROUP BY c.post_id;
@@ -0,0 +1,90 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: queries.sql
+
+package readmarker
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const countUnread = `-- name: CountUnread :many
+SELECT c.post_id, count(*) AS unread
+FROM comments c
+LEFT JOIN read_markers m ON m.post_id = c.post_id AND m.user_id = $1
+WHERE c.post_id = ANY($2::uuid[])
+  AND c.author_id <> $1
+  AND c.hidden_at IS NULL
+  AND (m.last_read_at IS NULL OR c.created_at > m.last_read_at)
+GROUP BY c.post_id
+`
+
+type CountUnreadParams struct {
+	UserID  uuid.UUID
+	PostIds []uuid.UUID
+}
+
+type CountUnreadRow struct {
+	PostID uuid.UUID
+	Unread int64
+}
+
+// Comments of the user count as read
+func (q *Queries) CountUnread(ctx context.Context, arg CountUnreadParams) ([]CountUnreadRow, error) {
+	rows, err := q.db.Query(ctx, countUnread, arg.UserID, arg.PostIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountUnreadRow
+	for rows.Next() {
+		var i CountUnreadRow
+		if err := rows.Scan(&i.PostID, &i.Unread); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markAllRead = `-- name: MarkAllRead :exec
+INSERT INTO read_markers (user_id, post_id, last_read_at) VALUES ($1, $2, now())
+ON CONFLICT (user_id, post_id) DO UPDATE SET last_read_at = GREATEST(read_markers.last_read_at, EXCLUDED.last_read_at)
+`
+
+type MarkAllReadParams struct {
+	UserID uuid.UUID
+	PostID uuid.UUID
+}
+
+func (q *Queries) MarkAllRead(ctx context.Context, arg MarkAllReadParams) error {
+	_, err := q.db.Exec(ctx, markAllRead, arg.UserID, arg.PostID)
+	return err
+}
+
+const markReadUpTo = `-- name: MarkReadUpTo :execrows
+INSERT INTO read_markers (user_id, post_id, last_read_at)
+SELECT $1, c.post_id, c.created_at FROM comments c WHERE c.id = $2 AND c.post_id = $3
+ON CONFLICT (user_id, post_id) DO UPDATE SET last_read_at = GREATEST(read_markers.last_read_at, EXCLUDED.last_read_at)
+`
+
+type MarkReadUpToParams struct {
+	UserID    uuid.UUID
+	CommentID uuid.UUID
+	PostID    uuid.UUID
+}
+
+// Markers only move forward, reading an older comment again does not make newer ones unread
+func (q *Queries) MarkReadUpTo(ctx context.Context, arg MarkReadUpToParams) (int64, error) {
+	result, err := q.db.Exec(ctx, markReadUpTo, arg.UserID, arg.CommentID, arg.PostID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,89 @@
+package readmarker
+
+import (
+	"backend/internal"
+	"backend/internal/database"
+	errorPkg "backend/internal/error"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+type Service struct {
+	logger *zap.Logger
+	tracer trace.Tracer
+	query  *Queries
+}
+
+func NewService(logger *zap.Logger, db DBTX) *Service {
+	return &Service{
+		logger: logger,
+		tracer: otel.Tracer("readmarker/service"),
+		query:  New(db),
+	}
+}
+
+// MarkAllRead marks every comment of the post that exists now as read by the user
+func (s *Service) MarkAllRead(ctx context.Context, userID, postID uuid.UUID) error {
+	traceCtx, span := s.tracer.Start(ctx, "MarkAllRead")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	err := s.query.MarkAllRead(traceCtx, MarkAllReadParams{UserID: userID, PostID: postID})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "mark post read")
+		if errors.Is(err, database.ErrForeignKeyViolation) {
+			err = errorPkg.NewNotFoundError("posts", "id", postID.String(), "")
+		}
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// MarkReadUpTo marks the comment and every comment of the post written before it as read by the user
+func (s *Service) MarkReadUpTo(ctx context.Context, userID, postID, commentID uuid.UUID) error {
+	traceCtx, span := s.tracer.Start(ctx, "MarkReadUpTo")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	count, err := s.query.MarkReadUpTo(traceCtx, MarkReadUpToParams{UserID: userID, PostID: postID, CommentID: commentID})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "mark post read up to comment")
+		span.RecordError(err)
+		return err
+	}
+	if count == 0 {
+		err = errorPkg.NewNotFoundError("comments", "id", commentID.String(), "")
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// CountUnread returns the number of comments the user has not read yet for each of the given posts, posts without
+// unread comments are missing from the result
+func (s *Service) CountUnread(ctx context.Context, userID uuid.UUID, postIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	traceCtx, span := s.tracer.Start(ctx, "CountUnread")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	rows, err := s.query.CountUnread(traceCtx, CountUnreadParams{UserID: userID, PostIds: postIDs})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "count unread comments")
+		span.RecordError(err)
+		return nil, err
+	}
+
+	result := make(map[uuid.UUID]int64, len(rows))
+	for _, row := range rows {
+		result[row.PostID] = row.Unread
+	}
+
+	return result, nil
+}
@@ -0,0 +1,165 @@
+package readmarker_test
+
+import (
+	"backend/internal/database/databasetest"
+	errorPkg "backend/internal/error"
+	"backend/internal/readmarker"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func TestService_MarkReadUpTo(t *testing.T) {
+	pool := databasetest.Open(t)
+	s := readmarker.NewService(zap.NewNop(), pool)
+	ctx := context.Background()
+
+	author := databasetest.CreateUser(t, pool)
+	postID := databasetest.CreatePost(t, pool, author)
+	otherPostID := databasetest.CreatePost(t, pool, author)
+	start := time.Now().Add(-time.Hour)
+	first := databasetest.CreateComment(t, pool, postID, author, start)
+	second := databasetest.CreateComment(t, pool, postID, author, start.Add(time.Minute))
+	last := databasetest.CreateComment(t, pool, postID, author, start.Add(2*time.Minute))
+	otherComment := databasetest.CreateComment(t, pool, otherPostID, author, start)
+
+	tests := []struct {
+		name       string
+		marks      []uuid.UUID
+		postID     uuid.UUID
+		wantErr    error
+		wantUnread int64
+	}{
+		{
+			name:       "Should count every comment without marker",
+			wantUnread: 3,
+		},
+		{
+			name:       "Should mark the comment and older comments read",
+			marks:      []uuid.UUID{second},
+			wantUnread: 1,
+		},
+		{
+			name:       "Should mark every comment read",
+			marks:      []uuid.UUID{last},
+			wantUnread: 0,
+		},
+		{
+			name:       "Should not move the marker back",
+			marks:      []uuid.UUID{second, first},
+			wantUnread: 1,
+		},
+		{
+			name:       "Should move the marker forward",
+			marks:      []uuid.UUID{first, last},
+			wantUnread: 0,
+		},
+		{
+			name:       "Should reject comment of another post",
+			marks:      []uuid.UUID{otherComment},
+			wantErr:    errorPkg.ErrNotFound,
+			wantUnread: 3,
+		},
+		{
+			name:       "Should reject unknown comment",
+			marks:      []uuid.UUID{uuid.New()},
+			wantErr:    errorPkg.ErrNotFound,
+			wantUnread: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := databasetest.CreateUser(t, pool)
+
+			var err error
+			for _, commentID := range tt.marks {
+				err = s.MarkReadUpTo(ctx, reader, postID, commentID)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("MarkReadUpTo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			unread, err := s.CountUnread(ctx, reader, []uuid.UUID{postID})
+			if err != nil {
+				t.Fatalf("CountUnread() error = %v", err)
+			}
+			assert.Equal(t, tt.wantUnread, unread[postID])
+		})
+	}
+}
+
+func TestService_CountUnread(t *testing.T) {
+	pool := databasetest.Open(t)
+	s := readmarker.NewService(zap.NewNop(), pool)
+	ctx := context.Background()
+	start := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name  string
+		setup func(t *testing.T, reader, postID uuid.UUID)
+		want  int64
+	}{
+		{
+			name: "Should count comments of other users",
+			setup: func(t *testing.T, reader, postID uuid.UUID) {
+				other := databasetest.CreateUser(t, pool)
+				databasetest.CreateComment(t, pool, postID, other, start)
+				databasetest.CreateComment(t, pool, postID, other, start.Add(time.Minute))
+			},
+			want: 2,
+		},
+		{
+			name: "Should not count own comments",
+			setup: func(t *testing.T, reader, postID uuid.UUID) {
+				databasetest.CreateComment(t, pool, postID, reader, start)
+			},
+			want: 0,
+		},
+		{
+			name: "Should not count hidden comments",
+			setup: func(t *testing.T, reader, postID uuid.UUID) {
+				other := databasetest.CreateUser(t, pool)
+				id := databasetest.CreateComment(t, pool, postID, other, start)
+				_, err := pool.Exec(ctx, "UPDATE comments SET hidden_at = now() WHERE id = $1", id)
+				if err != nil {
+					t.Fatalf("failed to hide comment: %v", err)
+				}
+			},
+			want: 0,
+		},
+		{
+			name: "Should only count comments written after marking the post read",
+			setup: func(t *testing.T, reader, postID uuid.UUID) {
+				other := databasetest.CreateUser(t, pool)
+				databasetest.CreateComment(t, pool, postID, other, start)
+				err := s.MarkAllRead(ctx, reader, postID)
+				if err != nil {
+					t.Fatalf("MarkAllRead() error = %v", err)
+				}
+				databasetest.CreateComment(t, pool, postID, other, time.Now().Add(time.Minute))
+			},
+			want: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := databasetest.CreateUser(t, pool)
+			postID := databasetest.CreatePost(t, pool, databasetest.CreateUser(t, pool))
+			tt.setup(t, reader, postID)
+
+			unread, err := s.CountUnread(ctx, reader, []uuid.UUID{postID})
+			if err != nil {
+				t.Fatalf("CountUnread() error = %v", err)
+			}
+			got, ok := unread[postID]
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.want > 0, ok, "posts without unread comments are left out")
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package report
+
+import (
+	"backend/internal"
+	errorPkg "backend/internal/error"
+	"backend/internal/jwt"
+	"backend/internal/problem"
+	"context"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+type ReportRequest struct {
+	Reason  string `json:"reason"  validate:"required,oneof=spam harassment off_topic illegal other"`
+	Details string `json:"details" validate:"max=2000"`
+}
+
+type ResolveRequest struct {
+	Action string `json:"action" validate:"required,oneof=dismiss hide warn approve"`
+	Note   string `json:"note"   validate:"max=2000"`
+}
+
+type Response struct {
+	ID        string `json:"id"`
+	PostID    string `json:"post_id"`
+	CommentID string `json:"comment_id,omitempty"`
+	Reason    string `json:"reason"`
+	Details   string `json:"details,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
+
+type QueueEntryResponse struct {
+	PostID          string   `json:"post_id"`
+	CommentID       string   `json:"comment_id,omitempty"`
+	ReportCount     int64    `json:"report_count"`
+	Reasons         []string `json:"reasons"`
+	Details         []string `json:"details,omitempty"`
+	FirstReportedAt string   `json:"first_reported_at"`
+	LastReportedAt  string   `json:"last_reported_at"`
+}
+
+//go:generate mockery --name=Store
+type Store interface {
+	Report(ctx context.Context, reporterID uuid.UUID, target Target, request ReportRequest) (Report, error)
+	CommentTarget(ctx context.Context, commentID uuid.UUID) (Target, error)
+	GetQueue(ctx context.Context, pagination internal.Pagination) ([]QueueEntry, error)
+	Resolve(ctx context.Context, moderatorID uuid.UUID, target Target, request ResolveRequest) error
+}
+
+type Handler struct {
+	validator *validator.Validate
+	logger    *zap.Logger
+	tracer    trace.Tracer
+	store     Store
+}
+
+func NewHandler(v *validator.Validate, logger *zap.Logger, store Store) *Handler {
+	return &Handler{
+		validator: v,
+		logger:    logger,
+		tracer:    otel.Tracer("report/handler"),
+		store:     store,
+	}
+}
+
+func (h *Handler) ReportPostHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "ReportPostEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	postID, err := internal.ParseUUID(r.PathValue("id"))
+	if err != nil {
+		problem.WriteError(traceCtx, w, fmt.Errorf("%w: %v", errorPkg.ErrInvalidUUID, err), logger)
+		return
+	}
+
+	h.report(w, r.WithContext(traceCtx), Target{PostID: postID})
+}
+
+func (h *Handler) ReportCommentHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "ReportCommentEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	commentID, err := internal.ParseUUID(r.PathValue("id"))
+	if err != nil {
+		problem.WriteError(traceCtx, w, fmt.Errorf("%w: %v", errorPkg.ErrInvalidUUID, err), logger)
+		return
+	}
+
+	target, err := h.store.CommentTarget(traceCtx, commentID)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	h.report(w, r.WithContext(traceCtx), target)
+}
+
+// QueueHandler lists the open reports for moderators, grouped by the reported post or comment
+func (h *Handler) QueueHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "ModerationQueueEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	pagination, err := internal.ParsePagination(r)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	entries, err := h.store.GetQueue(traceCtx, pagination)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	response := make([]QueueEntryResponse, len(entries))
+	for i, entry := range entries {
+		response[i] = GenerateQueueEntryResponse(entry)
+	}
+
+	internal.WriteJSONResponse(w, http.StatusOK, response)
+}
+
+// ResolvePostHandler closes the open reports about a post with the action of the moderator
+func (h *Handler) ResolvePostHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "ResolvePostReportsEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	postID, err := internal.ParseUUID(r.PathValue("id"))
+	if err != nil {
+		problem.WriteError(traceCtx, w, fmt.Errorf("%w: %v", errorPkg.ErrInvalidUUID, err), logger)
+		return
+	}
+
+	h.resolve(w, r.WithContext(traceCtx), Target{PostID: postID})
+}
+
+// ResolveCommentHandler closes the open reports about a comment with the action of the moderator
+func (h *Handler) ResolveCommentHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "ResolveCommentReportsEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	commentID, err := internal.ParseUUID(r.PathValue("id"))
+	if err != nil {
+		problem.WriteError(traceCtx, w, fmt.Errorf("%w: %v", errorPkg.ErrInvalidUUID, err), logger)
+		return
+	}
+
+	target, err := h.store.CommentTarget(traceCtx, commentID)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	h.resolve(w, r.WithContext(traceCtx), target)
+}
+
+func (h *Handler) report(w http.ResponseWriter, r *http.Request, target Target) {
+	ctx := r.Context()
+	logger := internal.LoggerWithContext(ctx, h.logger)
+
+	reporterID, err := jwt.GetUserIDFromContext(ctx)
+	if err != nil {
+		problem.WriteError(ctx, w, err, logger)
+		return
+	}
+
+	var request ReportRequest
+	err = internal.ParseAndValidateRequestBody(ctx, h.validator, r, &request)
+	if err != nil {
+		problem.WriteError(ctx, w, err, logger)
+		return
+	}
+
+	report, err := h.store.Report(ctx, reporterID, target, request)
+	if err != nil {
+		problem.WriteError(ctx, w, err, logger)
+		return
+	}
+
+	internal.WriteJSONResponse(w, http.StatusOK, GenerateResponse(report))
+}
+
+func (h *Handler) resolve(w http.ResponseWriter, r *http.Request, target Target) {
+	ctx := r.Context()
+	logger := internal.LoggerWithContext(ctx, h.logger)
+
+	moderatorID, err := jwt.GetUserIDFromContext(ctx)
+	if err != nil {
+		problem.WriteError(ctx, w, err, logger)
+		return
+	}
+
+	var request ResolveRequest
+	err = internal.ParseAndValidateRequestBody(ctx, h.validator, r, &request)
+	if err != nil {
+		problem.WriteError(ctx, w, err, logger)
+		return
+	}
+
+	err = h.store.Resolve(ctx, moderatorID, target, request)
+	if err != nil {
+		problem.WriteError(ctx, w, err, logger)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func GenerateResponse(report Report) Response {
+	response := Response{
+		ID:        report.ID.String(),
+		PostID:    report.PostID.String(),
+		Reason:    report.Reason,
+		Details:   report.Details.String,
+		CreatedAt: report.CreatedAt.Time.Format(time.RFC3339),
+	}
+	if report.CommentID.Valid {
+		response.CommentID = uuid.UUID(report.CommentID.Bytes).String()
+	}
+
+	return response
+}
+
+func GenerateQueueEntryResponse(entry QueueEntry) QueueEntryResponse {
+	response := QueueEntryResponse{
+		PostID:          entry.PostID.String(),
+		ReportCount:     entry.ReportCount,
+		Reasons:         entry.Reasons,
+		Details:         entry.Details,
+		FirstReportedAt: entry.FirstReportedAt.Time.Format(time.RFC3339),
+		LastReportedAt:  entry.LastReportedAt.Time.Format(time.RFC3339),
+	}
+	if entry.CommentID.Valid {
+		response.CommentID = uuid.UUID(entry.CommentID.Bytes).String()
+	}
+
+	return response
+}
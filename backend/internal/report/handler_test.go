@@ -0,0 +1,81 @@
+package report_test
+
+import (
+	"backend/internal"
+	errorPkg "backend/internal/error"
+	"backend/internal/jwt"
+	"backend/internal/report"
+	"backend/internal/report/mocks"
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler_ResolveCommentHandler(t *testing.T) {
+	moderator := jwt.User{
+		ID:       "81c1ecc1-66d7-4134-b5fe-d886a6f418a4",
+		Username: "mod",
+		Role:     jwt.RoleModerator,
+	}
+	commentID := uuid.MustParse("2a7d1b7e-6c53-4f6b-8d9e-0c1b2a3d4e5f")
+	target := report.Target{PostID: uuid.MustParse("1f0b3c8e-3b0a-4d8e-9f3e-9b1b0f6b7c11"), CommentID: commentID}
+
+	tests := []struct {
+		name       string
+		body       string
+		setupMock  func(m *mocks.Store)
+		wantStatus int
+	}{
+		{
+			name: "Should hide reported comment",
+			body: `{"action":"hide","note":"insults"}`,
+			setupMock: func(m *mocks.Store) {
+				m.On("CommentTarget", mock.Anything, commentID).Return(target, nil)
+				m.On("Resolve", mock.Anything, uuid.MustParse(moderator.ID), target, report.ResolveRequest{Action: report.ActionHide, Note: "insults"}).
+					Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name: "Should reject unknown action",
+			body: `{"action":"ban"}`,
+			setupMock: func(m *mocks.Store) {
+				m.On("CommentTarget", mock.Anything, commentID).Return(target, nil)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Should return not found without open reports",
+			body: `{"action":"dismiss"}`,
+			setupMock: func(m *mocks.Store) {
+				m.On("CommentTarget", mock.Anything, commentID).Return(target, nil)
+				m.On("Resolve", mock.Anything, uuid.MustParse(moderator.ID), target, report.ResolveRequest{Action: report.ActionDismiss}).
+					Return(errorPkg.NewNotFoundError("reports", "target", "comment "+commentID.String(), ""))
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mocks.NewStore(t)
+			tt.setupMock(m)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/api/moderation/comment/"+commentID.String()+"/resolve", strings.NewReader(tt.body))
+			r.SetPathValue("id", commentID.String())
+			r = r.WithContext(context.WithValue(r.Context(), internal.UserContextKey, moderator))
+
+			h := report.NewHandler(internal.NewValidator(), zap.NewNop(), m)
+			h.ResolveCommentHandler(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,137 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	internal "backend/internal"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	report "backend/internal/report"
+
+	uuid "github.com/google/uuid"
+)
+
+// Store is an autogenerated mock type for the Store type
+type Store struct {
+	mock.Mock
+}
+
+// CommentTarget provides a mock function with given fields: ctx, commentID
+func (_m *Store) CommentTarget(ctx context.Context, commentID uuid.UUID) (report.Target, error) {
+	ret := _m.Called(ctx, commentID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CommentTarget")
+	}
+
+	var r0 report.Target
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (report.Target, error)); ok {
+		return rf(ctx, commentID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) report.Target); ok {
+		r0 = rf(ctx, commentID)
+	} else {
+		r0 = ret.Get(0).(report.Target)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, commentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetQueue provides a mock function with given fields: ctx, pagination
+func (_m *Store) GetQueue(ctx context.Context, pagination internal.Pagination) ([]report.FindOpenGroupedByTargetRow, error) {
+	ret := _m.Called(ctx, pagination)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetQueue")
+	}
+
+	var r0 []report.FindOpenGroupedByTargetRow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, internal.Pagination) ([]report.FindOpenGroupedByTargetRow, error)); ok {
+		return rf(ctx, pagination)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, internal.Pagination) []report.FindOpenGroupedByTargetRow); ok {
+		r0 = rf(ctx, pagination)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]report.FindOpenGroupedByTargetRow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, internal.Pagination) error); ok {
+		r1 = rf(ctx, pagination)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Report provides a mock function with given fields: ctx, reporterID, target, request
+func (_m *Store) Report(ctx context.Context, reporterID uuid.UUID, target report.Target, request report.ReportRequest) (report.Report, error) {
+	ret := _m.Called(ctx, reporterID, target, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Report")
+	}
+
+	var r0 report.Report
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, report.Target, report.ReportRequest) (report.Report, error)); ok {
+		return rf(ctx, reporterID, target, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, report.Target, report.ReportRequest) report.Report); ok {
+		r0 = rf(ctx, reporterID, target, request)
+	} else {
+		r0 = ret.Get(0).(report.Report)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, report.Target, report.ReportRequest) error); ok {
+		r1 = rf(ctx, reporterID, target, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Resolve provides a mock function with given fields: ctx, moderatorID, target, request
+func (_m *Store) Resolve(ctx context.Context, moderatorID uuid.UUID, target report.Target, request report.ResolveRequest) error {
+	ret := _m.Called(ctx, moderatorID, target, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Resolve")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, report.Target, report.ResolveRequest) error); ok {
+		r0 = rf(ctx, moderatorID, target, request)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewStore creates a new instance of Store. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Store {
+	mock := &Store{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
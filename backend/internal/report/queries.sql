@@ -0,0 +1,36 @@
+-- name: Create :one
+INSERT INTO reports (post_id, comment_id, reporter_id, reason, details)
+VALUES (@post_id, @comment_id, @reporter_id::uuid, @reason, @details)
+RETURNING *;
+
+-- name: ExistsOpen :one
+SELECT EXISTS (
+    SELECT 1 FROM reports
+    WHERE post_id = @post_id AND comment_id IS NOT DISTINCT FROM @comment_id AND resolved_at IS NULL
+);
+
+-- name: FindOpenGroupedByTarget :many
+-- Open reports grouped by the reported post or comment, most reported first
+SELECT post_id,
+       comment_id,
+       count(*) AS report_count,
+       array_agg(DISTINCT reason ORDER BY reason)::text[] AS reasons,
+       array_remove(array_agg(details ORDER BY created_at), NULL)::text[] AS details,
+       min(created_at)::timestamptz AS first_reported_at,
+       max(created_at)::timestamptz AS last_reported_at
+FROM reports
+WHERE resolved_at IS NULL
+GROUP BY post_id, comment_id
+ORDER BY count(*) DESC, min(created_at)
+LIMIT @size OFFSET @skip;
+
+-- name: Resolve :execrows
+UPDATE reports
+SET resolution = @resolution, resolution_note = @resolution_note, resolved_by = @resolved_by, resolved_at = now()
+WHERE post_id = @post_id AND comment_id IS NOT DISTINCT FROM @comment_id AND resolved_at IS NULL;
+
+-- name: FindPostAuthorID :one
+SELECT author_id FROM posts WHERE id = $1;
+
+-- name: FindComment :one
+SELECT id, post_id, author_id FROM comments WHERE id = $1;
@@ -0,0 +1,186 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: queries.sql
+
+package report
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const create = `-- name: Create :one
+INSERT INTO reports (post_id, comment_id, reporter_id, reason, details)
+VALUES ($1, $2, $3::uuid, $4, $5)
+RETURNING id, post_id, comment_id, reporter_id, reason, details, created_at, resolution, resolution_note, resolved_by, resolved_at
+`
+
+type CreateParams struct {
+	PostID     uuid.UUID
+	CommentID  pgtype.UUID
+	ReporterID uuid.UUID
+	Reason     string
+	Details    pgtype.Text
+}
+
+func (q *Queries) Create(ctx context.Context, arg CreateParams) (Report, error) {
+	row := q.db.QueryRow(ctx, create,
+		arg.PostID,
+		arg.CommentID,
+		arg.ReporterID,
+		arg.Reason,
+		arg.Details,
+	)
+	var i Report
+	err := row.Scan(
+		&i.ID,
+		&i.PostID,
+		&i.CommentID,
+		&i.ReporterID,
+		&i.Reason,
+		&i.Details,
+		&i.CreatedAt,
+		&i.Resolution,
+		&i.ResolutionNote,
+		&i.ResolvedBy,
+		&i.ResolvedAt,
+	)
+	return i, err
+}
+
+const existsOpen = `-- name: ExistsOpen :one
+SELECT EXISTS (
+    SELECT 1 FROM reports
+    WHERE post_id = $1 AND comment_id IS NOT DISTINCT FROM $2 AND resolved_at IS NULL
+)
+`
+
+type ExistsOpenParams struct {
+	PostID    uuid.UUID
+	CommentID pgtype.UUID
+}
+
+func (q *Queries) ExistsOpen(ctx context.Context, arg ExistsOpenParams) (bool, error) {
+	row := q.db.QueryRow(ctx, existsOpen, arg.PostID, arg.CommentID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const findComment = `-- name: FindComment :one
+SELECT id, post_id, author_id FROM comments WHERE id = $1
+`
+
+type FindCommentRow struct {
+	ID       uuid.UUID
+	PostID   uuid.UUID
+	AuthorID uuid.UUID
+}
+
+func (q *Queries) FindComment(ctx context.Context, id uuid.UUID) (FindCommentRow, error) {
+	row := q.db.QueryRow(ctx, findComment, id)
+	var i FindCommentRow
+	err := row.Scan(&i.ID, &i.PostID, &i.AuthorID)
+	return i, err
+}
+
+const findOpenGroupedByTarget = `-- name: FindOpenGroupedByTarget :many
+SELECT post_id,
+       comment_id,
+       count(*) AS report_count,
+       array_agg(DISTINCT reason ORDER BY reason)::text[] AS reasons,
+       array_remove(array_agg(details ORDER BY created_at), NULL)::text[] AS details,
+       min(created_at)::timestamptz AS first_reported_at,
+       max(created_at)::timestamptz AS last_reported_at
+FROM reports
+WHERE resolved_at IS NULL
+GROUP BY post_id, comment_id
+ORDER BY count(*) DESC, min(created_at)
+LIMIT $2 OFFSET $1
+`
+
+type FindOpenGroupedByTargetParams struct {
+	Skip int32
+	Size int32
+}
+
+type FindOpenGroupedByTargetRow struct {
+	PostID          uuid.UUID
+	CommentID       pgtype.UUID
+	ReportCount     int64
+	Reasons         []string
+	Details         []string
+	FirstReportedAt pgtype.Timestamptz
+	LastReportedAt  pgtype.Timestamptz
+}
+
+// Open reports grouped by the reported post or comment, most reported first
+func (q *Queries) FindOpenGroupedByTarget(ctx context.Context, arg FindOpenGroupedByTargetParams) ([]FindOpenGroupedByTargetRow, error) {
+	rows, err := q.db.Query(ctx, findOpenGroupedByTarget, arg.Skip, arg.Size)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FindOpenGroupedByTargetRow
+	for rows.Next() {
+		var i FindOpenGroupedByTargetRow
+		if err := rows.Scan(
+			&i.PostID,
+			&i.CommentID,
+			&i.ReportCount,
+			&i.Reasons,
+			&i.Details,
+			&i.FirstReportedAt,
+			&i.LastReportedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findPostAuthorID = `-- name: FindPostAuthorID :one
+SELECT author_id FROM posts WHERE id = $1
+`
+
+func (q *Queries) FindPostAuthorID(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, findPostAuthorID, id)
+	var author_id uuid.UUID
+	err := row.Scan(&author_id)
+	return author_id, err
+}
+
+const resolve = `-- name: Resolve :execrows
+UPDATE reports
+SET resolution = $1, resolution_note = $2, resolved_by = $3, resolved_at = now()
+WHERE post_id = $4 AND comment_id IS NOT DISTINCT FROM $5 AND resolved_at IS NULL
+`
+
+type ResolveParams struct {
+	Resolution     pgtype.Text
+	ResolutionNote pgtype.Text
+	ResolvedBy     pgtype.UUID
+	PostID         uuid.UUID
+	CommentID      pgtype.UUID
+}
+
+func (q *Queries) Resolve(ctx context.Context, arg ResolveParams) (int64, error) {
+	result, err := q.db.Exec(ctx, resolve,
+		arg.Resolution,
+		arg.ResolutionNote,
+		arg.ResolvedBy,
+		arg.PostID,
+		arg.CommentID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS reports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    post_id UUID REFERENCES posts(id) ON DELETE CASCADE NOT NULL,
+    comment_id UUID REFERENCES comments(id) ON DELETE CASCADE,
+    -- reporter_id is NULL for reports filed by the content filter
+    reporter_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    reason VARCHAR(32) NOT NULL CHECK (reason IN ('spam', 'harassment', 'off_topic', 'illegal', 'other', 'filter')),
+    details TEXT,
+    created_at TIMESTAMPTZ DEFAULT now() NOT NULL,
+    resolution VARCHAR(16) CHECK (resolution IN ('dismiss', 'hide', 'warn', 'approve')),
+    resolution_note TEXT,
+    resolved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    resolved_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS reports_open_idx ON reports (post_id, comment_id) WHERE resolved_at IS NULL;
+
+-- A user has one open report per target, comment_id is NULL for reports about the post itself and reporter_id for
+-- reports of the content filter, which may report a target again
+CREATE UNIQUE INDEX IF NOT EXISTS reports_open_reporter_idx
+    ON reports (reporter_id, post_id, COALESCE(comment_id, '00000000-0000-0000-0000-000000000000'))
+    WHERE resolved_at IS NULL;
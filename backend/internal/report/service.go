@@ -0,0 +1,253 @@
+package report
+
+import (
+	"backend/internal"
+	"backend/internal/audit"
+	"backend/internal/database"
+	errorPkg "backend/internal/error"
+	"backend/internal/notification"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// Resolution actions a moderator can take on reported content
+const (
+	ActionDismiss = "dismiss"
+	ActionHide    = "hide"
+	ActionWarn    = "warn"
+	// ActionApprove publishes content the content filter held back, or restores hidden content
+	ActionApprove = "approve"
+)
+
+// AuditActionResolve is the audit trail action of resolved reports, the details hold the resolution action
+const AuditActionResolve = "report.resolve"
+
+// Target identifies the reported content, a zero CommentID refers to the post itself
+type Target struct {
+	PostID    uuid.UUID
+	CommentID uuid.UUID
+}
+
+// QueueEntry summarises the open reports about one post or comment
+type QueueEntry = FindOpenGroupedByTargetRow
+
+// PostHider hides reported posts and publishes approved ones, see post.Service
+type PostHider interface {
+	Hide(ctx context.Context, id uuid.UUID) error
+	Unhide(ctx context.Context, id uuid.UUID) error
+}
+
+// CommentHider hides reported comments and publishes approved ones, see comment.Service
+type CommentHider interface {
+	Hide(ctx context.Context, id uuid.UUID) error
+	Unhide(ctx context.Context, id uuid.UUID) error
+}
+
+// Auditor keeps the trail of moderation decisions, see audit.Service
+type Auditor interface {
+	Record(ctx context.Context, entry audit.Entry) error
+}
+
+// Notifier delivers warnings to authors, see notification.Service
+type Notifier interface {
+	Notify(ctx context.Context, recipientID, actorID uuid.UUID, notificationType string, postID, commentID uuid.UUID) error
+}
+
+type Service struct {
+	logger *zap.Logger
+	tracer trace.Tracer
+	query  *Queries
+
+	posts    PostHider
+	comments CommentHider
+	notifier Notifier
+	auditor  Auditor
+}
+
+func NewService(logger *zap.Logger, db DBTX, posts PostHider, comments CommentHider, notifier Notifier, auditor Auditor) *Service {
+	return &Service{
+		logger:   logger,
+		tracer:   otel.Tracer("report/service"),
+		query:    New(db),
+		posts:    posts,
+		comments: comments,
+		notifier: notifier,
+		auditor:  auditor,
+	}
+}
+
+// Report files a report about a post or comment, a user can have one open report per target
+func (s *Service) Report(ctx context.Context, reporterID uuid.UUID, target Target, r ReportRequest) (Report, error) {
+	traceCtx, span := s.tracer.Start(ctx, "Report")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	commentID := pgtype.UUID{Bytes: target.CommentID, Valid: target.CommentID != uuid.Nil}
+
+	report, err := s.query.Create(traceCtx, CreateParams{
+		PostID:     target.PostID,
+		CommentID:  commentID,
+		ReporterID: reporterID,
+		Reason:     r.Reason,
+		Details:    pgtype.Text{String: r.Details, Valid: r.Details != ""},
+	})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "create report")
+		if errors.Is(err, database.ErrForeignKeyViolation) {
+			err = errorPkg.NewNotFoundError("post", "id", target.PostID.String(), "")
+		}
+		// reports_open_reporter_idx allows a single open report of the user about the target
+		if errors.Is(err, database.ErrUniqueViolation) {
+			err = errorPkg.ErrAlreadyReported
+		}
+		span.RecordError(err)
+		return Report{}, err
+	}
+
+	logger.Info("Reported content", zap.String("id", report.ID.String()), zap.String("target", targetString(target)), zap.String("reason", r.Reason))
+	return report, nil
+}
+
+// GetQueue lists the open reports grouped by the reported content, most reported first
+func (s *Service) GetQueue(ctx context.Context, pagination internal.Pagination) ([]QueueEntry, error) {
+	traceCtx, span := s.tracer.Start(ctx, "GetQueue")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	entries, err := s.query.FindOpenGroupedByTarget(traceCtx, FindOpenGroupedByTargetParams{
+		Skip: pagination.Offset(),
+		Size: pagination.Size,
+	})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "get moderation queue")
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// Resolve applies the action of the moderator to the reported content and closes every open report about it
+func (s *Service) Resolve(ctx context.Context, moderatorID uuid.UUID, target Target, r ResolveRequest) error {
+	traceCtx, span := s.tracer.Start(ctx, "Resolve")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	commentID := pgtype.UUID{Bytes: target.CommentID, Valid: target.CommentID != uuid.Nil}
+
+	open, err := s.query.ExistsOpen(traceCtx, ExistsOpenParams{PostID: target.PostID, CommentID: commentID})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "check open reports")
+		span.RecordError(err)
+		return err
+	}
+	if !open {
+		err = errorPkg.NewNotFoundError("reports", "target", targetString(target), "no open reports about this content")
+		span.RecordError(err)
+		return err
+	}
+
+	switch r.Action {
+	case ActionHide:
+		err = s.hide(traceCtx, target)
+	case ActionWarn:
+		err = s.warn(traceCtx, moderatorID, target)
+	case ActionApprove:
+		err = s.approve(traceCtx, target)
+	}
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	count, err := s.query.Resolve(traceCtx, ResolveParams{
+		Resolution:     pgtype.Text{String: r.Action, Valid: true},
+		ResolutionNote: pgtype.Text{String: r.Note, Valid: r.Note != ""},
+		ResolvedBy:     pgtype.UUID{Bytes: moderatorID, Valid: true},
+		PostID:         target.PostID,
+		CommentID:      commentID,
+	})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "resolve reports")
+		span.RecordError(err)
+		return err
+	}
+
+	logger.Info("Resolved reports", zap.String("target", targetString(target)), zap.String("action", r.Action), zap.String("moderator_id", moderatorID.String()), zap.Int64("reports", count))
+
+	entry := audit.Entry{ActorID: moderatorID, Action: AuditActionResolve, TargetType: audit.TargetPost, TargetID: target.PostID, Details: r.Action}
+	if target.CommentID != uuid.Nil {
+		entry.TargetType = audit.TargetComment
+		entry.TargetID = target.CommentID
+	}
+	if r.Note != "" {
+		entry.Details += ": " + r.Note
+	}
+	err = s.auditor.Record(traceCtx, entry)
+	if err != nil {
+		logger.Error("Failed to record audit entry", zap.String("target", targetString(target)), zap.String("action", AuditActionResolve), zap.Error(err))
+	}
+
+	return nil
+}
+
+// CommentTarget returns the target for a comment, comments are addressed by their own ID in the API
+func (s *Service) CommentTarget(ctx context.Context, commentID uuid.UUID) (Target, error) {
+	traceCtx, span := s.tracer.Start(ctx, "CommentTarget")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	comment, err := s.query.FindComment(traceCtx, commentID)
+	if err != nil {
+		err = database.WrapDBErrorWithKeyValue(err, "comments", "id", commentID.String(), logger, "get reported comment")
+		span.RecordError(err)
+		return Target{}, err
+	}
+
+	return Target{PostID: comment.PostID, CommentID: comment.ID}, nil
+}
+
+func (s *Service) hide(ctx context.Context, target Target) error {
+	if target.CommentID != uuid.Nil {
+		return s.comments.Hide(ctx, target.CommentID)
+	}
+	return s.posts.Hide(ctx, target.PostID)
+}
+
+func (s *Service) approve(ctx context.Context, target Target) error {
+	if target.CommentID != uuid.Nil {
+		return s.comments.Unhide(ctx, target.CommentID)
+	}
+	return s.posts.Unhide(ctx, target.PostID)
+}
+
+func (s *Service) warn(ctx context.Context, moderatorID uuid.UUID, target Target) error {
+	logger := internal.LoggerWithContext(ctx, s.logger)
+
+	var authorID uuid.UUID
+	var err error
+	if target.CommentID != uuid.Nil {
+		var comment FindCommentRow
+		comment, err = s.query.FindComment(ctx, target.CommentID)
+		authorID = comment.AuthorID
+	} else {
+		authorID, err = s.query.FindPostAuthorID(ctx, target.PostID)
+	}
+	if err != nil {
+		return database.WrapDBError(err, logger, "get author of reported content")
+	}
+
+	return s.notifier.Notify(ctx, authorID, moderatorID, notification.TypeWarning, target.PostID, target.CommentID)
+}
+
+func targetString(target Target) string {
+	if target.CommentID != uuid.Nil {
+		return "comment " + target.CommentID.String()
+	}
+	return "post " + target.PostID.String()
+}
@@ -0,0 +1,64 @@
+package report_test
+
+import (
+	"backend/internal/database/databasetest"
+	errorPkg "backend/internal/error"
+	"backend/internal/report"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func TestService_Report(t *testing.T) {
+	pool := databasetest.Open(t)
+	s := report.NewService(zap.NewNop(), pool, nil, nil, nil, nil)
+	ctx := context.Background()
+
+	author := databasetest.CreateUser(t, pool)
+	postID := databasetest.CreatePost(t, pool, author)
+	commentID := databasetest.CreateComment(t, pool, postID, author, time.Now())
+	request := report.ReportRequest{Reason: "spam"}
+
+	tests := []struct {
+		name    string
+		targets []report.Target
+		wantErr error
+	}{
+		{
+			name:    "Should report post and its comment",
+			targets: []report.Target{{PostID: postID}, {PostID: postID, CommentID: commentID}},
+		},
+		{
+			name:    "Should reject second open report about post",
+			targets: []report.Target{{PostID: postID}, {PostID: postID}},
+			wantErr: errorPkg.ErrAlreadyReported,
+		},
+		{
+			name:    "Should reject second open report about comment",
+			targets: []report.Target{{PostID: postID, CommentID: commentID}, {PostID: postID, CommentID: commentID}},
+			wantErr: errorPkg.ErrAlreadyReported,
+		},
+		{
+			name:    "Should return not found for unknown post",
+			targets: []report.Target{{PostID: uuid.New()}},
+			wantErr: errorPkg.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reporter := databasetest.CreateUser(t, pool)
+
+			var err error
+			for _, target := range tt.targets {
+				_, err = s.Report(ctx, reporter, target, request)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Report() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package sshkey_test
+
+import (
+	"backend/internal"
+	"backend/internal/database"
+	errorPkg "backend/internal/error"
+	"backend/internal/jwt"
+	"backend/internal/sshkey"
+	"backend/internal/sshkey/mocks"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var user = jwt.User{
+	ID:       "81c1ecc1-66d7-4134-b5fe-d886a6f418a4",
+	Username: "reader",
+	Role:     jwt.RoleUser,
+}
+
+const publicKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOMqqnkVzrm0SdG6UOoqKLsabgH5C9okWi0dh2l9GKJl"
+
+func TestHandler_AddHandler(t *testing.T) {
+	added := sshkey.SshKey{
+		ID:          uuid.MustParse("1f0b3c8e-3b0a-4d8e-9f3e-9b1b0f6b7c11"),
+		UserID:      uuid.MustParse(user.ID),
+		Name:        "laptop",
+		PublicKey:   publicKey,
+		Fingerprint: "SHA256:9Qz6XKf4Yl0Vt2VZb8o4F0y2m1Yb1wQm0W1m3v2o1aE",
+		CreatedAt:   pgtype.Timestamptz{Time: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), Valid: true},
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		setupMock  func(m *mocks.Store)
+		wantStatus int
+	}{
+		{
+			name: "Should add key",
+			body: `{"name":"laptop","public_key":"` + publicKey + ` me@laptop"}`,
+			setupMock: func(m *mocks.Store) {
+				m.On("Add", mock.Anything, uuid.MustParse(user.ID), "laptop", publicKey+" me@laptop").Return(added, nil)
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "Should reject missing public key",
+			body:       `{"name":"laptop"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Should reject malformed public key",
+			body: `{"name":"laptop","public_key":"ssh-ed25519 garbage"}`,
+			setupMock: func(m *mocks.Store) {
+				m.On("Add", mock.Anything, uuid.MustParse(user.ID), "laptop", "ssh-ed25519 garbage").
+					Return(sshkey.SshKey{}, fmt.Errorf("%w: malformed public key", errorPkg.ErrInvalidRequest))
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Should reject key registered before",
+			body: `{"name":"laptop","public_key":"` + publicKey + `"}`,
+			setupMock: func(m *mocks.Store) {
+				m.On("Add", mock.Anything, uuid.MustParse(user.ID), "laptop", publicKey).Return(sshkey.SshKey{}, database.ErrUniqueViolation)
+			},
+			wantStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewStore(t)
+			if tt.setupMock != nil {
+				tt.setupMock(mockStore)
+			}
+			handler := sshkey.NewHandler(internal.NewValidator(), zap.NewNop(), mockStore)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/me/ssh-keys", strings.NewReader(tt.body))
+			req = req.WithContext(context.WithValue(req.Context(), internal.UserContextKey, user))
+			rr := httptest.NewRecorder()
+			handler.AddHandler(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+			if tt.wantStatus == http.StatusCreated {
+				var response sshkey.Response
+				err := json.Unmarshal(rr.Body.Bytes(), &response)
+				if err != nil {
+					t.Fatalf("Failed to decode response: %v", err)
+				}
+				assert.Equal(t, added.Fingerprint, response.Fingerprint)
+				assert.Empty(t, response.LastUsedAt)
+			}
+		})
+	}
+}
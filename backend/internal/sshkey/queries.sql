@@ -0,0 +1,12 @@
+-- name: Create :one
+INSERT INTO ssh_keys (user_id, name, public_key, fingerprint) VALUES ($1, $2, $3, $4) RETURNING *;
+
+-- name: Delete :execrows
+DELETE FROM ssh_keys WHERE id = @id AND user_id = @user_id;
+
+-- name: FindByUser :many
+SELECT * FROM ssh_keys WHERE user_id = $1 ORDER BY created_at, id;
+
+-- name: Use :one
+-- Looks up the key of a login and remembers when it was last used
+UPDATE ssh_keys SET last_used_at = now() WHERE fingerprint = $1 RETURNING *;
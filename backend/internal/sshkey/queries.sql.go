@@ -0,0 +1,112 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: queries.sql
+
+package sshkey
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const create = `-- name: Create :one
+INSERT INTO ssh_keys (user_id, name, public_key, fingerprint) VALUES ($1, $2, $3, $4) RETURNING id, user_id, name, public_key, fingerprint, created_at, last_used_at
+`
+
+type CreateParams struct {
+	UserID      uuid.UUID
+	Name        string
+	PublicKey   string
+	Fingerprint string
+}
+
+func (q *Queries) Create(ctx context.Context, arg CreateParams) (SshKey, error) {
+	row := q.db.QueryRow(ctx, create,
+		arg.UserID,
+		arg.Name,
+		arg.PublicKey,
+		arg.Fingerprint,
+	)
+	var i SshKey
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.PublicKey,
+		&i.Fingerprint,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+	)
+	return i, err
+}
+
+const delete = `-- name: Delete :execrows
+DELETE FROM ssh_keys WHERE id = $1 AND user_id = $2
+`
+
+type DeleteParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) Delete(ctx context.Context, arg DeleteParams) (int64, error) {
+	result, err := q.db.Exec(ctx, delete, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const findByUser = `-- name: FindByUser :many
+SELECT id, user_id, name, public_key, fingerprint, created_at, last_used_at FROM ssh_keys WHERE user_id = $1 ORDER BY created_at, id
+`
+
+func (q *Queries) FindByUser(ctx context.Context, userID uuid.UUID) ([]SshKey, error) {
+	rows, err := q.db.Query(ctx, findByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SshKey
+	for rows.Next() {
+		var i SshKey
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.PublicKey,
+			&i.Fingerprint,
+			&i.CreatedAt,
+			&i.LastUsedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const use = `-- name: Use :one
+UPDATE ssh_keys SET last_used_at = now() WHERE fingerprint = $1 RETURNING id, user_id, name, public_key, fingerprint, created_at, last_used_at
+`
+
+// Looks up the key of a login and remembers when it was last used
+func (q *Queries) Use(ctx context.Context, fingerprint string) (SshKey, error) {
+	row := q.db.QueryRow(ctx, use, fingerprint)
+	var i SshKey
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.PublicKey,
+		&i.Fingerprint,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,110 @@
+package sshkey
+
+import (
+	"backend/internal"
+	"backend/internal/database"
+	errorPkg "backend/internal/error"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/ssh"
+	"strings"
+)
+
+type Service struct {
+	logger *zap.Logger
+	tracer trace.Tracer
+	query  *Queries
+}
+
+func NewService(logger *zap.Logger, db DBTX) *Service {
+	return &Service{
+		logger: logger,
+		tracer: otel.Tracer("sshkey/service"),
+		query:  New(db),
+	}
+}
+
+// Add registers a public key in authorized_keys format for the user, a key can only belong to one user
+func (s *Service) Add(ctx context.Context, userID uuid.UUID, name, publicKey string) (SshKey, error) {
+	traceCtx, span := s.tracer.Start(ctx, "Add")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(publicKey))
+	if err != nil {
+		err = fmt.Errorf("%w: malformed public key: %v", errorPkg.ErrInvalidRequest, err)
+		span.RecordError(err)
+		return SshKey{}, err
+	}
+
+	sshKey, err := s.query.Create(traceCtx, CreateParams{
+		UserID:      userID,
+		Name:        name,
+		PublicKey:   strings.TrimSpace(string(ssh.MarshalAuthorizedKey(key))),
+		Fingerprint: ssh.FingerprintSHA256(key),
+	})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "add ssh key")
+		span.RecordError(err)
+		return SshKey{}, err
+	}
+
+	return sshKey, nil
+}
+
+func (s *Service) Remove(ctx context.Context, userID, id uuid.UUID) error {
+	traceCtx, span := s.tracer.Start(ctx, "Remove")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	count, err := s.query.Delete(traceCtx, DeleteParams{ID: id, UserID: userID})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "remove ssh key")
+		span.RecordError(err)
+		return err
+	}
+	if count == 0 {
+		err = errorPkg.NewNotFoundError("ssh_keys", "id", id.String(), "")
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetByUser lists the keys of the user, oldest first
+func (s *Service) GetByUser(ctx context.Context, userID uuid.UUID) ([]SshKey, error) {
+	traceCtx, span := s.tracer.Start(ctx, "GetByUser")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	keys, err := s.query.FindByUser(traceCtx, userID)
+	if err != nil {
+		err = database.WrapDBError(err, logger, "get ssh keys by user")
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// Authenticate returns the user the key is registered for and marks the key as used
+func (s *Service) Authenticate(ctx context.Context, key ssh.PublicKey) (uuid.UUID, error) {
+	traceCtx, span := s.tracer.Start(ctx, "Authenticate")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	fingerprint := ssh.FingerprintSHA256(key)
+	sshKey, err := s.query.Use(traceCtx, fingerprint)
+	if err != nil {
+		err = database.WrapDBErrorWithKeyValue(err, "ssh_keys", "fingerprint", fingerprint, logger, "use ssh key")
+		span.RecordError(err)
+		return uuid.Nil, err
+	}
+
+	return sshKey.UserID, nil
+}
@@ -0,0 +1,171 @@
+package syndication
+
+import (
+	"backend/internal/markdown"
+	"encoding/xml"
+	"time"
+)
+
+// Formats of the feeds, selected by the extension of the requested file
+const (
+	FormatAtom = "atom"
+	FormatRSS  = "rss"
+)
+
+var contentTypes = map[string]string{
+	FormatAtom: "application/atom+xml; charset=utf-8",
+	FormatRSS:  "application/rss+xml; charset=utf-8",
+}
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Updated   string      `xml:"updated"`
+	Published string      `xml:"published"`
+	Author    atomAuthor  `xml:"author"`
+	Link      atomLink    `xml:"link"`
+	Content   atomContent `xml:"content"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	DC      string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Self          atomLink  `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	GUID        rssGUID `xml:"guid"`
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	Author      string  `xml:"dc:creator"`
+	Description string  `xml:"description"`
+	PubDate     string  `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// WriteAtom encodes the feed as Atom 1.0. selfURL is the address of the feed, baseURL the address of the API the
+// entries link to.
+func WriteAtom(feed Feed, selfURL, baseURL string) ([]byte, error) {
+	doc := atomFeed{
+		ID:       selfURL,
+		Title:    feed.Title,
+		Subtitle: feed.Subtitle,
+		Updated:  atomTime(feed.Updated),
+		Links:    []atomLink{{Href: selfURL, Rel: "self", Type: contentTypes[FormatAtom]}},
+		Entries:  make([]atomEntry, len(feed.Entries)),
+	}
+	for i, e := range feed.Entries {
+		doc.Entries[i] = atomEntry{
+			ID:        "urn:uuid:" + e.ID.String(),
+			Title:     e.Title,
+			Updated:   atomTime(e.Updated),
+			Published: atomTime(e.Published),
+			Author:    atomAuthor{Name: e.Author},
+			Link:      atomLink{Href: entryURL(baseURL, e), Rel: "alternate"},
+			Content:   atomContent{Type: "html", Body: markdown.RenderHTML(e.Content)},
+		}
+	}
+
+	return encode(doc)
+}
+
+// WriteRSS encodes the feed as RSS 2.0, see WriteAtom
+func WriteRSS(feed Feed, selfURL, baseURL string) ([]byte, error) {
+	doc := rssDocument{
+		Version: "2.0",
+		Atom:    "http://www.w3.org/2005/Atom",
+		DC:      "http://purl.org/dc/elements/1.1/",
+		Channel: rssChannel{
+			Title:         feed.Title,
+			Link:          selfURL,
+			Description:   feed.Subtitle,
+			LastBuildDate: rssTime(feed.Updated),
+			Self:          atomLink{Href: selfURL, Rel: "self", Type: contentTypes[FormatRSS]},
+			Items:         make([]rssItem, len(feed.Entries)),
+		},
+	}
+	if doc.Channel.Description == "" {
+		doc.Channel.Description = feed.Title
+	}
+	for i, e := range feed.Entries {
+		doc.Channel.Items[i] = rssItem{
+			GUID:        rssGUID{Value: "urn:uuid:" + e.ID.String()},
+			Title:       e.Title,
+			Link:        entryURL(baseURL, e),
+			Author:      e.Author,
+			Description: markdown.RenderHTML(e.Content),
+			PubDate:     rssTime(e.Published),
+		}
+	}
+
+	return encode(doc)
+}
+
+func encode(doc any) ([]byte, error) {
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+// atomTime formats t as RFC 3339, a feed without entries and subject falls back to the Unix epoch because Atom
+// requires an updated element
+func atomTime(t time.Time) string {
+	if t.IsZero() {
+		t = time.Unix(0, 0)
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// rssTime formats t as RFC 822 with a four digit year, as RSS readers expect
+func rssTime(t time.Time) string {
+	if t.IsZero() {
+		t = time.Unix(0, 0)
+	}
+	return t.UTC().Format(time.RFC1123Z)
+}
+
+func entryURL(baseURL string, e Entry) string {
+	return baseURL + "/api/" + e.Kind + "/" + e.ID.String()
+}
@@ -0,0 +1,142 @@
+package syndication
+
+import (
+	"backend/internal"
+	errorPkg "backend/internal/error"
+	"backend/internal/problem"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"net/http"
+	"path"
+	"strings"
+)
+
+//go:generate mockery --name=Store
+type Store interface {
+	Posts(ctx context.Context) (Feed, error)
+	BoardPosts(ctx context.Context, boardID uuid.UUID) (Feed, error)
+	UserPosts(ctx context.Context, userID uuid.UUID) (Feed, error)
+	TagPosts(ctx context.Context, tag string) (Feed, error)
+	PostComments(ctx context.Context, postID uuid.UUID) (Feed, error)
+}
+
+type Handler struct {
+	logger *zap.Logger
+	tracer trace.Tracer
+	store  Store
+	// publicURL prefixes the links in feeds, the host of the request is used while it is empty
+	publicURL string
+}
+
+func NewHandler(logger *zap.Logger, store Store, publicURL string) *Handler {
+	return &Handler{
+		logger:    logger,
+		tracer:    otel.Tracer("syndication/handler"),
+		store:     store,
+		publicURL: strings.TrimSuffix(publicURL, "/"),
+	}
+}
+
+// PostsHandler serves the newest posts of all boards as posts.atom or posts.rss
+func (h *Handler) PostsHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "PostsFeedEndpoint")
+	defer span.End()
+
+	h.serve(traceCtx, w, r, "posts", func(ctx context.Context, _ uuid.UUID) (Feed, error) {
+		return h.store.Posts(ctx)
+	})
+}
+
+// BoardHandler serves the newest posts of the board in the id path value
+func (h *Handler) BoardHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "BoardFeedEndpoint")
+	defer span.End()
+
+	h.serve(traceCtx, w, r, "posts", h.store.BoardPosts)
+}
+
+// UserHandler serves the newest posts of the user in the id path value
+func (h *Handler) UserHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "UserFeedEndpoint")
+	defer span.End()
+
+	h.serve(traceCtx, w, r, "posts", h.store.UserPosts)
+}
+
+// TagHandler serves the newest posts with the tag in the tag path value
+func (h *Handler) TagHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "TagFeedEndpoint")
+	defer span.End()
+
+	tag := r.PathValue("tag")
+	h.serve(traceCtx, w, r, "posts", func(ctx context.Context, _ uuid.UUID) (Feed, error) {
+		return h.store.TagPosts(ctx, tag)
+	})
+}
+
+// CommentsHandler serves the newest comments of the post in the id path value
+func (h *Handler) CommentsHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "CommentsFeedEndpoint")
+	defer span.End()
+
+	h.serve(traceCtx, w, r, "comments", h.store.PostComments)
+}
+
+// serve loads the feed and writes it in the format named by the extension of the requested file, which must be
+// name.atom or name.rss. The response carries an ETag and the Last-Modified time of the feed, so feed readers
+// polling with conditional requests get 304 Not Modified until something changes.
+func (h *Handler) serve(ctx context.Context, w http.ResponseWriter, r *http.Request, name string, load func(ctx context.Context, id uuid.UUID) (Feed, error)) {
+	logger := internal.LoggerWithContext(ctx, h.logger)
+
+	file := path.Base(r.URL.Path)
+	base, format, _ := strings.Cut(file, ".")
+	if _, ok := contentTypes[format]; !ok || base != name {
+		problem.WriteError(ctx, w, errorPkg.NewNotFoundError("feeds", "name", file, ""), logger)
+		return
+	}
+
+	var id uuid.UUID
+	if value := r.PathValue("id"); value != "" {
+		var err error
+		id, err = internal.ParseUUID(value)
+		if err != nil {
+			problem.WriteError(ctx, w, fmt.Errorf("%w: %v", errorPkg.ErrInvalidUUID, err), logger)
+			return
+		}
+	}
+
+	feed, err := load(ctx, id)
+	if err != nil {
+		problem.WriteError(ctx, w, err, logger)
+		return
+	}
+
+	baseURL := h.baseURL(r)
+	selfURL := baseURL + r.URL.Path
+	var body []byte
+	if format == FormatAtom {
+		body, err = WriteAtom(feed, selfURL, baseURL)
+	} else {
+		body, err = WriteRSS(feed, selfURL, baseURL)
+	}
+	if err != nil {
+		problem.WriteError(ctx, w, err, logger)
+		return
+	}
+
+	internal.WriteConditionalResponse(w, r, http.StatusOK, contentTypes[format], body, feed.Updated)
+}
+
+func (h *Handler) baseURL(r *http.Request) string {
+	if h.publicURL != "" {
+		return h.publicURL
+	}
+	if r.TLS != nil {
+		return "https://" + r.Host
+	}
+	return "http://" + r.Host
+}
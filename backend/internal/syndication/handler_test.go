@@ -0,0 +1,197 @@
+package syndication_test
+
+import (
+	errorPkg "backend/internal/error"
+	"backend/internal/syndication"
+	"backend/internal/syndication/mocks"
+	"encoding/xml"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	boardID = uuid.MustParse("1f0b3c8e-3b0a-4d8e-9f3e-9b1b0f6b7c11")
+	updated = time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC)
+	feed    = syndication.Feed{
+		Title:   "Announcements",
+		Updated: updated,
+		Entries: []syndication.Entry{{
+			ID:        uuid.MustParse("54a46af2-b454-4746-8ab0-3cf26085a50b"),
+			Kind:      "post",
+			Title:     "Release",
+			Content:   "Version **2** is out",
+			Author:    "alice",
+			Published: updated.Add(-time.Hour),
+			Updated:   updated,
+		}},
+	}
+)
+
+func TestHandler_BoardHandler(t *testing.T) {
+	tests := []struct {
+		name            string
+		file            string
+		id              string
+		header          map[string]string
+		setupMock       func(m *mocks.Store)
+		wantStatus      int
+		wantContentType string
+	}{
+		{
+			name: "Should serve Atom feed",
+			file: "posts.atom",
+			id:   boardID.String(),
+			setupMock: func(m *mocks.Store) {
+				m.On("BoardPosts", mock.Anything, boardID).Return(feed, nil)
+			},
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/atom+xml; charset=utf-8",
+		},
+		{
+			name: "Should serve RSS feed",
+			file: "posts.rss",
+			id:   boardID.String(),
+			setupMock: func(m *mocks.Store) {
+				m.On("BoardPosts", mock.Anything, boardID).Return(feed, nil)
+			},
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/rss+xml; charset=utf-8",
+		},
+		{
+			name:   "Should answer not modified since last update",
+			file:   "posts.atom",
+			id:     boardID.String(),
+			header: map[string]string{"If-Modified-Since": updated.Format(http.TimeFormat)},
+			setupMock: func(m *mocks.Store) {
+				m.On("BoardPosts", mock.Anything, boardID).Return(feed, nil)
+			},
+			wantStatus: http.StatusNotModified,
+		},
+		{
+			name:   "Should serve feed updated after If-Modified-Since",
+			file:   "posts.atom",
+			id:     boardID.String(),
+			header: map[string]string{"If-Modified-Since": updated.Add(-time.Minute).Format(http.TimeFormat)},
+			setupMock: func(m *mocks.Store) {
+				m.On("BoardPosts", mock.Anything, boardID).Return(feed, nil)
+			},
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/atom+xml; charset=utf-8",
+		},
+		{
+			name:       "Should reject unknown format",
+			file:       "posts.json",
+			id:         boardID.String(),
+			setupMock:  func(m *mocks.Store) {},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "Should reject comment feed name",
+			file:       "comments.atom",
+			id:         boardID.String(),
+			setupMock:  func(m *mocks.Store) {},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "Should reject invalid board ID",
+			file:       "posts.atom",
+			id:         "not-a-uuid",
+			setupMock:  func(m *mocks.Store) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Should return not found for unknown board",
+			file: "posts.atom",
+			id:   boardID.String(),
+			setupMock: func(m *mocks.Store) {
+				m.On("BoardPosts", mock.Anything, boardID).
+					Return(syndication.Feed{}, errorPkg.NewNotFoundError("boards", "id", boardID.String(), ""))
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mocks.NewStore(t)
+			tt.setupMock(m)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/feeds/board/"+tt.id+"/"+tt.file, nil)
+			r.SetPathValue("id", tt.id)
+			r.SetPathValue("file", tt.file)
+			for key, value := range tt.header {
+				r.Header.Set(key, value)
+			}
+
+			h := syndication.NewHandler(zap.NewNop(), m, "https://forum.example.com/")
+			h.BoardHandler(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, tt.wantContentType, w.Header().Get("Content-Type"))
+				assert.Equal(t, updated.Format(http.TimeFormat), w.Header().Get("Last-Modified"))
+				assert.NotEmpty(t, w.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
+func TestHandler_TagHandler(t *testing.T) {
+	m := mocks.NewStore(t)
+	m.On("TagPosts", mock.Anything, "release").Return(feed, nil)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/feeds/tag/release/posts.rss", nil)
+	r.SetPathValue("tag", "release")
+	r.SetPathValue("file", "posts.rss")
+
+	h := syndication.NewHandler(zap.NewNop(), m, "https://forum.example.com/")
+	h.TagHandler(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/rss+xml; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "https://forum.example.com/feeds/tag/release/posts.rss")
+}
+
+func TestWriteAtom(t *testing.T) {
+	body, err := syndication.WriteAtom(feed, "https://forum.example.com/feeds/posts.atom", "https://forum.example.com")
+	if err != nil {
+		t.Fatalf("failed to write feed: %v", err)
+	}
+
+	var got struct {
+		ID      string `xml:"id"`
+		Updated string `xml:"updated"`
+		Entries []struct {
+			ID      string `xml:"id"`
+			Updated string `xml:"updated"`
+			Author  string `xml:"author>name"`
+			Link    struct {
+				Href string `xml:"href,attr"`
+			} `xml:"link"`
+			Content string `xml:"content"`
+		} `xml:"entry"`
+	}
+	err = xml.Unmarshal(body, &got)
+	if err != nil {
+		t.Fatalf("failed to parse feed: %v", err)
+	}
+
+	assert.Equal(t, "https://forum.example.com/feeds/posts.atom", got.ID)
+	assert.Equal(t, "2025-03-04T05:06:07Z", got.Updated)
+	if assert.Len(t, got.Entries, 1) {
+		entry := got.Entries[0]
+		assert.Equal(t, "urn:uuid:54a46af2-b454-4746-8ab0-3cf26085a50b", entry.ID)
+		assert.Equal(t, "alice", entry.Author)
+		assert.Equal(t, "https://forum.example.com/api/post/54a46af2-b454-4746-8ab0-3cf26085a50b", entry.Link.Href)
+		assert.True(t, strings.Contains(entry.Content, "<strong>2</strong>"))
+	}
+}
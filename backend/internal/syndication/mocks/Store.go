@@ -0,0 +1,171 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	syndication "backend/internal/syndication"
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// Store is an autogenerated mock type for the Store type
+type Store struct {
+	mock.Mock
+}
+
+// BoardPosts provides a mock function with given fields: ctx, boardID
+func (_m *Store) BoardPosts(ctx context.Context, boardID uuid.UUID) (syndication.Feed, error) {
+	ret := _m.Called(ctx, boardID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BoardPosts")
+	}
+
+	var r0 syndication.Feed
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (syndication.Feed, error)); ok {
+		return rf(ctx, boardID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) syndication.Feed); ok {
+		r0 = rf(ctx, boardID)
+	} else {
+		r0 = ret.Get(0).(syndication.Feed)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, boardID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PostComments provides a mock function with given fields: ctx, postID
+func (_m *Store) PostComments(ctx context.Context, postID uuid.UUID) (syndication.Feed, error) {
+	ret := _m.Called(ctx, postID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PostComments")
+	}
+
+	var r0 syndication.Feed
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (syndication.Feed, error)); ok {
+		return rf(ctx, postID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) syndication.Feed); ok {
+		r0 = rf(ctx, postID)
+	} else {
+		r0 = ret.Get(0).(syndication.Feed)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, postID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Posts provides a mock function with given fields: ctx
+func (_m *Store) Posts(ctx context.Context) (syndication.Feed, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Posts")
+	}
+
+	var r0 syndication.Feed
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (syndication.Feed, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) syndication.Feed); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(syndication.Feed)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TagPosts provides a mock function with given fields: ctx, tag
+func (_m *Store) TagPosts(ctx context.Context, tag string) (syndication.Feed, error) {
+	ret := _m.Called(ctx, tag)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TagPosts")
+	}
+
+	var r0 syndication.Feed
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (syndication.Feed, error)); ok {
+		return rf(ctx, tag)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) syndication.Feed); ok {
+		r0 = rf(ctx, tag)
+	} else {
+		r0 = ret.Get(0).(syndication.Feed)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, tag)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserPosts provides a mock function with given fields: ctx, userID
+func (_m *Store) UserPosts(ctx context.Context, userID uuid.UUID) (syndication.Feed, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UserPosts")
+	}
+
+	var r0 syndication.Feed
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (syndication.Feed, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) syndication.Feed); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(syndication.Feed)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewStore creates a new instance of Store. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Store {
+	mock := &Store{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
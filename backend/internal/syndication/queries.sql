@@ -0,0 +1,32 @@
+-- name: FindPosts :many
+-- Newest published posts, optionally of a single board, author or tag. updated is the latest of creation, publication
+-- and the last edit or moderation.
+SELECT p.id, p.title, p.content, p.create_at, GREATEST(p.create_at, p.updated_at)::timestamptz AS updated,
+       p.author_id, u.name AS author_name, p.board_id
+FROM posts p
+JOIN users u ON u.id = p.author_id
+WHERE p.hidden_at IS NULL AND p.status = 'published'
+  AND (sqlc.narg(board_id)::uuid IS NULL OR p.board_id = sqlc.narg(board_id))
+  AND (sqlc.narg(author_id)::uuid IS NULL OR p.author_id = sqlc.narg(author_id))
+  AND (sqlc.narg(tag)::varchar IS NULL OR p.tags @> ARRAY[sqlc.narg(tag)::varchar])
+ORDER BY p.create_at DESC, p.id DESC
+LIMIT @size;
+
+-- name: FindComments :many
+-- Newest visible comments of a post. updated is the latest of creation and the last edit.
+SELECT c.id, c.title, c.content, c.created_at, GREATEST(c.created_at, c.updated_at)::timestamptz AS updated,
+       c.author_id, u.name AS author_name
+FROM comments c
+JOIN users u ON u.id = c.author_id
+WHERE c.post_id = @post_id AND c.hidden_at IS NULL
+ORDER BY c.created_at DESC, c.id DESC
+LIMIT @size;
+
+-- name: FindPost :one
+SELECT id, title, create_at FROM posts WHERE id = $1 AND hidden_at IS NULL AND status = 'published';
+
+-- name: FindBoard :one
+SELECT id, name, description, created_at FROM boards WHERE id = $1;
+
+-- name: FindUser :one
+SELECT id, name, created_at FROM users WHERE id = $1;
@@ -0,0 +1,191 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: queries.sql
+
+package syndication
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const findBoard = `-- name: FindBoard :one
+SELECT id, name, description, created_at FROM boards WHERE id = $1
+`
+
+type FindBoardRow struct {
+	ID          uuid.UUID
+	Name        string
+	Description pgtype.Text
+	CreatedAt   pgtype.Timestamptz
+}
+
+func (q *Queries) FindBoard(ctx context.Context, id uuid.UUID) (FindBoardRow, error) {
+	row := q.db.QueryRow(ctx, findBoard, id)
+	var i FindBoardRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const findComments = `-- name: FindComments :many
+SELECT c.id, c.title, c.content, c.created_at, GREATEST(c.created_at, c.updated_at)::timestamptz AS updated,
+       c.author_id, u.name AS author_name
+FROM comments c
+JOIN users u ON u.id = c.author_id
+WHERE c.post_id = $1 AND c.hidden_at IS NULL
+ORDER BY c.created_at DESC, c.id DESC
+LIMIT $2
+`
+
+type FindCommentsParams struct {
+	PostID uuid.UUID
+	Size   int32
+}
+
+type FindCommentsRow struct {
+	ID         uuid.UUID
+	Title      pgtype.Text
+	Content    pgtype.Text
+	CreatedAt  pgtype.Timestamptz
+	Updated    pgtype.Timestamptz
+	AuthorID   uuid.UUID
+	AuthorName string
+}
+
+// Newest visible comments of a post. updated is the latest of creation and the last edit.
+func (q *Queries) FindComments(ctx context.Context, arg FindCommentsParams) ([]FindCommentsRow, error) {
+	rows, err := q.db.Query(ctx, findComments, arg.PostID, arg.Size)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FindCommentsRow
+	for rows.Next() {
+		var i FindCommentsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Content,
+			&i.CreatedAt,
+			&i.Updated,
+			&i.AuthorID,
+			&i.AuthorName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findPost = `-- name: FindPost :one
+SELECT id, title, create_at FROM posts WHERE id = $1 AND hidden_at IS NULL AND status = 'published'
+`
+
+type FindPostRow struct {
+	ID       uuid.UUID
+	Title    pgtype.Text
+	CreateAt pgtype.Timestamptz
+}
+
+func (q *Queries) FindPost(ctx context.Context, id uuid.UUID) (FindPostRow, error) {
+	row := q.db.QueryRow(ctx, findPost, id)
+	var i FindPostRow
+	err := row.Scan(&i.ID, &i.Title, &i.CreateAt)
+	return i, err
+}
+
+const findPosts = `-- name: FindPosts :many
+SELECT p.id, p.title, p.content, p.create_at, GREATEST(p.create_at, p.updated_at)::timestamptz AS updated,
+       p.author_id, u.name AS author_name, p.board_id
+FROM posts p
+JOIN users u ON u.id = p.author_id
+WHERE p.hidden_at IS NULL AND p.status = 'published'
+  AND ($1::uuid IS NULL OR p.board_id = $1)
+  AND ($2::uuid IS NULL OR p.author_id = $2)
+  AND ($3::varchar IS NULL OR p.tags @> ARRAY[$3::varchar])
+ORDER BY p.create_at DESC, p.id DESC
+LIMIT $4
+`
+
+type FindPostsParams struct {
+	BoardID  pgtype.UUID
+	AuthorID pgtype.UUID
+	Tag      pgtype.Text
+	Size     int32
+}
+
+type FindPostsRow struct {
+	ID         uuid.UUID
+	Title      pgtype.Text
+	Content    pgtype.Text
+	CreateAt   pgtype.Timestamptz
+	Updated    pgtype.Timestamptz
+	AuthorID   uuid.UUID
+	AuthorName string
+	BoardID    pgtype.UUID
+}
+
+// Newest published posts, optionally of a single board, author or tag. updated is the latest of creation, publication
+// and the last edit or moderation.
+func (q *Queries) FindPosts(ctx context.Context, arg FindPostsParams) ([]FindPostsRow, error) {
+	rows, err := q.db.Query(ctx, findPosts,
+		arg.BoardID,
+		arg.AuthorID,
+		arg.Tag,
+		arg.Size,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FindPostsRow
+	for rows.Next() {
+		var i FindPostsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Content,
+			&i.CreateAt,
+			&i.Updated,
+			&i.AuthorID,
+			&i.AuthorName,
+			&i.BoardID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findUser = `-- name: FindUser :one
+SELECT id, name, created_at FROM users WHERE id = $1
+`
+
+type FindUserRow struct {
+	ID        uuid.UUID
+	Name      string
+	CreatedAt pgtype.Timestamptz
+}
+
+func (q *Queries) FindUser(ctx context.Context, id uuid.UUID) (FindUserRow, error) {
+	row := q.db.QueryRow(ctx, findUser, id)
+	var i FindUserRow
+	err := row.Scan(&i.ID, &i.Name, &i.CreatedAt)
+	return i, err
+}
@@ -0,0 +1,197 @@
+package syndication
+
+import (
+	"backend/internal"
+	"backend/internal/database"
+	"context"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"time"
+)
+
+// Size is the number of entries in a feed, feed readers poll often enough to not miss older ones
+const Size = 50
+
+// Feed is the format independent content of a feed, see WriteAtom and WriteRSS
+type Feed struct {
+	Title    string
+	Subtitle string
+	// Updated is the latest update of any entry, or the creation of the feed subject while there are no entries
+	Updated time.Time
+	Entries []Entry
+}
+
+type Entry struct {
+	ID uuid.UUID
+	// Kind is post or comment and selects the API path the entry links to
+	Kind      string
+	Title     string
+	Content   string
+	Author    string
+	Published time.Time
+	Updated   time.Time
+}
+
+type Service struct {
+	logger *zap.Logger
+	tracer trace.Tracer
+	query  *Queries
+}
+
+func NewService(logger *zap.Logger, db DBTX) *Service {
+	return &Service{
+		logger: logger,
+		tracer: otel.Tracer("syndication/service"),
+		query:  New(db),
+	}
+}
+
+// Posts returns the feed of the newest published posts of all boards
+func (s *Service) Posts(ctx context.Context) (Feed, error) {
+	traceCtx, span := s.tracer.Start(ctx, "Posts")
+	defer span.End()
+
+	feed := Feed{Title: "Posts"}
+	err := s.addPosts(traceCtx, &feed, FindPostsParams{})
+	if err != nil {
+		span.RecordError(err)
+		return Feed{}, err
+	}
+
+	return feed, nil
+}
+
+// BoardPosts returns the feed of the newest published posts of a board
+func (s *Service) BoardPosts(ctx context.Context, boardID uuid.UUID) (Feed, error) {
+	traceCtx, span := s.tracer.Start(ctx, "BoardPosts")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	board, err := s.query.FindBoard(traceCtx, boardID)
+	if err != nil {
+		err = database.WrapDBErrorWithKeyValue(err, "boards", "id", boardID.String(), logger, "get board for feed")
+		span.RecordError(err)
+		return Feed{}, err
+	}
+
+	feed := Feed{Title: board.Name, Subtitle: board.Description.String, Updated: board.CreatedAt.Time}
+	err = s.addPosts(traceCtx, &feed, FindPostsParams{BoardID: pgtype.UUID{Bytes: boardID, Valid: true}})
+	if err != nil {
+		span.RecordError(err)
+		return Feed{}, err
+	}
+
+	return feed, nil
+}
+
+// UserPosts returns the feed of the newest published posts of a user
+func (s *Service) UserPosts(ctx context.Context, userID uuid.UUID) (Feed, error) {
+	traceCtx, span := s.tracer.Start(ctx, "UserPosts")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	user, err := s.query.FindUser(traceCtx, userID)
+	if err != nil {
+		err = database.WrapDBErrorWithKeyValue(err, "users", "id", userID.String(), logger, "get user for feed")
+		span.RecordError(err)
+		return Feed{}, err
+	}
+
+	feed := Feed{Title: "Posts by " + user.Name, Updated: user.CreatedAt.Time}
+	err = s.addPosts(traceCtx, &feed, FindPostsParams{AuthorID: pgtype.UUID{Bytes: userID, Valid: true}})
+	if err != nil {
+		span.RecordError(err)
+		return Feed{}, err
+	}
+
+	return feed, nil
+}
+
+// TagPosts returns the feed of the newest published posts with a tag
+func (s *Service) TagPosts(ctx context.Context, tag string) (Feed, error) {
+	traceCtx, span := s.tracer.Start(ctx, "TagPosts")
+	defer span.End()
+
+	feed := Feed{Title: "Posts tagged " + tag}
+	err := s.addPosts(traceCtx, &feed, FindPostsParams{Tag: pgtype.Text{String: tag, Valid: true}})
+	if err != nil {
+		span.RecordError(err)
+		return Feed{}, err
+	}
+
+	return feed, nil
+}
+
+// PostComments returns the feed of the newest comments of a published post
+func (s *Service) PostComments(ctx context.Context, postID uuid.UUID) (Feed, error) {
+	traceCtx, span := s.tracer.Start(ctx, "PostComments")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	post, err := s.query.FindPost(traceCtx, postID)
+	if err != nil {
+		err = database.WrapDBErrorWithKeyValue(err, "post", "id", postID.String(), logger, "get post for feed")
+		span.RecordError(err)
+		return Feed{}, err
+	}
+
+	comments, err := s.query.FindComments(traceCtx, FindCommentsParams{PostID: postID, Size: Size})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "get comments for feed")
+		span.RecordError(err)
+		return Feed{}, err
+	}
+
+	feed := Feed{Title: "Comments on " + post.Title.String, Updated: post.CreateAt.Time}
+	feed.Entries = make([]Entry, len(comments))
+	for i, c := range comments {
+		feed.Entries[i] = Entry{
+			ID:        c.ID,
+			Kind:      "comment",
+			Title:     c.Title.String,
+			Content:   c.Content.String,
+			Author:    c.AuthorName,
+			Published: c.CreatedAt.Time,
+			Updated:   c.Updated.Time,
+		}
+	}
+	feed.updated()
+
+	return feed, nil
+}
+
+func (s *Service) addPosts(ctx context.Context, feed *Feed, params FindPostsParams) error {
+	params.Size = Size
+	posts, err := s.query.FindPosts(ctx, params)
+	if err != nil {
+		return database.WrapDBError(err, internal.LoggerWithContext(ctx, s.logger), "get posts for feed")
+	}
+
+	feed.Entries = make([]Entry, len(posts))
+	for i, p := range posts {
+		feed.Entries[i] = Entry{
+			ID:        p.ID,
+			Kind:      "post",
+			Title:     p.Title.String,
+			Content:   p.Content.String,
+			Author:    p.AuthorName,
+			Published: p.CreateAt.Time,
+			Updated:   p.Updated.Time,
+		}
+	}
+	feed.updated()
+
+	return nil
+}
+
+// updated moves Updated forward to the latest update of the entries
+func (f *Feed) updated() {
+	for _, e := range f.Entries {
+		if e.Updated.After(f.Updated) {
+			f.Updated = e.Updated
+		}
+	}
+}
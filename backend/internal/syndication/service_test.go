@@ -0,0 +1,71 @@
+package syndication_test
+
+import (
+	"backend/internal/database/databasetest"
+	"backend/internal/syndication"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+func TestService_PostComments(t *testing.T) {
+	pool := databasetest.Open(t)
+	s := syndication.NewService(zap.NewNop(), pool)
+	ctx := context.Background()
+
+	author := databasetest.CreateUser(t, pool)
+	postID := databasetest.CreatePost(t, pool, author)
+	written := time.Now().Add(-time.Hour).Truncate(time.Microsecond)
+	commentID := databasetest.CreateComment(t, pool, postID, author, written)
+
+	feed, err := s.PostComments(ctx, postID)
+	if err != nil {
+		t.Fatalf("failed to get feed: %v", err)
+	}
+	if assert.Len(t, feed.Entries, 1) {
+		assert.True(t, written.Equal(feed.Entries[0].Updated))
+	}
+
+	edited := written.Add(30 * time.Minute)
+	_, err = pool.Exec(ctx, "UPDATE comments SET updated_at = $2 WHERE id = $1", commentID, edited)
+	if err != nil {
+		t.Fatalf("failed to edit comment: %v", err)
+	}
+
+	feed, err = s.PostComments(ctx, postID)
+	if err != nil {
+		t.Fatalf("failed to get feed: %v", err)
+	}
+	if assert.Len(t, feed.Entries, 1) {
+		assert.True(t, written.Equal(feed.Entries[0].Published))
+		assert.True(t, edited.Equal(feed.Entries[0].Updated))
+	}
+	assert.True(t, edited.Equal(feed.Updated))
+}
+
+func TestService_TagPosts(t *testing.T) {
+	pool := databasetest.Open(t)
+	s := syndication.NewService(zap.NewNop(), pool)
+	ctx := context.Background()
+
+	author := databasetest.CreateUser(t, pool)
+	tagged := databasetest.CreatePost(t, pool, author)
+	databasetest.CreatePost(t, pool, author)
+	// a tag of its own keeps posts of other tests out of the feed
+	tag := "test-" + author.String()[:8]
+	_, err := pool.Exec(ctx, "UPDATE posts SET tags = ARRAY[$2::varchar, 'other'] WHERE id = $1", tagged, tag)
+	if err != nil {
+		t.Fatalf("failed to tag post: %v", err)
+	}
+
+	feed, err := s.TagPosts(ctx, tag)
+	if err != nil {
+		t.Fatalf("failed to get feed: %v", err)
+	}
+	assert.Equal(t, "Posts tagged "+tag, feed.Title)
+	if assert.Len(t, feed.Entries, 1) {
+		assert.Equal(t, tagged, feed.Entries[0].ID)
+	}
+}
@@ -0,0 +1,243 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	board "backend/internal/board"
+	comment "backend/internal/comment"
+
+	context "context"
+
+	jwt "backend/internal/jwt"
+
+	mock "github.com/stretchr/testify/mock"
+
+	post "backend/internal/post"
+
+	ssh "golang.org/x/crypto/ssh"
+
+	tui "backend/internal/tui"
+
+	uuid "github.com/google/uuid"
+)
+
+// Store is an autogenerated mock type for the Store type
+type Store struct {
+	mock.Mock
+}
+
+// Authenticate provides a mock function with given fields: ctx, key
+func (_m *Store) Authenticate(ctx context.Context, key ssh.PublicKey) (uuid.UUID, error) {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Authenticate")
+	}
+
+	var r0 uuid.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, ssh.PublicKey) (uuid.UUID, error)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ssh.PublicKey) uuid.UUID); ok {
+		r0 = rf(ctx, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ssh.PublicKey) error); ok {
+		r1 = rf(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Boards provides a mock function with given fields: ctx
+func (_m *Store) Boards(ctx context.Context) ([]board.Board, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Boards")
+	}
+
+	var r0 []board.Board
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]board.Board, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []board.Board); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]board.Board)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreatePost provides a mock function with given fields: ctx, r
+func (_m *Store) CreatePost(ctx context.Context, r post.CreateRequest) (post.Post, error) {
+	ret := _m.Called(ctx, r)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePost")
+	}
+
+	var r0 post.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, post.CreateRequest) (post.Post, error)); ok {
+		return rf(ctx, r)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, post.CreateRequest) post.Post); ok {
+		r0 = rf(ctx, r)
+	} else {
+		r0 = ret.Get(0).(post.Post)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, post.CreateRequest) error); ok {
+		r1 = rf(ctx, r)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Login provides a mock function with given fields: ctx, userID
+func (_m *Store) Login(ctx context.Context, userID uuid.UUID) (jwt.User, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Login")
+	}
+
+	var r0 jwt.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (jwt.User, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) jwt.User); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(jwt.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Posts provides a mock function with given fields: ctx, viewerID, boardID
+func (_m *Store) Posts(ctx context.Context, viewerID uuid.UUID, boardID uuid.UUID) ([]tui.Summary, error) {
+	ret := _m.Called(ctx, viewerID, boardID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Posts")
+	}
+
+	var r0 []tui.Summary
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) ([]tui.Summary, error)); ok {
+		return rf(ctx, viewerID, boardID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) []tui.Summary); ok {
+		r0 = rf(ctx, viewerID, boardID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]tui.Summary)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(ctx, viewerID, boardID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Reply provides a mock function with given fields: ctx, r
+func (_m *Store) Reply(ctx context.Context, r comment.CreateRequest) (comment.Comment, error) {
+	ret := _m.Called(ctx, r)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reply")
+	}
+
+	var r0 comment.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, comment.CreateRequest) (comment.Comment, error)); ok {
+		return rf(ctx, r)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, comment.CreateRequest) comment.Comment); ok {
+		r0 = rf(ctx, r)
+	} else {
+		r0 = ret.Get(0).(comment.Comment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, comment.CreateRequest) error); ok {
+		r1 = rf(ctx, r)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Thread provides a mock function with given fields: ctx, viewerID, postID
+func (_m *Store) Thread(ctx context.Context, viewerID uuid.UUID, postID uuid.UUID) (tui.Thread, error) {
+	ret := _m.Called(ctx, viewerID, postID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Thread")
+	}
+
+	var r0 tui.Thread
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) (tui.Thread, error)); ok {
+		return rf(ctx, viewerID, postID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) tui.Thread); ok {
+		r0 = rf(ctx, viewerID, postID)
+	} else {
+		r0 = ret.Get(0).(tui.Thread)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(ctx, viewerID, postID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewStore creates a new instance of Store. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Store {
+	mock := &Store{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
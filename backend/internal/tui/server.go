@@ -0,0 +1,228 @@
+package tui
+
+import (
+	"backend/internal"
+	"backend/internal/board"
+	"backend/internal/comment"
+	"backend/internal/gateway"
+	"backend/internal/jwt"
+	"backend/internal/post"
+	"backend/internal/problem"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/term"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// handshakeTimeout closes connections that do not log in in time
+	handshakeTimeout = 30 * time.Second
+	// idleTimeout closes sessions without input
+	idleTimeout = 30 * time.Minute
+)
+
+//go:generate mockery --name Store
+type Store interface {
+	Authenticate(ctx context.Context, key ssh.PublicKey) (uuid.UUID, error)
+	Login(ctx context.Context, userID uuid.UUID) (jwt.User, error)
+	Boards(ctx context.Context) ([]board.Board, error)
+	Posts(ctx context.Context, viewerID, boardID uuid.UUID) ([]Summary, error)
+	Thread(ctx context.Context, viewerID, postID uuid.UUID) (Thread, error)
+	CreatePost(ctx context.Context, r post.CreateRequest) (post.Post, error)
+	Reply(ctx context.Context, r comment.CreateRequest) (comment.Comment, error)
+}
+
+// Server is an SSH server presenting the forum as terminal UI. Users log in with a public key they registered
+// through the web API, any user name is accepted as the key decides who logs in.
+type Server struct {
+	logger  *zap.Logger
+	tracer  trace.Tracer
+	store   Store
+	addr    string
+	hostKey ssh.Signer
+}
+
+func NewServer(logger *zap.Logger, store Store, addr string, hostKey ssh.Signer) *Server {
+	return &Server{
+		logger:  logger,
+		tracer:  otel.Tracer("tui/server"),
+		store:   store,
+		addr:    addr,
+		hostKey: hostKey,
+	}
+}
+
+// LoadHostKey reads the private host key at path and generates an Ed25519 key there if it does not exist yet, so
+// clients see the same host key after a restart
+func LoadHostKey(path string) (ssh.Signer, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		block, err := ssh.MarshalPrivateKey(privateKey, "")
+		if err != nil {
+			return nil, err
+		}
+		data = pem.EncodeToMemory(block)
+
+		err = os.MkdirAll(filepath.Dir(path), 0o700)
+		if err != nil {
+			return nil, err
+		}
+		err = os.WriteFile(path, data, 0o600)
+		if err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	return ssh.ParsePrivateKey(data)
+}
+
+// Run serves terminals on the address of the server until ctx is done
+func (s *Server) Run(ctx context.Context) {
+	gateway.Run(ctx, s.logger, "SSH", s.addr, s.Serve)
+}
+
+// Serve accepts connections on the listener until ctx is done, open sessions are closed with it
+func (s *Server) Serve(ctx context.Context, listener net.Listener) {
+	gateway.Serve(ctx, s.logger, "SSH", listener, s.handle)
+}
+
+func (s *Server) handle(ctx context.Context, conn net.Conn) {
+	host := gateway.ClientIP(conn)
+	ctx = context.WithValue(ctx, internal.ClientIPContextKey, host)
+
+	config := &ssh.ServerConfig{
+		ServerVersion: "SSH-2.0-CLI-Forum",
+		// called for every offered key before the client proves it holds the private key, the login is only
+		// checked and recorded once the handshake succeeded
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			userID, err := s.store.Authenticate(ctx, key)
+			if err != nil {
+				return nil, err
+			}
+			return &ssh.Permissions{Extensions: map[string]string{"id": userID.String()}}, nil
+		},
+	}
+	config.AddHostKey(s.hostKey)
+
+	_ = conn.SetDeadline(time.Now().Add(handshakeTimeout))
+	sshConn, channels, requests, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		s.logger.Debug("Failed SSH handshake", zap.String("client_ip", host), zap.Error(err))
+		return
+	}
+	defer sshConn.Close()
+	go ssh.DiscardRequests(requests)
+
+	userID, err := uuid.Parse(sshConn.Permissions.Extensions["id"])
+	if err != nil {
+		s.logger.Error("Invalid user of SSH session", zap.String("id", sshConn.Permissions.Extensions["id"]), zap.Error(err))
+		return
+	}
+	user, err := s.store.Login(ctx, userID)
+	if err != nil {
+		s.logger.Info("Rejected SSH login", zap.String("client_ip", host), zap.String("id", userID.String()), zap.Error(err))
+		// clients show the reason of a rejected session, for example the end of a suspension
+		newChannel, ok := <-channels
+		if ok {
+			_ = newChannel.Reject(ssh.Prohibited, problem.FromError(err).Detail)
+		}
+		return
+	}
+	_ = conn.SetDeadline(time.Time{})
+	ctx = context.WithValue(ctx, internal.UserContextKey, user)
+
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "only sessions are supported")
+			continue
+		}
+		channel, channelRequests, err := newChannel.Accept()
+		if err != nil {
+			s.logger.Debug("Failed to accept SSH channel", zap.Error(err))
+			continue
+		}
+		go s.serveChannel(ctx, userID, channel, channelRequests)
+	}
+}
+
+// serveChannel answers the requests of a session channel and runs the UI once the client asks for a shell
+func (s *Server) serveChannel(ctx context.Context, userID uuid.UUID, channel ssh.Channel, requests <-chan *ssh.Request) {
+	terminal := term.NewTerminal(channel, "")
+	started := false
+
+	for req := range requests {
+		switch req.Type {
+		case "pty-req":
+			var pty struct {
+				Term          string
+				Columns, Rows uint32
+				Width, Height uint32
+				Modes         string
+			}
+			if ssh.Unmarshal(req.Payload, &pty) == nil {
+				_ = terminal.SetSize(int(pty.Columns), int(pty.Rows))
+			}
+			_ = req.Reply(true, nil)
+		case "window-change":
+			var size struct {
+				Columns, Rows uint32
+				Width, Height uint32
+			}
+			if ssh.Unmarshal(req.Payload, &size) == nil {
+				_ = terminal.SetSize(int(size.Columns), int(size.Rows))
+			}
+		case "shell":
+			if started {
+				_ = req.Reply(false, nil)
+				continue
+			}
+			started = true
+			_ = req.Reply(true, nil)
+			go func() {
+				s.runSession(ctx, userID, terminal, channel)
+				_, _ = channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{0}))
+				_ = channel.Close()
+			}()
+		default:
+			// exec, subsystems and environment variables are not supported
+			if req.WantReply {
+				_ = req.Reply(false, nil)
+			}
+		}
+	}
+}
+
+func (s *Server) runSession(ctx context.Context, userID uuid.UUID, terminal *term.Terminal, channel ssh.Channel) {
+	sess := &session{
+		server:   s,
+		ctx:      ctx,
+		userID:   userID,
+		terminal: terminal,
+		idle: time.AfterFunc(idleTimeout, func() {
+			_ = channel.Close()
+		}),
+	}
+	defer sess.idle.Stop()
+
+	err := sess.run()
+	if err != nil {
+		s.logger.Debug("Closed SSH session", zap.String("user_id", userID.String()), zap.Error(err))
+	}
+}
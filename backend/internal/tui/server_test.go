@@ -0,0 +1,249 @@
+package tui_test
+
+import (
+	"backend/internal/board"
+	"backend/internal/comment"
+	errorPkg "backend/internal/error"
+	"backend/internal/jwt"
+	"backend/internal/post"
+	"backend/internal/tui"
+	"backend/internal/tui/mocks"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/ssh"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var (
+	user = jwt.User{
+		ID:       "81c1ecc1-66d7-4134-b5fe-d886a6f418a4",
+		Username: "reader",
+		Role:     jwt.RoleUser,
+	}
+	general = board.Board{ID: uuid.MustParse("1f0b3c8e-3b0a-4d8e-9f3e-9b1b0f6b7c11"), Slug: "general", Name: "General"}
+	release = post.Post{
+		ID:       uuid.MustParse("54a46af2-b454-4746-8ab0-3cf26085a50b"),
+		AuthorID: uuid.MustParse("8d1c4a2e-0f4b-4c55-9a51-3b0c4f2e9d10"),
+		Title:    pgtype.Text{String: "Release", Valid: true},
+		Content:  pgtype.Text{String: "Version **2** is out", Valid: true},
+		BoardID:  pgtype.UUID{Bytes: general.ID, Valid: true},
+		Status:   post.StatusPublished,
+	}
+	question = comment.Comment{
+		ID:      uuid.MustParse("6a0e5f51-4f0c-4d5e-8b4b-2c1d3e4f5a6b"),
+		PostID:  release.ID,
+		Title:   pgtype.Text{String: "When?", Valid: true},
+		Content: pgtype.Text{String: "When does it ship?", Valid: true},
+	}
+)
+
+func TestServer_Session(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      []string
+		setupMock  func(m *mocks.Store)
+		wantOutput []string
+	}{
+		{
+			name:  "Should list boards",
+			input: []string{"q"},
+			setupMock: func(m *mocks.Store) {
+				m.On("Boards", mock.Anything).Return([]board.Board{general}, nil)
+			},
+			wantOutput: []string{"General", "Bye"},
+		},
+		{
+			name:  "Should read thread",
+			input: []string{"1", "1", "q"},
+			setupMock: func(m *mocks.Store) {
+				m.On("Boards", mock.Anything).Return([]board.Board{general}, nil)
+				m.On("Posts", mock.Anything, uuid.MustParse(user.ID), general.ID).Return([]tui.Summary{{Post: release, Author: "alice"}}, nil)
+				m.On("Thread", mock.Anything, uuid.MustParse(user.ID), release.ID).Return(tui.Thread{
+					Post:    release,
+					Author:  "alice",
+					Replies: []tui.Reply{{Comment: question, Author: "bob"}, {Comment: question, Author: "carol", Collapsed: true}},
+				}, nil)
+			},
+			wantOutput: []string{"Release", "2 comments", "When does it ship?", "(comment of a muted or blocked user)"},
+		},
+		{
+			name:  "Should reply to comment",
+			input: []string{"1", "1", "r 1", "", "Next week", ".", "y", "q"},
+			setupMock: func(m *mocks.Store) {
+				m.On("Boards", mock.Anything).Return([]board.Board{general}, nil)
+				m.On("Posts", mock.Anything, uuid.MustParse(user.ID), general.ID).Return([]tui.Summary{{Post: release, Author: "alice"}}, nil)
+				m.On("Thread", mock.Anything, uuid.MustParse(user.ID), release.ID).Return(tui.Thread{
+					Post:    release,
+					Author:  "alice",
+					Replies: []tui.Reply{{Comment: question, Author: "bob"}},
+				}, nil)
+				m.On("Reply", mock.Anything, comment.CreateRequest{
+					PostID:   release.ID,
+					AuthorID: uuid.MustParse(user.ID),
+					ParentID: &question.ID,
+					Title:    "Re: When?",
+					Content:  "Next week",
+				}).Return(comment.Comment{ID: uuid.New()}, nil)
+			},
+			wantOutput: []string{"Replied"},
+		},
+		{
+			name:  "Should keep a single Re: prefix in replies to replies",
+			input: []string{"1", "1", "r 1", "", "Tomorrow", ".", "y", "q"},
+			setupMock: func(m *mocks.Store) {
+				answer := comment.Comment{ID: uuid.New(), PostID: release.ID, Title: pgtype.Text{String: "Re: When?", Valid: true}}
+				m.On("Boards", mock.Anything).Return([]board.Board{general}, nil)
+				m.On("Posts", mock.Anything, uuid.MustParse(user.ID), general.ID).Return([]tui.Summary{{Post: release, Author: "alice"}}, nil)
+				m.On("Thread", mock.Anything, uuid.MustParse(user.ID), release.ID).Return(tui.Thread{
+					Post:    release,
+					Author:  "alice",
+					Replies: []tui.Reply{{Comment: answer, Author: "bob"}},
+				}, nil)
+				m.On("Reply", mock.Anything, comment.CreateRequest{
+					PostID:   release.ID,
+					AuthorID: uuid.MustParse(user.ID),
+					ParentID: &answer.ID,
+					Title:    "Re: When?",
+					Content:  "Tomorrow",
+				}).Return(comment.Comment{ID: uuid.New()}, nil)
+			},
+			wantOutput: []string{"Replied"},
+		},
+		{
+			name:  "Should show error of the services",
+			input: []string{"1", "1", "r", "", "Late", ".", "y", "q"},
+			setupMock: func(m *mocks.Store) {
+				m.On("Boards", mock.Anything).Return([]board.Board{general}, nil)
+				m.On("Posts", mock.Anything, uuid.MustParse(user.ID), general.ID).Return([]tui.Summary{{Post: release, Author: "alice"}}, nil)
+				m.On("Thread", mock.Anything, uuid.MustParse(user.ID), release.ID).Return(tui.Thread{Post: release, Author: "alice"}, nil)
+				m.On("Reply", mock.Anything, mock.Anything).Return(comment.Comment{}, errorPkg.ErrPostLocked)
+			},
+			wantOutput: []string{"The thread is locked and does not accept new comments"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStore := mocks.NewStore(t)
+			mockStore.On("Authenticate", mock.Anything, mock.Anything).Return(uuid.MustParse(user.ID), nil)
+			mockStore.On("Login", mock.Anything, uuid.MustParse(user.ID)).Return(user, nil).Once()
+			tt.setupMock(mockStore)
+
+			client := dial(t, mockStore)
+			session, err := client.NewSession()
+			if err != nil {
+				t.Fatalf("NewSession() error = %v", err)
+			}
+			var output bytes.Buffer
+			session.Stdout = &output
+			session.Stdin = strings.NewReader(strings.Join(tt.input, "\r") + "\r")
+			err = session.RequestPty("xterm", 40, 120, ssh.TerminalModes{})
+			if err != nil {
+				t.Fatalf("RequestPty() error = %v", err)
+			}
+			err = session.Shell()
+			if err != nil {
+				t.Fatalf("Shell() error = %v", err)
+			}
+			err = session.Wait()
+			if err != nil {
+				t.Fatalf("Wait() error = %v", err)
+			}
+
+			for _, want := range tt.wantOutput {
+				assert.Contains(t, output.String(), want)
+			}
+		})
+	}
+}
+
+func TestServer_RejectsUnknownKey(t *testing.T) {
+	mockStore := mocks.NewStore(t)
+	mockStore.On("Authenticate", mock.Anything, mock.Anything).Return(uuid.Nil, errorPkg.ErrCredentialInvalid)
+
+	// the login is neither checked nor recorded without a completed handshake
+	_, err := ssh.Dial("tcp", serve(t, mockStore), clientConfig(t))
+	assert.ErrorContains(t, err, "unable to authenticate")
+}
+
+func TestServer_RejectsSuspendedUser(t *testing.T) {
+	mockStore := mocks.NewStore(t)
+	mockStore.On("Authenticate", mock.Anything, mock.Anything).Return(uuid.MustParse(user.ID), nil)
+	mockStore.On("Login", mock.Anything, uuid.MustParse(user.ID)).Return(jwt.User{}, errorPkg.NewSuspendedError(nil, "spam")).Once()
+
+	client := dial(t, mockStore)
+	_, err := client.NewSession()
+	var openErr *ssh.OpenChannelError
+	if assert.ErrorAs(t, err, &openErr) {
+		assert.Equal(t, ssh.Prohibited, openErr.Reason)
+		assert.Contains(t, openErr.Message, "spam")
+	}
+}
+
+func TestLoadHostKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ssh", "ssh_host_key")
+	generated, err := tui.LoadHostKey(path)
+	if err != nil {
+		t.Fatalf("LoadHostKey() error = %v", err)
+	}
+	loaded, err := tui.LoadHostKey(path)
+	if err != nil {
+		t.Fatalf("LoadHostKey() error = %v", err)
+	}
+
+	assert.Equal(t, generated.PublicKey().Marshal(), loaded.PublicKey().Marshal())
+}
+
+// dial starts the server on a free port and logs in with a new client key
+func dial(t *testing.T, store tui.Store) *ssh.Client {
+	client, err := ssh.Dial("tcp", serve(t, store), clientConfig(t))
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	return client
+}
+
+func serve(t *testing.T, store tui.Store) string {
+	hostKey, err := tui.LoadHostKey(filepath.Join(t.TempDir(), "ssh_host_key"))
+	if err != nil {
+		t.Fatalf("LoadHostKey() error = %v", err)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go tui.NewServer(zap.NewNop(), store, "", hostKey).Serve(ctx, listener)
+	return listener.Addr().String()
+}
+
+func clientConfig(t *testing.T) *ssh.ClientConfig {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	signer, err := ssh.NewSignerFromKey(privateKey)
+	if err != nil {
+		t.Fatalf("NewSignerFromKey() error = %v", err)
+	}
+
+	return &ssh.ClientConfig{
+		User:            "anyone",
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+	}
+}
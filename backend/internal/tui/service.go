@@ -0,0 +1,299 @@
+package tui
+
+import (
+	"backend/internal"
+	"backend/internal/audit"
+	"backend/internal/auth"
+	"backend/internal/board"
+	"backend/internal/comment"
+	errorPkg "backend/internal/error"
+	"backend/internal/gateway"
+	"backend/internal/jwt"
+	"backend/internal/post"
+	"backend/internal/ratelimit"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/ssh"
+	"time"
+)
+
+// Summary is a post in the list of a board
+type Summary struct {
+	Post      post.Post
+	Author    string
+	Collapsed bool
+}
+
+// Thread is a post with its comments in reading order, replies follow the comment they answer
+type Thread struct {
+	Post    post.Post
+	Author  string
+	Replies []Reply
+}
+
+type Reply struct {
+	Comment comment.Comment
+	Author  string
+	// Depth is the number of comments between the reply and the post
+	Depth     int
+	Collapsed bool
+}
+
+// Keys finds the user of a public key, see sshkey.Service
+type Keys interface {
+	Authenticate(ctx context.Context, key ssh.PublicKey) (uuid.UUID, error)
+}
+
+type Boards interface {
+	GetAll(ctx context.Context) ([]board.Board, error)
+}
+
+type Posts interface {
+	GetByBoard(ctx context.Context, viewerID, boardID uuid.UUID) ([]post.Listed, error)
+	GetByID(ctx context.Context, id uuid.UUID) (post.Post, error)
+	Create(ctx context.Context, r post.CreateRequest) (post.Post, error)
+}
+
+type Comments interface {
+	GetByPost(ctx context.Context, viewerID, postId uuid.UUID) ([]comment.Listed, error)
+	Create(ctx context.Context, arg comment.CreateRequest) (comment.Comment, error)
+}
+
+// Auditor keeps the trail of logins, see audit.Service
+type Auditor interface {
+	Record(ctx context.Context, entry audit.Entry) error
+}
+
+// Service gives the terminal UI the content of the forum through the services of the web API
+type Service struct {
+	logger *zap.Logger
+	tracer trace.Tracer
+
+	keys     Keys
+	boards   Boards
+	posts    Posts
+	comments Comments
+	users    gateway.Users
+	auditor  Auditor
+	limiter  *ratelimit.Limiter
+}
+
+func NewService(logger *zap.Logger, keys Keys, boards Boards, posts Posts, comments Comments, users gateway.Users, auditor Auditor, limiter *ratelimit.Limiter) *Service {
+	return &Service{
+		logger:   logger,
+		tracer:   otel.Tracer("tui/service"),
+		keys:     keys,
+		boards:   boards,
+		posts:    posts,
+		comments: comments,
+		users:    users,
+		auditor:  auditor,
+		limiter:  limiter,
+	}
+}
+
+// Authenticate returns the user a public key is registered for. SSH clients offer keys before proving they hold
+// the private key, so nothing is recorded here, see Login.
+func (s *Service) Authenticate(ctx context.Context, key ssh.PublicKey) (uuid.UUID, error) {
+	traceCtx, span := s.tracer.Start(ctx, "Authenticate")
+	defer span.End()
+
+	userID, err := s.keys.Authenticate(traceCtx, key)
+	if err != nil {
+		// Not recorded as failed login, clients try every key they have until one is accepted
+		err = fmt.Errorf("%w: %v", errorPkg.ErrCredentialInvalid, err)
+		span.RecordError(err)
+		return uuid.Nil, err
+	}
+
+	return userID, nil
+}
+
+// Login returns the user of a completed handshake and records the login, suspended users are rejected like on the
+// web API
+func (s *Service) Login(ctx context.Context, userID uuid.UUID) (jwt.User, error) {
+	traceCtx, span := s.tracer.Start(ctx, "Login")
+	defer span.End()
+
+	userEntity, err := s.users.GetByID(traceCtx, userID)
+	if err != nil {
+		span.RecordError(err)
+		return jwt.User{}, err
+	}
+	if userEntity.IsSuspended(time.Now()) {
+		s.record(traceCtx, audit.Entry{Action: auth.ActionLoginFailed, TargetID: userEntity.ID, Details: "ssh: suspended"})
+		err = userEntity.SuspensionError()
+		span.RecordError(err)
+		return jwt.User{}, err
+	}
+
+	s.record(traceCtx, audit.Entry{ActorID: userEntity.ID, Action: auth.ActionLogin, TargetID: userEntity.ID, Details: "ssh"})
+	return jwt.User{ID: userEntity.ID.String(), Username: userEntity.Name, Role: userEntity.Role, IssuedAt: time.Now()}, nil
+}
+
+func (s *Service) Boards(ctx context.Context) ([]board.Board, error) {
+	traceCtx, span := s.tracer.Start(ctx, "Boards")
+	defer span.End()
+
+	boards, err := s.boards.GetAll(traceCtx)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return boards, nil
+}
+
+// Posts lists the posts of a board in the order of the web API, pinned posts first
+func (s *Service) Posts(ctx context.Context, viewerID, boardID uuid.UUID) ([]Summary, error) {
+	traceCtx, span := s.tracer.Start(ctx, "Posts")
+	defer span.End()
+
+	listed, err := s.posts.GetByBoard(traceCtx, viewerID, boardID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	names := gateway.NewNames(s.users)
+	summaries := make([]Summary, len(listed))
+	for i, l := range listed {
+		author, err := names.Get(traceCtx, l.Post.AuthorID)
+		if err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+		summaries[i] = Summary{Post: l.Post, Author: author, Collapsed: l.Collapsed}
+	}
+
+	return summaries, nil
+}
+
+// Thread returns a post and its comments, unpublished posts are only found by their author
+func (s *Service) Thread(ctx context.Context, viewerID, postID uuid.UUID) (Thread, error) {
+	traceCtx, span := s.tracer.Start(ctx, "Thread")
+	defer span.End()
+
+	p, err := s.posts.GetByID(traceCtx, postID)
+	if err == nil && !p.Published() && p.AuthorID != viewerID {
+		err = errorPkg.NewNotFoundError("post", "id", postID.String(), "")
+	}
+	if err != nil {
+		span.RecordError(err)
+		return Thread{}, err
+	}
+
+	listed, err := s.comments.GetByPost(traceCtx, viewerID, postID)
+	if err != nil {
+		span.RecordError(err)
+		return Thread{}, err
+	}
+
+	names := gateway.NewNames(s.users)
+	thread := Thread{Post: p}
+	thread.Author, err = names.Get(traceCtx, p.AuthorID)
+	if err != nil {
+		span.RecordError(err)
+		return Thread{}, err
+	}
+	for _, l := range threaded(listed) {
+		author, err := names.Get(traceCtx, l.Comment.AuthorID)
+		if err != nil {
+			span.RecordError(err)
+			return Thread{}, err
+		}
+		thread.Replies = append(thread.Replies, Reply{Comment: l.Comment, Author: author, Depth: l.depth, Collapsed: l.Collapsed})
+	}
+
+	return thread, nil
+}
+
+// CreatePost writes a post like the web API, which also limits the posts of the user
+func (s *Service) CreatePost(ctx context.Context, r post.CreateRequest) (post.Post, error) {
+	traceCtx, span := s.tracer.Start(ctx, "CreatePost")
+	defer span.End()
+
+	err := gateway.CheckWrite(traceCtx, s.users, s.limiter, r.AuthorID, ratelimit.RoutePosts)
+	if err != nil {
+		span.RecordError(err)
+		return post.Post{}, err
+	}
+
+	created, err := s.posts.Create(traceCtx, r)
+	if err != nil {
+		span.RecordError(err)
+		return post.Post{}, err
+	}
+
+	return created, nil
+}
+
+// Reply writes a comment like the web API, which also limits the comments of the user
+func (s *Service) Reply(ctx context.Context, r comment.CreateRequest) (comment.Comment, error) {
+	traceCtx, span := s.tracer.Start(ctx, "Reply")
+	defer span.End()
+
+	err := gateway.CheckWrite(traceCtx, s.users, s.limiter, r.AuthorID, ratelimit.RouteComments)
+	if err != nil {
+		span.RecordError(err)
+		return comment.Comment{}, err
+	}
+
+	created, err := s.comments.Create(traceCtx, r)
+	if err != nil {
+		span.RecordError(err)
+		return comment.Comment{}, err
+	}
+
+	return created, nil
+}
+
+// record adds a login to the audit trail, a failure is logged but does not fail the login
+func (s *Service) record(ctx context.Context, entry audit.Entry) {
+	entry.TargetType = audit.TargetUser
+	err := s.auditor.Record(ctx, entry)
+	if err != nil {
+		internal.LoggerWithContext(ctx, s.logger).Error("Failed to record audit entry", zap.String("action", entry.Action), zap.Error(err))
+	}
+}
+
+type threadedComment struct {
+	comment.Listed
+	depth int
+}
+
+// threaded orders comments depth first, each comment followed by its replies in the original order. Replies whose
+// parent is not listed, for example because it was hidden, are shown at the top level.
+func threaded(listed []comment.Listed) []threadedComment {
+	ids := make(map[uuid.UUID]bool, len(listed))
+	for _, l := range listed {
+		ids[l.Comment.ID] = true
+	}
+
+	children := make(map[uuid.UUID][]comment.Listed)
+	var roots []comment.Listed
+	for _, l := range listed {
+		if l.Comment.ParentID.Valid && ids[l.Comment.ParentID.Bytes] {
+			children[l.Comment.ParentID.Bytes] = append(children[l.Comment.ParentID.Bytes], l)
+			continue
+		}
+		roots = append(roots, l)
+	}
+
+	result := make([]threadedComment, 0, len(listed))
+	var walk func(l comment.Listed, depth int)
+	walk = func(l comment.Listed, depth int) {
+		result = append(result, threadedComment{Listed: l, depth: depth})
+		for _, child := range children[l.Comment.ID] {
+			walk(child, depth+1)
+		}
+	}
+	for _, root := range roots {
+		walk(root, 0)
+	}
+	return result
+}
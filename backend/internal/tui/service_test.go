@@ -0,0 +1,132 @@
+package tui_test
+
+import (
+	"backend/internal"
+	"backend/internal/comment"
+	"backend/internal/config"
+	errorPkg "backend/internal/error"
+	"backend/internal/post"
+	"backend/internal/ratelimit"
+	"backend/internal/tui"
+	userPkg "backend/internal/user"
+	"context"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+// users finds the users of a map by ID
+type users map[uuid.UUID]userPkg.User
+
+func (u users) GetByID(_ context.Context, id uuid.UUID) (userPkg.User, error) {
+	found, ok := u[id]
+	if !ok {
+		return userPkg.User{}, errorPkg.NewNotFoundError("users", "id", id.String(), "")
+	}
+	return found, nil
+}
+
+// writes counts the posts and comments that reach the services
+type writes struct {
+	posts    int
+	comments int
+}
+
+func (w *writes) GetByBoard(context.Context, uuid.UUID, uuid.UUID) ([]post.Listed, error) {
+	return nil, nil
+}
+
+func (w *writes) GetByID(context.Context, uuid.UUID) (post.Post, error) {
+	return post.Post{}, nil
+}
+
+func (w *writes) Create(_ context.Context, r post.CreateRequest) (post.Post, error) {
+	w.posts++
+	return post.Post{ID: uuid.New(), AuthorID: r.AuthorID}, nil
+}
+
+// replies is the comment service of writes
+type replies struct {
+	*writes
+}
+
+func (r replies) GetByPost(context.Context, uuid.UUID, uuid.UUID) ([]comment.Listed, error) {
+	return nil, nil
+}
+
+func (r replies) Create(_ context.Context, arg comment.CreateRequest) (comment.Comment, error) {
+	r.comments++
+	return comment.Comment{ID: uuid.New(), PostID: arg.PostID}, nil
+}
+
+func TestService_Writes(t *testing.T) {
+	readerID := uuid.MustParse(user.ID)
+	suspendedID := uuid.MustParse("8d1c4a2e-0f4b-4c55-9a51-3b0c4f2e9d10")
+	known := users{
+		readerID:    {ID: readerID, Name: user.Username, Role: user.Role},
+		suspendedID: {ID: suspendedID, Name: "bob", Role: user.Role, SuspendedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true}},
+	}
+
+	tests := []struct {
+		name         string
+		authorID     uuid.UUID
+		attempts     int
+		wantErr      error
+		wantPosts    int
+		wantComments int
+	}{
+		{
+			name:         "Should write post and reply",
+			authorID:     readerID,
+			attempts:     1,
+			wantPosts:    1,
+			wantComments: 1,
+		},
+		{
+			name:     "Should reject user suspended during the session",
+			authorID: suspendedID,
+			attempts: 1,
+			wantErr:  errorPkg.ErrSuspended,
+		},
+		{
+			name:         "Should limit posts and replies like the web API",
+			authorID:     readerID,
+			attempts:     2,
+			wantErr:      errorPkg.ErrRateLimited,
+			wantPosts:    1,
+			wantComments: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter, err := ratelimit.NewLimiter([]config.RateLimitRule{
+				{Route: ratelimit.RoutePosts, Requests: 1, Per: time.Minute},
+				{Route: ratelimit.RouteComments, Requests: 1, Per: time.Minute},
+			})
+			assert.NoError(t, err)
+			w := &writes{}
+			service := tui.NewService(zap.NewNop(), nil, nil, w, replies{w}, known, nil, limiter)
+			ctx := context.WithValue(context.Background(), internal.UserContextKey, user)
+
+			var postErr, replyErr error
+			for range tt.attempts {
+				_, postErr = service.CreatePost(ctx, post.CreateRequest{AuthorID: tt.authorID, Title: "Release", Content: "Out now"})
+				_, replyErr = service.Reply(ctx, comment.CreateRequest{PostID: release.ID, AuthorID: tt.authorID, Title: "Re: Release", Content: "Great"})
+			}
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, postErr, tt.wantErr)
+				assert.ErrorIs(t, replyErr, tt.wantErr)
+			} else {
+				assert.NoError(t, postErr)
+				assert.NoError(t, replyErr)
+			}
+			assert.Equal(t, tt.wantPosts, w.posts)
+			assert.Equal(t, tt.wantComments, w.comments)
+		})
+	}
+}
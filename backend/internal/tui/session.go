@@ -0,0 +1,355 @@
+package tui
+
+import (
+	"backend/internal/board"
+	"backend/internal/comment"
+	errorPkg "backend/internal/error"
+	"backend/internal/markdown"
+	"backend/internal/post"
+	"backend/internal/problem"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"golang.org/x/term"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// SGR sequences of the screens, content is styled by markdown.RenderANSI
+const (
+	reset     = "\x1b[0m"
+	bold      = "\x1b[1m"
+	dim       = "\x1b[2m"
+	highlight = "\x1b[1;36m"
+	clear     = "\x1b[2J\x1b[H"
+)
+
+const timeFormat = "2006-01-02 15:04"
+
+// screen shows one view of the forum and handles one line of input, it returns the screen to show next or nil to
+// end the session
+type screen func() (screen, error)
+
+// session is the UI of one SSH session, errors returned by screens end it
+type session struct {
+	server   *Server
+	ctx      context.Context
+	userID   uuid.UUID
+	terminal *term.Terminal
+	idle     *time.Timer
+
+	// notice is shown above the prompt of the next screen, such as the result of the last action
+	notice string
+}
+
+func (s *session) run() error {
+	next := s.boards
+	for next != nil {
+		var err error
+		next, err = next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	s.printf("Bye\n")
+	return nil
+}
+
+func (s *session) boards() (screen, error) {
+	boards, err := s.server.store.Boards(s.ctx)
+	s.printf(clear + highlight + "Boards" + reset + "\n\n")
+	if err != nil {
+		s.fail(err)
+	}
+	for i, b := range boards {
+		s.printf("%3d  %s%s%s  %s%s%s\n", i+1, bold, plain(b.Name), reset, dim, plain(b.Description.String), reset)
+	}
+
+	input, err := s.prompt("number open board · q quit", "boards> ")
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case input == "q":
+		return nil, nil
+	case input == "":
+		return s.boards, nil
+	}
+	if i, ok := choose(input, len(boards)); ok {
+		return s.posts(boards[i]), nil
+	}
+	s.notice = "Unknown command " + input
+	return s.boards, nil
+}
+
+func (s *session) posts(b board.Board) screen {
+	var current screen
+	current = func() (screen, error) {
+		summaries, err := s.server.store.Posts(s.ctx, s.userID, b.ID)
+		s.printf(clear+highlight+"%s"+reset+"  %s%s%s\n\n", plain(b.Name), dim, plain(b.Description.String), reset)
+		if err != nil {
+			s.fail(err)
+		}
+		for i, summary := range summaries {
+			title := bold + plain(summary.Post.Title.String) + reset
+			if summary.Collapsed {
+				title = dim + "(post of a muted or blocked user)" + reset
+			}
+			s.printf("%3d  %s%s  %s%s · %s%s\n", i+1, flags(summary.Post), title, dim, plain(summary.Author), summary.Post.CreateAt.Time.Format(timeFormat), reset)
+		}
+		if len(summaries) == 0 && err == nil {
+			s.printf("%sNo posts yet%s\n", dim, reset)
+		}
+
+		input, err := s.prompt("number open thread · n new post · b back · q quit", plain(b.Slug)+"> ")
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case input == "q":
+			return nil, nil
+		case input == "b":
+			return s.boards, nil
+		case input == "":
+			return current, nil
+		case input == "n":
+			return current, s.newPost(b)
+		}
+		if i, ok := choose(input, len(summaries)); ok {
+			return s.thread(b, summaries[i].Post.ID, current), nil
+		}
+		s.notice = "Unknown command " + input
+		return current, nil
+	}
+	return current
+}
+
+func (s *session) thread(b board.Board, postID uuid.UUID, back screen) screen {
+	var current screen
+	current = func() (screen, error) {
+		thread, err := s.server.store.Thread(s.ctx, s.userID, postID)
+		if err != nil {
+			s.fail(err)
+			return back, nil
+		}
+
+		p := thread.Post
+		s.printf(clear+"%s"+bold+"%s"+reset+"\n%s%s · %s · %s%s\n\n", flags(p), plain(p.Title.String), dim, plain(thread.Author), p.CreateAt.Time.Format(timeFormat), plain(b.Name), reset)
+		s.printf("%s\n\n", markdown.RenderANSI(p.Content.String))
+		s.printf(highlight+"%d comments"+reset+"\n", len(thread.Replies))
+		for i, reply := range thread.Replies {
+			indent := strings.Repeat("    ", reply.Depth)
+			s.printf("\n%s%s[%d]%s %s%s · %s%s\n", indent, bold, i+1, reset, dim, plain(reply.Author), reply.Comment.CreatedAt.Time.Format(timeFormat), reset)
+			content := markdown.RenderANSI(reply.Comment.Content.String)
+			if reply.Collapsed {
+				content = dim + "(comment of a muted or blocked user)" + reset
+			}
+			s.printf("%s\n", indentLines(content, indent))
+		}
+
+		input, err := s.prompt("r reply · r N reply to comment N · b back · q quit", "thread> ")
+		if err != nil {
+			return nil, err
+		}
+		command, arg, _ := strings.Cut(input, " ")
+		switch {
+		case input == "q":
+			return nil, nil
+		case input == "b":
+			return back, nil
+		case input == "":
+			return current, nil
+		case command == "r" && arg == "":
+			return current, s.reply(p, nil)
+		case command == "r":
+			i, ok := choose(strings.TrimSpace(arg), len(thread.Replies))
+			if !ok {
+				s.notice = "No comment " + arg
+				return current, nil
+			}
+			return current, s.reply(p, &thread.Replies[i].Comment)
+		}
+		s.notice = "Unknown command " + input
+		return current, nil
+	}
+	return current
+}
+
+func (s *session) newPost(b board.Board) error {
+	title, content, ok, err := s.compose("")
+	if err != nil || !ok {
+		return err
+	}
+
+	created, err := s.server.store.CreatePost(s.ctx, post.CreateRequest{AuthorID: s.userID, BoardID: &b.ID, Title: title, Content: content})
+	switch {
+	case err != nil:
+		s.fail(err)
+	case created.HiddenAt.Valid:
+		s.notice = "Your post is held for review by a moderator"
+	default:
+		s.notice = "Posted " + plain(title)
+	}
+	return nil
+}
+
+func (s *session) reply(p post.Post, parent *comment.Comment) error {
+	title := p.Title.String
+	request := comment.CreateRequest{PostID: p.ID, AuthorID: s.userID}
+	if parent != nil {
+		title = parent.Title.String
+		request.ParentID = &parent.ID
+	}
+
+	title, content, ok, err := s.compose("Re: " + strings.TrimPrefix(title, "Re: "))
+	if err != nil || !ok {
+		return err
+	}
+	request.Title = title
+	request.Content = content
+
+	created, err := s.server.store.Reply(s.ctx, request)
+	switch {
+	case err != nil:
+		s.fail(err)
+	case created.HiddenAt.Valid:
+		s.notice = "Your reply is held for review by a moderator"
+	default:
+		s.notice = "Replied"
+	}
+	return nil
+}
+
+// compose asks for a title, defaulting to defaultTitle, and markdown content, ok is false if the user cancelled
+func (s *session) compose(defaultTitle string) (title, content string, ok bool, err error) {
+	prompt := "Title: "
+	if defaultTitle != "" {
+		prompt = "Title [" + plain(defaultTitle) + "]: "
+	}
+	title, err = s.readLine(prompt)
+	if err != nil {
+		return "", "", false, err
+	}
+	title = strings.TrimSpace(title)
+	if title == "" {
+		title = defaultTitle
+	}
+	if title == "" {
+		s.notice = "Cancelled, a title is required"
+		return "", "", false, nil
+	}
+
+	s.printf("%sWrite markdown, end with a line containing only a dot%s\n", dim, reset)
+	var lines []string
+	for {
+		line, err := s.readLine("| ")
+		if err != nil {
+			return "", "", false, err
+		}
+		if line == "." {
+			break
+		}
+		lines = append(lines, line)
+	}
+	content = strings.TrimSpace(strings.Join(lines, "\n"))
+	if content == "" {
+		s.notice = "Cancelled, the text is empty"
+		return "", "", false, nil
+	}
+
+	answer, err := s.readLine("Send? [y/N] ")
+	if err != nil {
+		return "", "", false, err
+	}
+	if !strings.EqualFold(strings.TrimSpace(answer), "y") {
+		s.notice = "Cancelled"
+		return "", "", false, nil
+	}
+	return title, content, true, nil
+}
+
+// prompt shows the notice and the help line and reads a command
+func (s *session) prompt(help, prompt string) (string, error) {
+	s.printf("\n")
+	if s.notice != "" {
+		s.printf("%s%s%s\n", highlight, s.notice, reset)
+		s.notice = ""
+	}
+	s.printf("%s%s%s\n", dim, help, reset)
+
+	line, err := s.readLine(prompt)
+	return strings.TrimSpace(line), err
+}
+
+func (s *session) readLine(prompt string) (string, error) {
+	s.terminal.SetPrompt(prompt)
+	line, err := s.terminal.ReadLine()
+	s.idle.Reset(idleTimeout)
+	return line, err
+}
+
+func (s *session) printf(format string, args ...any) {
+	_, _ = fmt.Fprintf(s.terminal, format, args...)
+}
+
+// fail shows an error of the services as notice, like the detail of a problem response
+func (s *session) fail(err error) {
+	if errors.Is(err, errorPkg.ErrRateLimited) {
+		// the detail of the problem refers to a header of the web API
+		s.notice = "Too many posts and replies, try again later"
+		return
+	}
+	s.notice = problem.FromError(err).Detail
+}
+
+// choose parses a one-based list position
+func choose(input string, count int) (int, bool) {
+	n, err := strconv.Atoi(input)
+	if err != nil || n < 1 || n > count {
+		return 0, false
+	}
+	return n - 1, true
+}
+
+// flags marks pinned, locked, archived and unpublished posts
+func flags(p post.Post) string {
+	var b strings.Builder
+	if p.PinnedAt.Valid {
+		b.WriteString("[pinned] ")
+	}
+	if p.LockedAt.Valid {
+		b.WriteString("[locked] ")
+	}
+	if p.ArchivedAt.Valid {
+		b.WriteString("[archived] ")
+	}
+	if !p.Published() {
+		b.WriteString("[" + p.Status + "] ")
+	}
+	return b.String()
+}
+
+func indentLines(s, indent string) string {
+	if indent == "" {
+		return s
+	}
+	return indent + strings.ReplaceAll(s, "\n", "\n"+indent)
+}
+
+// plain removes control characters from names and titles so they cannot send escape sequences to the terminal
+func plain(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -2,34 +2,72 @@ package user
 
 import (
 	"backend/internal"
+	errorPkg "backend/internal/error"
 	"backend/internal/jwt"
+	"backend/internal/problem"
 	"context"
 	"fmt"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"net/http"
+	"time"
 )
 
+type MeResponse struct {
+	ID                  string `json:"id"`
+	Name                string `json:"name"`
+	Role                string `json:"role"`
+	UnreadNotifications int64  `json:"unread_notifications"`
+}
+
+type RoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=USER MODERATOR ADMIN"`
+}
+
+// SuspendRequest suspends a user until the given time, without until the user is banned permanently
+type SuspendRequest struct {
+	Until  *time.Time `json:"until,omitempty" validate:"omitempty,gt"`
+	Reason string     `json:"reason" validate:"required,max=1000"`
+}
+
+type Response struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	Role           string `json:"role"`
+	Suspended      bool   `json:"suspended"`
+	SuspendedUntil string `json:"suspended_until,omitempty"`
+	BanReason      string `json:"ban_reason,omitempty"`
+}
+
 type Store interface {
 	Create(ctx context.Context, name, password string) (User, error)
 	GetByID(ctx context.Context, id uuid.UUID) (User, error)
 	UpdateName(ctx context.Context, id uuid.UUID, name string) (User, error)
 	UpdatePassword(ctx context.Context, id uuid.UUID, password string) error
+	UpdateRole(ctx context.Context, id uuid.UUID, role string) (User, error)
+	Suspend(ctx context.Context, actorRole string, id uuid.UUID, until *time.Time, reason string) (User, error)
+	Unsuspend(ctx context.Context, id uuid.UUID) (User, error)
 	Delete(ctx context.Context, id uuid.UUID) error
 }
 
+type NotificationCounter interface {
+	CountUnread(ctx context.Context, userID uuid.UUID) (int64, error)
+}
+
 type Handler struct {
-	Validator *validator.Validate
-	Logger    *zap.Logger
-	Store     Store
+	Validator     *validator.Validate
+	Logger        *zap.Logger
+	Store         Store
+	Notifications NotificationCounter
 }
 
-func NewHandler(validator *validator.Validate, logger *zap.Logger, store Store) *Handler {
+func NewHandler(validator *validator.Validate, logger *zap.Logger, store Store, notifications NotificationCounter) *Handler {
 	return &Handler{
-		Validator: validator,
-		Logger:    logger,
-		Store:     store,
+		Validator:     validator,
+		Logger:        logger,
+		Store:         store,
+		Notifications: notifications,
 	}
 }
 
@@ -40,3 +78,130 @@ func (h *Handler) CreateHandler(w http.ResponseWriter, r *http.Request) {
 	user := r.Context().Value(internal.UserContextKey).(jwt.User)
 	fmt.Printf("Is you %s !", user.Username)
 }
+
+func (h *Handler) MeHandler(w http.ResponseWriter, r *http.Request) {
+	logger := internal.LoggerWithContext(r.Context(), h.Logger)
+
+	u, err := jwt.GetUserFromContext(r.Context())
+	if err != nil {
+		problem.WriteError(r.Context(), w, err, logger)
+		return
+	}
+
+	id, err := internal.ParseUUID(u.ID)
+	if err != nil {
+		problem.WriteError(r.Context(), w, err, logger)
+		return
+	}
+
+	userEntity, err := h.Store.GetByID(r.Context(), id)
+	if err != nil {
+		problem.WriteError(r.Context(), w, err, logger)
+		return
+	}
+
+	unread, err := h.Notifications.CountUnread(r.Context(), id)
+	if err != nil {
+		problem.WriteError(r.Context(), w, err, logger)
+		return
+	}
+
+	internal.WriteJSONResponse(w, http.StatusOK, MeResponse{
+		ID:                  userEntity.ID.String(),
+		Name:                userEntity.Name,
+		Role:                userEntity.Role,
+		UnreadNotifications: unread,
+	})
+}
+
+// UpdateRoleHandler changes the role of a user, the user has to log in again for the new role to apply
+func (h *Handler) UpdateRoleHandler(w http.ResponseWriter, r *http.Request) {
+	logger := internal.LoggerWithContext(r.Context(), h.Logger)
+
+	id, err := internal.ParseUUID(r.PathValue("id"))
+	if err != nil {
+		problem.WriteError(r.Context(), w, fmt.Errorf("%w: %v", errorPkg.ErrInvalidUUID, err), logger)
+		return
+	}
+
+	var request RoleRequest
+	err = internal.ParseAndValidateRequestBody(r.Context(), h.Validator, r, &request)
+	if err != nil {
+		problem.WriteError(r.Context(), w, err, logger)
+		return
+	}
+
+	userEntity, err := h.Store.UpdateRole(r.Context(), id, request.Role)
+	if err != nil {
+		problem.WriteError(r.Context(), w, err, logger)
+		return
+	}
+
+	internal.WriteJSONResponse(w, http.StatusOK, GenerateResponse(userEntity))
+}
+
+// SuspendHandler suspends or bans a user, tokens issued before are rejected from then on
+func (h *Handler) SuspendHandler(w http.ResponseWriter, r *http.Request) {
+	logger := internal.LoggerWithContext(r.Context(), h.Logger)
+
+	u, err := jwt.GetUserFromContext(r.Context())
+	if err != nil {
+		problem.WriteError(r.Context(), w, err, logger)
+		return
+	}
+
+	id, err := internal.ParseUUID(r.PathValue("id"))
+	if err != nil {
+		problem.WriteError(r.Context(), w, fmt.Errorf("%w: %v", errorPkg.ErrInvalidUUID, err), logger)
+		return
+	}
+
+	var request SuspendRequest
+	err = internal.ParseAndValidateRequestBody(r.Context(), h.Validator, r, &request)
+	if err != nil {
+		problem.WriteError(r.Context(), w, err, logger)
+		return
+	}
+
+	userEntity, err := h.Store.Suspend(r.Context(), u.Role, id, request.Until, request.Reason)
+	if err != nil {
+		problem.WriteError(r.Context(), w, err, logger)
+		return
+	}
+
+	internal.WriteJSONResponse(w, http.StatusOK, GenerateResponse(userEntity))
+}
+
+func (h *Handler) UnsuspendHandler(w http.ResponseWriter, r *http.Request) {
+	logger := internal.LoggerWithContext(r.Context(), h.Logger)
+
+	id, err := internal.ParseUUID(r.PathValue("id"))
+	if err != nil {
+		problem.WriteError(r.Context(), w, fmt.Errorf("%w: %v", errorPkg.ErrInvalidUUID, err), logger)
+		return
+	}
+
+	userEntity, err := h.Store.Unsuspend(r.Context(), id)
+	if err != nil {
+		problem.WriteError(r.Context(), w, err, logger)
+		return
+	}
+
+	internal.WriteJSONResponse(w, http.StatusOK, GenerateResponse(userEntity))
+}
+
+func GenerateResponse(u User) Response {
+	response := Response{
+		ID:        u.ID.String(),
+		Name:      u.Name,
+		Role:      u.Role,
+		Suspended: u.IsSuspended(time.Now()),
+	}
+	if response.Suspended {
+		response.BanReason = u.BanReason.String
+		if u.SuspendedUntil.Valid {
+			response.SuspendedUntil = u.SuspendedUntil.Time.Format(time.RFC3339)
+		}
+	}
+	return response
+}
@@ -13,5 +13,14 @@ UPDATE users SET name = $2, password = $3 WHERE id = $1 RETURNING *;
 -- name: UpdatePassword :execrows
 UPDATE users SET password = $2 WHERE id = $1;
 
+-- name: UpdateRole :one
+UPDATE users SET role = $2 WHERE id = $1 RETURNING *;
+
+-- name: Suspend :one
+UPDATE users SET suspended_at = now(), suspended_until = $2, ban_reason = $3 WHERE id = $1 RETURNING *;
+
+-- name: Unsuspend :one
+UPDATE users SET suspended_at = NULL, suspended_until = NULL, ban_reason = NULL WHERE id = $1 RETURNING *;
+
 -- name: Delete :execrows
 DELETE FROM users WHERE id = $1;
\ No newline at end of file
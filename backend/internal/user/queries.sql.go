@@ -9,10 +9,11 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const create = `-- name: Create :one
-INSERT INTO users (name, password) VALUES ($1, $2) RETURNING id, name, password
+INSERT INTO users (name, password) VALUES ($1, $2) RETURNING id, name, password, role, suspended_at, suspended_until, ban_reason, created_at
 `
 
 type CreateParams struct {
@@ -23,7 +24,16 @@ type CreateParams struct {
 func (q *Queries) Create(ctx context.Context, arg CreateParams) (User, error) {
 	row := q.db.QueryRow(ctx, create, arg.Name, arg.Password)
 	var i User
-	err := row.Scan(&i.ID, &i.Name, &i.Password)
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Password,
+		&i.Role,
+		&i.SuspendedAt,
+		&i.SuspendedUntil,
+		&i.BanReason,
+		&i.CreatedAt,
+	)
 	return i, err
 }
 
@@ -40,29 +50,93 @@ func (q *Queries) Delete(ctx context.Context, id uuid.UUID) (int64, error) {
 }
 
 const getByID = `-- name: GetByID :one
-SELECT id, name, password FROM users WHERE id = $1
+SELECT id, name, password, role, suspended_at, suspended_until, ban_reason, created_at FROM users WHERE id = $1
 `
 
 func (q *Queries) GetByID(ctx context.Context, id uuid.UUID) (User, error) {
 	row := q.db.QueryRow(ctx, getByID, id)
 	var i User
-	err := row.Scan(&i.ID, &i.Name, &i.Password)
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Password,
+		&i.Role,
+		&i.SuspendedAt,
+		&i.SuspendedUntil,
+		&i.BanReason,
+		&i.CreatedAt,
+	)
 	return i, err
 }
 
 const getByName = `-- name: GetByName :one
-SELECT id, name, password FROM users WHERE name = $1
+SELECT id, name, password, role, suspended_at, suspended_until, ban_reason, created_at FROM users WHERE name = $1
 `
 
 func (q *Queries) GetByName(ctx context.Context, name string) (User, error) {
 	row := q.db.QueryRow(ctx, getByName, name)
 	var i User
-	err := row.Scan(&i.ID, &i.Name, &i.Password)
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Password,
+		&i.Role,
+		&i.SuspendedAt,
+		&i.SuspendedUntil,
+		&i.BanReason,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const suspend = `-- name: Suspend :one
+UPDATE users SET suspended_at = now(), suspended_until = $2, ban_reason = $3 WHERE id = $1 RETURNING id, name, password, role, suspended_at, suspended_until, ban_reason, created_at
+`
+
+type SuspendParams struct {
+	ID             uuid.UUID
+	SuspendedUntil pgtype.Timestamptz
+	BanReason      pgtype.Text
+}
+
+func (q *Queries) Suspend(ctx context.Context, arg SuspendParams) (User, error) {
+	row := q.db.QueryRow(ctx, suspend, arg.ID, arg.SuspendedUntil, arg.BanReason)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Password,
+		&i.Role,
+		&i.SuspendedAt,
+		&i.SuspendedUntil,
+		&i.BanReason,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const unsuspend = `-- name: Unsuspend :one
+UPDATE users SET suspended_at = NULL, suspended_until = NULL, ban_reason = NULL WHERE id = $1 RETURNING id, name, password, role, suspended_at, suspended_until, ban_reason, created_at
+`
+
+func (q *Queries) Unsuspend(ctx context.Context, id uuid.UUID) (User, error) {
+	row := q.db.QueryRow(ctx, unsuspend, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Password,
+		&i.Role,
+		&i.SuspendedAt,
+		&i.SuspendedUntil,
+		&i.BanReason,
+		&i.CreatedAt,
+	)
 	return i, err
 }
 
 const updateName = `-- name: UpdateName :one
-UPDATE users SET name = $2, password = $3 WHERE id = $1 RETURNING id, name, password
+UPDATE users SET name = $2, password = $3 WHERE id = $1 RETURNING id, name, password, role, suspended_at, suspended_until, ban_reason, created_at
 `
 
 type UpdateNameParams struct {
@@ -74,7 +148,16 @@ type UpdateNameParams struct {
 func (q *Queries) UpdateName(ctx context.Context, arg UpdateNameParams) (User, error) {
 	row := q.db.QueryRow(ctx, updateName, arg.ID, arg.Name, arg.Password)
 	var i User
-	err := row.Scan(&i.ID, &i.Name, &i.Password)
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Password,
+		&i.Role,
+		&i.SuspendedAt,
+		&i.SuspendedUntil,
+		&i.BanReason,
+		&i.CreatedAt,
+	)
 	return i, err
 }
 
@@ -94,3 +177,28 @@ func (q *Queries) UpdatePassword(ctx context.Context, arg UpdatePasswordParams)
 	}
 	return result.RowsAffected(), nil
 }
+
+const updateRole = `-- name: UpdateRole :one
+UPDATE users SET role = $2 WHERE id = $1 RETURNING id, name, password, role, suspended_at, suspended_until, ban_reason, created_at
+`
+
+type UpdateRoleParams struct {
+	ID   uuid.UUID
+	Role string
+}
+
+func (q *Queries) UpdateRole(ctx context.Context, arg UpdateRoleParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateRole, arg.ID, arg.Role)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Password,
+		&i.Role,
+		&i.SuspendedAt,
+		&i.SuspendedUntil,
+		&i.BanReason,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -4,5 +4,10 @@ CREATE TABLE IF NOT EXISTS users
 (
     id       UUID PRIMARY KEY DEFAULT gen_random_uuid(),
     name     VARCHAR(255) UNIQUE NOT NULL,
-    password VARCHAR(255) NOT NULL
+    password VARCHAR(255) NOT NULL,
+    role     VARCHAR(32) NOT NULL DEFAULT 'USER' CHECK (role IN ('USER', 'MODERATOR', 'ADMIN')),
+    suspended_at    TIMESTAMPTZ,
+    suspended_until TIMESTAMPTZ,
+    ban_reason      TEXT,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now()
 );
\ No newline at end of file
@@ -2,30 +2,68 @@ package user
 
 import (
 	"backend/internal"
+	"backend/internal/audit"
 	"backend/internal/database"
+	errorPkg "backend/internal/error"
+	"backend/internal/jwt"
 	"context"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
+	"time"
 )
 
-// HasRole is a placeholder for the actual role checking logic, because the role storage is not in project scope.
+// HasRole reports whether the stored role of the user is the role or a more privileged one
 func (u User) HasRole(role string) bool {
-	return true
+	return jwt.RoleIncludes(u.Role, role)
+}
+
+// IsSuspended reports whether the user is suspended at the given time, a suspension without end is a permanent ban
+func (u User) IsSuspended(at time.Time) bool {
+	if !u.SuspendedAt.Valid {
+		return false
+	}
+	return !u.SuspendedUntil.Valid || u.SuspendedUntil.Time.After(at)
+}
+
+// SuspensionError describes the suspension of the user, only meaningful if the user is suspended
+func (u User) SuspensionError() error {
+	var until *time.Time
+	if u.SuspendedUntil.Valid {
+		until = &u.SuspendedUntil.Time
+	}
+	return errorPkg.NewSuspendedError(until, u.BanReason.String)
+}
+
+// Actions recorded in the audit trail
+const (
+	ActionPasswordChange = "user.password_change"
+	ActionRoleChange     = "user.role_change"
+	ActionSuspend        = "user.suspend"
+	ActionUnsuspend      = "user.unsuspend"
+	ActionDelete         = "user.delete"
+)
+
+// Auditor keeps the trail of changes to accounts, see audit.Service
+type Auditor interface {
+	Record(ctx context.Context, entry audit.Entry) error
 }
 
 type Service struct {
-	logger *zap.Logger
-	tracer trace.Tracer
-	query  *Queries
+	logger  *zap.Logger
+	tracer  trace.Tracer
+	query   *Queries
+	auditor Auditor
 }
 
-func NewService(logger *zap.Logger, db DBTX) *Service {
+func NewService(logger *zap.Logger, db DBTX, auditor Auditor) *Service {
 	return &Service{
-		logger: logger,
-		tracer: otel.Tracer("user/service"),
-		query:  New(db),
+		logger:  logger,
+		tracer:  otel.Tracer("user/service"),
+		query:   New(db),
+		auditor: auditor,
 	}
 }
 
@@ -113,10 +151,117 @@ func (s *Service) UpdatePassword(ctx context.Context, id uuid.UUID, password str
 	}
 
 	logger.Debug("Updated user password", zap.String("id", id.String()), zap.Int64("affected_rows", count))
+	if count > 0 {
+		s.record(traceCtx, ActionPasswordChange, id, "")
+	}
 
 	return nil
 }
 
+// UpdateRole changes the role of the user. It applies to tokens issued before as well, the role is read again on every
+// request, see CurrentRole.
+func (s *Service) UpdateRole(ctx context.Context, id uuid.UUID, role string) (User, error) {
+	traceCtx, span := s.tracer.Start(ctx, "UpdateRole")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	user, err := s.query.UpdateRole(traceCtx, UpdateRoleParams{
+		ID:   id,
+		Role: role,
+	})
+	if err != nil {
+		err = database.WrapDBErrorWithKeyValue(err, "users", "id", id.String(), logger, "update user role")
+		span.RecordError(err)
+		return User{}, err
+	}
+
+	logger.Info("Updated user role", zap.String("id", id.String()), zap.String("role", role))
+	s.record(traceCtx, ActionRoleChange, id, role)
+
+	return user, nil
+}
+
+// Suspend suspends the user until the given time, a nil until bans the user permanently. Users can only be suspended
+// by someone with a more privileged role than their own.
+func (s *Service) Suspend(ctx context.Context, actorRole string, id uuid.UUID, until *time.Time, reason string) (User, error) {
+	traceCtx, span := s.tracer.Start(ctx, "Suspend")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	target, err := s.GetByID(traceCtx, id)
+	if err != nil {
+		span.RecordError(err)
+		return User{}, err
+	}
+	if target.HasRole(actorRole) {
+		err = errorPkg.ErrForbidden
+		span.RecordError(err)
+		return User{}, err
+	}
+
+	params := SuspendParams{
+		ID:        id,
+		BanReason: pgtype.Text{String: reason, Valid: true},
+	}
+	if until != nil {
+		params.SuspendedUntil = pgtype.Timestamptz{Time: *until, Valid: true}
+	}
+
+	user, err := s.query.Suspend(traceCtx, params)
+	if err != nil {
+		err = database.WrapDBErrorWithKeyValue(err, "users", "id", id.String(), logger, "suspend user")
+		span.RecordError(err)
+		return User{}, err
+	}
+
+	if until == nil {
+		logger.Info("Banned user", zap.String("id", id.String()), zap.String("reason", reason))
+		s.record(traceCtx, ActionSuspend, id, "permanent: "+reason)
+	} else {
+		logger.Info("Suspended user", zap.String("id", id.String()), zap.Time("until", *until), zap.String("reason", reason))
+		s.record(traceCtx, ActionSuspend, id, "until "+until.Format(time.RFC3339)+": "+reason)
+	}
+
+	return user, nil
+}
+
+func (s *Service) Unsuspend(ctx context.Context, id uuid.UUID) (User, error) {
+	traceCtx, span := s.tracer.Start(ctx, "Unsuspend")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	user, err := s.query.Unsuspend(traceCtx, id)
+	if err != nil {
+		err = database.WrapDBErrorWithKeyValue(err, "users", "id", id.String(), logger, "unsuspend user")
+		span.RecordError(err)
+		return User{}, err
+	}
+
+	logger.Info("Unsuspended user", zap.String("id", id.String()))
+	s.record(traceCtx, ActionUnsuspend, id, "")
+
+	return user, nil
+}
+
+// CurrentRole returns the role of the user, or the suspension as an error if the user is currently suspended and the
+// suspension started after the given time, see jwt.UserChecker
+func (s *Service) CurrentRole(ctx context.Context, id uuid.UUID, since time.Time) (string, error) {
+	traceCtx, span := s.tracer.Start(ctx, "CurrentRole")
+	defer span.End()
+
+	user, err := s.GetByID(traceCtx, id)
+	if err != nil {
+		span.RecordError(err)
+		return "", err
+	}
+
+	if user.IsSuspended(time.Now()) && !user.SuspendedAt.Time.Before(since) {
+		return "", user.SuspensionError()
+	}
+
+	return user.Role, nil
+}
+
 func (s *Service) Delete(ctx context.Context, id uuid.UUID) error {
 	traceCtx, span := s.tracer.Start(ctx, "Delete")
 	defer span.End()
@@ -130,6 +275,17 @@ func (s *Service) Delete(ctx context.Context, id uuid.UUID) error {
 	}
 
 	logger.Debug("Deleted user", zap.String("id", id.String()), zap.Int64("affected_rows", count))
+	if count > 0 {
+		s.record(traceCtx, ActionDelete, id, "")
+	}
 
 	return nil
 }
+
+// record adds an action on the user to the audit trail, a failure is logged but does not undo the action
+func (s *Service) record(ctx context.Context, action string, id uuid.UUID, details string) {
+	err := s.auditor.Record(ctx, audit.Entry{Action: action, TargetType: audit.TargetUser, TargetID: id, Details: details})
+	if err != nil {
+		internal.LoggerWithContext(ctx, s.logger).Error("Failed to record audit entry", zap.String("id", id.String()), zap.String("action", action), zap.Error(err))
+	}
+}
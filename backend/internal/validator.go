@@ -1,9 +1,19 @@
 package internal
 
-import "github.com/go-playground/validator/v10"
+import (
+	"github.com/go-playground/validator/v10"
+	"regexp"
+)
+
+// slugPattern matches lowercase URL path segments such as board slugs, e.g. "go-help"
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
 
 func NewValidator() *validator.Validate {
-	return validator.New()
+	v := validator.New()
+	_ = v.RegisterValidation("slug", func(fl validator.FieldLevel) bool {
+		return slugPattern.MatchString(fl.Field().String())
+	})
+	return v
 }
 
 func ValidateStruct(v *validator.Validate, s interface{}) error {
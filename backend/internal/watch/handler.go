@@ -0,0 +1,175 @@
+package watch
+
+import (
+	"backend/internal"
+	errorPkg "backend/internal/error"
+	"backend/internal/jwt"
+	"backend/internal/problem"
+	"context"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+type Request struct {
+	Level string `json:"level" validate:"required,oneof=all mentions muted"`
+}
+
+type Response struct {
+	ID        string `json:"id"`
+	PostID    string `json:"post_id,omitempty"`
+	BoardID   string `json:"board_id,omitempty"`
+	Level     string `json:"level"`
+	CreatedAt string `json:"created_at"`
+}
+
+//go:generate mockery --name=Store
+type Store interface {
+	WatchPost(ctx context.Context, userID, postID uuid.UUID, level string) (Watch, error)
+	WatchBoard(ctx context.Context, userID, boardID uuid.UUID, level string) (Watch, error)
+	UnwatchPost(ctx context.Context, userID, postID uuid.UUID) error
+	UnwatchBoard(ctx context.Context, userID, boardID uuid.UUID) error
+	GetByUser(ctx context.Context, userID uuid.UUID) ([]Watch, error)
+}
+
+type Handler struct {
+	validator *validator.Validate
+	logger    *zap.Logger
+	tracer    trace.Tracer
+	store     Store
+}
+
+func NewHandler(v *validator.Validate, logger *zap.Logger, store Store) *Handler {
+	return &Handler{
+		validator: v,
+		logger:    logger,
+		tracer:    otel.Tracer("watch/handler"),
+		store:     store,
+	}
+}
+
+// GetAllHandler lists the posts and boards the current user watches
+func (h *Handler) GetAllHandler(w http.ResponseWriter, r *http.Request) {
+	traceCtx, span := h.tracer.Start(r.Context(), "GetAllWatchEndpoint")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	userID, err := jwt.GetUserIDFromContext(traceCtx)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	watches, err := h.store.GetByUser(traceCtx, userID)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	response := make([]Response, len(watches))
+	for i, watch := range watches {
+		response[i] = GenerateResponse(watch)
+	}
+
+	internal.WriteJSONResponse(w, http.StatusOK, response)
+}
+
+// WatchPostHandler watches the post with the requested level, watching it again changes the level
+func (h *Handler) WatchPostHandler(w http.ResponseWriter, r *http.Request) {
+	h.watch(w, r, "WatchPostEndpoint", h.store.WatchPost)
+}
+
+// WatchBoardHandler watches every post of the board with the requested level, a watch on a single post of the
+// board takes precedence
+func (h *Handler) WatchBoardHandler(w http.ResponseWriter, r *http.Request) {
+	h.watch(w, r, "WatchBoardEndpoint", h.store.WatchBoard)
+}
+
+func (h *Handler) UnwatchPostHandler(w http.ResponseWriter, r *http.Request) {
+	h.unwatch(w, r, "UnwatchPostEndpoint", h.store.UnwatchPost)
+}
+
+func (h *Handler) UnwatchBoardHandler(w http.ResponseWriter, r *http.Request) {
+	h.unwatch(w, r, "UnwatchBoardEndpoint", h.store.UnwatchBoard)
+}
+
+func (h *Handler) watch(w http.ResponseWriter, r *http.Request, spanName string, watch func(ctx context.Context, userID, targetID uuid.UUID, level string) (Watch, error)) {
+	traceCtx, span := h.tracer.Start(r.Context(), spanName)
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	userID, err := jwt.GetUserIDFromContext(traceCtx)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	targetID, err := internal.ParseUUID(r.PathValue("id"))
+	if err != nil {
+		problem.WriteError(traceCtx, w, fmt.Errorf("%w: %v", errorPkg.ErrInvalidUUID, err), logger)
+		return
+	}
+
+	var request Request
+	err = internal.ParseAndValidateRequestBody(traceCtx, h.validator, r, &request)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	result, err := watch(traceCtx, userID, targetID, request.Level)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	logger.Info("Watched", zap.String("id", result.ID.String()), zap.String("level", result.Level))
+	internal.WriteJSONResponse(w, http.StatusOK, GenerateResponse(result))
+}
+
+func (h *Handler) unwatch(w http.ResponseWriter, r *http.Request, spanName string, unwatch func(ctx context.Context, userID, targetID uuid.UUID) error) {
+	traceCtx, span := h.tracer.Start(r.Context(), spanName)
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, h.logger)
+
+	userID, err := jwt.GetUserIDFromContext(traceCtx)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	targetID, err := internal.ParseUUID(r.PathValue("id"))
+	if err != nil {
+		problem.WriteError(traceCtx, w, fmt.Errorf("%w: %v", errorPkg.ErrInvalidUUID, err), logger)
+		return
+	}
+
+	err = unwatch(traceCtx, userID, targetID)
+	if err != nil {
+		problem.WriteError(traceCtx, w, err, logger)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func GenerateResponse(w Watch) Response {
+	response := Response{
+		ID:        w.ID.String(),
+		Level:     w.Level,
+		CreatedAt: w.CreatedAt.Time.Format(time.RFC3339),
+	}
+	if w.PostID.Valid {
+		response.PostID = uuid.UUID(w.PostID.Bytes).String()
+	}
+	if w.BoardID.Valid {
+		response.BoardID = uuid.UUID(w.BoardID.Bytes).String()
+	}
+
+	return response
+}
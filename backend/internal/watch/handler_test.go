@@ -0,0 +1,102 @@
+package watch_test
+
+import (
+	"backend/internal"
+	errorPkg "backend/internal/error"
+	"backend/internal/jwt"
+	"backend/internal/watch"
+	"backend/internal/watch/mocks"
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandler_WatchPostHandler(t *testing.T) {
+	user := jwt.User{
+		ID:       "81c1ecc1-66d7-4134-b5fe-d886a6f418a4",
+		Username: "test",
+		Role:     "USER",
+	}
+	postID := uuid.MustParse("1f0b3c8e-3b0a-4d8e-9f3e-9b1b0f6b7c11")
+
+	tests := []struct {
+		name       string
+		pathID     string
+		body       string
+		setupMock  func(m *mocks.Store)
+		wantStatus int
+		wantResult watch.Response
+	}{
+		{
+			name:   "Should watch post with level",
+			pathID: postID.String(),
+			body:   `{"level":"mentions"}`,
+			setupMock: func(m *mocks.Store) {
+				m.On("WatchPost", mock.Anything, uuid.MustParse(user.ID), postID, watch.LevelMentions).Return(watch.Watch{
+					ID:        uuid.MustParse("54a46af2-b454-4746-8ab0-3cf26085a50b"),
+					UserID:    uuid.MustParse(user.ID),
+					PostID:    pgtype.UUID{Bytes: postID, Valid: true},
+					Level:     watch.LevelMentions,
+					CreatedAt: pgtype.Timestamptz{Time: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)},
+				}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantResult: watch.Response{
+				ID:        "54a46af2-b454-4746-8ab0-3cf26085a50b",
+				PostID:    postID.String(),
+				Level:     watch.LevelMentions,
+				CreatedAt: "2000-01-01T00:00:00Z",
+			},
+		},
+		{
+			name:       "Should reject unknown level",
+			pathID:     postID.String(),
+			body:       `{"level":"sometimes"}`,
+			setupMock:  func(m *mocks.Store) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Should return not found for missing post",
+			pathID: postID.String(),
+			body:   `{"level":"all"}`,
+			setupMock: func(m *mocks.Store) {
+				m.On("WatchPost", mock.Anything, uuid.MustParse(user.ID), postID, watch.LevelAll).
+					Return(watch.Watch{}, errorPkg.NewNotFoundError("posts", "id", postID.String(), ""))
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mocks.NewStore(t)
+			tt.setupMock(m)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/api/post/"+tt.pathID+"/watch", strings.NewReader(tt.body))
+			r.SetPathValue("id", tt.pathID)
+			r = r.WithContext(context.WithValue(r.Context(), internal.UserContextKey, user))
+
+			h := watch.NewHandler(internal.NewValidator(), zap.NewNop(), m)
+			h.WatchPostHandler(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusOK {
+				jsonWant, err := json.Marshal(tt.wantResult)
+				if err != nil {
+					t.Fatalf("failed to marshal expected response: %v", err)
+				}
+				assert.Equal(t, string(jsonWant), w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	uuid "github.com/google/uuid"
+	mock "github.com/stretchr/testify/mock"
+
+	watch "backend/internal/watch"
+)
+
+// Store is an autogenerated mock type for the Store type
+type Store struct {
+	mock.Mock
+}
+
+// GetByUser provides a mock function with given fields: ctx, userID
+func (_m *Store) GetByUser(ctx context.Context, userID uuid.UUID) ([]watch.Watch, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByUser")
+	}
+
+	var r0 []watch.Watch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]watch.Watch, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []watch.Watch); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]watch.Watch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UnwatchBoard provides a mock function with given fields: ctx, userID, boardID
+func (_m *Store) UnwatchBoard(ctx context.Context, userID uuid.UUID, boardID uuid.UUID) error {
+	ret := _m.Called(ctx, userID, boardID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnwatchBoard")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(ctx, userID, boardID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UnwatchPost provides a mock function with given fields: ctx, userID, postID
+func (_m *Store) UnwatchPost(ctx context.Context, userID uuid.UUID, postID uuid.UUID) error {
+	ret := _m.Called(ctx, userID, postID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnwatchPost")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(ctx, userID, postID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WatchBoard provides a mock function with given fields: ctx, userID, boardID, level
+func (_m *Store) WatchBoard(ctx context.Context, userID uuid.UUID, boardID uuid.UUID, level string) (watch.Watch, error) {
+	ret := _m.Called(ctx, userID, boardID, level)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WatchBoard")
+	}
+
+	var r0 watch.Watch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, string) (watch.Watch, error)); ok {
+		return rf(ctx, userID, boardID, level)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, string) watch.Watch); ok {
+		r0 = rf(ctx, userID, boardID, level)
+	} else {
+		r0 = ret.Get(0).(watch.Watch)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID, string) error); ok {
+		r1 = rf(ctx, userID, boardID, level)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WatchPost provides a mock function with given fields: ctx, userID, postID, level
+func (_m *Store) WatchPost(ctx context.Context, userID uuid.UUID, postID uuid.UUID, level string) (watch.Watch, error) {
+	ret := _m.Called(ctx, userID, postID, level)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WatchPost")
+	}
+
+	var r0 watch.Watch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, string) (watch.Watch, error)); ok {
+		return rf(ctx, userID, postID, level)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, string) watch.Watch); ok {
+		r0 = rf(ctx, userID, postID, level)
+	} else {
+		r0 = ret.Get(0).(watch.Watch)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID, string) error); ok {
+		r1 = rf(ctx, userID, postID, level)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewStore creates a new instance of Store. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Store {
+	mock := &Store{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,40 @@
+-- name: UpsertPost :one
+INSERT INTO watches (user_id, post_id, level) VALUES (@user_id, @post_id::uuid, @level)
+ON CONFLICT (user_id, post_id) DO UPDATE SET level = EXCLUDED.level
+RETURNING *;
+
+-- name: UpsertBoard :one
+INSERT INTO watches (user_id, board_id, level) VALUES (@user_id, @board_id::uuid, @level)
+ON CONFLICT (user_id, board_id) DO UPDATE SET level = EXCLUDED.level
+RETURNING *;
+
+-- name: CreatePostIfAbsent :exec
+INSERT INTO watches (user_id, post_id, level) VALUES (@user_id, @post_id::uuid, @level)
+ON CONFLICT (user_id, post_id) DO NOTHING;
+
+-- name: DeletePost :execrows
+DELETE FROM watches WHERE user_id = @user_id AND post_id = @post_id::uuid;
+
+-- name: DeleteBoard :execrows
+DELETE FROM watches WHERE user_id = @user_id AND board_id = @board_id::uuid;
+
+-- name: FindByUser :many
+SELECT * FROM watches WHERE user_id = $1 ORDER BY created_at DESC;
+
+-- name: FindPostWatchers :many
+-- Effective level of every user watching the post, a watch on the post itself overrides a watch on its board
+SELECT DISTINCT ON (w.user_id) w.user_id, w.level
+FROM watches w
+WHERE w.post_id = @post_id::uuid
+   OR w.board_id = (SELECT p.board_id FROM posts p WHERE p.id = @post_id::uuid)
+ORDER BY w.user_id, w.post_id IS NULL;
+
+
+-- name: FindLevel :one
+-- Effective level of one user for the post, see FindPostWatchers
+SELECT w.level
+FROM watches w
+WHERE w.user_id = @user_id
+  AND (w.post_id = @post_id::uuid OR w.board_id = (SELECT p.board_id FROM posts p WHERE p.id = @post_id::uuid))
+ORDER BY w.post_id IS NULL
+LIMIT 1;
@@ -0,0 +1,201 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.28.0
+// source: queries.sql
+
+package watch
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const createPostIfAbsent = `-- name: CreatePostIfAbsent :exec
+INSERT INTO watches (user_id, post_id, level) VALUES ($1, $2::uuid, $3)
+ON CONFLICT (user_id, post_id) DO NOTHING
+`
+
+type CreatePostIfAbsentParams struct {
+	UserID uuid.UUID
+	PostID uuid.UUID
+	Level  string
+}
+
+func (q *Queries) CreatePostIfAbsent(ctx context.Context, arg CreatePostIfAbsentParams) error {
+	_, err := q.db.Exec(ctx, createPostIfAbsent, arg.UserID, arg.PostID, arg.Level)
+	return err
+}
+
+const deleteBoard = `-- name: DeleteBoard :execrows
+DELETE FROM watches WHERE user_id = $1 AND board_id = $2::uuid
+`
+
+type DeleteBoardParams struct {
+	UserID  uuid.UUID
+	BoardID uuid.UUID
+}
+
+func (q *Queries) DeleteBoard(ctx context.Context, arg DeleteBoardParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteBoard, arg.UserID, arg.BoardID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deletePost = `-- name: DeletePost :execrows
+DELETE FROM watches WHERE user_id = $1 AND post_id = $2::uuid
+`
+
+type DeletePostParams struct {
+	UserID uuid.UUID
+	PostID uuid.UUID
+}
+
+func (q *Queries) DeletePost(ctx context.Context, arg DeletePostParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deletePost, arg.UserID, arg.PostID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const findByUser = `-- name: FindByUser :many
+SELECT id, user_id, post_id, board_id, level, created_at FROM watches WHERE user_id = $1 ORDER BY created_at DESC
+`
+
+func (q *Queries) FindByUser(ctx context.Context, userID uuid.UUID) ([]Watch, error) {
+	rows, err := q.db.Query(ctx, findByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Watch
+	for rows.Next() {
+		var i Watch
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.PostID,
+			&i.BoardID,
+			&i.Level,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findLevel = `-- name: FindLevel :one
+SELECT w.level
+FROM watches w
+WHERE w.user_id = $1
+  AND (w.post_id = $2::uuid OR w.board_id = (SELECT p.board_id FROM posts p WHERE p.id = $2::uuid))
+ORDER BY w.post_id IS NULL
+LIMIT 1
+`
+
+type FindLevelParams struct {
+	UserID uuid.UUID
+	PostID uuid.UUID
+}
+
+// Effective level of one user for the post, see FindPostWatchers
+func (q *Queries) FindLevel(ctx context.Context, arg FindLevelParams) (string, error) {
+	row := q.db.QueryRow(ctx, findLevel, arg.UserID, arg.PostID)
+	var level string
+	err := row.Scan(&level)
+	return level, err
+}
+
+const findPostWatchers = `-- name: FindPostWatchers :many
+SELECT DISTINCT ON (w.user_id) w.user_id, w.level
+FROM watches w
+WHERE w.post_id = $1::uuid
+   OR w.board_id = (SELECT p.board_id FROM posts p WHERE p.id = $1::uuid)
+ORDER BY w.user_id, w.post_id IS NULL
+`
+
+type FindPostWatchersRow struct {
+	UserID uuid.UUID
+	Level  string
+}
+
+// Effective level of every user watching the post, a watch on the post itself overrides a watch on its board
+func (q *Queries) FindPostWatchers(ctx context.Context, postID uuid.UUID) ([]FindPostWatchersRow, error) {
+	rows, err := q.db.Query(ctx, findPostWatchers, postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FindPostWatchersRow
+	for rows.Next() {
+		var i FindPostWatchersRow
+		if err := rows.Scan(&i.UserID, &i.Level); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertBoard = `-- name: UpsertBoard :one
+INSERT INTO watches (user_id, board_id, level) VALUES ($1, $2::uuid, $3)
+ON CONFLICT (user_id, board_id) DO UPDATE SET level = EXCLUDED.level
+RETURNING id, user_id, post_id, board_id, level, created_at
+`
+
+type UpsertBoardParams struct {
+	UserID  uuid.UUID
+	BoardID uuid.UUID
+	Level   string
+}
+
+func (q *Queries) UpsertBoard(ctx context.Context, arg UpsertBoardParams) (Watch, error) {
+	row := q.db.QueryRow(ctx, upsertBoard, arg.UserID, arg.BoardID, arg.Level)
+	var i Watch
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.PostID,
+		&i.BoardID,
+		&i.Level,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const upsertPost = `-- name: UpsertPost :one
+INSERT INTO watches (user_id, post_id, level) VALUES ($1, $2::uuid, $3)
+ON CONFLICT (user_id, post_id) DO UPDATE SET level = EXCLUDED.level
+RETURNING id, user_id, post_id, board_id, level, created_at
+`
+
+type UpsertPostParams struct {
+	UserID uuid.UUID
+	PostID uuid.UUID
+	Level  string
+}
+
+func (q *Queries) UpsertPost(ctx context.Context, arg UpsertPostParams) (Watch, error) {
+	row := q.db.QueryRow(ctx, upsertPost, arg.UserID, arg.PostID, arg.Level)
+	var i Watch
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.PostID,
+		&i.BoardID,
+		&i.Level,
+		&i.CreatedAt,
+	)
+	return i, err
+}
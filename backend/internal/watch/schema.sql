@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS watches (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE NOT NULL,
+    post_id UUID REFERENCES posts(id) ON DELETE CASCADE,
+    board_id UUID REFERENCES boards(id) ON DELETE CASCADE,
+    level VARCHAR(16) NOT NULL CHECK (level IN ('all', 'mentions', 'muted')),
+    created_at TIMESTAMPTZ DEFAULT now() NOT NULL,
+    CHECK ((post_id IS NULL) <> (board_id IS NULL)),
+    UNIQUE (user_id, post_id),
+    UNIQUE (user_id, board_id)
+);
+
+CREATE INDEX IF NOT EXISTS watches_post_id_idx ON watches (post_id);
+CREATE INDEX IF NOT EXISTS watches_board_id_idx ON watches (board_id);
\ No newline at end of file
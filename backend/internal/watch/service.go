@@ -0,0 +1,199 @@
+package watch
+
+import (
+	"backend/internal"
+	"backend/internal/database"
+	errorPkg "backend/internal/error"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// Levels decide which activity in a watched thread notifies the watcher
+const (
+	// LevelAll notifies about every new comment
+	LevelAll = "all"
+	// LevelMentions only notifies when the watcher is @mentioned
+	LevelMentions = "mentions"
+	// LevelMuted never notifies, not even about mentions
+	LevelMuted = "muted"
+)
+
+// Watcher is a user watching a post with the level that applies to it, either from a watch on the post itself or
+// from a watch on its board
+type Watcher struct {
+	UserID uuid.UUID
+	Level  string
+}
+
+type Service struct {
+	logger *zap.Logger
+	tracer trace.Tracer
+	query  *Queries
+}
+
+func NewService(logger *zap.Logger, db DBTX) *Service {
+	return &Service{
+		logger: logger,
+		tracer: otel.Tracer("watch/service"),
+		query:  New(db),
+	}
+}
+
+// WatchPost starts watching the post or changes the level of an existing watch
+func (s *Service) WatchPost(ctx context.Context, userID, postID uuid.UUID, level string) (Watch, error) {
+	traceCtx, span := s.tracer.Start(ctx, "WatchPost")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	watch, err := s.query.UpsertPost(traceCtx, UpsertPostParams{UserID: userID, PostID: postID, Level: level})
+	if err != nil {
+		err = wrapTargetError(err, "posts", postID, logger, "watch post")
+		span.RecordError(err)
+		return Watch{}, err
+	}
+
+	return watch, nil
+}
+
+// WatchBoard starts watching every post of the board or changes the level of an existing watch
+func (s *Service) WatchBoard(ctx context.Context, userID, boardID uuid.UUID, level string) (Watch, error) {
+	traceCtx, span := s.tracer.Start(ctx, "WatchBoard")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	watch, err := s.query.UpsertBoard(traceCtx, UpsertBoardParams{UserID: userID, BoardID: boardID, Level: level})
+	if err != nil {
+		err = wrapTargetError(err, "boards", boardID, logger, "watch board")
+		span.RecordError(err)
+		return Watch{}, err
+	}
+
+	return watch, nil
+}
+
+// WatchIfAbsent watches the post with LevelAll unless the user already chose a level for it. Authors and
+// commenters are subscribed to their threads this way.
+func (s *Service) WatchIfAbsent(ctx context.Context, userID, postID uuid.UUID) error {
+	traceCtx, span := s.tracer.Start(ctx, "WatchIfAbsent")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	err := s.query.CreatePostIfAbsent(traceCtx, CreatePostIfAbsentParams{UserID: userID, PostID: postID, Level: LevelAll})
+	if err != nil {
+		err = wrapTargetError(err, "posts", postID, logger, "watch post if absent")
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *Service) UnwatchPost(ctx context.Context, userID, postID uuid.UUID) error {
+	traceCtx, span := s.tracer.Start(ctx, "UnwatchPost")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	count, err := s.query.DeletePost(traceCtx, DeletePostParams{UserID: userID, PostID: postID})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "unwatch post")
+		span.RecordError(err)
+		return err
+	}
+	if count == 0 {
+		err = errorPkg.NewNotFoundError("watches", "post_id", postID.String(), "")
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *Service) UnwatchBoard(ctx context.Context, userID, boardID uuid.UUID) error {
+	traceCtx, span := s.tracer.Start(ctx, "UnwatchBoard")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	count, err := s.query.DeleteBoard(traceCtx, DeleteBoardParams{UserID: userID, BoardID: boardID})
+	if err != nil {
+		err = database.WrapDBError(err, logger, "unwatch board")
+		span.RecordError(err)
+		return err
+	}
+	if count == 0 {
+		err = errorPkg.NewNotFoundError("watches", "board_id", boardID.String(), "")
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *Service) GetByUser(ctx context.Context, userID uuid.UUID) ([]Watch, error) {
+	traceCtx, span := s.tracer.Start(ctx, "GetByUser")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	watches, err := s.query.FindByUser(traceCtx, userID)
+	if err != nil {
+		err = database.WrapDBError(err, logger, "get watches by user")
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return watches, nil
+}
+
+// GetWatchers returns every user watching the post, directly or through its board, with their effective level
+func (s *Service) GetWatchers(ctx context.Context, postID uuid.UUID) ([]Watcher, error) {
+	traceCtx, span := s.tracer.Start(ctx, "GetWatchers")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	rows, err := s.query.FindPostWatchers(traceCtx, postID)
+	if err != nil {
+		err = database.WrapDBError(err, logger, "get post watchers")
+		span.RecordError(err)
+		return nil, err
+	}
+
+	watchers := make([]Watcher, len(rows))
+	for i, row := range rows {
+		watchers[i] = Watcher{UserID: row.UserID, Level: row.Level}
+	}
+
+	return watchers, nil
+}
+
+// IsMuted reports whether the user muted the post, directly or through its board
+func (s *Service) IsMuted(ctx context.Context, userID, postID uuid.UUID) (bool, error) {
+	traceCtx, span := s.tracer.Start(ctx, "IsMuted")
+	defer span.End()
+	logger := internal.LoggerWithContext(traceCtx, s.logger)
+
+	level, err := s.query.FindLevel(traceCtx, FindLevelParams{UserID: userID, PostID: postID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		err = database.WrapDBError(err, logger, "get watch level")
+		span.RecordError(err)
+		return false, err
+	}
+
+	return level == LevelMuted, nil
+}
+
+// wrapTargetError reports a watch on a post or board that does not exist as not found instead of a foreign key
+// violation
+func wrapTargetError(err error, table string, id uuid.UUID, logger *zap.Logger, operation string) error {
+	err = database.WrapDBError(err, logger, operation)
+	if errors.Is(err, database.ErrForeignKeyViolation) {
+		return errorPkg.NewNotFoundError(table, "id", id.String(), "")
+	}
+	return err
+}